@@ -99,6 +99,7 @@ export default function SqlQueryEditor({
         query={queryWithDefaults}
         isQueryRunnable={isQueryRunnable}
         dialect={dialect}
+        supportsStreaming={datasource.supportsStreaming}
       />
 
       <Space v={0.5} />
@@ -25,6 +25,7 @@ export interface QueryHeaderProps {
   preconfiguredDataset: string;
   query: QueryWithDefaults;
   queryRowFilter: QueryRowFilter;
+  supportsStreaming?: boolean;
 }
 
 export function QueryHeader({
@@ -37,6 +38,7 @@ export function QueryHeader({
   preconfiguredDataset,
   query,
   queryRowFilter,
+  supportsStreaming,
 }: QueryHeaderProps) {
   const { editorMode } = query;
   const [_, copyToClipboard] = useCopyToClipboard();
@@ -132,6 +134,48 @@ export function QueryHeader({
           options={QUERY_FORMAT_OPTIONS}
         />
 
+        {supportsStreaming && (
+          <InlineSwitch
+            id={`sql-stream-${htmlId}`}
+            label={t('grafana-sql.components.query-header.label-stream', 'Stream')}
+            transparent={true}
+            showLabel={true}
+            value={!!query.stream}
+            onChange={(ev) => {
+              if (!(ev.target instanceof HTMLInputElement)) {
+                return;
+              }
+
+              reportInteraction('grafana_sql_stream_toggled', {
+                datasource: query.datasource?.type,
+                stream: ev.target.checked,
+              });
+
+              onChange({ ...query, stream: ev.target.checked });
+            }}
+          />
+        )}
+
+        <InlineSwitch
+          id={`sql-explain-${htmlId}`}
+          label={t('grafana-sql.components.query-header.label-explain', 'Explain')}
+          transparent={true}
+          showLabel={true}
+          value={!!query.explain}
+          onChange={(ev) => {
+            if (!(ev.target instanceof HTMLInputElement)) {
+              return;
+            }
+
+            reportInteraction('grafana_sql_explain_toggled', {
+              datasource: query.datasource?.type,
+              explain: ev.target.checked,
+            });
+
+            onChange({ ...query, explain: ev.target.checked });
+          }}
+        />
+
         {editorMode === EditorMode.Builder && (
           <>
             <InlineSwitch
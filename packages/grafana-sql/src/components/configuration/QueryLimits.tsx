@@ -0,0 +1,129 @@
+import { DataSourceSettings } from '@grafana/data';
+import { t, Trans } from '@grafana/i18n';
+import { ConfigSubSection } from '@grafana/plugin-ui';
+import { Field, Icon, Label, Stack, Tooltip } from '@grafana/ui';
+
+import { SQLOptions, SQLQueryLimits } from '../../types';
+
+import { NumberInput } from './NumberInput';
+
+interface Props {
+  onOptionsChange: Function;
+  options: DataSourceSettings<SQLOptions>;
+}
+
+export const QueryLimits = (props: Props) => {
+  const { onOptionsChange, options } = props;
+  const jsonData = options.jsonData;
+
+  const onJSONDataNumberChanged = (property: keyof SQLQueryLimits) => {
+    return (number?: number) => {
+      onOptionsChange({
+        ...options,
+        jsonData: {
+          ...jsonData,
+          [property]: number,
+        },
+      });
+    };
+  };
+
+  const labelWidth = 40;
+
+  return (
+    <ConfigSubSection title={t('grafana-sql.components.query-limits.title-query-limits', 'Query limits')}>
+      <Field
+        label={
+          <Label>
+            <Stack gap={0.5}>
+              <span>
+                <Trans i18nKey="grafana-sql.components.query-limits.row-limit">Row limit</Trans>
+              </span>
+              <Tooltip
+                content={
+                  <span>
+                    <Trans i18nKey="grafana-sql.components.query-limits.content-row-limit">
+                      The maximum number of rows returned by a query. It can only lower the row limit of the Grafana
+                      server. If set to 0, the row limit of the server is used.
+                    </Trans>
+                  </span>
+                }
+              >
+                <Icon name="info-circle" size="sm" />
+              </Tooltip>
+            </Stack>
+          </Label>
+        }
+      >
+        <NumberInput
+          value={jsonData.rowLimit ?? 0}
+          defaultValue={0}
+          onChange={onJSONDataNumberChanged('rowLimit')}
+          width={labelWidth}
+        />
+      </Field>
+
+      <Field
+        label={
+          <Label>
+            <Stack gap={0.5}>
+              <span>
+                <Trans i18nKey="grafana-sql.components.query-limits.max-estimated-cost">Max estimated cost</Trans>
+              </span>
+              <Tooltip
+                content={
+                  <span>
+                    <Trans i18nKey="grafana-sql.components.query-limits.content-max-estimated-cost">
+                      Queries are explained before they run, and rejected if the cost estimated by the database is above
+                      the limit. The cost is in the unit of the database. If set to 0, the cost is not checked.
+                    </Trans>
+                  </span>
+                }
+              >
+                <Icon name="info-circle" size="sm" />
+              </Tooltip>
+            </Stack>
+          </Label>
+        }
+      >
+        <NumberInput
+          value={jsonData.maxEstimatedCost ?? 0}
+          defaultValue={0}
+          onChange={onJSONDataNumberChanged('maxEstimatedCost')}
+          width={labelWidth}
+        />
+      </Field>
+
+      <Field
+        label={
+          <Label>
+            <Stack gap={0.5}>
+              <span>
+                <Trans i18nKey="grafana-sql.components.query-limits.max-full-scan-rows">Max full scan rows</Trans>
+              </span>
+              <Tooltip
+                content={
+                  <span>
+                    <Trans i18nKey="grafana-sql.components.query-limits.content-max-full-scan-rows">
+                      Queries are explained before they run, and rejected if they read a table of more rows than the
+                      limit with a full table scan. If set to 0, full table scans are not checked.
+                    </Trans>
+                  </span>
+                }
+              >
+                <Icon name="info-circle" size="sm" />
+              </Tooltip>
+            </Stack>
+          </Label>
+        }
+      >
+        <NumberInput
+          value={jsonData.maxFullScanRows ?? 0}
+          defaultValue={0}
+          onChange={onJSONDataNumberChanged('maxFullScanRows')}
+          width={labelWidth}
+        />
+      </Field>
+    </ConfigSubSection>
+  );
+};
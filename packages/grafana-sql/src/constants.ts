@@ -26,6 +26,18 @@ const fillParam: FuncParameter = {
     ]),
 };
 
+const calendarUnitParam: FuncParameter = {
+  name: 'Unit',
+  required: true,
+  options: () =>
+    Promise.resolve(['day', 'week', 'month', 'quarter', 'year'].map((unit) => ({ label: unit, value: unit }))),
+};
+const timezoneParam: FuncParameter = {
+  name: 'Time zone',
+  required: false,
+  options: () => Promise.resolve([{ label: "'$__timezone'", value: "'$__timezone'" }]),
+};
+
 export const MACRO_FUNCTIONS = (columnParam: FuncParameter) => [
   {
     name: '$__timeGroup',
@@ -37,6 +49,16 @@ export const MACRO_FUNCTIONS = (columnParam: FuncParameter) => [
     description: 'Time grouping function with time as alias',
     parameters: [columnParam, intervalParam, fillParam],
   },
+  {
+    name: '$__timeBucket',
+    description: 'Calendar time grouping function',
+    parameters: [columnParam, calendarUnitParam, timezoneParam, fillParam],
+  },
+  {
+    name: '$__timeBucketAlias',
+    description: 'Calendar time grouping function with time as alias',
+    parameters: [columnParam, calendarUnitParam, timezoneParam, fillParam],
+  },
   {
     name: '$__time',
     description: 'An expression to rename the column to time',
@@ -58,6 +80,8 @@ export const MACRO_FUNCTIONS = (columnParam: FuncParameter) => [
 
 export const MACRO_NAMES = [
   '$__time',
+  '$__timeBucket',
+  '$__timeBucketAlias',
   '$__timeEpoch',
   '$__timeFilter',
   '$__timeFrom',
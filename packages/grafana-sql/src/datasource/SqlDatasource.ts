@@ -1,4 +1,4 @@
-import { lastValueFrom, Observable, throwError } from 'rxjs';
+import { lastValueFrom, merge, Observable, throwError } from 'rxjs';
 import { map } from 'rxjs/operators';
 
 import {
@@ -35,6 +35,8 @@ import { MACRO_NAMES } from '../constants';
 import { DB, SQLQuery, SQLOptions, SqlQueryModel, QueryFormat } from '../types';
 import migrateAnnotation from '../utils/migration';
 
+import { doSqlChannelStream } from './streaming';
+
 export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLOptions> {
   id: number;
   responseParser: ResponseParser;
@@ -42,6 +44,8 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
   interval: string;
   db: DB;
   preconfiguredDatabase: string;
+  // Data sources whose backend implements the stream handler can run queries as streams.
+  supportsStreaming = false;
 
   constructor(
     instanceSettings: DataSourceInstanceSettings<SQLOptions>,
@@ -120,6 +124,7 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
       datasource: this.getRef(),
       rawSql: this.templateSrv.replace(target.rawSql, scopedVars, this.interpolateVariable),
       format: target.format,
+      explain: target.explain,
     };
   }
 
@@ -145,7 +150,28 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
       });
     });
 
-    return super.query(request);
+    const streamingTargets = this.supportsStreaming
+      ? request.targets.filter((target) => target.stream && this.filterQuery(target))
+      : [];
+    if (streamingTargets.length === 0) {
+      return super.query(request);
+    }
+
+    const streams = streamingTargets.map((target) =>
+      doSqlChannelStream(
+        {
+          ...this.applyTemplateVariables(target, request.scopedVars),
+          streamInterval: target.streamInterval,
+        },
+        this.uid,
+        request
+      )
+    );
+    const targets = request.targets.filter((target) => !streamingTargets.includes(target));
+    if (targets.length === 0) {
+      return merge(...streams);
+    }
+    return merge(super.query({ ...request, targets }), ...streams);
   }
 
   private checkForDatabaseIssue(request: DataQueryRequest<SQLQuery>) {
@@ -0,0 +1,94 @@
+import { defer, map, mergeMap, Observable } from 'rxjs';
+
+import {
+  DataFrameJSON,
+  DataQueryRequest,
+  DataQueryResponse,
+  LiveChannelEvent,
+  LiveChannelScope,
+  LoadingState,
+  StreamingDataFrame,
+} from '@grafana/data';
+import { config, getGrafanaLiveSrv } from '@grafana/runtime';
+
+import { SQLQuery } from '../types';
+
+/**
+ * Calculate a unique key for the query. The key is used to pick a channel, so that panels running the same
+ * query share the same stream. This key is not secure and is only picked to avoid possible collisions.
+ */
+export async function getLiveStreamKey(query: SQLQuery): Promise<string> {
+  const str = JSON.stringify({
+    rawSql: query.rawSql,
+    format: query.format,
+    streamInterval: query.streamInterval,
+  });
+
+  const msgUint8 = new TextEncoder().encode(str); // encode as (utf-8) Uint8Array
+  const hashBuffer = await crypto.subtle.digest('SHA-1', msgUint8); // hash the message
+  const hashArray = Array.from(new Uint8Array(hashBuffer.slice(0, 8))); // first 8 bytes
+  return `${hashArray.map((b) => b.toString(16).padStart(2, '0')).join('')}/${config.bootData.user.orgId}`;
+}
+
+/**
+ * Runs the query in the backend at the stream interval, and appends the new rows to a streaming frame.
+ * The query should only select the rows after the $__lastSeen() macro.
+ */
+export function doSqlChannelStream(
+  query: SQLQuery,
+  uid: string,
+  options: DataQueryRequest<SQLQuery>
+): Observable<DataQueryResponse> {
+  // maximum time to keep values
+  const range = options.range;
+  const maxDelta = range.to.valueOf() - range.from.valueOf() + 1000;
+  let maxLength = options.maxDataPoints ?? 1000;
+  if (maxLength > 100) {
+    // for small buffers, keep them small
+    maxLength *= 2;
+  }
+
+  let frame: StreamingDataFrame | undefined = undefined;
+  const updateFrame = (msg: LiveChannelEvent<unknown>) => {
+    if ('message' in msg && msg.message) {
+      const p: DataFrameJSON = msg.message;
+      if (!frame) {
+        frame = StreamingDataFrame.fromDataFrameJSON(p, { maxLength, maxDelta });
+        frame.refId = query.refId;
+      } else {
+        frame.push(p);
+      }
+    }
+    return frame;
+  };
+
+  return defer(() => getLiveStreamKey(query)).pipe(
+    mergeMap((key) => {
+      return getGrafanaLiveSrv()
+        .getStream({
+          scope: LiveChannelScope.DataSource,
+          namespace: uid,
+          path: `stream/${key}`,
+          data: {
+            rawSql: query.rawSql,
+            format: query.format,
+            streamInterval: query.streamInterval,
+            timeRange: {
+              from: range.from.valueOf().toString(),
+              to: range.to.valueOf().toString(),
+            },
+          },
+        })
+        .pipe(
+          map((evt) => {
+            const frame = updateFrame(evt);
+            return {
+              data: frame ? [frame] : [],
+              key: query.refId,
+              state: LoadingState.Streaming,
+            };
+          })
+        );
+    })
+  );
+}
@@ -14,6 +14,7 @@ export { COMMON_FNS, MACRO_FUNCTIONS } from './constants';
 export { SqlDatasource } from './datasource/SqlDatasource';
 export { formatSQL } from './utils/formatSQL';
 export { ConnectionLimits } from './components/configuration/ConnectionLimits';
+export { QueryLimits } from './components/configuration/QueryLimits';
 export { MaxLifetimeField } from './components/configuration/MaxLifetimeField';
 export { MaxOpenConnectionsField } from './components/configuration/MaxOpenConnectionsField';
 export { Divider } from './components/configuration/Divider';
@@ -34,7 +34,13 @@ export interface SQLConnectionLimits {
   connMaxLifetime: number;
 }
 
-export interface SQLOptions extends SQLConnectionLimits, DataSourceJsonData {
+export interface SQLQueryLimits {
+  rowLimit?: number;
+  maxEstimatedCost?: number;
+  maxFullScanRows?: number;
+}
+
+export interface SQLOptions extends SQLConnectionLimits, SQLQueryLimits, DataSourceJsonData {
   tlsAuth: boolean;
   tlsAuthWithCACert: boolean;
   timezone: string;
@@ -59,6 +65,11 @@ export interface SQLQuery extends DataQuery {
   sql?: SQLExpression;
   editorMode?: EditorMode;
   rawQuery?: boolean;
+  // Streaming queries are run periodically by the backend, which pushes only the new rows.
+  stream?: boolean;
+  streamInterval?: string;
+  // Explained queries return the plan of the query, without running it.
+  explain?: boolean;
 }
 
 export interface NameValue {
@@ -218,25 +218,34 @@ type ResampleCommand struct {
 	VarToResample string
 	Downsampler   mathexp.ReducerID
 	Upsampler     mathexp.Upsampler
+	FillValue     float64
 	TimeRange     TimeRange
 	refID         string
 }
 
 // NewResampleCommand creates a new ResampleCMD.
-func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, tr TimeRange) (*ResampleCommand, error) {
+// fillValue must be set when the upsampler is mathexp.UpsamplerFillValue and is ignored otherwise.
+func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, fillValue *float64, tr TimeRange) (*ResampleCommand, error) {
 	// TODO: validate reducer here, before execution
 	window, err := gtime.ParseDuration(rawWindow)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to parse resample "window" duration field %q: %w`, window, err)
 	}
-	return &ResampleCommand{
+	cmd := &ResampleCommand{
 		Window:        window,
 		VarToResample: varToResample,
 		Downsampler:   downsampler,
 		Upsampler:     upsampler,
 		TimeRange:     tr,
 		refID:         refID,
-	}, nil
+	}
+	if upsampler == mathexp.UpsamplerFillValue {
+		if fillValue == nil {
+			return nil, fmt.Errorf("fillValue must be specified when upsampler is '%s'", mathexp.UpsamplerFillValue)
+		}
+		cmd.FillValue = *fillValue
+	}
+	return cmd, nil
 }
 
 // UnmarshalResampleCommand creates a ResampleCMD from Grafana's frontend query.
@@ -282,10 +291,20 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 		return nil, fmt.Errorf("expected resample downsampler to be a string, got type %T", upsampler)
 	}
 
+	var fillValue *float64
+	if rawFillValue, ok := rn.Query["fillValue"]; ok && rawFillValue != nil {
+		value, ok := rawFillValue.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected resample fillValue to be a number, got type %T", rawFillValue)
+		}
+		fillValue = &value
+	}
+
 	return NewResampleCommand(rn.RefID, window,
 		varToResample,
 		mathexp.ReducerID(downsampler),
 		mathexp.Upsampler(upsampler),
+		fillValue,
 		rn.TimeRange)
 }
 
@@ -308,7 +327,7 @@ func (gr *ResampleCommand) Execute(ctx context.Context, now time.Time, vars math
 		}
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, timeRange.From, timeRange.To)
+			num, err := v.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, gr.FillValue, timeRange.From, timeRange.To)
 			if err != nil {
 				return newRes, err
 			}
@@ -345,6 +364,8 @@ const (
 	TypeThreshold
 	// TypeSQL is the CMDType for running SQL expressions
 	TypeSQL
+	// TypeForecast is the CMDType for predicting future values of series
+	TypeForecast
 )
 
 func (gt CommandType) String() string {
@@ -361,6 +382,8 @@ func (gt CommandType) String() string {
 		return "threshold"
 	case TypeSQL:
 		return "sql"
+	case TypeForecast:
+		return "forecast"
 	default:
 		return "unknown"
 	}
@@ -381,6 +404,8 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeThreshold, nil
 	case "sql":
 		return TypeSQL, nil
+	case "forecast":
+		return TypeForecast, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -99,6 +99,64 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 	}
 }
 
+func Test_UnmarshalResampleCommand_FillValue(t *testing.T) {
+	var tests = []struct {
+		name              string
+		upsampler         string
+		queryFillValue    string
+		isError           bool
+		expectedFillValue float64
+	}{
+		{
+			name:      "fill value is not needed for other upsamplers",
+			upsampler: "linear",
+		},
+		{
+			name:              "fill value is set when upsampler is 'fillvalue'",
+			upsampler:         "fillvalue",
+			queryFillValue:    `, "fillValue": -12`,
+			expectedFillValue: -12,
+		},
+		{
+			name:      "error if upsampler is 'fillvalue' but field fillValue is not specified",
+			upsampler: "fillvalue",
+			isError:   true,
+		},
+		{
+			name:           "error if field fillValue is not a number",
+			upsampler:      "fillvalue",
+			queryFillValue: `, "fillValue": "-12"`,
+			isError:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := fmt.Sprintf(`{ "expression" : "$A", "window": "1m", "downsampler": "mean", "upsampler": "%s"%s }`, test.upsampler, test.queryFillValue)
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(q), &qmap))
+
+			cmd, err := UnmarshalResampleCommand(&rawNode{
+				RefID:      "A",
+				Query:      qmap,
+				QueryType:  "",
+				TimeRange:  RelativeTimeRange{},
+				DataSource: nil,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NotNil(t, cmd)
+
+			require.Equal(t, mathexp.Upsampler(test.upsampler), cmd.Upsampler)
+			require.Equal(t, test.expectedFillValue, cmd.FillValue)
+		})
+	}
+}
+
 func TestReduceExecute(t *testing.T) {
 	varToReduce := util.GenerateShortUID()
 
@@ -221,7 +279,7 @@ func TestResampleCommand_Execute(t *testing.T) {
 		From: -10 * time.Second,
 		To:   0,
 	}
-	cmd, err := NewResampleCommand(util.GenerateShortUID(), "1s", varToReduce, "sum", "pad", tr)
+	cmd, err := NewResampleCommand(util.GenerateShortUID(), "1s", varToReduce, "sum", "pad", nil, tr)
 	require.NoError(t, err)
 
 	var tests = []struct {
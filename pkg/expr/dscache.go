@@ -0,0 +1,137 @@
+package expr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+
+	"github.com/grafana/grafana/pkg/expr/metrics"
+)
+
+// dsResultCache is a bounded cache of the response frames of data source queries. It lets requests that
+// opt in with Request.CacheDatasourceResults, such as the evaluations of alert rules, share the result of
+// a query that is made with the same data source, query, time range and interval.
+//
+// Frames are stored in their Arrow encoding, so each lookup returns a copy that the caller can modify.
+// All methods are no-ops on a nil receiver, which is what newDSResultCache returns when the cache is disabled.
+type dsResultCache struct {
+	entries *expirable.LRU[string, [][]byte]
+	metrics *metrics.ExprMetrics
+}
+
+func newDSResultCache(maxEntries int, ttl time.Duration, m *metrics.ExprMetrics) *dsResultCache {
+	if maxEntries <= 0 || ttl <= 0 {
+		return nil
+	}
+	return &dsResultCache{
+		entries: expirable.NewLRU[string, [][]byte](maxEntries, nil, ttl),
+		metrics: m,
+	}
+}
+
+// get returns a copy of the frames cached for the query of the node.
+func (c *dsResultCache) get(dn *DSNode, q backend.DataQuery) (data.Frames, bool) {
+	if c == nil || !dn.request.CacheDatasourceResults {
+		return nil, false
+	}
+	key, ok := dsResultCacheKey(dn, q)
+	if !ok {
+		return nil, false
+	}
+	if encoded, ok := c.entries.Get(key); ok {
+		frames, err := data.UnmarshalArrowFrames(encoded)
+		if err == nil {
+			for _, f := range frames {
+				f.RefID = dn.refID
+			}
+			c.metrics.DSCacheRequests.WithLabelValues("hit", dn.datasource.Type).Inc()
+			return frames, true
+		}
+		c.entries.Remove(key)
+	}
+	c.metrics.DSCacheRequests.WithLabelValues("miss", dn.datasource.Type).Inc()
+	return nil, false
+}
+
+// set caches the frames of a successful response to the query of the node.
+func (c *dsResultCache) set(dn *DSNode, q backend.DataQuery, frames data.Frames) {
+	if c == nil || !dn.request.CacheDatasourceResults {
+		return
+	}
+	key, ok := dsResultCacheKey(dn, q)
+	if !ok {
+		return
+	}
+	encoded, err := frames.MarshalArrow()
+	if err != nil {
+		return
+	}
+	c.entries.Add(key, encoded)
+}
+
+// ruleMetadataHeaderPrefix is the prefix of the headers that describe the evaluated rule. They are left
+// out of the cache key, so that the same query of different rules gets the same key.
+const ruleMetadataHeaderPrefix = "http_X-Rule-"
+
+// dsResultCacheKey returns the key of the query of the node. The refID, data source reference and
+// the fields that are part of the request itself are removed from the query model, and the remaining
+// fields are encoded with sorted keys, so that the same query of different rules gets the same key.
+// The identity and the forwarded headers of the request are part of the key, as data sources can
+// return different results for different callers.
+// It returns false if the query model cannot be normalized.
+func dsResultCacheKey(dn *DSNode, q backend.DataQuery) (string, bool) {
+	if dn.datasource == nil {
+		return "", false
+	}
+	var model map[string]any
+	if err := json.Unmarshal(q.JSON, &model); err != nil {
+		return "", false
+	}
+	for _, k := range []string{"refId", "datasource", "datasourceId", "intervalMs", "maxDataPoints", "queryType"} {
+		delete(model, k)
+	}
+	normalized, err := json.Marshal(model)
+	if err != nil {
+		return "", false
+	}
+
+	identity := ""
+	if dn.request.User != nil {
+		identity = dn.request.User.GetUID()
+	}
+	headers := make([]string, 0, len(dn.request.Headers))
+	for k, v := range dn.request.Headers {
+		if strings.HasPrefix(k, ruleMetadataHeaderPrefix) {
+			continue
+		}
+		headers = append(headers, k+"="+v)
+	}
+	sort.Strings(headers)
+
+	h := sha256.New()
+	for _, part := range []string{
+		strconv.FormatInt(dn.orgID, 10),
+		identity,
+		strings.Join(headers, "\x00"),
+		dn.datasource.UID,
+		strconv.Itoa(dn.datasource.Version),
+		q.QueryType,
+		strconv.FormatInt(q.TimeRange.From.UnixNano(), 10),
+		strconv.FormatInt(q.TimeRange.To.UnixNano(), 10),
+		strconv.FormatInt(int64(q.Interval), 10),
+		strconv.FormatInt(q.MaxDataPoints, 10),
+		string(normalized),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
@@ -0,0 +1,145 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	claims "github.com/grafana/authlib/types"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+type countingEndpoint struct {
+	backend.QueryDataHandler
+	queries int
+}
+
+func (ce *countingEndpoint) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	ce.queries += len(req.Queries)
+	return ce.QueryDataHandler.QueryData(ctx, req)
+}
+
+func TestDSResultCache(t *testing.T) {
+	dsDF := data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+		data.NewField("value", data.Labels{"test": "label"}, []*float64{fp(2), fp(3)}),
+	)
+	ds := &datasources.DataSource{OrgID: 1, UID: "test", Type: "test"}
+	dsQuery := func(refID string, model string, from time.Time) Query {
+		return Query{
+			RefID:      refID,
+			DataSource: ds,
+			JSON:       json.RawMessage(model),
+			TimeRange:  AbsoluteTimeRange{From: from, To: from.Add(time.Hour)},
+		}
+	}
+	from := time.Unix(0, 0)
+	responses := map[string]backend.DataResponse{
+		"A": {Frames: data.Frames{dsDF}},
+		"B": {Frames: data.Frames{dsDF}},
+	}
+
+	newService := func(flags ...any) (*Service, *countingEndpoint) {
+		s, _ := newMockQueryService(responses, nil)
+		s.features = featuremgmt.WithFeatures(flags...)
+		s.dsResultCache = newDSResultCache(10, time.Minute, s.metrics)
+		endpoint := &countingEndpoint{QueryDataHandler: s.dataService}
+		s.dataService = endpoint
+		return s, endpoint
+	}
+	execute := func(t *testing.T, s *Service, cache bool, queries ...Query) *backend.QueryDataResponse {
+		t.Helper()
+		pl, err := s.BuildPipeline(t.Context(), &Request{Queries: queries, CacheDatasourceResults: cache})
+		require.NoError(t, err)
+		res, err := s.ExecutePipeline(t.Context(), time.Now(), pl)
+		require.NoError(t, err)
+		return res
+	}
+
+	for _, grouped := range []bool{false, true} {
+		var flags []any
+		if grouped {
+			flags = append(flags, featuremgmt.FlagSseGroupByDatasource)
+		}
+
+		t.Run(fmt.Sprintf("same query of different requests is queried once (grouped=%t)", grouped), func(t *testing.T) {
+			s, endpoint := newService(flags...)
+
+			first := execute(t, s, true, dsQuery("A", `{ "datasource": { "uid": "test" }, "refId": "A", "expr": "up" }`, from))
+			second := execute(t, s, true, dsQuery("B", `{ "refId": "B", "expr": "up", "datasource": { "uid": "test", "type": "test" } }`, from))
+			require.Equal(t, 1, endpoint.queries)
+			require.NoError(t, second.Responses["B"].Error)
+			require.Len(t, second.Responses["B"].Frames, 1)
+			require.Equal(t, first.Responses["A"].Frames[0].Fields, second.Responses["B"].Frames[0].Fields)
+		})
+
+		t.Run(fmt.Sprintf("different queries or time ranges are not shared (grouped=%t)", grouped), func(t *testing.T) {
+			s, endpoint := newService(flags...)
+
+			execute(t, s, true, dsQuery("A", `{ "expr": "up" }`, from))
+			execute(t, s, true, dsQuery("A", `{ "expr": "down" }`, from))
+			execute(t, s, true, dsQuery("A", `{ "expr": "up" }`, from.Add(time.Minute)))
+			require.Equal(t, 3, endpoint.queries)
+		})
+	}
+
+	t.Run("requests of different callers are not shared", func(t *testing.T) {
+		s, endpoint := newService()
+		execute := func(user identity.Requester, headers map[string]string) {
+			t.Helper()
+			pl, err := s.BuildPipeline(t.Context(), &Request{
+				Queries:                []Query{dsQuery("A", `{ "expr": "up" }`, from)},
+				User:                   user,
+				Headers:                headers,
+				CacheDatasourceResults: true,
+			})
+			require.NoError(t, err)
+			_, err = s.ExecutePipeline(t.Context(), time.Now(), pl)
+			require.NoError(t, err)
+		}
+		alice := &identity.StaticRequester{Type: claims.TypeUser, UserUID: "alice"}
+		bob := &identity.StaticRequester{Type: claims.TypeUser, UserUID: "bob"}
+
+		execute(alice, map[string]string{"FromAlert": "true", "http_X-Rule-Uid": "rule-1"})
+		execute(alice, map[string]string{"FromAlert": "true", "http_X-Rule-Uid": "rule-2"})
+		require.Equal(t, 1, endpoint.queries)
+
+		execute(bob, map[string]string{"FromAlert": "true", "http_X-Rule-Uid": "rule-1"})
+		require.Equal(t, 2, endpoint.queries)
+
+		execute(alice, map[string]string{"FromAlert": "true", "X-Tenant": "a"})
+		require.Equal(t, 3, endpoint.queries)
+	})
+
+	t.Run("requests that do not opt in are always queried", func(t *testing.T) {
+		s, endpoint := newService()
+
+		execute(t, s, false, dsQuery("A", `{ "expr": "up" }`, from))
+		execute(t, s, true, dsQuery("A", `{ "expr": "up" }`, from))
+		execute(t, s, false, dsQuery("A", `{ "expr": "up" }`, from))
+		require.Equal(t, 3, endpoint.queries)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		s, endpoint := newService()
+		endpoint.QueryDataHandler = &mockEndpoint{Responses: map[string]backend.DataResponse{"A": {Error: fmt.Errorf("womp womp")}}}
+
+		res := execute(t, s, true, dsQuery("A", `{ "expr": "up" }`, from))
+		require.ErrorContains(t, res.Responses["A"].Error, "womp womp")
+		execute(t, s, true, dsQuery("A", `{ "expr": "up" }`, from))
+		require.Equal(t, 2, endpoint.queries)
+	})
+
+	t.Run("disabled cache is nil", func(t *testing.T) {
+		require.Nil(t, newDSResultCache(0, time.Minute, nil))
+		require.Nil(t, newDSResultCache(10, 0, nil))
+	})
+}
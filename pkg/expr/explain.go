@@ -0,0 +1,182 @@
+package expr
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"golang.org/x/exp/maps"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// PipelinePlan describes a DataPipeline: its nodes in execution order and the dependencies between them.
+type PipelinePlan struct {
+	Nodes []PlanNode `json:"nodes"`
+	// Duration is the time it took to execute the whole pipeline. It is only set if the pipeline was executed.
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// PlanNode describes a single node of a PipelinePlan.
+type PlanNode struct {
+	RefID string `json:"refId"`
+	// Order is the position of the node in the execution order, starting at 0.
+	Order int `json:"order"`
+	// NodeType is the kind of node, e.g. "Expression" or "Datasource".
+	NodeType string `json:"nodeType"`
+	// Type is the command type of an expression, the data source type of a data source query,
+	// or the command type of a machine learning query.
+	Type          string `json:"type"`
+	DatasourceUID string `json:"datasourceUid,omitempty"`
+	// DependsOn are the refIDs of the nodes whose results are inputs to this node.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// InputTo are the refIDs of the nodes that use the results of this node.
+	InputTo []string `json:"inputTo,omitempty"`
+	// Stats is only set if the pipeline was executed.
+	Stats *NodeStats `json:"stats,omitempty"`
+}
+
+// NodeStats holds the execution statistics of a PlanNode.
+type NodeStats struct {
+	Duration time.Duration `json:"duration"`
+	// Grouped is true if the node was queried in a single request together with the other queries
+	// to the same data source. Duration is then the duration of that request.
+	Grouped bool `json:"grouped,omitempty"`
+	// Skipped is true if the node was not executed because one of its dependencies failed.
+	Skipped bool   `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+	// ValueType is the type of the result, e.g. "seriesSet" or "numberSet".
+	ValueType string `json:"valueType,omitempty"`
+	// SeriesCount is the number of items (series or numbers) in the result.
+	SeriesCount int `json:"seriesCount"`
+	// FrameCount, FieldCount and RowCount describe the shape of the data frames of the result.
+	FrameCount int `json:"frameCount"`
+	FieldCount int `json:"fieldCount"`
+	RowCount   int `json:"rowCount"`
+}
+
+// Plan returns the plan of the pipeline, without execution stats.
+func (dp *DataPipeline) Plan() *PipelinePlan {
+	plan := &PipelinePlan{}
+	if dp == nil {
+		return plan
+	}
+	plan.Nodes = make([]PlanNode, 0, len(*dp))
+	for i, node := range *dp {
+		n := PlanNode{
+			RefID:    node.RefID(),
+			Order:    i,
+			NodeType: node.NodeType().String(),
+		}
+		if needsVars := node.NeedsVars(); len(needsVars) > 0 {
+			n.DependsOn = slices.Clone(needsVars)
+		}
+		if inputTo := node.IsInputTo(); len(inputTo) > 0 {
+			n.InputTo = maps.Keys(inputTo)
+			slices.Sort(n.InputTo)
+		}
+		switch t := node.(type) {
+		case *CMDNode:
+			if t.Command != nil {
+				n.Type = t.Command.Type()
+			}
+		case *DSNode:
+			if t.datasource != nil {
+				n.Type = t.datasource.Type
+				n.DatasourceUID = t.datasource.UID
+			}
+		case *MLNode:
+			if t.command != nil {
+				n.Type = t.command.Type()
+			}
+		}
+		plan.Nodes = append(plan.Nodes, n)
+	}
+	return plan
+}
+
+// ExplainPipeline builds the pipeline for the request and returns its plan without executing it.
+func (s *Service) ExplainPipeline(ctx context.Context, req *Request) (*PipelinePlan, error) {
+	pipeline, err := s.buildPipeline(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.Plan(), nil
+}
+
+// ExecutePipelineWithPlan executes an expression pipeline like ExecutePipeline, and also returns
+// the plan of the pipeline with the execution stats of each node.
+func (s *Service) ExecutePipelineWithPlan(ctx context.Context, now time.Time, pipeline DataPipeline) (*backend.QueryDataResponse, *PipelinePlan, error) {
+	stats := &executionStats{nodes: make(map[string]*NodeStats, len(pipeline))}
+	start := time.Now()
+	res, err := s.executePipeline(ctx, now, pipeline, stats)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan := pipeline.Plan()
+	plan.Duration = time.Since(start)
+	for i := range plan.Nodes {
+		plan.Nodes[i].Stats = stats.nodes[plan.Nodes[i].RefID]
+	}
+	return res, plan, nil
+}
+
+// executionStats collects the NodeStats of the nodes of a pipeline during execution.
+// All methods are no-ops on a nil receiver so that the execution does not need to check
+// whether stats are collected.
+type executionStats struct {
+	nodes map[string]*NodeStats
+}
+
+func (es *executionStats) record(refID string, d time.Duration, res mathexp.Results) {
+	if es == nil {
+		return
+	}
+	stats := &NodeStats{
+		Duration:    d,
+		SeriesCount: len(res.Values),
+	}
+	if res.Error != nil {
+		stats.Error = res.Error.Error()
+	}
+	for _, v := range res.Values {
+		if v == nil {
+			continue
+		}
+		if stats.ValueType == "" {
+			stats.ValueType = v.Type().String()
+		}
+		frame := v.AsDataFrame()
+		if frame == nil {
+			continue
+		}
+		stats.FrameCount++
+		stats.FieldCount += len(frame.Fields)
+		if rows, err := frame.RowLen(); err == nil {
+			stats.RowCount += rows
+		}
+	}
+	es.nodes[refID] = stats
+}
+
+// recordGroup records the stats of data source nodes that were queried in a single request.
+func (es *executionStats) recordGroup(nodes []*DSNode, d time.Duration, vars mathexp.Vars) {
+	if es == nil {
+		return
+	}
+	for _, dn := range nodes {
+		es.record(dn.refID, d, vars[dn.refID])
+		es.nodes[dn.refID].Grouped = true
+	}
+}
+
+func (es *executionStats) skip(refID string, err error) {
+	if es == nil {
+		return
+	}
+	es.nodes[refID] = &NodeStats{
+		Skipped: true,
+		Error:   err.Error(),
+	}
+}
@@ -0,0 +1,131 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+func TestExplainPipeline(t *testing.T) {
+	dsDF := data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+		data.NewField("value", data.Labels{"test": "label"}, []*float64{fp(2), fp(3)}),
+	)
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON: json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange: AbsoluteTimeRange{
+				From: time.Time{},
+				To:   time.Time{},
+			},
+		},
+		{
+			RefID:      "B",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "reduce", "reducer": "last", "expression": "$C" }`),
+		},
+		{
+			RefID:      "C",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2" }`),
+		},
+	}
+
+	expectedPlan := func() []PlanNode {
+		return []PlanNode{
+			{RefID: "A", Order: 0, NodeType: "Datasource", Type: "test", DatasourceUID: "test", InputTo: []string{"C"}},
+			{RefID: "C", Order: 1, NodeType: "Expression", Type: "math", DependsOn: []string{"A"}, InputTo: []string{"B"}},
+			{RefID: "B", Order: 2, NodeType: "Expression", Type: "reduce", DependsOn: []string{"C"}},
+		}
+	}
+
+	t.Run("explain returns the plan without executing it", func(t *testing.T) {
+		s, req := newMockQueryService(nil, queries)
+
+		plan, err := s.ExplainPipeline(t.Context(), req)
+		require.NoError(t, err)
+		require.Equal(t, expectedPlan(), plan.Nodes)
+		require.Zero(t, plan.Duration)
+	})
+
+	t.Run("explain returns build errors", func(t *testing.T) {
+		s, req := newMockQueryService(nil, queries[1:])
+
+		_, err := s.ExplainPipeline(t.Context(), req)
+		require.ErrorContains(t, err, "unable to find dependent node 'A'")
+	})
+
+	t.Run("execute with plan returns stats of each node", func(t *testing.T) {
+		s, req := newMockQueryService(map[string]backend.DataResponse{"A": {Frames: data.Frames{dsDF}}}, queries)
+		pl, err := s.BuildPipeline(t.Context(), req)
+		require.NoError(t, err)
+
+		res, plan, err := s.ExecutePipelineWithPlan(t.Context(), time.Now(), pl)
+		require.NoError(t, err)
+		require.Len(t, res.Responses, 3)
+		require.Len(t, plan.Nodes, 3)
+		require.Positive(t, plan.Duration)
+
+		stats := map[string]NodeStats{}
+		for i, n := range plan.Nodes {
+			require.NotNil(t, n.Stats, n.RefID)
+			stats[n.RefID] = *n.Stats
+			n.Stats = nil
+			require.Equal(t, expectedPlan()[i], n)
+		}
+		require.Equal(t, "seriesSet", stats["C"].ValueType)
+		require.Equal(t, 1, stats["C"].SeriesCount)
+		require.Equal(t, 1, stats["C"].FrameCount)
+		require.Equal(t, 2, stats["C"].FieldCount)
+		require.Equal(t, 2, stats["C"].RowCount)
+		require.Equal(t, "numberSet", stats["B"].ValueType)
+		require.Equal(t, 1, stats["B"].SeriesCount)
+		require.Equal(t, 1, stats["B"].RowCount)
+	})
+
+	t.Run("execute with plan marks grouped data source queries", func(t *testing.T) {
+		s, req := newMockQueryService(map[string]backend.DataResponse{"A": {Frames: data.Frames{dsDF}}}, queries)
+		s.features = featuremgmt.WithFeatures(featuremgmt.FlagSseGroupByDatasource)
+		pl, err := s.BuildPipeline(t.Context(), req)
+		require.NoError(t, err)
+
+		_, plan, err := s.ExecutePipelineWithPlan(t.Context(), time.Now(), pl)
+		require.NoError(t, err)
+		require.Equal(t, "A", plan.Nodes[0].RefID)
+		require.True(t, plan.Nodes[0].Stats.Grouped)
+		require.Equal(t, 1, plan.Nodes[0].Stats.SeriesCount)
+		require.False(t, plan.Nodes[1].Stats.Grouped)
+	})
+
+	t.Run("execute with plan marks nodes skipped after a failed dependency", func(t *testing.T) {
+		s, req := newMockQueryService(map[string]backend.DataResponse{"A": {Error: fmt.Errorf("womp womp")}}, queries)
+		pl, err := s.BuildPipeline(t.Context(), req)
+		require.NoError(t, err)
+
+		_, plan, err := s.ExecutePipelineWithPlan(t.Context(), time.Now(), pl)
+		require.NoError(t, err)
+		byRefID := map[string]*NodeStats{}
+		for _, n := range plan.Nodes {
+			byRefID[n.RefID] = n.Stats
+		}
+		require.Contains(t, byRefID["A"].Error, "womp womp")
+		require.False(t, byRefID["A"].Skipped)
+		require.True(t, byRefID["C"].Skipped)
+		require.True(t, byRefID["B"].Skipped)
+	})
+}
@@ -0,0 +1,435 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+const (
+	// forecastLabel is the label that distinguishes the returned series when all outputs are requested.
+	forecastLabel = "forecast"
+
+	defaultForecastConfidence = 2.0
+	defaultHoltWintersAlpha   = 0.5
+	defaultHoltWintersBeta    = 0.1
+	defaultHoltWintersGamma   = 0.1
+
+	// maxForecastPoints limits the number of predicted points per series.
+	maxForecastPoints = 10000
+)
+
+// ForecastCommand is an expression command that fits a model to each input series and returns
+// the values predicted for the given horizon, optionally with a confidence band.
+type ForecastCommand struct {
+	VarToForecast string
+	Model         ForecastModel
+	Horizon       time.Duration
+	// Interval between predicted points. If zero, the median interval of each input series is used.
+	Interval     time.Duration
+	Output       ForecastOutput
+	Confidence   float64
+	SeasonLength int
+	Alpha        float64
+	Beta         float64
+	Gamma        float64
+	refID        string
+}
+
+// NewForecastCommand creates a new ForecastCommand from the query.
+func NewForecastCommand(refID string, q ForecastQuery) (*ForecastCommand, error) {
+	varToForecast := strings.TrimPrefix(q.Expression, "$")
+	if varToForecast == "" {
+		return nil, fmt.Errorf("no variable specified to reference for refId %v", refID)
+	}
+
+	cmd := &ForecastCommand{
+		VarToForecast: varToForecast,
+		Model:         q.Model,
+		Output:        q.Output,
+		Confidence:    defaultForecastConfidence,
+		SeasonLength:  q.SeasonLength,
+		Alpha:         defaultHoltWintersAlpha,
+		Beta:          defaultHoltWintersBeta,
+		Gamma:         defaultHoltWintersGamma,
+		refID:         refID,
+	}
+
+	switch cmd.Model {
+	case ForecastModelLinear, ForecastModelHoltWinters:
+	default:
+		return nil, fmt.Errorf("forecast model '%s' is not supported. Supported only: [%s,%s]", q.Model, ForecastModelLinear, ForecastModelHoltWinters)
+	}
+
+	switch cmd.Output {
+	case "":
+		cmd.Output = ForecastOutputPredicted
+	case ForecastOutputPredicted, ForecastOutputLower, ForecastOutputUpper, ForecastOutputAll:
+	default:
+		return nil, fmt.Errorf("forecast output '%s' is not supported. Supported only: [%s,%s,%s,%s]", q.Output, ForecastOutputPredicted, ForecastOutputLower, ForecastOutputUpper, ForecastOutputAll)
+	}
+
+	var err error
+	cmd.Horizon, err = gtime.ParseDuration(q.Horizon)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse forecast "horizon" duration field %q: %w`, q.Horizon, err)
+	}
+	if cmd.Horizon <= 0 {
+		return nil, fmt.Errorf("forecast horizon must be greater than zero, got %q", q.Horizon)
+	}
+
+	if q.Interval != "" {
+		cmd.Interval, err = gtime.ParseDuration(q.Interval)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse forecast "interval" duration field %q: %w`, q.Interval, err)
+		}
+		if cmd.Interval <= 0 {
+			return nil, fmt.Errorf("forecast interval must be greater than zero, got %q", q.Interval)
+		}
+		if int64(cmd.Horizon/cmd.Interval) > maxForecastPoints {
+			return nil, fmt.Errorf("forecast horizon %s with interval %s results in more than %d points", cmd.Horizon, cmd.Interval, maxForecastPoints)
+		}
+	}
+
+	if q.Confidence != nil {
+		if *q.Confidence < 0 {
+			return nil, fmt.Errorf("forecast confidence must not be negative, got %v", *q.Confidence)
+		}
+		cmd.Confidence = *q.Confidence
+	}
+
+	if q.SeasonLength < 0 {
+		return nil, fmt.Errorf("forecast season length must not be negative, got %v", q.SeasonLength)
+	}
+	for _, f := range []struct {
+		name  string
+		value *float64
+		dest  *float64
+	}{
+		{"alpha", q.Alpha, &cmd.Alpha},
+		{"beta", q.Beta, &cmd.Beta},
+		{"gamma", q.Gamma, &cmd.Gamma},
+	} {
+		if f.value == nil {
+			continue
+		}
+		if *f.value < 0 || *f.value > 1 {
+			return nil, fmt.Errorf("forecast smoothing factor %s must be between 0 and 1, got %v", f.name, *f.value)
+		}
+		*f.dest = *f.value
+	}
+
+	return cmd, nil
+}
+
+// UnmarshalForecastCommand creates a ForecastCommand from Grafana's frontend query.
+func UnmarshalForecastCommand(rn *rawNode) (*ForecastCommand, error) {
+	q := ForecastQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the forecast command: %w", err)
+	}
+	return NewForecastCommand(rn.RefID, q)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (fc *ForecastCommand) NeedsVars() []string {
+	return []string{fc.VarToForecast}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (fc *ForecastCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteForecast")
+	defer span.End()
+	span.SetAttributes(attribute.String("model", string(fc.Model)))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[fc.VarToForecast].Values {
+		if val == nil {
+			continue
+		}
+		switch v := val.(type) {
+		case mathexp.Series:
+			newRes.Values = append(newRes.Values, fc.forecastSeries(v)...)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+			return newRes, nil
+		default:
+			return newRes, fmt.Errorf("can only forecast type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (fc *ForecastCommand) Type() string {
+	return TypeForecast.String()
+}
+
+// forecastPoint is a non-null point of the input series.
+type forecastPoint struct {
+	t time.Time
+	v float64
+}
+
+// forecastModel predicts the value at a time after the last point of the fitted series,
+// and reports the standard deviation of its residuals.
+type forecastModel interface {
+	predict(t time.Time) float64
+	residualStdDev() float64
+}
+
+// forecastSeries returns the forecast series for s. If s does not have enough points to fit the model,
+// the series have no points and a warning notice.
+func (fc *ForecastCommand) forecastSeries(s mathexp.Series) []mathexp.Value {
+	points := make([]forecastPoint, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		if f == nil || math.IsNaN(*f) || math.IsInf(*f, 0) {
+			continue
+		}
+		points = append(points, forecastPoint{t: t, v: *f})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].t.Before(points[j].t)
+	})
+	// The models assume one point per step, so only the last value of a repeated timestamp is kept.
+	deduped := points[:0]
+	for _, p := range points {
+		if n := len(deduped); n > 0 && deduped[n-1].t.Equal(p.t) {
+			deduped[n-1] = p
+			continue
+		}
+		deduped = append(deduped, p)
+	}
+	points = deduped
+
+	step := medianInterval(points)
+	interval := fc.Interval
+	if interval == 0 {
+		interval = step
+	}
+
+	var model forecastModel
+	var err error
+	switch {
+	case interval <= 0:
+		err = errors.New("at least two points with distinct timestamps are required")
+	case fc.Interval == 0 && int64(fc.Horizon/interval) > maxForecastPoints:
+		err = fmt.Errorf("horizon %s with the series interval %s results in more than %d points, set an interval", fc.Horizon, interval, maxForecastPoints)
+	case fc.Model == ForecastModelLinear:
+		model, err = fitLinearTrend(points)
+	default:
+		model, err = fitHoltWinters(points, step, fc.SeasonLength, fc.Alpha, fc.Beta, fc.Gamma)
+	}
+
+	outputs := []ForecastOutput{fc.Output}
+	if fc.Output == ForecastOutputAll {
+		outputs = []ForecastOutput{ForecastOutputPredicted, ForecastOutputLower, ForecastOutputUpper}
+	}
+	result := make([]mathexp.Value, 0, len(outputs))
+	for _, output := range outputs {
+		labels := s.GetLabels().Copy()
+		if fc.Output == ForecastOutputAll {
+			if labels == nil {
+				labels = data.Labels{}
+			}
+			labels[forecastLabel] = string(output)
+		}
+		newSeries := mathexp.NewSeries(fc.refID, labels, 0)
+		if err != nil {
+			newSeries.AddNotice(data.Notice{
+				Severity: data.NoticeSeverityWarning,
+				Text:     fmt.Sprintf("Series %s could not be forecast: %s", s.GetLabels(), err),
+			})
+			result = append(result, newSeries)
+			continue
+		}
+		band := 0.0
+		switch output {
+		case ForecastOutputLower:
+			band = -fc.Confidence * model.residualStdDev()
+		case ForecastOutputUpper:
+			band = fc.Confidence * model.residualStdDev()
+		}
+		last := points[len(points)-1].t
+		for k := 1; time.Duration(k)*interval <= fc.Horizon; k++ {
+			t := last.Add(time.Duration(k) * interval)
+			f := model.predict(t) + band
+			newSeries.AppendPoint(t, &f)
+		}
+		result = append(result, newSeries)
+	}
+	return result
+}
+
+// medianInterval returns the median duration between consecutive points, ignoring duplicates.
+// For an even number of intervals the lower one is used, so gaps left by nulls do not widen it.
+func medianInterval(points []forecastPoint) time.Duration {
+	deltas := make([]time.Duration, 0, len(points))
+	for i := 1; i < len(points); i++ {
+		if d := points[i].t.Sub(points[i-1].t); d > 0 {
+			deltas = append(deltas, d)
+		}
+	}
+	if len(deltas) == 0 {
+		return 0
+	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i] < deltas[j] })
+	return deltas[(len(deltas)-1)/2]
+}
+
+// linearTrend is a least-squares fit of value = intercept + slope * seconds since start.
+type linearTrend struct {
+	start     time.Time
+	intercept float64
+	slope     float64
+	stdDev    float64
+}
+
+func fitLinearTrend(points []forecastPoint) (*linearTrend, error) {
+	if len(points) < 2 {
+		return nil, errors.New("at least two points are required")
+	}
+	m := &linearTrend{start: points[0].t}
+	n := float64(len(points))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		x := p.t.Sub(m.start).Seconds()
+		sumX += x
+		sumY += p.v
+		sumXY += x * p.v
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return nil, errors.New("at least two points with distinct timestamps are required")
+	}
+	m.slope = (n*sumXY - sumX*sumY) / denominator
+	m.intercept = (sumY - m.slope*sumX) / n
+
+	var sumSq float64
+	for _, p := range points {
+		r := p.v - m.at(p.t)
+		sumSq += r * r
+	}
+	m.stdDev = math.Sqrt(sumSq / n)
+	return m, nil
+}
+
+func (m *linearTrend) at(t time.Time) float64 {
+	return m.intercept + m.slope*t.Sub(m.start).Seconds()
+}
+
+func (m *linearTrend) predict(t time.Time) float64 {
+	return m.at(t)
+}
+
+func (m *linearTrend) residualStdDev() float64 {
+	return m.stdDev
+}
+
+// holtWinters is an additive Holt-Winters model. The points are assumed to be evenly spaced by step,
+// and the trend and the seasons are in steps of the series, whatever the interval of the forecast.
+// If seasonLength is 0 there is no seasonal component (Holt's linear trend method).
+type holtWinters struct {
+	level    float64
+	trend    float64
+	seasonal []float64
+	// next is the index in seasonal of the first predicted point.
+	next   int
+	last   time.Time
+	step   time.Duration
+	stdDev float64
+}
+
+func fitHoltWinters(points []forecastPoint, step time.Duration, seasonLength int, alpha, beta, gamma float64) (*holtWinters, error) {
+	if step <= 0 {
+		return nil, errors.New("at least two points with distinct timestamps are required")
+	}
+	y := make([]float64, len(points))
+	for i, p := range points {
+		y[i] = p.v
+	}
+
+	m := &holtWinters{step: step}
+	start := 1
+	if seasonLength > 0 {
+		if len(y) < 2*seasonLength {
+			return nil, fmt.Errorf("at least two seasons (%d points) are required", 2*seasonLength)
+		}
+		first, second := mean(y[:seasonLength]), mean(y[seasonLength:2*seasonLength])
+		m.level = first
+		m.trend = (second - first) / float64(seasonLength)
+		m.seasonal = make([]float64, seasonLength)
+		for i := range m.seasonal {
+			m.seasonal[i] = y[i] - first
+		}
+		start = seasonLength
+	} else {
+		if len(y) < 2 {
+			return nil, errors.New("at least two points are required")
+		}
+		m.level = y[0]
+		m.trend = y[1] - y[0]
+	}
+
+	var sumSq float64
+	for t := start; t < len(y); t++ {
+		s := m.season(t)
+		r := y[t] - (m.level + m.trend + s)
+		sumSq += r * r
+
+		level := alpha*(y[t]-s) + (1-alpha)*(m.level+m.trend)
+		m.trend = beta*(level-m.level) + (1-beta)*m.trend
+		m.level = level
+		if seasonLength > 0 {
+			m.seasonal[t%seasonLength] = gamma*(y[t]-level) + (1-gamma)*s
+		}
+	}
+	m.next = len(y)
+	m.last = points[len(points)-1].t
+	if n := len(y) - start; n > 0 {
+		m.stdDev = math.Sqrt(sumSq / float64(n))
+	}
+	return m, nil
+}
+
+func (m *holtWinters) season(t int) float64 {
+	if len(m.seasonal) == 0 {
+		return 0
+	}
+	return m.seasonal[t%len(m.seasonal)]
+}
+
+// predict returns the value h = (t - last point) / step steps after the last point. The season of a
+// fractional step is the season of the nearest step.
+func (m *holtWinters) predict(t time.Time) float64 {
+	h := float64(t.Sub(m.last)) / float64(m.step)
+	return m.level + h*m.trend + m.season(m.next+int(math.Round(h))-1)
+}
+
+func (m *holtWinters) residualStdDev() float64 {
+	return m.stdDev
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,260 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestUnmarshalForecastCommand(t *testing.T) {
+	var tests = []struct {
+		name     string
+		query    string
+		isError  bool
+		expected *ForecastCommand
+	}{
+		{
+			name:  "defaults are applied",
+			query: `{ "expression": "$A", "model": "holt_winters", "horizon": "1h" }`,
+			expected: &ForecastCommand{
+				VarToForecast: "A",
+				Model:         ForecastModelHoltWinters,
+				Horizon:       time.Hour,
+				Output:        ForecastOutputPredicted,
+				Confidence:    2,
+				Alpha:         0.5,
+				Beta:          0.1,
+				Gamma:         0.1,
+				refID:         "B",
+			},
+		},
+		{
+			name:  "all fields are read",
+			query: `{ "expression": "$A", "model": "linear", "horizon": "1d", "interval": "1h", "output": "all", "confidence": 3, "seasonLength": 24, "alpha": 0.2, "beta": 0.3, "gamma": 0 }`,
+			expected: &ForecastCommand{
+				VarToForecast: "A",
+				Model:         ForecastModelLinear,
+				Horizon:       24 * time.Hour,
+				Interval:      time.Hour,
+				Output:        ForecastOutputAll,
+				Confidence:    3,
+				SeasonLength:  24,
+				Alpha:         0.2,
+				Beta:          0.3,
+				Gamma:         0,
+				refID:         "B",
+			},
+		},
+		{
+			name:    "error when expression is empty",
+			query:   `{ "expression": "", "model": "linear", "horizon": "1h" }`,
+			isError: true,
+		},
+		{
+			name:    "error when model is not known",
+			query:   `{ "expression": "$A", "model": "arima", "horizon": "1h" }`,
+			isError: true,
+		},
+		{
+			name:    "error when output is not known",
+			query:   `{ "expression": "$A", "model": "linear", "horizon": "1h", "output": "median" }`,
+			isError: true,
+		},
+		{
+			name:    "error when horizon is missing",
+			query:   `{ "expression": "$A", "model": "linear" }`,
+			isError: true,
+		},
+		{
+			name:    "error when interval results in too many points",
+			query:   `{ "expression": "$A", "model": "linear", "horizon": "1y", "interval": "1s" }`,
+			isError: true,
+		},
+		{
+			name:    "error when smoothing factor is out of range",
+			query:   `{ "expression": "$A", "model": "holt_winters", "horizon": "1h", "alpha": 1.5 }`,
+			isError: true,
+		},
+		{
+			name:    "error when season length is negative",
+			query:   `{ "expression": "$A", "model": "holt_winters", "horizon": "1h", "seasonLength": -1 }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd, err := UnmarshalForecastCommand(&rawNode{
+				RefID:    "B",
+				QueryRaw: []byte(test.query),
+			})
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, cmd)
+		})
+	}
+}
+
+func TestForecastExecute(t *testing.T) {
+	start := time.Unix(0, 0)
+	newSeries := func(labels data.Labels, values ...*float64) mathexp.Series {
+		s := mathexp.NewSeries("A", labels, len(values))
+		for i, v := range values {
+			s.SetPoint(i, start.Add(time.Duration(i)*time.Minute), v)
+		}
+		return s
+	}
+	points := func(s mathexp.Series) []*float64 {
+		result := make([]*float64, 0, s.Len())
+		for i := 0; i < s.Len(); i++ {
+			_, f := s.GetPoint(i)
+			result = append(result, f)
+		}
+		return result
+	}
+	execute := func(t *testing.T, q ForecastQuery, values ...mathexp.Value) mathexp.Results {
+		t.Helper()
+		q.Expression = "$A"
+		cmd, err := NewForecastCommand("B", q)
+		require.NoError(t, err)
+		vars := mathexp.Vars{"A": mathexp.Results{Values: values}}
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("linear trend extends the series and skips nulls", func(t *testing.T) {
+		input := newSeries(data.Labels{"host": "a"}, util.Pointer(1.0), nil, util.Pointer(3.0), util.Pointer(4.0))
+		res := execute(t, ForecastQuery{Model: ForecastModelLinear, Horizon: "2m"}, input)
+
+		require.Len(t, res.Values, 1)
+		s := res.Values[0].(mathexp.Series)
+		require.Equal(t, data.Labels{"host": "a"}, s.GetLabels())
+		require.Equal(t, 2, s.Len())
+		ts, f := s.GetPoint(0)
+		require.Equal(t, start.Add(4*time.Minute), ts)
+		require.InDelta(t, 5, *f, 1e-9)
+		ts, f = s.GetPoint(1)
+		require.Equal(t, start.Add(5*time.Minute), ts)
+		require.InDelta(t, 6, *f, 1e-9)
+	})
+
+	t.Run("output all returns labeled confidence band", func(t *testing.T) {
+		input := newSeries(nil, util.Pointer(0.0), util.Pointer(2.0), util.Pointer(0.0), util.Pointer(2.0))
+		res := execute(t, ForecastQuery{Model: ForecastModelLinear, Horizon: "1m", Output: ForecastOutputAll, Confidence: util.Pointer(1.0)}, input)
+
+		require.Len(t, res.Values, 3)
+		byOutput := map[string]float64{}
+		for _, v := range res.Values {
+			s := v.(mathexp.Series)
+			require.Equal(t, 1, s.Len())
+			byOutput[s.GetLabels()[forecastLabel]] = *points(s)[0]
+		}
+		// the fit is 0.4 + 0.4/min with residuals of ±0.4 and ±1.2, so the stddev is sqrt(0.8)
+		require.InDelta(t, 2, byOutput["predicted"], 1e-9)
+		require.InDelta(t, 2-0.894427191, byOutput["lower"], 1e-9)
+		require.InDelta(t, 2+0.894427191, byOutput["upper"], 1e-9)
+	})
+
+	t.Run("holt winters repeats the season", func(t *testing.T) {
+		values := make([]*float64, 0, 12)
+		for i := 0; i < 12; i++ {
+			values = append(values, util.Pointer(float64(10+(i%3)*5)))
+		}
+		res := execute(t, ForecastQuery{Model: ForecastModelHoltWinters, Horizon: "3m", SeasonLength: 3}, newSeries(nil, values...))
+
+		require.Len(t, res.Values, 1)
+		got := points(res.Values[0].(mathexp.Series))
+		require.Len(t, got, 3)
+		for i, expected := range []float64{10, 15, 20} {
+			require.InDelta(t, expected, *got[i], 1e-9)
+		}
+	})
+
+	t.Run("holt winters places the forecast at the interval", func(t *testing.T) {
+		values := make([]*float64, 0, 12)
+		for i := 0; i < 12; i++ {
+			values = append(values, util.Pointer(float64(10+(i%3)*5)))
+		}
+		res := execute(t, ForecastQuery{Model: ForecastModelHoltWinters, Horizon: "6m", Interval: "3m", SeasonLength: 3}, newSeries(nil, values...))
+
+		s := res.Values[0].(mathexp.Series)
+		require.Equal(t, 2, s.Len())
+		ts, f := s.GetPoint(0)
+		require.Equal(t, start.Add(14*time.Minute), ts)
+		require.InDelta(t, 20, *f, 1e-9)
+		ts, f = s.GetPoint(1)
+		require.Equal(t, start.Add(17*time.Minute), ts)
+		require.InDelta(t, 20, *f, 1e-9)
+
+		trend := newSeries(nil, util.Pointer(0.0), util.Pointer(1.0), util.Pointer(2.0), util.Pointer(3.0))
+		res = execute(t, ForecastQuery{Model: ForecastModelHoltWinters, Horizon: "4m", Interval: "2m"}, trend)
+		got := points(res.Values[0].(mathexp.Series))
+		require.Len(t, got, 2)
+		require.InDelta(t, 5, *got[0], 1e-9)
+		require.InDelta(t, 7, *got[1], 1e-9)
+	})
+
+	t.Run("holt winters keeps the last value of repeated timestamps", func(t *testing.T) {
+		input := mathexp.NewSeries("A", nil, 24)
+		for i := 0; i < 12; i++ {
+			ts := start.Add(time.Duration(i) * time.Minute)
+			input.SetPoint(2*i, ts, util.Pointer(-1.0))
+			input.SetPoint(2*i+1, ts, util.Pointer(float64(10+(i%3)*5)))
+		}
+		res := execute(t, ForecastQuery{Model: ForecastModelHoltWinters, Horizon: "3m", SeasonLength: 3}, input)
+
+		got := points(res.Values[0].(mathexp.Series))
+		require.Len(t, got, 3)
+		for i, expected := range []float64{10, 15, 20} {
+			require.InDelta(t, expected, *got[i], 1e-9)
+		}
+	})
+
+	t.Run("holt winters with a single timestamp returns an empty series with a notice", func(t *testing.T) {
+		input := mathexp.NewSeries("A", nil, 6)
+		for i := 0; i < 6; i++ {
+			input.SetPoint(i, start, util.Pointer(float64(i)))
+		}
+		res := execute(t, ForecastQuery{Model: ForecastModelHoltWinters, Horizon: "3m", Interval: "1m", SeasonLength: 3}, input)
+
+		s := res.Values[0].(mathexp.Series)
+		require.Equal(t, 0, s.Len())
+		require.Len(t, s.Frame.Meta.Notices, 1)
+		require.Contains(t, s.Frame.Meta.Notices[0].Text, "distinct timestamps")
+	})
+
+	t.Run("not enough points returns an empty series with a notice", func(t *testing.T) {
+		res := execute(t, ForecastQuery{Model: ForecastModelHoltWinters, Horizon: "3m", SeasonLength: 3}, newSeries(nil, util.Pointer(1.0), util.Pointer(2.0)))
+
+		require.Len(t, res.Values, 1)
+		s := res.Values[0].(mathexp.Series)
+		require.Equal(t, 0, s.Len())
+		require.Len(t, s.Frame.Meta.Notices, 1)
+		require.Equal(t, data.NoticeSeverityWarning, s.Frame.Meta.Notices[0].Severity)
+	})
+
+	t.Run("no data is passed through", func(t *testing.T) {
+		res := execute(t, ForecastQuery{Model: ForecastModelLinear, Horizon: "1m"}, mathexp.NoData{}.New())
+		require.Len(t, res.Values, 1)
+		require.IsType(t, mathexp.NoData{}, res.Values[0])
+	})
+
+	t.Run("error when input is a number", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", ForecastQuery{Expression: "$A", Model: ForecastModelLinear, Horizon: "1m"})
+		require.NoError(t, err)
+		vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNumber("A", nil)}}}
+		_, err = cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.Error(t, err)
+	})
+}
@@ -65,8 +65,8 @@ type ExecutableNode interface {
 type DataPipeline []Node
 
 // execute runs all the command/datasource requests in the pipeline return a
-// map of the refId of the of each command
-func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (mathexp.Vars, error) {
+// map of the refId of the of each command. If stats is not nil, the execution stats of each node are recorded in it.
+func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service, stats *executionStats) (mathexp.Vars, error) {
 	vars := make(mathexp.Vars)
 	//nolint:staticcheck // not yet migrated to OpenFeature
 	groupByDSFlag := s.features.IsEnabled(c, featuremgmt.FlagSseGroupByDatasource)
@@ -80,7 +80,7 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			dsNodes = append(dsNodes, node.(*DSNode))
 		}
 
-		executeDSNodesGrouped(c, now, vars, s, dsNodes)
+		executeDSNodesGrouped(c, now, vars, s, dsNodes, stats)
 	}
 
 	for _, node := range *dp {
@@ -115,6 +115,7 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 						Error: depErr,
 					}
 					vars[node.RefID()] = errResult
+					stats.skip(node.RefID(), depErr)
 					hasDepError = true
 					break
 				}
@@ -137,10 +138,12 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			return vars, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
 		}
 
+		start := time.Now()
 		res, err := execNode.Execute(c, now, vars, s)
 		if err != nil {
 			res.Error = err
 		}
+		stats.record(node.RefID(), time.Since(start), res)
 
 		vars[node.RefID()] = res
 	}
@@ -30,7 +30,7 @@ type HysteresisCommand struct {
 }
 
 func (h *HysteresisCommand) NeedsVars() []string {
-	return []string{h.ReferenceVar}
+	return baselineVars([]string{h.ReferenceVar}, &h.LoadingThresholdFunc, &h.UnloadingThresholdFunc)
 }
 
 func (h *HysteresisCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer, metrics *metrics.ExprMetrics) (mathexp.Results, error) {
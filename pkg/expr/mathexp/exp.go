@@ -214,28 +214,8 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 	aMatched := make([]bool, len(aResults.Values))
 	bMatched := make([]bool, len(bResults.Values))
 	collectDrops := func() {
-		check := func(v string, matchArray []bool, r *Results) {
-			for i, b := range matchArray {
-				if b {
-					continue
-				}
-				if e.Drops == nil {
-					e.Drops = make(map[string]map[string][]data.Labels)
-				}
-				if e.Drops[biNode.String()] == nil {
-					e.Drops[biNode.String()] = make(map[string][]data.Labels)
-				}
-
-				if r.Values[i].Type() == parse.TypeNoData {
-					continue
-				}
-
-				e.DropCount++
-				e.Drops[biNode.String()][v] = append(e.Drops[biNode.String()][v], r.Values[i].GetLabels())
-			}
-		}
-		check(aVar, aMatched, &aResults)
-		check(bVar, bMatched, &bResults)
+		e.collectDrops(biNode, aVar, aMatched, aResults)
+		e.collectDrops(biNode, bVar, bMatched, bResults)
 	}
 
 	aValueLen := len(aResults.Values)
@@ -306,6 +286,29 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 	return unions
 }
 
+// collectDrops records the items of r that were not matched in a binary operation,
+// so they can be reported as a notice.
+func (e *State) collectDrops(biNode *parse.BinaryNode, v string, matchArray []bool, r Results) {
+	for i, b := range matchArray {
+		if b {
+			continue
+		}
+		if e.Drops == nil {
+			e.Drops = make(map[string]map[string][]data.Labels)
+		}
+		if e.Drops[biNode.String()] == nil {
+			e.Drops[biNode.String()] = make(map[string][]data.Labels)
+		}
+
+		if r.Values[i].Type() == parse.TypeNoData {
+			continue
+		}
+
+		e.DropCount++
+		e.Drops[biNode.String()][v] = append(e.Drops[biNode.String()][v], r.Values[i].GetLabels())
+	}
+}
+
 func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	res := Results{Values: Values{}}
 	ar, err := e.walk(node.Args[0])
@@ -316,7 +319,15 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	if err != nil {
 		return res, err
 	}
-	unions := e.union(ar, br, node)
+	var unions []*Union
+	if usesLabelMatching(ar, br, node) {
+		unions, err = e.unionMatching(ar, br, node)
+		if err != nil {
+			return res, err
+		}
+	} else {
+		unions = e.union(ar, br, node)
+	}
 	for _, uni := range unions {
 		var value Value
 		switch at := uni.A.(type) {
@@ -361,7 +372,11 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, at, bFloat, true)
 			// case Series op Series
 			case Series:
-				value, err = e.biSeriesSeries(uni.Labels, node.OpStr, at, bt)
+				if node.Matching != nil && node.Matching.Alignment == parse.AlignOuter {
+					value, err = e.biSeriesSeriesOuter(uni.Labels, node.OpStr, at, bt)
+				} else {
+					value, err = e.biSeriesSeries(uni.Labels, node.OpStr, at, bt)
+				}
 			case NoData:
 				value = uni.B
 			default:
@@ -0,0 +1,229 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// seriesPoint is a single time/value pair of a Series.
+type seriesPoint struct {
+	t time.Time
+	f *float64
+}
+
+// sortedPoints returns the points of the series ordered from oldest to newest
+// without mutating the series itself, since the input may be shared with other nodes.
+func sortedPoints(s Series) []seriesPoint {
+	points := make([]seriesPoint, s.Len())
+	for i := range points {
+		points[i].t, points[i].f = s.GetPoint(i)
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].t.Before(points[j].t)
+	})
+	return points
+}
+
+// perSeries calls seriesF for each Series in varSet. NoData values are passed through,
+// any other type results in an error since these functions need the time dimension.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newRes.Values = append(newRes.Values, seriesF(v))
+		case NoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: expected %v, got %v", name, parse.TypeSeriesSet, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// perPointPair builds a new series where each point is the result of pairF applied to a point
+// and its predecessor. The first point has no predecessor, so the resulting series is one point shorter.
+// If either value is null, the resulting point is null.
+func perPointPair(e *State, s Series, pairF func(prev, cur float64, elapsed time.Duration) float64) Series {
+	points := sortedPoints(s)
+	size := len(points) - 1
+	if size < 0 {
+		size = 0
+	}
+	newSeries := NewSeries(e.RefID, s.GetLabels(), size)
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+		if prev.f == nil || cur.f == nil {
+			newSeries.SetPoint(i-1, cur.t, nil)
+			continue
+		}
+		nF := pairF(*prev.f, *cur.f, cur.t.Sub(prev.t))
+		newSeries.SetPoint(i-1, cur.t, &nF)
+	}
+	return newSeries
+}
+
+// perSecond divides v by the elapsed time in seconds. Points that share a timestamp result in NaN.
+func perSecond(v float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return math.NaN()
+	}
+	return v / elapsed.Seconds()
+}
+
+// counterIncrease returns the increase between two samples of a monotonic counter.
+// A decrease is treated as a counter reset, in which case the current value is the increase.
+func counterIncrease(prev, cur float64) float64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+// delta returns the difference between each point and the previous point of each series.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) Series {
+		return perPointPair(e, s, func(prev, cur float64, _ time.Duration) float64 {
+			return cur - prev
+		})
+	})
+}
+
+// increase returns the increase between each point and the previous point of each series,
+// treating the series as a counter. Decreasing values are considered counter resets.
+func increase(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "increase", varSet, func(s Series) Series {
+		return perPointPair(e, s, func(prev, cur float64, _ time.Duration) float64 {
+			return counterIncrease(prev, cur)
+		})
+	})
+}
+
+// rate returns the per-second increase between each point and the previous point of each series,
+// treating the series as a counter. Decreasing values are considered counter resets.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) Series {
+		return perPointPair(e, s, func(prev, cur float64, elapsed time.Duration) float64 {
+			return perSecond(counterIncrease(prev, cur), elapsed)
+		})
+	})
+}
+
+// deriv returns the per-second change between each point and the previous point of each series,
+// treating the series as a gauge.
+func deriv(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "deriv", varSet, func(s Series) Series {
+		return perPointPair(e, s, func(prev, cur float64, elapsed time.Duration) float64 {
+			return perSecond(cur-prev, elapsed)
+		})
+	})
+}
+
+// cumsum returns the running total of each series. Null points are skipped
+// from the total and remain null in the result.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) Series {
+		points := sortedPoints(s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), len(points))
+		sum := float64(0)
+		for i, p := range points {
+			if p.f == nil {
+				newSeries.SetPoint(i, p.t, nil)
+				continue
+			}
+			sum += *p.f
+			nF := sum
+			newSeries.SetPoint(i, p.t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// movingAvg returns, for each point of each series, the average of the non-null values
+// within the window that ends at (and includes) the point. If there are no non-null values
+// in the window the resulting point is null.
+func movingAvg(e *State, varSet Results, rawWindow string) (Results, error) {
+	window, err := parseSeriesFuncDuration(rawWindow)
+	if err != nil {
+		return Results{}, err
+	}
+	if window <= 0 {
+		return Results{}, fmt.Errorf("moving_avg: window must be greater than zero, got %q", rawWindow)
+	}
+	return perSeries(e, "moving_avg", varSet, func(s Series) Series {
+		points := sortedPoints(s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), len(points))
+		start := 0
+		for i, p := range points {
+			for !points[start].t.After(p.t.Add(-window)) {
+				start++
+			}
+			sum, count := float64(0), 0
+			for _, wp := range points[start : i+1] {
+				if wp.f == nil {
+					continue
+				}
+				sum += *wp.f
+				count++
+			}
+			if count == 0 {
+				newSeries.SetPoint(i, p.t, nil)
+				continue
+			}
+			nF := sum / float64(count)
+			newSeries.SetPoint(i, p.t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// shift moves each point of each series forward in time by the duration.
+// A negative duration moves the points backward.
+func shift(e *State, varSet Results, rawDuration string) (Results, error) {
+	d, err := parseSeriesFuncDuration(rawDuration)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "shift", varSet, func(s Series) Series {
+		points := sortedPoints(s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), len(points))
+		for i, p := range points {
+			newSeries.SetPoint(i, p.t.Add(d), p.f)
+		}
+		return newSeries
+	})
+}
+
+// parseSeriesFuncDuration parses a duration argument such as "5m" or "1d".
+func parseSeriesFuncDuration(raw string) (time.Duration, error) {
+	d, err := gtime.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse duration %q: %w", raw, err)
+	}
+	return d, nil
+}
+
+// checkDurationArg returns a parse time check that validates that the string argument
+// at argIdx is a valid duration. If positive is true the duration must be greater than zero.
+func checkDurationArg(argIdx int, positive bool) func(*parse.Tree, *parse.FuncNode) error {
+	return func(_ *parse.Tree, f *parse.FuncNode) error {
+		arg, ok := f.Args[argIdx].(*parse.StringNode)
+		if !ok {
+			return fmt.Errorf("parse: expected a duration string for argument %v of %s", argIdx, f.Name)
+		}
+		d, err := parseSeriesFuncDuration(arg.Text)
+		if err != nil {
+			return fmt.Errorf("parse: %s: %w", f.Name, err)
+		}
+		if positive && d <= 0 {
+			return fmt.Errorf("parse: %s: duration must be greater than zero, got %q", f.Name, arg.Text)
+		}
+		return nil
+	}
+}
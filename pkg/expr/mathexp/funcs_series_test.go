@@ -0,0 +1,217 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestSeriesFuncs(t *testing.T) {
+	counter := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(20, 0), float64Pointer(30)},
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(10, 0), float64Pointer(20)},
+				tp{time.Unix(30, 0), float64Pointer(10)},
+				tp{time.Unix(40, 0), nil},
+				tp{time.Unix(50, 0), float64Pointer(20)},
+			),
+		),
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "delta on series",
+			expr:      "delta($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), float64Pointer(10)},
+					tp{time.Unix(30, 0), float64Pointer(-20)},
+					tp{time.Unix(40, 0), nil},
+					tp{time.Unix(50, 0), nil},
+				),
+			),
+		},
+		{
+			name:      "increase handles counter resets",
+			expr:      "increase($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), float64Pointer(10)},
+					tp{time.Unix(30, 0), float64Pointer(10)},
+					tp{time.Unix(40, 0), nil},
+					tp{time.Unix(50, 0), nil},
+				),
+			),
+		},
+		{
+			name:      "rate is per second increase",
+			expr:      "rate($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), float64Pointer(1)},
+					tp{time.Unix(30, 0), float64Pointer(1)},
+					tp{time.Unix(40, 0), nil},
+					tp{time.Unix(50, 0), nil},
+				),
+			),
+		},
+		{
+			name:      "deriv is per second delta",
+			expr:      "deriv($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), float64Pointer(1)},
+					tp{time.Unix(30, 0), float64Pointer(-2)},
+					tp{time.Unix(40, 0), nil},
+					tp{time.Unix(50, 0), nil},
+				),
+			),
+		},
+		{
+			name:      "cumsum skips nulls",
+			expr:      "cumsum($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(0)},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), float64Pointer(50)},
+					tp{time.Unix(30, 0), float64Pointer(60)},
+					tp{time.Unix(40, 0), nil},
+					tp{time.Unix(50, 0), float64Pointer(80)},
+				),
+			),
+		},
+		{
+			name:      "moving_avg over time window",
+			expr:      `moving_avg($A, "20s")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(0)},
+					tp{time.Unix(10, 0), float64Pointer(10)},
+					tp{time.Unix(20, 0), float64Pointer(25)},
+					tp{time.Unix(30, 0), float64Pointer(20)},
+					tp{time.Unix(40, 0), float64Pointer(10)},
+					tp{time.Unix(50, 0), float64Pointer(20)},
+				),
+			),
+		},
+		{
+			name:      "shift moves points in time",
+			expr:      `shift($A, "-10s")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(-10, 0), float64Pointer(0)},
+					tp{time.Unix(0, 0), float64Pointer(20)},
+					tp{time.Unix(10, 0), float64Pointer(30)},
+					tp{time.Unix(20, 0), float64Pointer(10)},
+					tp{time.Unix(30, 0), nil},
+					tp{time.Unix(40, 0), float64Pointer(20)},
+				),
+			),
+		},
+		{
+			name:      "rate on no data",
+			expr:      "rate($A)",
+			vars:      Vars{"A": resultValuesNoErr(NewNoData())},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(NewNoData()),
+		},
+		{
+			name:      "rate on number - should error",
+			expr:      "rate($A)",
+			vars:      Vars{"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(1)))},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "rate on scalar - should error",
+			expr:     "rate(1)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "moving_avg with invalid window - should error",
+			expr:     `moving_avg($A, "soon")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "moving_avg with negative window - should error",
+			expr:     `moving_avg($A, "-5m")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "shift without duration - should error",
+			expr:     `shift($A)`,
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+				tt.execErrIs(t, err)
+				if err == nil {
+					require.Equal(t, tt.results, res)
+				}
+			}
+		})
+	}
+}
+
+func TestRateSameTimestamp(t *testing.T) {
+	e, err := New("rate($A)")
+	require.NoError(t, err)
+	res, err := e.Execute("", Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", nil,
+				tp{time.Unix(10, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(2)},
+			),
+		),
+	}, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	require.Len(t, res.Values, 1)
+	_, f := res.Values[0].(Series).GetPoint(0)
+	require.True(t, math.IsNaN(*f))
+}
@@ -0,0 +1,96 @@
+package mathexp
+
+import (
+	"math"
+	"time"
+)
+
+// interpolateLinear returns the value at t on the straight line between the points (t0, v0) and (t1, v1).
+// If v1 is nil, or t is not between t0 and t1, nil is returned.
+func interpolateLinear(t0 time.Time, v0 float64, t1 time.Time, v1 *float64, t time.Time) *float64 {
+	if v1 == nil || t.Before(t0) || t.After(t1) {
+		return nil
+	}
+	span := t1.Sub(t0)
+	if span <= 0 {
+		return &v0
+	}
+	f := v0 + (*v1-v0)*float64(t.Sub(t0))/float64(span)
+	return &f
+}
+
+// naturalSpline is a natural cubic spline through the non-null points of a series.
+// The spline is only defined between the first and the last of those points.
+type naturalSpline struct {
+	start time.Time
+	x     []float64 // seconds since start
+	y     []float64
+	m     []float64 // second derivatives at each x
+}
+
+// newNaturalSpline fits a natural cubic spline through the points of the series, which must be sorted by time.
+// Null and NaN values are skipped. If several points share a timestamp, the last one is used.
+func newNaturalSpline(s Series) *naturalSpline {
+	sp := &naturalSpline{}
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		if v == nil || math.IsNaN(*v) {
+			continue
+		}
+		if len(sp.x) == 0 {
+			sp.start = t
+		}
+		x := t.Sub(sp.start).Seconds()
+		if n := len(sp.x); n > 0 && x <= sp.x[n-1] {
+			sp.y[n-1] = *v
+			continue
+		}
+		sp.x = append(sp.x, x)
+		sp.y = append(sp.y, *v)
+	}
+
+	n := len(sp.x)
+	sp.m = make([]float64, n)
+	if n < 3 {
+		return sp
+	}
+
+	// Solve the tridiagonal system for the second derivatives with the
+	// natural boundary conditions m[0] = m[n-1] = 0 (Thomas algorithm).
+	c := make([]float64, n)
+	d := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		h0 := sp.x[i] - sp.x[i-1]
+		h1 := sp.x[i+1] - sp.x[i]
+		rhs := 6 * ((sp.y[i+1]-sp.y[i])/h1 - (sp.y[i]-sp.y[i-1])/h0)
+		diag := 2*(h0+h1) - h0*c[i-1]
+		c[i] = h1 / diag
+		d[i] = (rhs - h0*d[i-1]) / diag
+	}
+	for i := n - 2; i > 0; i-- {
+		sp.m[i] = d[i] - c[i]*sp.m[i+1]
+	}
+	return sp
+}
+
+// at returns the value of the spline at t, or nil if t is outside the fitted points.
+func (sp *naturalSpline) at(t time.Time) *float64 {
+	n := len(sp.x)
+	if n < 2 {
+		return nil
+	}
+	x := t.Sub(sp.start).Seconds()
+	if x < sp.x[0] || x > sp.x[n-1] {
+		return nil
+	}
+	i := 0
+	for i < n-2 && x > sp.x[i+1] {
+		i++
+	}
+	h := sp.x[i+1] - sp.x[i]
+	a := sp.x[i+1] - x
+	b := x - sp.x[i]
+	f := sp.m[i]*a*a*a/(6*h) + sp.m[i+1]*b*b*b/(6*h) +
+		(sp.y[i]/h-sp.m[i]*h/6)*a + (sp.y[i+1]/h-sp.m[i+1]*h/6)*b
+	return &f
+}
@@ -0,0 +1,174 @@
+package mathexp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// usesLabelMatching returns true if the items of the binary operation should be paired
+// with the on/ignoring modifiers rather than by the default union logic. Scalars and
+// NoData have no labels to match on, so they always use the default union.
+func usesLabelMatching(aResults, bResults Results, biNode *parse.BinaryNode) bool {
+	if biNode.Matching == nil || !biNode.Matching.LabelsSet {
+		return false
+	}
+	for _, r := range []Results{aResults, bResults} {
+		for _, v := range r.Values {
+			if v == nil {
+				continue
+			}
+			if t := v.Type(); t == parse.TypeScalar || t == parse.TypeNoData {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// unionMatching creates Union objects by pairing the items of both sides that have the same
+// labels, where only the labels listed in on(...) are compared, or all labels except those listed
+// in ignoring(...). By default, each item may only be paired once. With group_left
+// (or group_right) many items of the left (or right) side may be paired with the same item of
+// the other side.
+func (e *State) unionMatching(aResults, bResults Results, biNode *parse.BinaryNode) ([]*Union, error) {
+	m := biNode.Matching
+	unions := []*Union{}
+
+	aMatched := make([]bool, len(aResults.Values))
+	bMatched := make([]bool, len(bResults.Values))
+
+	// The "one" side is the side whose items may only be paired once per match group.
+	manyRes, oneRes := aResults, bResults
+	manyMatched, oneMatched := aMatched, bMatched
+	manySide, oneSide := "left", "right"
+	if m.Card == parse.CardOneToMany {
+		manyRes, oneRes = bResults, aResults
+		manyMatched, oneMatched = bMatched, aMatched
+		manySide, oneSide = "right", "left"
+	}
+
+	oneBySignature := make(map[data.Fingerprint]int, len(oneRes.Values))
+	for i, v := range oneRes.Values {
+		sig := matchSignature(v.GetLabels(), m)
+		if _, ok := oneBySignature[sig.Fingerprint()]; ok {
+			return nil, fmt.Errorf("found duplicate items for the match group %s on the %s side of %q, matching labels must be unique on one side", sig, oneSide, biNode)
+		}
+		oneBySignature[sig.Fingerprint()] = i
+	}
+
+	manySeen := make(map[data.Fingerprint]struct{}, len(manyRes.Values))
+	for iMany, many := range manyRes.Values {
+		sig := matchSignature(many.GetLabels(), m)
+		iOne, ok := oneBySignature[sig.Fingerprint()]
+		if !ok {
+			continue
+		}
+		if m.Card == parse.CardOneToOne {
+			if _, ok := manySeen[sig.Fingerprint()]; ok {
+				return nil, fmt.Errorf("found duplicate items for the match group %s on the %s side of %q, many-to-one matching must be explicit (group_left/group_right)", sig, manySide, biNode)
+			}
+			manySeen[sig.Fingerprint()] = struct{}{}
+		}
+
+		one := oneRes.Values[iOne]
+		u := &Union{
+			Labels: matchResultLabels(many.GetLabels(), one.GetLabels(), m),
+			A:      many,
+			B:      one,
+		}
+		if m.Card == parse.CardOneToMany {
+			u.A, u.B = one, many
+		}
+		unions = append(unions, u)
+		manyMatched[iMany] = true
+		oneMatched[iOne] = true
+	}
+
+	e.collectDrops(biNode, biNode.Args[0].String(), aMatched, aResults)
+	e.collectDrops(biNode, biNode.Args[1].String(), bMatched, bResults)
+	return unions, nil
+}
+
+// matchSignature returns the labels that are compared when pairing items.
+func matchSignature(labels data.Labels, m *parse.VectorMatching) data.Labels {
+	sig := data.Labels{}
+	if m.On {
+		for _, name := range m.MatchingLabels {
+			if v, ok := labels[name]; ok {
+				sig[name] = v
+			}
+		}
+		return sig
+	}
+	for name, v := range labels {
+		sig[name] = v
+	}
+	for _, name := range m.MatchingLabels {
+		delete(sig, name)
+	}
+	return sig
+}
+
+// matchResultLabels returns the labels of the result of a paired operation. For one-to-one matching
+// these are the matching labels. For group_left and group_right these are the labels of the "many" side,
+// plus the labels listed in the group modifier, copied from the "one" side.
+func matchResultLabels(manyLabels, oneLabels data.Labels, m *parse.VectorMatching) data.Labels {
+	if m.Card == parse.CardOneToOne {
+		return matchSignature(manyLabels, m)
+	}
+	labels := manyLabels.Copy()
+	if labels == nil {
+		labels = data.Labels{}
+	}
+	for _, name := range m.Include {
+		if v, ok := oneLabels[name]; ok {
+			labels[name] = v
+		} else {
+			delete(labels, name)
+		}
+	}
+	return labels
+}
+
+// biSeriesSeriesOuter performs the binary operation for each timestamp that exists in either series.
+// When a series has no point at a timestamp, its most recent earlier value is used. Before the first
+// point of a series, its value is null.
+func (e *State) biSeriesSeriesOuter(labels data.Labels, op string, aSeries, bSeries Series) (Series, error) {
+	aPoints := sortedPoints(aSeries)
+	bPoints := sortedPoints(bSeries)
+
+	newSeries := NewSeries(e.RefID, labels, 0)
+	var aF, bF *float64
+	aIdx, bIdx := 0, 0
+	for aIdx < len(aPoints) || bIdx < len(bPoints) {
+		var t time.Time
+		switch {
+		case bIdx == len(bPoints) || (aIdx < len(aPoints) && aPoints[aIdx].t.Before(bPoints[bIdx].t)):
+			t = aPoints[aIdx].t
+		default:
+			t = bPoints[bIdx].t
+		}
+		for aIdx < len(aPoints) && !aPoints[aIdx].t.After(t) {
+			aF = aPoints[aIdx].f
+			aIdx++
+		}
+		for bIdx < len(bPoints) && !bPoints[bIdx].t.After(t) {
+			bF = bPoints[bIdx].f
+			bIdx++
+		}
+		if aF == nil || bF == nil {
+			newSeries.AppendPoint(t, nil)
+			continue
+		}
+		nF, err := binaryOp(op, *aF, *bF)
+		if err != nil {
+			return newSeries, err
+		}
+		newSeries.AppendPoint(t, &nF)
+	}
+	return newSeries, nil
+}
@@ -0,0 +1,216 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestLabelMatching(t *testing.T) {
+	errorCounts := Results{
+		Values: Values{
+			makeNumber("", data.Labels{"host": "a", "ds": "sql", "code": "500"}, float64Pointer(2)),
+			makeNumber("", data.Labels{"host": "a", "ds": "sql", "code": "503"}, float64Pointer(4)),
+			makeNumber("", data.Labels{"host": "b", "ds": "sql", "code": "500"}, float64Pointer(1)),
+		},
+	}
+	requests := Results{
+		Values: Values{
+			makeNumber("", data.Labels{"host": "a", "ds": "es", "team": "x"}, float64Pointer(8)),
+			makeNumber("", data.Labels{"host": "b", "ds": "es", "team": "y"}, float64Pointer(10)),
+		},
+	}
+	totalErrors := Results{
+		Values: Values{
+			makeNumber("", data.Labels{"host": "a", "ds": "sql"}, float64Pointer(6)),
+			makeNumber("", data.Labels{"host": "b", "ds": "sql"}, float64Pointer(1)),
+			makeNumber("", data.Labels{"host": "c", "ds": "sql"}, float64Pointer(1)),
+		},
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "on pairs by the listed labels and keeps only those",
+			expr:      "$A / on(host) $B",
+			vars:      Vars{"A": totalErrors, "B": requests},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a"}, float64Pointer(0.75)),
+				makeNumber("", data.Labels{"host": "b"}, float64Pointer(0.1)),
+			),
+		},
+		{
+			name:      "ignoring pairs by all other labels",
+			expr:      `$A / ignoring(ds, "team") $B`,
+			vars:      Vars{"A": totalErrors, "B": requests},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a"}, float64Pointer(0.75)),
+				makeNumber("", data.Labels{"host": "b"}, float64Pointer(0.1)),
+			),
+		},
+		{
+			name:      "group_left keeps the labels of the many side and copies included labels",
+			expr:      "$A / on(host) group_left(team) $B",
+			vars:      Vars{"A": errorCounts, "B": requests},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a", "ds": "sql", "code": "500", "team": "x"}, float64Pointer(0.25)),
+				makeNumber("", data.Labels{"host": "a", "ds": "sql", "code": "503", "team": "x"}, float64Pointer(0.5)),
+				makeNumber("", data.Labels{"host": "b", "ds": "sql", "code": "500", "team": "y"}, float64Pointer(0.1)),
+			),
+		},
+		{
+			name:      "group_right keeps the operand order",
+			expr:      "$B - on(host) group_right $A",
+			vars:      Vars{"A": errorCounts, "B": requests},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a", "ds": "sql", "code": "500"}, float64Pointer(6)),
+				makeNumber("", data.Labels{"host": "a", "ds": "sql", "code": "503"}, float64Pointer(4)),
+				makeNumber("", data.Labels{"host": "b", "ds": "sql", "code": "500"}, float64Pointer(9)),
+			),
+		},
+		{
+			name:      "one-to-one matching with duplicates on the left side - should error",
+			expr:      "$A / on(host) $B",
+			vars:      Vars{"A": errorCounts, "B": requests},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:      "duplicates on the one side - should error",
+			expr:      "$B / on(host) group_left $A",
+			vars:      Vars{"A": errorCounts, "B": requests},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:      "scalar operands ignore the matching modifiers",
+			expr:      "$A * on(host) 2",
+			vars:      Vars{"A": totalErrors},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a", "ds": "sql"}, float64Pointer(12)),
+				makeNumber("", data.Labels{"host": "b", "ds": "sql"}, float64Pointer(2)),
+				makeNumber("", data.Labels{"host": "c", "ds": "sql"}, float64Pointer(2)),
+			),
+		},
+		{
+			name:     "label in both on and group_left - should error",
+			expr:     "$A / on(host) group_left(host) $B",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "missing label list - should error",
+			expr:     "$A / on $B",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+				tt.execErrIs(t, err)
+				if err == nil {
+					// dropped items are reported in a notice, which is not what these tests are about
+					for _, v := range res.Values {
+						v.(Number).Frame.Meta = nil
+					}
+					for _, v := range tt.results.Values {
+						v.(Number).Frame.Meta = nil
+					}
+					require.ElementsMatch(t, tt.results.Values, res.Values)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeAlignment(t *testing.T) {
+	vars := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(2)},
+				tp{time.Unix(10, 0), float64Pointer(4)},
+				tp{time.Unix(20, 0), float64Pointer(6)},
+			),
+		),
+		"B": resultValuesNoErr(
+			makeSeries("", nil,
+				tp{time.Unix(5, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(2)},
+			),
+		),
+	}
+
+	var tests = []struct {
+		name    string
+		expr    string
+		results Results
+	}{
+		{
+			name: "default alignment only keeps shared timestamps",
+			expr: "$A / $B",
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(2)},
+				),
+			),
+		},
+		{
+			name: "inner alignment only keeps shared timestamps",
+			expr: "$A / inner $B",
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(2)},
+				),
+			),
+		},
+		{
+			name: "outer alignment uses the most recent earlier value",
+			expr: "$A / outer $B",
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(5, 0), float64Pointer(2)},
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), float64Pointer(3)},
+				),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", vars, tracing.InitializeTracerForTest())
+			require.NoError(t, err)
+			require.Equal(t, tt.results, res)
+		})
+	}
+}
+
+func TestMatchingString(t *testing.T) {
+	e, err := New(`$A / on(host, "pod2") group_left(team) outer $B`)
+	require.NoError(t, err)
+	require.Equal(t, `$A / on(host, pod2) group_left(team) outer $B`, e.Root.String())
+}
@@ -0,0 +1,149 @@
+package parse
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MatchCardinality describes how many items on each side of a binary operation may be paired.
+type MatchCardinality int
+
+const (
+	// CardOneToOne pairs each item on one side with at most one item on the other side.
+	CardOneToOne MatchCardinality = iota
+	// CardManyToOne pairs many items on the left side with one item on the right side (group_left).
+	CardManyToOne
+	// CardOneToMany pairs one item on the left side with many items on the right side (group_right).
+	CardOneToMany
+)
+
+// TimeAlignment describes how the points of two series in a binary operation are lined up.
+type TimeAlignment string
+
+const (
+	// AlignInner only keeps timestamps that exist in both series. This is the default.
+	AlignInner TimeAlignment = "inner"
+	// AlignOuter keeps the timestamps of both series. The side that has no point at a timestamp
+	// uses its most recent earlier value.
+	AlignOuter TimeAlignment = "outer"
+)
+
+// Keywords of the binary operation modifiers.
+const (
+	keywordOn         = "on"
+	keywordIgnoring   = "ignoring"
+	keywordGroupLeft  = "group_left"
+	keywordGroupRight = "group_right"
+)
+
+// VectorMatching holds the modifiers of a binary operation that control how items
+// of the two sides are paired by their labels, and how their points are aligned in time.
+// For example in `$A / on(host) group_left(team) outer $B`.
+type VectorMatching struct {
+	// LabelsSet is true if on(...) or ignoring(...) was used.
+	LabelsSet bool
+	// On is true if MatchingLabels are the only labels used for matching (on),
+	// otherwise MatchingLabels are excluded from matching (ignoring).
+	On             bool
+	MatchingLabels []string
+	Card           MatchCardinality
+	// Include are the labels of the "one" side copied onto the result of group_left or group_right.
+	Include   []string
+	Alignment TimeAlignment
+}
+
+// String returns the modifiers in the form they are written in an expression.
+func (m *VectorMatching) String() string {
+	if m == nil {
+		return ""
+	}
+	parts := make([]string, 0, 3)
+	if m.LabelsSet {
+		kw := keywordIgnoring
+		if m.On {
+			kw = keywordOn
+		}
+		parts = append(parts, kw+"("+strings.Join(m.MatchingLabels, ", ")+")")
+		switch m.Card {
+		case CardManyToOne:
+			parts = append(parts, keywordGroupLeft+"("+strings.Join(m.Include, ", ")+")")
+		case CardOneToMany:
+			parts = append(parts, keywordGroupRight+"("+strings.Join(m.Include, ", ")+")")
+		}
+	}
+	if m.Alignment != "" {
+		parts = append(parts, string(m.Alignment))
+	}
+	return strings.Join(parts, " ")
+}
+
+// matching parses the optional modifiers that follow a binary operator:
+// [("on" | "ignoring") labels [("group_left" | "group_right") [labels]]] ["inner" | "outer"].
+// It returns nil if there are no modifiers.
+func (t *Tree) matching() *VectorMatching {
+	var m *VectorMatching
+	if token := t.peek(); token.typ == itemFunc && (token.val == keywordOn || token.val == keywordIgnoring) {
+		t.next()
+		m = &VectorMatching{
+			LabelsSet:      true,
+			On:             token.val == keywordOn,
+			MatchingLabels: t.labelList(token.val),
+		}
+		if token := t.peek(); token.typ == itemFunc && (token.val == keywordGroupLeft || token.val == keywordGroupRight) {
+			t.next()
+			m.Card = CardManyToOne
+			if token.val == keywordGroupRight {
+				m.Card = CardOneToMany
+			}
+			if t.peek().typ == itemLeftParen {
+				m.Include = t.labelList(token.val)
+			}
+		}
+	}
+	if token := t.peek(); token.typ == itemFunc && (token.val == string(AlignInner) || token.val == string(AlignOuter)) {
+		t.next()
+		if m == nil {
+			m = &VectorMatching{}
+		}
+		m.Alignment = TimeAlignment(token.val)
+	}
+	if m != nil && m.On {
+		for _, l := range m.Include {
+			for _, ml := range m.MatchingLabels {
+				if l == ml {
+					t.errorf("label %q must not occur in on() and the group modifier at once", l)
+				}
+			}
+		}
+	}
+	return m
+}
+
+// labelList parses a parenthesized, comma separated list of label names.
+// Label names may be bare identifiers or quoted strings.
+func (t *Tree) labelList(context string) []string {
+	t.expect(itemLeftParen, context)
+	labels := []string{}
+	for {
+		token := t.next()
+		switch token.typ {
+		case itemRightParen:
+			return labels
+		case itemFunc:
+			labels = append(labels, token.val)
+		case itemString:
+			s, err := strconv.Unquote(token.val)
+			if err != nil {
+				t.errorf("Unquoting error: %s", err)
+			}
+			labels = append(labels, s)
+		default:
+			t.unexpected(token, context)
+		}
+		if token := t.next(); token.typ == itemRightParen {
+			return labels
+		} else if token.typ != itemComma {
+			t.unexpected(token, context)
+		}
+	}
+}
@@ -168,16 +168,18 @@ func (t *Tree) parse() {
 }
 
 /* Grammar:
-O -> A {"||" A}
-A -> C {"&&" C}
-C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=") P}
-P -> M {( "+" | "-" ) M}
-M -> E {( "*" | "/" ) F}
-E -> F {( "**" ) F}
+O -> A {"||" mod A}
+A -> C {"&&" mod C}
+C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=") mod P}
+P -> M {( "+" | "-" ) mod M}
+M -> E {( "*" | "/" ) mod F}
+E -> F {( "**" ) mod F}
 F -> v | "(" O ")" | "!" O | "-" O
 v -> number | func(..) | queryVar
 Func -> name "(" param {"," param} ")"
 param -> number | "string" | queryVar
+mod -> [( "on" | "ignoring" ) labels [( "group_left" | "group_right" ) [labels]]] ["inner" | "outer"]
+labels -> "(" [label {"," label}] ")"
 */
 
 // expr:
@@ -188,7 +190,7 @@ func (t *Tree) O() Node {
 	for {
 		switch t.peek().typ {
 		case itemOr:
-			n = newBinary(t.next(), n, t.A())
+			n = newBinary(t.next(), t.matching(), n, t.A())
 		default:
 			return n
 		}
@@ -201,7 +203,7 @@ func (t *Tree) A() Node {
 	for {
 		switch t.peek().typ {
 		case itemAnd:
-			n = newBinary(t.next(), n, t.C())
+			n = newBinary(t.next(), t.matching(), n, t.C())
 		default:
 			return n
 		}
@@ -214,7 +216,7 @@ func (t *Tree) C() Node {
 	for {
 		switch t.peek().typ {
 		case itemEq, itemNotEq, itemGreater, itemGreaterEq, itemLess, itemLessEq:
-			n = newBinary(t.next(), n, t.P())
+			n = newBinary(t.next(), t.matching(), n, t.P())
 		default:
 			return n
 		}
@@ -227,7 +229,7 @@ func (t *Tree) P() Node {
 	for {
 		switch t.peek().typ {
 		case itemPlus, itemMinus:
-			n = newBinary(t.next(), n, t.M())
+			n = newBinary(t.next(), t.matching(), n, t.M())
 		default:
 			return n
 		}
@@ -240,7 +242,7 @@ func (t *Tree) M() Node {
 	for {
 		switch t.peek().typ {
 		case itemMult, itemDiv, itemMod:
-			n = newBinary(t.next(), n, t.E())
+			n = newBinary(t.next(), t.matching(), n, t.E())
 		default:
 			return n
 		}
@@ -253,7 +255,7 @@ func (t *Tree) E() Node {
 	for {
 		switch t.peek().typ {
 		case itemPow:
-			n = newBinary(t.next(), n, t.F())
+			n = newBinary(t.next(), t.matching(), n, t.F())
 		default:
 			return n
 		}
@@ -332,6 +334,10 @@ func (t *Tree) Func() (f *FuncNode) {
 				t.errorf("Unquoting error: %s", err)
 			}
 			f.append(newString(token.pos, token.val, s))
+		case itemComma:
+			if len(f.Args) == 0 {
+				t.unexpected(token, "func")
+			}
 		case itemRightParen:
 			return
 		}
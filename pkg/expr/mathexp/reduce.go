@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
@@ -22,11 +24,30 @@ const (
 	ReducerCount  ReducerID = "count"
 	ReducerLast   ReducerID = "last"
 	ReducerMedian ReducerID = "median"
+
+	ReducerFirst        ReducerID = "first"
+	ReducerRange        ReducerID = "range"
+	ReducerDiff         ReducerID = "diff"
+	ReducerStdDev       ReducerID = "stddev"
+	ReducerVariance     ReducerID = "variance"
+	ReducerCountNonNull ReducerID = "count_nonnull"
+
+	// Common percentiles. Any other percentile can be requested as pNN, for example p99.9.
+
+	ReducerP50 ReducerID = "p50"
+	ReducerP75 ReducerID = "p75"
+	ReducerP90 ReducerID = "p90"
+	ReducerP95 ReducerID = "p95"
+	ReducerP99 ReducerID = "p99"
 )
 
 // GetSupportedReduceFuncs returns collection of supported function names
 func GetSupportedReduceFuncs() []ReducerID {
-	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian}
+	return []ReducerID{
+		ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian,
+		ReducerFirst, ReducerRange, ReducerDiff, ReducerStdDev, ReducerVariance, ReducerCountNonNull,
+		ReducerP50, ReducerP75, ReducerP90, ReducerP95, ReducerP99,
+	}
 }
 
 func Sum(fv *Float64Field) *float64 {
@@ -126,6 +147,113 @@ func Median(fv *Float64Field) *float64 {
 	}
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// Range returns the difference between the maximum and the minimum value.
+func Range(fv *Float64Field) *float64 {
+	maxV := Max(fv)
+	minV := Min(fv)
+	f := *maxV - *minV
+	return &f
+}
+
+// Diff returns the difference between the last and the first value.
+func Diff(fv *Float64Field) *float64 {
+	first := First(fv)
+	last := Last(fv)
+	if first == nil || last == nil {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+// Variance returns the population variance of the values.
+func Variance(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	mean := Avg(fv)
+	if math.IsNaN(*mean) {
+		return mean
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *mean
+		sum += d * d
+	}
+	f := sum / float64(fv.Len())
+	return &f
+}
+
+// StdDev returns the population standard deviation of the values.
+func StdDev(fv *Float64Field) *float64 {
+	f := math.Sqrt(*Variance(fv))
+	return &f
+}
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+// Percentile returns a reducer that calculates the p-th percentile (0 < p <= 100) of the values,
+// linearly interpolating between the closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				nan := math.NaN()
+				return &nan
+			}
+			values = append(values, *v)
+		}
+
+		if len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+
+		sort.Float64s(values)
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// parsePercentile returns the percentile of a pNN reducer such as p95 or p99.9.
+func parsePercentile(rFunc ReducerID) (float64, bool) {
+	raw, ok := strings.CutPrefix(string(rFunc), "p")
+	if !ok {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(p) || p <= 0 || p > 100 {
+		return 0, false
+	}
+	return p, true
+}
+
 func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 	switch rFunc {
 	case ReducerSum:
@@ -142,7 +270,22 @@ func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 		return Last, nil
 	case ReducerMedian:
 		return Median, nil
+	case ReducerFirst:
+		return First, nil
+	case ReducerRange:
+		return Range, nil
+	case ReducerDiff:
+		return Diff, nil
+	case ReducerStdDev:
+		return StdDev, nil
+	case ReducerVariance:
+		return Variance, nil
+	case ReducerCountNonNull:
+		return CountNonNull, nil
 	default:
+		if p, ok := parsePercentile(rFunc); ok {
+			return Percentile(p), nil
+		}
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
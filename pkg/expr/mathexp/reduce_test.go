@@ -28,6 +28,17 @@ var seriesEmpty = Vars{
 	),
 }
 
+var seriesOneToFive = Vars{
+	"A": resultValuesNoErr(
+		makeSeries("temp", nil,
+			tp{time.Unix(5, 0), float64Pointer(4)},
+			tp{time.Unix(10, 0), float64Pointer(1)},
+			tp{time.Unix(15, 0), float64Pointer(5)},
+			tp{time.Unix(20, 0), float64Pointer(3)},
+			tp{time.Unix(25, 0), float64Pointer(2)}),
+	),
+}
+
 func TestSeriesReduce(t *testing.T) {
 	var tests = []struct {
 		name        string
@@ -274,6 +285,166 @@ func TestSeriesReduce(t *testing.T) {
 			resultsIs:   require.Equal,
 			results:     resultValuesNoErr(makeNumber("", nil, nil)),
 		},
+		{
+			name:        "first series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "first empty series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "range series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "range series with a nil value",
+			red:         "range",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "diff series",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(-1))),
+		},
+		{
+			name:        "diff series with a nil value",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "variance series",
+			red:         "variance",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(0.25))),
+		},
+		{
+			name:        "stddev series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(0.5))),
+		},
+		{
+			name:        "stddev series with a nil value",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "stddev empty series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "count_nonnull series with a nil value",
+			red:         "count_nonnull",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "p50 series",
+			red:         "p50",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1.5))),
+		},
+		{
+			name:        "p25 series",
+			red:         "p25",
+			varToReduce: "A",
+			vars:        seriesOneToFive,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "p62.5 series interpolates",
+			red:         "p62.5",
+			varToReduce: "A",
+			vars:        seriesOneToFive,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(3.5))),
+		},
+		{
+			name:        "p100 series",
+			red:         "p100",
+			varToReduce: "A",
+			vars:        seriesOneToFive,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(5))),
+		},
+		{
+			name:        "p99 series with a nil value",
+			red:         "p99",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "p0 reduction will error",
+			red:         "p0",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
+		{
+			name:        "p101 reduction will error",
+			red:         "p101",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
 	}
 
 	for _, tt := range tests {
@@ -20,17 +20,32 @@ const (
 
 	// Do not fill values (nill)
 	UpsamplerFillNA Upsampler = "fillna"
+
+	// Fill with a constant value
+	UpsamplerFillValue Upsampler = "fillvalue"
+
+	// Linear interpolation between the surrounding values
+	UpsamplerLinear Upsampler = "linear"
+
+	// Natural cubic spline interpolation through all values
+	UpsamplerSpline Upsampler = "spline"
 )
 
-// Resample turns the Series into a Number based on the given reduction function
-func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, from, to time.Time) (Series, error) {
+// Resample turns the Series into a Number based on the given reduction function.
+// fillValue is only used by the UpsamplerFillValue upsampler.
+func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, fillValue float64, from, to time.Time) (Series, error) {
 	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
 	if newSeriesLength <= 0 {
 		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
 	}
 	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
+	var spline *naturalSpline
+	if upsampler == UpsamplerSpline {
+		spline = newNaturalSpline(s)
+	}
 	bookmark := 0
 	var lastSeen *float64
+	var lastSeenTime time.Time
 	idx := 0
 	t := from
 	for !t.After(to) && idx <= newSeriesLength {
@@ -44,6 +59,7 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 			bookmark++
 			sIdx++
 			lastSeen = v
+			lastSeenTime = st
 			vals = append(vals, v)
 		}
 		var value *float64
@@ -63,30 +79,31 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 				}
 			case UpsamplerFillNA:
 				value = nil
+			case UpsamplerFillValue:
+				v := fillValue
+				value = &v
+			case UpsamplerLinear:
+				if lastSeen == nil || sIdx == s.Len() { // no surrounding vals
+					value = nil
+				} else {
+					nextTime, next := s.GetPoint(sIdx)
+					value = interpolateLinear(lastSeenTime, *lastSeen, nextTime, next, t)
+				}
+			case UpsamplerSpline:
+				value = spline.at(t)
 			default:
 				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
 			}
-		} else if len(vals) == 1 {
+		} else if len(vals) == 1 && keepsSingleValue(downsampler) {
 			value = vals[0]
 		} else { // downsampling
-			fVec := data.NewField("", s.GetLabels(), vals)
-			ff := Float64Field(*fVec)
-			var tmp *float64
-			switch downsampler {
-			case ReducerSum:
-				tmp = Sum(&ff)
-			case ReducerMean:
-				tmp = Avg(&ff)
-			case ReducerMin:
-				tmp = Min(&ff)
-			case ReducerMax:
-				tmp = Max(&ff)
-			case ReducerLast:
-				tmp = Last(&ff)
-			default:
+			reduce, err := GetReduceFunc(downsampler)
+			if err != nil {
 				return s, fmt.Errorf("downsampling %v not implemented", downsampler)
 			}
-			value = tmp
+			fVec := data.NewField("", s.GetLabels(), vals)
+			ff := Float64Field(*fVec)
+			value = reduce(&ff)
 		}
 		resampled.SetPoint(idx, t, value)
 		t = t.Add(interval)
@@ -94,3 +111,14 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	}
 	return resampled, nil
 }
+
+// keepsSingleValue reports whether the downsampler returns the value itself for a window with a single value.
+// Reducers that count the values or measure their spread do not.
+func keepsSingleValue(downsampler ReducerID) bool {
+	switch downsampler {
+	case ReducerCount, ReducerCountNonNull, ReducerRange, ReducerDiff, ReducerStdDev, ReducerVariance:
+		return false
+	default:
+		return true
+	}
+}
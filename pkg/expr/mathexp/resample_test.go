@@ -1,6 +1,7 @@
 package mathexp
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ func TestResampleSeries(t *testing.T) {
 		interval         time.Duration
 		downsampler      ReducerID
 		upsampler        Upsampler
+		fillValue        float64
 		timeRange        backend.TimeRange
 		seriesToResample Series
 		series           Series
@@ -245,6 +247,117 @@ func TestResampleSeries(t *testing.T) {
 				time.Unix(10, 0), nil,
 			}),
 		},
+		{
+			name:        "resample series: upsampling (mean / fillvalue )",
+			interval:    time.Second * 2,
+			downsampler: "mean",
+			upsampler:   "fillvalue",
+			fillValue:   5,
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(11, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(2, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(7, 0), float64Pointer(1),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(5),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(5),
+			}, tp{
+				time.Unix(6, 0), float64Pointer(5),
+			}, tp{
+				time.Unix(8, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(5),
+			}),
+		},
+		{
+			name:        "resample series: upsampling (mean / linear )",
+			interval:    time.Second * 2,
+			downsampler: "mean",
+			upsampler:   "linear",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(11, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(8, 0), float64Pointer(8),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(4),
+			}, tp{
+				time.Unix(6, 0), float64Pointer(6),
+			}, tp{
+				time.Unix(8, 0), float64Pointer(8),
+			}, tp{
+				time.Unix(10, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: upsampling (mean / linear ) with null neighbour",
+			interval:    time.Second * 2,
+			downsampler: "mean",
+			upsampler:   "linear",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(7, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(6, 0), nil,
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(2, 0), nil,
+			}, tp{
+				time.Unix(4, 0), nil,
+			}, tp{
+				time.Unix(6, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: upsampling (mean / spline )",
+			interval:    time.Second * 2,
+			downsampler: "mean",
+			upsampler:   "spline",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(11, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(4),
+			}, tp{
+				time.Unix(8, 0), float64Pointer(0),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(2.75),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(4),
+			}, tp{
+				time.Unix(6, 0), float64Pointer(2.75),
+			}, tp{
+				time.Unix(8, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(10, 0), nil,
+			}),
+		},
 		{
 			name:        "resample series: downsampling (last / pad )",
 			interval:    time.Second * 3,
@@ -280,7 +393,7 @@ func TestResampleSeries(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			series, err := tt.seriesToResample.Resample("", tt.interval, tt.downsampler, tt.upsampler, tt.timeRange.From, tt.timeRange.To)
+			series, err := tt.seriesToResample.Resample("", tt.interval, tt.downsampler, tt.upsampler, tt.fillValue, tt.timeRange.From, tt.timeRange.To)
 			if tt.series.Frame == nil {
 				require.Error(t, err)
 			} else {
@@ -290,3 +403,57 @@ func TestResampleSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestResampleSeriesDownsamplers(t *testing.T) {
+	// the first window has the values 1, 4, 2, 3 and the second window has the single value 5
+	seriesToResample := makeSeries("", nil,
+		tp{time.Unix(1, 0), float64Pointer(1)},
+		tp{time.Unix(2, 0), float64Pointer(4)},
+		tp{time.Unix(3, 0), float64Pointer(2)},
+		tp{time.Unix(4, 0), float64Pointer(3)},
+		tp{time.Unix(15, 0), float64Pointer(5)},
+	)
+	expected := map[ReducerID][2]float64{
+		ReducerSum:          {10, 5},
+		ReducerMean:         {2.5, 5},
+		ReducerMin:          {1, 5},
+		ReducerMax:          {4, 5},
+		ReducerCount:        {4, 1},
+		ReducerLast:         {3, 5},
+		ReducerMedian:       {2.5, 5},
+		ReducerFirst:        {1, 5},
+		ReducerRange:        {3, 0},
+		ReducerDiff:         {2, 0},
+		ReducerStdDev:       {math.Sqrt(1.25), 0},
+		ReducerVariance:     {1.25, 0},
+		ReducerCountNonNull: {4, 1},
+		ReducerP50:          {2.5, 5},
+		ReducerP75:          {3.25, 5},
+		ReducerP90:          {3.7, 5},
+		ReducerP95:          {3.85, 5},
+		ReducerP99:          {3.97, 5},
+	}
+
+	for _, downsampler := range GetSupportedReduceFuncs() {
+		t.Run(string(downsampler), func(t *testing.T) {
+			exp, ok := expected[downsampler]
+			require.Truef(t, ok, "no expectation for the downsampler %s", downsampler)
+
+			series, err := seriesToResample.Resample("", 10*time.Second, downsampler, UpsamplerFillNA, 0, time.Unix(0, 0), time.Unix(20, 0))
+			require.NoError(t, err)
+			require.Equal(t, 3, series.Len())
+			_, v := series.GetPoint(0)
+			require.Nil(t, v)
+			for i, e := range exp {
+				_, v := series.GetPoint(i + 1)
+				require.NotNil(t, v)
+				require.InDelta(t, e, *v, 1e-9)
+			}
+		})
+	}
+
+	t.Run("unknown downsampler", func(t *testing.T) {
+		_, err := seriesToResample.Resample("", 10*time.Second, "unknown", UpsamplerFillNA, 0, time.Unix(0, 0), time.Unix(20, 0))
+		require.ErrorContains(t, err, "downsampling unknown not implemented")
+	})
+}
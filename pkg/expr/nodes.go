@@ -151,6 +151,8 @@ func buildCMDNode(ctx context.Context, rn *rawNode, toggles featuremgmt.FeatureT
 		node.Command, err = UnmarshalThresholdCommand(rn)
 	case TypeSQL:
 		node.Command, err = UnmarshalSQLCommand(ctx, rn, cfg)
+	case TypeForecast:
+		node.Command, err = UnmarshalForecastCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
@@ -242,9 +244,21 @@ func (s *Service) buildDSNode(_ *simple.DirectedGraph, rn *rawNode, req *Request
 	return dsNode, nil
 }
 
+// dataQuery returns the query of the node for a request at now.
+func (dn *DSNode) dataQuery(now time.Time) backend.DataQuery {
+	return backend.DataQuery{
+		RefID:         dn.refID,
+		MaxDataPoints: dn.maxDP,
+		Interval:      time.Duration(int64(time.Millisecond) * dn.intervalMS),
+		JSON:          dn.query,
+		TimeRange:     dn.timeRange.AbsoluteTime(now),
+		QueryType:     dn.queryType,
+	}
+}
+
 // executeDSNodesGrouped groups datasource node queries by the datasource instance, and then sends them
 // in a single request with one or more queries to the datasource.
-func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars, s *Service, nodes []*DSNode) {
+func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars, s *Service, nodes []*DSNode, stats *executionStats) {
 	type dsKey struct {
 		uid   string // in theory I think this all I need for the key, but rather be safe
 		id    int64
@@ -260,6 +274,10 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 		func() {
 			ctx, span := s.tracer.Start(ctx, "SSE.ExecuteDatasourceQuery")
 			defer span.End()
+			start := time.Now()
+			defer func() {
+				stats.recordGroup(nodeGroup, time.Since(start), vars)
+			}()
 
 			firstNode := nodeGroup[0]
 			logger := logger.FromContext(ctx).New("datasourceType", firstNode.datasource.Type,
@@ -276,16 +294,25 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 				Headers: firstNode.request.Headers,
 			}
 
-			// add all the queries from the node group to the request
+			// add the queries from the node group to the request, unless their results are cached
+			queried := make([]*DSNode, 0, len(nodeGroup))
 			for _, dn := range nodeGroup {
-				req.Queries = append(req.Queries, backend.DataQuery{
-					RefID:         dn.refID,
-					MaxDataPoints: dn.maxDP,
-					Interval:      time.Duration(int64(time.Millisecond) * dn.intervalMS),
-					JSON:          dn.query,
-					TimeRange:     dn.timeRange.AbsoluteTime(now),
-					QueryType:     dn.queryType,
-				})
+				q := dn.dataQuery(now)
+				if dataFrames, ok := s.dsResultCache.get(dn, q); ok {
+					var result mathexp.Results
+					responseType, result, err := s.converter.Convert(ctx, dn.datasource.Type, dataFrames)
+					if err != nil {
+						result.Error = makeConversionError(dn.RefID(), err)
+					}
+					logger.Debug("Data source result served from cache", "queryRefId", dn.refID, "responseType", responseType)
+					vars[dn.refID] = result
+					continue
+				}
+				queried = append(queried, dn)
+				req.Queries = append(req.Queries, q)
+			}
+			if len(queried) == 0 {
+				return
 			}
 
 			instrument := func(e error, rt string) {
@@ -307,7 +334,7 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 			// get the new client if it exists
 			qsDSClient, ok, err := s.qsDatasourceClientBuilder.BuildClient(firstNode.datasource.Type, firstNode.datasource.UID)
 			if err != nil {
-				for _, dn := range nodeGroup {
+				for _, dn := range queried {
 					vars[dn.refID] = mathexp.Results{Error: datasources.ErrDataSourceNotFound}
 				}
 				instrument(err, "")
@@ -318,7 +345,7 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 			if !ok { // legacy flow
 				pCtx, err := s.pCtxProvider.GetWithDataSource(ctx, firstNode.datasource.Type, firstNode.request.User, firstNode.datasource)
 				if err != nil {
-					for _, dn := range nodeGroup {
+					for _, dn := range queried {
 						vars[dn.refID] = mathexp.Results{Error: datasources.ErrDataSourceNotFound}
 					}
 					return
@@ -328,7 +355,7 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 			} else { // new query service flow
 				k8sReq, err := ConvertBackendRequestToDataRequest(req)
 				if err != nil {
-					for _, dn := range nodeGroup {
+					for _, dn := range queried {
 						vars[dn.refID] = mathexp.Results{Error: datasources.ErrDataSourceNotFound}
 					}
 					return
@@ -338,19 +365,20 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 			}
 
 			if queryErr != nil {
-				for _, dn := range nodeGroup {
+				for _, dn := range queried {
 					vars[dn.refID] = mathexp.Results{Error: MakeQueryError(firstNode.refID, firstNode.datasource.UID, queryErr)}
 				}
 				instrument(queryErr, "")
 				return
 			}
-			for _, dn := range nodeGroup {
+			for i, dn := range queried {
 				dataFrames, err := getResponseFrame(logger, resp, dn.refID)
 				if err != nil {
 					vars[dn.refID] = mathexp.Results{Error: MakeQueryError(dn.refID, dn.datasource.UID, err)}
 					instrument(err, "")
 					return
 				}
+				s.dsResultCache.set(dn, req.Queries[i], dataFrames)
 
 				var result mathexp.Results
 				responseType, result, err := s.converter.Convert(ctx, dn.datasource.Type, dataFrames)
@@ -378,21 +406,13 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 	)
 
 	req := &backend.QueryDataRequest{
-		Queries: []backend.DataQuery{
-			{
-				RefID:         dn.refID,
-				MaxDataPoints: dn.maxDP,
-				Interval:      time.Duration(int64(time.Millisecond) * dn.intervalMS),
-				JSON:          dn.query,
-				TimeRange:     dn.timeRange.AbsoluteTime(now),
-				QueryType:     dn.queryType,
-			},
-		},
+		Queries: []backend.DataQuery{dn.dataQuery(now)},
 		Headers: dn.request.Headers,
 	}
 
 	responseType := "unknown"
 	respStatus := "success"
+	fromCache := false
 	defer func() {
 		if e != nil {
 			responseType = "error"
@@ -400,49 +420,30 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 			span.SetStatus(codes.Error, "failed to query data source")
 			span.RecordError(e)
 		}
+		if fromCache {
+			logger.Debug("Data source result served from cache", "responseType", responseType)
+			return
+		}
 		logger.Debug("Data source queried", "responseType", responseType)
 		useDataplane := strings.HasPrefix(responseType, "dataplane-")
 		s.metrics.DSRequests.WithLabelValues(respStatus, fmt.Sprintf("%t", useDataplane), dn.datasource.Type).Inc()
 	}()
 
-	var resp *backend.QueryDataResponse
-	qsDSClient, ok, err := s.qsDatasourceClientBuilder.BuildClient(dn.datasource.Type, dn.datasource.UID)
-	if err != nil {
-		return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
-	}
-
-	if !ok { // use single tenant client
-		pCtx, err := s.pCtxProvider.GetWithDataSource(ctx, dn.datasource.Type, dn.request.User, dn.datasource)
+	dataFrames, fromCache := s.dsResultCache.get(dn, req.Queries[0])
+	if !fromCache {
+		resp, err := dn.queryData(ctx, s, req)
 		if err != nil {
 			return mathexp.Results{}, err
 		}
-		req.PluginContext = pCtx
-		resp, err = s.dataService.QueryData(ctx, req)
-		if err != nil {
-			return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
-		}
-	} else { // use query-service client (single or multi tenant)
-		k8sReq, err := ConvertBackendRequestToDataRequest(req)
-		if err != nil {
-			return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
-		}
-
-		// make the query with a mt client
-		resp, err = qsDSClient.QueryData(ctx, *k8sReq)
-
-		// handle error
+		dataFrames, err = getResponseFrame(logger, resp, dn.refID)
 		if err != nil {
 			return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
 		}
-	}
-
-	dataFrames, err := getResponseFrame(logger, resp, dn.refID)
-	if err != nil {
-		return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
+		s.dsResultCache.set(dn, req.Queries[0], dataFrames)
 	}
 
 	var result mathexp.Results
-
+	var err error
 	if dn.isInputToSQLExpr {
 		var converted bool
 		dataType := categorizeFrameInputType(dataFrames)
@@ -462,3 +463,38 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 
 	return result, err
 }
+
+// queryData sends the request to the data source of the node.
+func (dn *DSNode) queryData(ctx context.Context, s *Service, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	var resp *backend.QueryDataResponse
+	qsDSClient, ok, err := s.qsDatasourceClientBuilder.BuildClient(dn.datasource.Type, dn.datasource.UID)
+	if err != nil {
+		return nil, MakeQueryError(dn.refID, dn.datasource.UID, err)
+	}
+
+	if !ok { // use single tenant client
+		pCtx, err := s.pCtxProvider.GetWithDataSource(ctx, dn.datasource.Type, dn.request.User, dn.datasource)
+		if err != nil {
+			return nil, err
+		}
+		req.PluginContext = pCtx
+		resp, err = s.dataService.QueryData(ctx, req)
+		if err != nil {
+			return nil, MakeQueryError(dn.refID, dn.datasource.UID, err)
+		}
+	} else { // use query-service client (single or multi tenant)
+		k8sReq, err := ConvertBackendRequestToDataRequest(req)
+		if err != nil {
+			return nil, MakeQueryError(dn.refID, dn.datasource.UID, err)
+		}
+
+		// make the query with a mt client
+		resp, err = qsDSClient.QueryData(ctx, *k8sReq)
+
+		// handle error
+		if err != nil {
+			return nil, MakeQueryError(dn.refID, dn.datasource.UID, err)
+		}
+	}
+	return resp, nil
+}
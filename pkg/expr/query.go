@@ -29,6 +29,9 @@ const (
 
 	// SQL query
 	QueryTypeSQL QueryType = "sql"
+
+	// Forecast future values of query results
+	QueryTypeForecast QueryType = "forecast"
 )
 
 type MathQuery struct {
@@ -60,6 +63,9 @@ type ResampleQuery struct {
 
 	// The upsample function
 	Upsampler mathexp.Upsampler `json:"upsampler"`
+
+	// Only valid when upsampler is fillvalue
+	FillValue *float64 `json:"fillValue,omitempty"`
 }
 
 type ThresholdQuery struct {
@@ -70,6 +76,39 @@ type ThresholdQuery struct {
 	Conditions []ThresholdConditionJSON `json:"conditions"`
 }
 
+// QueryType = forecast
+type ForecastQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The forecast model
+	Model ForecastModel `json:"model"`
+
+	// How far past the last point to forecast
+	Horizon string `json:"horizon" jsonschema:"minLength=1,example=1h,example=1d"`
+
+	// The time between predicted points. Defaults to the median interval of the input series
+	Interval string `json:"interval,omitempty" jsonschema:"example=1m"`
+
+	// The series to return
+	Output ForecastOutput `json:"output,omitempty"`
+
+	// Width of the confidence band in standard deviations of the residuals. Defaults to 2
+	Confidence *float64 `json:"confidence,omitempty"`
+
+	// Number of points in a season. Only valid for holt_winters, 0 disables seasonality
+	SeasonLength int `json:"seasonLength,omitempty"`
+
+	// Level smoothing factor between 0 and 1. Only valid for holt_winters, defaults to 0.5
+	Alpha *float64 `json:"alpha,omitempty"`
+
+	// Trend smoothing factor between 0 and 1. Only valid for holt_winters, defaults to 0.1
+	Beta *float64 `json:"beta,omitempty"`
+
+	// Seasonal smoothing factor between 0 and 1. Only valid for holt_winters, defaults to 0.1
+	Gamma *float64 `json:"gamma,omitempty"`
+}
+
 type ClassicQuery struct {
 	Conditions []classic.ConditionJSON `json:"conditions"`
 }
@@ -107,6 +146,36 @@ const (
 	ReduceModeReplace ReduceMode = "replaceNN"
 )
 
+// The model used to predict future values
+// +enum
+type ForecastModel string
+
+const (
+	// Least-squares linear trend
+	ForecastModelLinear ForecastModel = "linear"
+
+	// Additive Holt-Winters exponential smoothing. Without a season length it is Holt's linear trend method
+	ForecastModelHoltWinters ForecastModel = "holt_winters"
+)
+
+// The forecast series to return
+// +enum
+type ForecastOutput string
+
+const (
+	// Only the predicted values (default)
+	ForecastOutputPredicted ForecastOutput = "predicted"
+
+	// Only the lower bound of the confidence band
+	ForecastOutputLower ForecastOutput = "lower"
+
+	// Only the upper bound of the confidence band
+	ForecastOutputUpper ForecastOutput = "upper"
+
+	// The predicted values and both bounds, distinguished by the forecast label
+	ForecastOutputAll ForecastOutput = "all"
+)
+
 //go:embed query.types.json
 var f embed.FS
 
@@ -27,6 +27,8 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				reflect.TypeOf(ReduceModeDrop),       // pick an example value (not the root)
 				reflect.TypeOf(ThresholdIsAbove),
 				reflect.TypeOf(classic.ConditionOperatorAnd),
+				reflect.TypeOf(ForecastModelLinear),
+				reflect.TypeOf(ForecastOutputAll),
 			},
 		})
 	require.NoError(t, err)
@@ -161,6 +163,31 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				},
 			},
 		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeForecast),
+			GoType:         reflect.TypeOf(&ForecastQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "linear trend for the next day",
+					SaveModel: data.AsUnstructured(ForecastQuery{
+						Expression: "$A",
+						Model:      ForecastModelLinear,
+						Horizon:    "1d",
+					}),
+				},
+				{
+					Name: "daily seasonality with confidence band",
+					SaveModel: data.AsUnstructured(ForecastQuery{
+						Expression:   "$A",
+						Model:        ForecastModelHoltWinters,
+						Horizon:      "6h",
+						Interval:     "1h",
+						Output:       ForecastOutputAll,
+						SeasonLength: 24,
+					}),
+				},
+			},
+		},
 	)
 
 	require.NoError(t, err)
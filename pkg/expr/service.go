@@ -69,6 +69,7 @@ type Service struct {
 	tracer                    tracing.Tracer
 	metrics                   *metrics.ExprMetrics
 	qsDatasourceClientBuilder dsquerierclient.QSDatasourceClientBuilder
+	dsResultCache             *dsResultCache
 }
 
 type pluginContextProvider interface {
@@ -78,19 +79,21 @@ type pluginContextProvider interface {
 
 func ProvideService(cfg *setting.Cfg, pluginClient plugins.Client, pCtxProvider *plugincontext.Provider,
 	features featuremgmt.FeatureToggles, registerer prometheus.Registerer, tracer tracing.Tracer, builder dsquerierclient.QSDatasourceClientBuilder) *Service {
+	m := metrics.NewSSEMetrics(registerer)
 	return &Service{
 		cfg:           cfg,
 		dataService:   pluginClient,
 		pCtxProvider:  pCtxProvider,
 		features:      features,
 		tracer:        tracer,
-		metrics:       metrics.NewSSEMetrics(registerer),
+		metrics:       m,
 		pluginsClient: pluginClient,
 		converter: &ResultConverter{
 			Features: features,
 			Tracer:   tracer,
 		},
 		qsDatasourceClientBuilder: builder,
+		dsResultCache:             newDSResultCache(cfg.ExpressionsDatasourceCacheMaxEntries, cfg.ExpressionsDatasourceCacheTTL, m),
 	}
 }
 
@@ -108,10 +111,14 @@ func (s *Service) BuildPipeline(ctx context.Context, req *Request) (DataPipeline
 
 // ExecutePipeline executes an expression pipeline and returns all the results.
 func (s *Service) ExecutePipeline(ctx context.Context, now time.Time, pipeline DataPipeline) (*backend.QueryDataResponse, error) {
+	return s.executePipeline(ctx, now, pipeline, nil)
+}
+
+func (s *Service) executePipeline(ctx context.Context, now time.Time, pipeline DataPipeline, stats *executionStats) (*backend.QueryDataResponse, error) {
 	ctx, span := s.tracer.Start(ctx, "SSE.ExecutePipeline")
 	defer span.End()
 	res := backend.NewQueryDataResponse()
-	vars, err := pipeline.execute(ctx, now, s)
+	vars, err := pipeline.execute(ctx, now, s, stats)
 	if err != nil {
 		return nil, err
 	}
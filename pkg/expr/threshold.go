@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp"
@@ -25,7 +26,11 @@ type ThresholdCommand struct {
 	RefID         string
 	ThresholdFunc ThresholdType
 	Invert        bool
-	predicate     predicate
+	// BaselineVar is the variable that the values are compared with by the baseline threshold types.
+	BaselineVar string
+	// BaselineOffset is added to the timestamps of the baseline series before pairing them with the input points.
+	BaselineOffset time.Duration
+	predicate      predicate
 }
 
 // +enum
@@ -42,6 +47,14 @@ const (
 	ThresholdIsOutsideRange         ThresholdType = "outside_range"
 	ThresholdIsWithinRangeIncluded  ThresholdType = "within_range_included"
 	ThresholdIsOutsideRangeIncluded ThresholdType = "outside_range_included"
+
+	// Threshold types that compare the input with a baseline variable.
+
+	ThresholdPercentChangeAbove ThresholdType = "pct_change_gt"
+	ThresholdPercentChangeBelow ThresholdType = "pct_change_lt"
+	ThresholdRatioAbove         ThresholdType = "ratio_gt"
+	ThresholdRatioBelow         ThresholdType = "ratio_lt"
+	ThresholdZScoreAbove        ThresholdType = "zscore_gt"
 )
 
 var (
@@ -56,10 +69,31 @@ var (
 		string(ThresholdIsOutsideRange),
 		string(ThresholdIsWithinRangeIncluded),
 		string(ThresholdIsOutsideRangeIncluded),
+		string(ThresholdPercentChangeAbove),
+		string(ThresholdPercentChangeBelow),
+		string(ThresholdRatioAbove),
+		string(ThresholdRatioBelow),
+		string(ThresholdZScoreAbove),
 	}
 )
 
 func NewThresholdCommand(refID, referenceVar string, thresholdFunc ThresholdType, conditions []float64) (*ThresholdCommand, error) {
+	if isBaselineThresholdFunc(thresholdFunc) {
+		return nil, fmt.Errorf("threshold function '%s' requires a baseline", thresholdFunc)
+	}
+	predicate, err := newThresholdPredicate(thresholdFunc, conditions)
+	if err != nil {
+		return nil, err
+	}
+	return &ThresholdCommand{
+		RefID:         refID,
+		ReferenceVar:  referenceVar,
+		ThresholdFunc: thresholdFunc,
+		predicate:     predicate,
+	}, nil
+}
+
+func newThresholdPredicate(thresholdFunc ThresholdType, conditions []float64) (predicate, error) {
 	var predicate predicate
 	switch thresholdFunc {
 	case ThresholdIsOutsideRange:
@@ -87,6 +121,16 @@ func NewThresholdCommand(refID, referenceVar string, thresholdFunc ThresholdType
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
 		}
 		predicate = greaterThanPredicate{value: conditions[0]}
+	case ThresholdPercentChangeAbove, ThresholdRatioAbove, ThresholdZScoreAbove:
+		if len(conditions) < 1 {
+			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
+		}
+		predicate = greaterThanPredicate{value: conditions[0]}
+	case ThresholdPercentChangeBelow, ThresholdRatioBelow:
+		if len(conditions) < 1 {
+			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
+		}
+		predicate = lessThanPredicate{value: conditions[0]}
 	case ThresholdIsBelow:
 		if len(conditions) < 1 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
@@ -115,18 +159,35 @@ func NewThresholdCommand(refID, referenceVar string, thresholdFunc ThresholdType
 	default:
 		return nil, fmt.Errorf("expected threshold function to be one of [%s], got %s", strings.Join(supportedThresholdFuncs, ", "), thresholdFunc)
 	}
-
-	return &ThresholdCommand{
-		RefID:         refID,
-		ReferenceVar:  referenceVar,
-		ThresholdFunc: thresholdFunc,
-		predicate:     predicate,
-	}, nil
+	return predicate, nil
 }
 
 type ConditionEvalJSON struct {
 	Params []float64     `json:"params"`
 	Type   ThresholdType `json:"type"` // e.g. "gt"
+	// The variable to compare with. Only used by the baseline threshold types
+	Baseline string `json:"baseline,omitempty" jsonschema:"example=B"`
+	// Shifts the baseline series forward in time before pairing its points with the input, for example 1w
+	BaselineOffset string `json:"baselineOffset,omitempty" jsonschema:"example=1w"`
+}
+
+// newThresholdCommandFromJSON creates a ThresholdCommand from the evaluator of a threshold condition.
+func newThresholdCommandFromJSON(refID, referenceVar string, eval ConditionEvalJSON) (*ThresholdCommand, error) {
+	if !isBaselineThresholdFunc(eval.Type) {
+		if eval.Baseline != "" || eval.BaselineOffset != "" {
+			return nil, fmt.Errorf("threshold function '%s' does not support a baseline", eval.Type)
+		}
+		return NewThresholdCommand(refID, referenceVar, eval.Type, eval.Params)
+	}
+	var offset time.Duration
+	if eval.BaselineOffset != "" {
+		var err error
+		offset, err = gtime.ParseDuration(eval.BaselineOffset)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse "baselineOffset" duration field %q: %w`, eval.BaselineOffset, err)
+		}
+	}
+	return NewBaselineThresholdCommand(refID, referenceVar, eval.Type, eval.Params, strings.TrimPrefix(eval.Baseline, "$"), offset)
 }
 
 // UnmarshalResampleCommand creates a ResampleCMD from Grafana's frontend query.
@@ -146,12 +207,12 @@ func UnmarshalThresholdCommand(rn *rawNode) (Command, error) {
 	}
 	firstCondition := cmdConfig.Conditions[0]
 
-	threshold, err := NewThresholdCommand(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
+	threshold, err := newThresholdCommandFromJSON(rn.RefID, referenceVar, firstCondition.Evaluator)
 	if err != nil {
 		return nil, fmt.Errorf("invalid condition: %w", err)
 	}
 	if firstCondition.UnloadEvaluator != nil {
-		unloading, err := NewThresholdCommand(rn.RefID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params)
+		unloading, err := newThresholdCommandFromJSON(rn.RefID, referenceVar, *firstCondition.UnloadEvaluator)
 		if err != nil {
 			return nil, fmt.Errorf("invalid unloadCondition: %w", err)
 		}
@@ -171,7 +232,7 @@ func UnmarshalThresholdCommand(rn *rawNode) (Command, error) {
 // NeedsVars returns the variable names (refIds) that are dependencies
 // to execute the command and allows the command to fulfill the Command interface.
 func (tc *ThresholdCommand) NeedsVars() []string {
-	return []string{tc.ReferenceVar}
+	return baselineVars([]string{tc.ReferenceVar}, tc)
 }
 
 func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars, _ tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
@@ -192,20 +253,28 @@ func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp
 	refVarResult := vars[tc.ReferenceVar]
 	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(refVarResult.Values))}
 	for _, val := range refVarResult.Values {
+		derive := noBaseline
+		if tc.BaselineVar != "" {
+			var err error
+			derive, err = tc.baselineFuncFor(val, vars[tc.BaselineVar])
+			if err != nil {
+				return newRes, err
+			}
+		}
 		switch v := val.(type) {
 		case mathexp.Series:
 			s := mathexp.NewSeries(tc.RefID, v.GetLabels(), v.Len())
 			for i := 0; i < v.Len(); i++ {
 				t, value := v.GetPoint(i)
-				s.SetPoint(i, t, eval(value))
+				s.SetPoint(i, t, eval(derive(value, t)))
 			}
 			newRes.Values = append(newRes.Values, s)
 		case mathexp.Number:
 			copyV := mathexp.NewNumber(tc.RefID, v.GetLabels())
-			copyV.SetValue(eval(v.GetFloat64Value()))
+			copyV.SetValue(eval(derive(v.GetFloat64Value(), time.Time{})))
 			newRes.Values = append(newRes.Values, copyV)
 		case mathexp.Scalar:
-			copyV := mathexp.NewScalar(tc.RefID, eval(v.GetFloat64Value()))
+			copyV := mathexp.NewScalar(tc.RefID, eval(derive(v.GetFloat64Value(), time.Time{})))
 			newRes.Values = append(newRes.Values, copyV)
 		case mathexp.NoData:
 			newRes.Values = append(newRes.Values, mathexp.NewNoData())
@@ -0,0 +1,208 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// baselineFunc returns the value that is compared with the threshold, given a value of the input at time t.
+// For numbers and scalars t is zero. It returns nil if there is nothing to compare.
+type baselineFunc func(value *float64, t time.Time) *float64
+
+func noBaseline(value *float64, _ time.Time) *float64 {
+	return value
+}
+
+func noBaselineValue(*float64, time.Time) *float64 {
+	return nil
+}
+
+func isBaselineThresholdFunc(thresholdFunc ThresholdType) bool {
+	switch thresholdFunc {
+	case ThresholdPercentChangeAbove, ThresholdPercentChangeBelow, ThresholdRatioAbove, ThresholdRatioBelow, ThresholdZScoreAbove:
+		return true
+	}
+	return false
+}
+
+// NewBaselineThresholdCommand creates a ThresholdCommand that compares each value of referenceVar with the matching item of baselineVar:
+//   - pct_change_gt and pct_change_lt compare the change from the baseline in percent, (value - baseline) / |baseline| * 100.
+//   - ratio_gt and ratio_lt compare value / baseline.
+//   - zscore_gt compares the absolute z-score of the value against the mean and standard deviation of the baseline series.
+//
+// For the percent change and ratio types, the baseline offset is added to the timestamps of a baseline series before its
+// points are paired with the points of an input series, e.g. 1w to compare with the same window last week.
+func NewBaselineThresholdCommand(refID, referenceVar string, thresholdFunc ThresholdType, conditions []float64, baselineVar string, baselineOffset time.Duration) (*ThresholdCommand, error) {
+	if !isBaselineThresholdFunc(thresholdFunc) {
+		return nil, fmt.Errorf("threshold function '%s' does not support a baseline", thresholdFunc)
+	}
+	if baselineVar == "" {
+		return nil, fmt.Errorf("threshold function '%s' requires a baseline", thresholdFunc)
+	}
+	if baselineOffset != 0 && thresholdFunc == ThresholdZScoreAbove {
+		return nil, fmt.Errorf("threshold function '%s' does not support a baseline offset", thresholdFunc)
+	}
+	predicate, err := newThresholdPredicate(thresholdFunc, conditions)
+	if err != nil {
+		return nil, err
+	}
+	return &ThresholdCommand{
+		RefID:          refID,
+		ReferenceVar:   referenceVar,
+		ThresholdFunc:  thresholdFunc,
+		BaselineVar:    baselineVar,
+		BaselineOffset: baselineOffset,
+		predicate:      predicate,
+	}, nil
+}
+
+// baselineFuncFor returns the baselineFunc for the input value val. The item of the baseline that has the same labels as val is used.
+// If there is no such item but the baseline has a single item, that item is used for every input.
+func (tc *ThresholdCommand) baselineFuncFor(val mathexp.Value, baseline mathexp.Results) (baselineFunc, error) {
+	if _, ok := val.(mathexp.NoData); ok {
+		return noBaseline, nil
+	}
+	item := findBaselineItem(val.GetLabels(), baseline)
+	if item == nil {
+		return noBaselineValue, nil
+	}
+
+	if tc.ThresholdFunc == ThresholdZScoreAbove {
+		s, ok := item.(mathexp.Series)
+		if !ok {
+			return nil, fmt.Errorf("baseline %s of threshold function '%s' must be a time series, got type %v", tc.BaselineVar, tc.ThresholdFunc, item.Type())
+		}
+		return zScore(s), nil
+	}
+
+	compare := ratio
+	if tc.ThresholdFunc == ThresholdPercentChangeAbove || tc.ThresholdFunc == ThresholdPercentChangeBelow {
+		compare = percentChange
+	}
+	switch b := item.(type) {
+	case mathexp.Number:
+		return compareWithBaselineValue(b.GetFloat64Value(), compare), nil
+	case mathexp.Scalar:
+		return compareWithBaselineValue(b.GetFloat64Value(), compare), nil
+	case mathexp.Series:
+		if _, ok := val.(mathexp.Series); !ok {
+			return nil, fmt.Errorf("baseline %s of threshold function '%s' is a time series, but the input is type %v. Reduce the baseline first", tc.BaselineVar, tc.ThresholdFunc, val.Type())
+		}
+		return compareWithBaselineSeries(b, tc.BaselineOffset, compare), nil
+	case mathexp.NoData:
+		return noBaselineValue, nil
+	default:
+		return nil, fmt.Errorf("unsupported format of the baseline data, got type %v", item.Type())
+	}
+}
+
+func findBaselineItem(labels data.Labels, baseline mathexp.Results) mathexp.Value {
+	fp := labels.Fingerprint()
+	for _, v := range baseline.Values {
+		if v != nil && v.GetLabels().Fingerprint() == fp {
+			return v
+		}
+	}
+	if len(baseline.Values) == 1 {
+		return baseline.Values[0]
+	}
+	return nil
+}
+
+func compareWithBaselineValue(b *float64, compare func(value, baseline float64) *float64) baselineFunc {
+	if b == nil {
+		return noBaselineValue
+	}
+	return func(value *float64, _ time.Time) *float64 {
+		if value == nil {
+			return nil
+		}
+		return compare(*value, *b)
+	}
+}
+
+func compareWithBaselineSeries(s mathexp.Series, offset time.Duration, compare func(value, baseline float64) *float64) baselineFunc {
+	points := make(map[int64]float64, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		if f != nil {
+			points[t.Add(offset).UnixNano()] = *f
+		}
+	}
+	return func(value *float64, t time.Time) *float64 {
+		b, ok := points[t.UnixNano()]
+		if value == nil || !ok {
+			return nil
+		}
+		return compare(*value, b)
+	}
+}
+
+// zScore returns a baselineFunc that calculates the absolute z-score of a value against the mean and
+// population standard deviation of the non-null values of s.
+func zScore(s mathexp.Series) baselineFunc {
+	values := make([]float64, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		_, f := s.GetPoint(i)
+		if f != nil && !math.IsNaN(*f) {
+			values = append(values, *f)
+		}
+	}
+	if len(values) < 2 {
+		return noBaselineValue
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var sumSq float64
+	for _, v := range values {
+		sumSq += (v - mean) * (v - mean)
+	}
+	stdDev := math.Sqrt(sumSq / float64(len(values)))
+	if stdDev == 0 {
+		return noBaselineValue
+	}
+	return func(value *float64, _ time.Time) *float64 {
+		if value == nil {
+			return nil
+		}
+		z := math.Abs(*value-mean) / stdDev
+		return &z
+	}
+}
+
+// percentChange returns the change from baseline to value in percent, or nil if the baseline is zero.
+func percentChange(value, baseline float64) *float64 {
+	if baseline == 0 {
+		return nil
+	}
+	f := (value - baseline) / math.Abs(baseline) * 100
+	return &f
+}
+
+// ratio returns value divided by baseline, or nil if the baseline is zero.
+func ratio(value, baseline float64) *float64 {
+	if baseline == 0 {
+		return nil
+	}
+	f := value / baseline
+	return &f
+}
+
+// baselineVars returns vars with the baseline variables of the threshold commands appended, skipping duplicates.
+func baselineVars(vars []string, commands ...*ThresholdCommand) []string {
+	for _, c := range commands {
+		if c.BaselineVar != "" && !slices.Contains(vars, c.BaselineVar) {
+			vars = append(vars, c.BaselineVar)
+		}
+	}
+	return vars
+}
@@ -0,0 +1,204 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestBaselineThresholdExecute(t *testing.T) {
+	week := 7 * 24 * time.Hour
+	host := func(h string) data.Labels {
+		return data.Labels{"host": h}
+	}
+	seriesAt := func(refID string, labels data.Labels, start time.Time, values ...*float64) mathexp.Series {
+		s := mathexp.NewSeries(refID, labels, len(values))
+		for i, v := range values {
+			s.SetPoint(i, start.Add(time.Duration(i)*time.Minute), v)
+		}
+		return s
+	}
+	now := time.Unix(0, 0).Add(week)
+	resultNumber := func(labels data.Labels, value *float64) mathexp.Number {
+		n := mathexp.NewNumber("C", labels)
+		n.SetValue(value)
+		return n
+	}
+
+	testCases := []struct {
+		name     string
+		fn       ThresholdType
+		param    float64
+		offset   time.Duration
+		input    mathexp.Values
+		baseline mathexp.Values
+		expected mathexp.Values
+		errorMsg string
+	}{
+		{
+			name:  "percent change pairs items by labels",
+			fn:    ThresholdPercentChangeAbove,
+			param: 20,
+			input: mathexp.Values{
+				newNumber(host("a"), util.Pointer(130.0)),
+				newNumber(host("b"), util.Pointer(110.0)),
+				newNumber(host("c"), util.Pointer(110.0)),
+				newNumber(host("d"), util.Pointer(110.0)),
+			},
+			baseline: mathexp.Values{
+				newNumber(host("b"), util.Pointer(100.0)),
+				newNumber(host("a"), util.Pointer(100.0)),
+				newNumber(host("c"), util.Pointer(0.0)),
+			},
+			expected: mathexp.Values{
+				resultNumber(host("a"), util.Pointer(1.0)),
+				resultNumber(host("b"), util.Pointer(0.0)),
+				// zero or missing baseline has no percent change
+				resultNumber(host("c"), nil),
+				resultNumber(host("d"), nil),
+			},
+		},
+		{
+			name:  "percent change below uses the sign of the change",
+			fn:    ThresholdPercentChangeBelow,
+			param: -20,
+			input: mathexp.Values{
+				newNumber(host("a"), util.Pointer(-130.0)),
+				newNumber(host("b"), util.Pointer(70.0)),
+			},
+			baseline: mathexp.Values{
+				newNumber(host("a"), util.Pointer(-100.0)),
+				newNumber(host("b"), util.Pointer(100.0)),
+			},
+			expected: mathexp.Values{
+				resultNumber(host("a"), util.Pointer(1.0)),
+				resultNumber(host("b"), util.Pointer(1.0)),
+			},
+		},
+		{
+			name:  "single baseline applies to all items",
+			fn:    ThresholdRatioAbove,
+			param: 2,
+			input: mathexp.Values{
+				newNumber(host("a"), util.Pointer(30.0)),
+				newNumber(host("b"), util.Pointer(10.0)),
+			},
+			baseline: mathexp.Values{
+				newScalar(util.Pointer(10.0)),
+			},
+			expected: mathexp.Values{
+				resultNumber(host("a"), util.Pointer(1.0)),
+				resultNumber(host("b"), util.Pointer(0.0)),
+			},
+		},
+		{
+			name:   "ratio to last week pairs points shifted by the offset",
+			fn:     ThresholdRatioAbove,
+			param:  1.5,
+			offset: week,
+			input: mathexp.Values{
+				seriesAt("", host("a"), now, util.Pointer(10.0), util.Pointer(20.0), util.Pointer(30.0)),
+			},
+			baseline: mathexp.Values{
+				seriesAt("", host("a"), now.Add(-week), util.Pointer(10.0), util.Pointer(10.0)),
+			},
+			expected: mathexp.Values{
+				seriesAt("C", host("a"), now, util.Pointer(0.0), util.Pointer(1.0), nil),
+			},
+		},
+		{
+			name:  "z-score compares with the distribution of the baseline",
+			fn:    ThresholdZScoreAbove,
+			param: 2,
+			input: mathexp.Values{
+				newNumber(host("a"), util.Pointer(15.0)),
+				newNumber(host("b"), util.Pointer(5.0)),
+				newNumber(host("c"), util.Pointer(11.0)),
+			},
+			baseline: mathexp.Values{
+				seriesAt("", host("a"), now, util.Pointer(8.0), util.Pointer(12.0)),
+				seriesAt("", host("b"), now, util.Pointer(8.0), nil, util.Pointer(12.0)),
+				seriesAt("", host("c"), now, util.Pointer(8.0), util.Pointer(12.0)),
+			},
+			expected: mathexp.Values{
+				resultNumber(host("a"), util.Pointer(1.0)),
+				resultNumber(host("b"), util.Pointer(1.0)),
+				resultNumber(host("c"), util.Pointer(0.0)),
+			},
+		},
+		{
+			name:  "z-score requires a series baseline",
+			fn:    ThresholdZScoreAbove,
+			param: 2,
+			input: mathexp.Values{
+				newNumber(host("a"), util.Pointer(14.0)),
+			},
+			baseline: mathexp.Values{
+				newNumber(host("a"), util.Pointer(14.0)),
+			},
+			errorMsg: "must be a time series",
+		},
+		{
+			name:  "series baseline of a number should error",
+			fn:    ThresholdRatioAbove,
+			param: 2,
+			input: mathexp.Values{
+				newNumber(host("a"), util.Pointer(14.0)),
+			},
+			baseline: mathexp.Values{
+				seriesAt("", host("a"), now, util.Pointer(8.0)),
+			},
+			errorMsg: "Reduce the baseline first",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := NewBaselineThresholdCommand("C", "A", tc.fn, []float64{tc.param}, "B", tc.offset)
+			require.NoError(t, err)
+
+			result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+				"A": mathexp.Results{Values: tc.input},
+				"B": mathexp.Results{Values: tc.baseline},
+			}, tracing.InitializeTracerForTest(), nil)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result.Values)
+		})
+	}
+}
+
+func TestBaselineHysteresisExecute(t *testing.T) {
+	number := func(label string, value float64) mathexp.Number {
+		n := mathexp.NewNumber("C", data.Labels{"label": label})
+		n.SetValue(&value)
+		return n
+	}
+
+	loading, err := NewBaselineThresholdCommand("C", "A", ThresholdPercentChangeAbove, []float64{50}, "B", 0)
+	require.NoError(t, err)
+	unloading, err := NewBaselineThresholdCommand("C", "A", ThresholdPercentChangeAbove, []float64{10}, "B", 0)
+	require.NoError(t, err)
+	cmd, err := NewHysteresisCommand("C", "A", *loading, *unloading, Fingerprints{
+		data.Labels{"label": "loaded"}.Fingerprint(): {},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+
+	result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{number("loaded", 120), number("unloaded", 120)}},
+		"B": mathexp.Results{Values: mathexp.Values{number("loaded", 100), number("unloaded", 100)}},
+	}, tracing.InitializeTracerForTest(), nil)
+	require.NoError(t, err)
+	require.Equal(t, mathexp.Values{number("unloaded", 0), number("loaded", 1)}, result.Values)
+}
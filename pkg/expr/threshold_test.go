@@ -77,6 +77,12 @@ func TestNewThresholdCommand(t *testing.T) {
 			args:        []float64{0, 1},
 			shouldError: false,
 		},
+		{
+			fn:            "pct_change_gt",
+			args:          []float64{0},
+			shouldError:   true,
+			expectedError: "requires a baseline",
+		},
 		{
 			fn:            "gt",
 			args:          []float64{},
@@ -217,6 +223,98 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 			}`,
 			shouldError: true,
 		},
+		{
+			description: "unmarshal baseline threshold",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "ratio_gt",
+						"params": [1.5],
+						"baseline": "$B",
+						"baselineOffset": "1w"
+					}
+				}]
+			}`,
+			assert: func(t *testing.T, command Command) {
+				require.IsType(t, &ThresholdCommand{}, command)
+				cmd := command.(*ThresholdCommand)
+				require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+				require.Equal(t, ThresholdRatioAbove, cmd.ThresholdFunc)
+				require.Equal(t, "B", cmd.BaselineVar)
+				require.Equal(t, 7*24*time.Hour, cmd.BaselineOffset)
+				require.Equal(t, greaterThanPredicate{1.5}, cmd.predicate)
+			},
+		},
+		{
+			description: "unmarshal baseline threshold without baseline should error",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "zscore_gt",
+						"params": [3]
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "requires a baseline",
+		},
+		{
+			description: "unmarshal with baseline for a constant threshold should error",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [3],
+						"baseline": "B"
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "does not support a baseline",
+		},
+		{
+			description: "unmarshal with invalid baseline offset should error",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "pct_change_gt",
+						"params": [3],
+						"baseline": "B",
+						"baselineOffset": "last week"
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "baselineOffset",
+		},
+		{
+			description: "unmarshal as hysteresis command with baselines",
+			query: `{
+				  "expression": "A",
+				  "conditions": [
+				    {
+				      "evaluator": { "params": [20], "type": "pct_change_gt", "baseline": "B" },
+				      "unloadEvaluator": { "params": [10], "type": "pct_change_lt", "baseline": "C" }
+				    }
+				  ]
+				}`,
+			assert: func(t *testing.T, c Command) {
+				require.IsType(t, &HysteresisCommand{}, c)
+				cmd := c.(*HysteresisCommand)
+				require.Equal(t, []string{"A", "B", "C"}, cmd.NeedsVars())
+				require.Equal(t, "B", cmd.LoadingThresholdFunc.BaselineVar)
+				require.Equal(t, "C", cmd.UnloadingThresholdFunc.BaselineVar)
+				require.True(t, cmd.UnloadingThresholdFunc.Invert)
+			},
+		},
 		{
 			description: "unmarshal as hysteresis command if two evaluators",
 			query: `{
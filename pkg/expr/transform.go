@@ -19,6 +19,9 @@ type Request struct {
 	OrgId   int64
 	Queries []Query
 	User    identity.Requester
+	// CacheDatasourceResults allows the results of the data source queries of the request to be
+	// served from, and stored in, the data source result cache of the service, if it is enabled.
+	CacheDatasourceResults bool
 }
 
 // Query is like plugins.DataSubQuery, but with a a time range, and only the UID
@@ -7,6 +7,7 @@ import (
 	"io/fs"
 	"os"
 	"path"
+	"slices"
 	"strconv"
 	"time"
 
@@ -40,6 +41,7 @@ import (
 
 type AlertRuleService interface {
 	CleanUpDeletedAlertRules(ctx context.Context) (int64, error)
+	CleanUpStateHistory(ctx context.Context) (int64, error)
 }
 
 type CleanUpService struct {
@@ -141,6 +143,10 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		cleanupJobs = append(cleanupJobs, cleanUpJob{"cleanup trash alert rules", srv.cleanUpTrashAlertRules})
 	}
 
+	if usesSQLStateHistory(srv.Cfg.UnifiedAlerting.StateHistory) {
+		cleanupJobs = append(cleanupJobs, cleanUpJob{"cleanup alert state history", srv.cleanUpAlertStateHistory})
+	}
+
 	logger := srv.log.FromContext(ctx)
 	logger.Debug("Starting cleanup jobs", "jobs", fmt.Sprintf("%v", cleanupJobs))
 
@@ -422,6 +428,30 @@ func (srv *CleanUpService) cleanUpTrashAlertRules(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) cleanUpAlertStateHistory(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	affected, err := srv.alertRuleService.CleanUpStateHistory(ctx)
+	if err != nil {
+		logger.Error("Problem cleaning up alert state history", "error", err)
+	} else {
+		logger.Debug("Cleaned up alert state history", "rows affected", affected)
+	}
+}
+
+// usesSQLStateHistory returns true if alert state history is written to the database and has a retention.
+func usesSQLStateHistory(cfg setting.UnifiedAlertingStateHistorySettings) bool {
+	if !cfg.Enabled || cfg.SQLRetention <= 0 {
+		return false
+	}
+	if cfg.Backend == "sql" {
+		return true
+	}
+	if cfg.Backend != "multiple" {
+		return false
+	}
+	return cfg.MultiPrimary == "sql" || slices.Contains(cfg.MultiSecondaries, "sql")
+}
+
 // cleanupStaleLBACRules exists to clean up lbac rules that are stale from teams getting deleted as we do not have
 // cascading deletions on teams to delete existing lbac rules
 func (srv *CleanUpService) cleanupStaleLBACRules(ctx context.Context) {
@@ -36,3 +36,23 @@ func TestCleanUpTmpFiles(t *testing.T) {
 		require.False(t, service.shouldCleanupTempFile(weekAgo, now))
 	})
 }
+
+func TestUsesSQLStateHistory(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      setting.UnifiedAlertingStateHistorySettings
+		expected bool
+	}{
+		{"disabled", setting.UnifiedAlertingStateHistorySettings{Enabled: false, Backend: "sql", SQLRetention: time.Hour}, false},
+		{"sql backend", setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "sql", SQLRetention: time.Hour}, true},
+		{"no retention", setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "sql"}, false},
+		{"other backend", setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "loki", SQLRetention: time.Hour}, false},
+		{"sql primary", setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "multiple", MultiPrimary: "sql", SQLRetention: time.Hour}, true},
+		{"sql secondary", setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "multiple", MultiPrimary: "loki", MultiSecondaries: []string{"annotations", "sql"}, SQLRetention: time.Hour}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, usesSQLStateHistory(tc.cfg))
+		})
+	}
+}
@@ -17,10 +17,12 @@ import (
 	apiprometheus "github.com/grafana/grafana/pkg/services/ngalert/api/prometheus"
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -73,6 +75,9 @@ type API struct {
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
 	AlertRules           *provisioning.AlertRuleService
+	RuleTemplates        *provisioning.RuleTemplateService
+	MaintenanceWindows   *provisioning.MaintenanceWindowService
+	Imports              *provisioning.ImportService
 	AlertsRouter         *sender.AlertsRouter
 	EvaluatorFactory     eval.EvaluatorFactory
 	ConditionValidator   *eval.ConditionValidator
@@ -81,6 +86,10 @@ type API struct {
 	Tracer               tracing.Tracer
 	AppUrl               *url.URL
 	UserService          user.Service
+	// RecordingWriter writes the results of recording rules. It is used to backfill recording rules.
+	RecordingWriter schedule.RecordingWriter
+	// ImageStore is the built-in store of screenshots. It is nil if the store is disabled.
+	ImageStore *image.BlobImageStore
 
 	// Hooks can be used to replace API handlers for specific paths.
 	Hooks *Hooks
@@ -123,7 +132,12 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 				api.RuleStore,
 				ruleAuthzService,
 			),
-			receiverAuthz: accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
+			receiverAuthz:        accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
+			ruleStore:            api.RuleStore,
+			ruleAuthz:            ruleAuthzService,
+			ruleStates:           api.StateManager,
+			appURL:               api.AppUrl,
+			disableGrafanaFolder: api.Cfg.UnifiedAlerting.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel),
 		},
 		convertSrv,
 		api.FeatureManager,
@@ -151,6 +165,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			amRefresher:        api.MultiOrgAlertmanager,
 			featureManager:     api.FeatureManager,
 			userService:        api.UserService,
+			backfiller:         backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer, api.Cfg.UnifiedAlerting, api.FeatureManager),
+			recordingWriter:    api.RecordingWriter,
 		},
 	), m)
 	api.RegisterTestingApiEndpoints(NewTestingApi(
@@ -184,6 +200,9 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		ruleTemplates:       api.RuleTemplates,
+		maintenanceWindows:  api.MaintenanceWindows,
+		imports:             api.Imports,
 		// XXX: Used to flag recording rules, remove when FT is removed
 		featureManager: api.FeatureManager,
 	}), m)
@@ -194,4 +213,9 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	}), m)
 
 	api.RegisterConvertPrometheusApiEndpoints(NewConvertPrometheusApi(convertSrv), m)
+
+	// Images of the image store are served without authentication as their URLs are signed.
+	if api.ImageStore != nil {
+		api.RouteRegister.Get(image.ImageStoreRoutePath, routing.Wrap(api.ImageStore.RouteGetImage))
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -45,6 +46,13 @@ type AlertmanagerSrv struct {
 	silenceSvc     SilenceService
 	featureManager featuremgmt.FeatureToggles
 	receiverAuthz  receiversAuthz
+
+	// Used to simulate routing of the alerts of a rule.
+	ruleStore            RuleStore
+	ruleAuthz            RuleAccessControlService
+	ruleStates           ruleStateReader
+	appURL               *url.URL
+	disableGrafanaFolder bool
 }
 
 type UnknownReceiverError struct {
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+type ruleStateReader interface {
+	GetStatesForRuleUID(orgID int64, alertRuleUID string) []*state.State
+}
+
+func (srv AlertmanagerSrv) RoutePostGrafanaRoutingSimulation(c *contextmodel.ReqContext, body apimodels.RoutingSimulationBody) response.Response {
+	ctx := c.Req.Context()
+	at := time.Now()
+	if body.Time != nil {
+		at = *body.Time
+	}
+
+	lbls := data.Labels(body.Labels)
+	var rule *ngmodels.AlertRule
+	if body.RuleUID != "" {
+		var err error
+		rule, err = srv.ruleStore.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{UID: body.RuleUID, OrgID: c.GetOrgID()})
+		if err != nil {
+			if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule", err)
+		}
+		if err := srv.ruleAuthz.AuthorizeAccessInFolder(ctx, c.SignedInUser, rule); err != nil {
+			return errorToResponse(err)
+		}
+		if rule.Type() != ngmodels.RuleTypeAlerting {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("rule %s is not an alerting rule", rule.UID), "")
+		}
+		f, err := srv.ruleStore.GetNamespaceByUID(ctx, rule.NamespaceUID, c.GetOrgID(), c.SignedInUser)
+		if err != nil {
+			return toNamespaceErrorResponse(err)
+		}
+		extraLabels := state.GetRuleExtraLabels(srv.log, rule, f.Fullpath, !srv.disableGrafanaFolder, srv.featureManager)
+		lbls = state.GetAlertLabels(ctx, srv.log, rule, lbls, extraLabels, srv.appURL, at)
+	}
+
+	lset := make(model.LabelSet, len(lbls))
+	for k, v := range lbls {
+		lset[model.LabelName(k)] = model.LabelValue(v)
+	}
+	if err := lset.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid labels")
+	}
+
+	simulation, err := srv.mam.SimulateRouting(ctx, c.GetOrgID(), lset, at)
+	if err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) || errors.Is(err, notifier.ErrNoAlertmanagerForOrg) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to simulate routing", err)
+	}
+
+	result := newRoutingSimulationResult(lbls, at, simulation)
+	if rule != nil {
+		result.Inhibitions = append(result.Inhibitions, srv.simulateRuleInhibitions(rule, lbls)...)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// simulateRuleInhibitions returns the inhibitions of the rule. An inhibition is active if the inhibiting rule
+// has a firing alert with the same values of the equal labels.
+func (srv AlertmanagerSrv) simulateRuleInhibitions(rule *ngmodels.AlertRule, lbls data.Labels) []apimodels.SimulatedInhibition {
+	result := make([]apimodels.SimulatedInhibition, 0, len(rule.InhibitedBy))
+	for _, inhibition := range rule.InhibitedBy {
+		active := false
+		for _, s := range srv.ruleStates.GetStatesForRuleUID(rule.OrgID, inhibition.RuleUID) {
+			if s.IsFiring() && inhibition.Inhibits(s.Labels, lbls) {
+				active = true
+				break
+			}
+		}
+		result = append(result, apimodels.SimulatedInhibition{
+			Source:  "rule",
+			RuleUID: inhibition.RuleUID,
+			Equal:   inhibition.Equal,
+			Active:  active,
+		})
+	}
+	return result
+}
+
+func newRoutingSimulationResult(lbls data.Labels, at time.Time, simulation notifier.RoutingSimulation) apimodels.RoutingSimulationResult {
+	result := apimodels.RoutingSimulationResult{
+		Labels:      lbls,
+		Time:        at,
+		Routes:      make([]apimodels.SimulatedRoute, 0, len(simulation.Routes)),
+		Inhibitions: make([]apimodels.SimulatedInhibition, 0, len(simulation.Inhibitions)),
+	}
+	if result.Labels == nil {
+		result.Labels = map[string]string{}
+	}
+	toIntervals := func(intervals []notifier.SimulatedTimeInterval) []apimodels.SimulatedTimeInterval {
+		res := make([]apimodels.SimulatedTimeInterval, 0, len(intervals))
+		for _, i := range intervals {
+			res = append(res, apimodels.SimulatedTimeInterval{Name: i.Name, Active: i.Active})
+		}
+		return res
+	}
+	for _, r := range simulation.Routes {
+		result.Routes = append(result.Routes, apimodels.SimulatedRoute{
+			ID:                  r.ID,
+			Path:                r.Path,
+			Autogenerated:       r.Autogenerated,
+			Receiver:            r.Receiver,
+			GroupBy:             r.GroupBy,
+			GroupWait:           model.Duration(r.GroupWait),
+			GroupInterval:       model.Duration(r.GroupInterval),
+			RepeatInterval:      model.Duration(r.RepeatInterval),
+			MuteTimeIntervals:   toIntervals(r.MuteTimeIntervals),
+			ActiveTimeIntervals: toIntervals(r.ActiveTimeIntervals),
+			Muted:               r.Muted,
+		})
+	}
+	for _, i := range simulation.Inhibitions {
+		result.Inhibitions = append(result.Inhibitions, apimodels.SimulatedInhibition{
+			Source:         "alertmanager",
+			SourceMatchers: i.SourceMatchers,
+			Equal:          i.Equal,
+			Active:         i.Active,
+		})
+	}
+	return result
+}
@@ -34,6 +34,9 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	ruleTemplates       RuleTemplateService
+	maintenanceWindows  MaintenanceWindowService
+	imports             ImportService
 	folderSvc           folder.Service
 
 	// XXX: Used to flag recording rules, remove when FT is removed
@@ -81,6 +84,30 @@ type AlertRuleService interface {
 	GetAlertRuleWithFolderFullpath(ctx context.Context, u identity.Requester, ruleUID string) (provisioning.AlertRuleWithFolderFullpath, error)
 	GetAlertRuleGroupWithFolderFullpath(ctx context.Context, u identity.Requester, folder, group string) (alerting_models.AlertRuleGroupWithFolderFullpath, error)
 	GetAlertGroupsWithFolderFullpath(ctx context.Context, u identity.Requester, opts *provisioning.FilterOptions) ([]alerting_models.AlertRuleGroupWithFolderFullpath, error)
+	SetAlertRulesPaused(ctx context.Context, user identity.Requester, selector alerting_models.AlertRuleSelector, paused bool, provenance alerting_models.Provenance) ([]alerting_models.AlertRule, error)
+}
+
+type RuleTemplateService interface {
+	GetTemplates(ctx context.Context, orgID int64) ([]alerting_models.RuleTemplate, error)
+	GetTemplate(ctx context.Context, orgID int64, uid string) (alerting_models.RuleTemplate, error)
+	CreateTemplate(ctx context.Context, t alerting_models.RuleTemplate) (alerting_models.RuleTemplate, error)
+	UpdateTemplate(ctx context.Context, user identity.Requester, t alerting_models.RuleTemplate, provenance alerting_models.Provenance) (alerting_models.RuleTemplate, error)
+	DeleteTemplate(ctx context.Context, orgID int64, uid string) error
+	GetInstances(ctx context.Context, orgID int64, templateUID string) ([]alerting_models.RuleTemplateInstance, error)
+	CreateInstance(ctx context.Context, user identity.Requester, templateUID string, target alerting_models.AlertRule, values map[string]string, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
+	UpdateInstances(ctx context.Context, user identity.Requester, orgID int64, templateUID string, updates []alerting_models.RuleTemplateInstance, provenance alerting_models.Provenance) ([]alerting_models.AlertRule, error)
+}
+
+type MaintenanceWindowService interface {
+	GetWindows(ctx context.Context, orgID int64) ([]alerting_models.MaintenanceWindow, error)
+	GetWindow(ctx context.Context, orgID int64, uid string) (alerting_models.MaintenanceWindow, error)
+	CreateWindow(ctx context.Context, user identity.Requester, w alerting_models.MaintenanceWindow) (alerting_models.MaintenanceWindow, error)
+	UpdateWindow(ctx context.Context, w alerting_models.MaintenanceWindow) (alerting_models.MaintenanceWindow, error)
+	DeleteWindow(ctx context.Context, orgID int64, uid string) error
+}
+
+type ImportService interface {
+	Import(ctx context.Context, user identity.Requester, resources provisioning.ImportResources, provenance alerting_models.Provenance, dryRun bool) ([]provisioning.ImportChange, error)
 }
 
 func (srv *ProvisioningSrv) RouteGetPolicyTree(c *contextmodel.ReqContext) response.Response {
@@ -520,6 +547,174 @@ func (srv *ProvisioningSrv) RouteDeleteAlertRuleGroup(c *contextmodel.ReqContext
 	return response.JSON(http.StatusNoContent, "")
 }
 
+func (srv *ProvisioningSrv) RouteGetRuleTemplates(c *contextmodel.ReqContext) response.Response {
+	templates, err := srv.ruleTemplates.GetTemplates(c.Req.Context(), c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule templates", err)
+	}
+	result := make(definitions.RuleTemplates, 0, len(templates))
+	for _, t := range templates {
+		result = append(result, ApiRuleTemplateFromRuleTemplate(t))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	t, err := srv.ruleTemplates.GetTemplate(c.Req.Context(), c.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule template", err)
+	}
+	return response.JSON(http.StatusOK, ApiRuleTemplateFromRuleTemplate(t))
+}
+
+func (srv *ProvisioningSrv) RoutePostRuleTemplate(c *contextmodel.ReqContext, body definitions.RuleTemplate) response.Response {
+	created, err := srv.ruleTemplates.CreateTemplate(c.Req.Context(), RuleTemplateFromApiRuleTemplate(c.GetOrgID(), body))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create rule template", err)
+	}
+	return response.JSON(http.StatusCreated, ApiRuleTemplateFromRuleTemplate(created))
+}
+
+func (srv *ProvisioningSrv) RoutePutRuleTemplate(c *contextmodel.ReqContext, body definitions.RuleTemplate, UID string) response.Response {
+	body.UID = UID
+	provenance := determineProvenance(c)
+	updated, err := srv.ruleTemplates.UpdateTemplate(c.Req.Context(), c.SignedInUser, RuleTemplateFromApiRuleTemplate(c.GetOrgID(), body), alerting_models.Provenance(provenance))
+	if err != nil {
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update rule template", err)
+	}
+	return response.JSON(http.StatusOK, ApiRuleTemplateFromRuleTemplate(updated))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	if err := srv.ruleTemplates.DeleteTemplate(c.Req.Context(), c.GetOrgID(), UID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete rule template", err)
+	}
+	return response.JSON(http.StatusNoContent, "")
+}
+
+func (srv *ProvisioningSrv) RouteGetRuleTemplateInstances(c *contextmodel.ReqContext, UID string) response.Response {
+	instances, err := srv.ruleTemplates.GetInstances(c.Req.Context(), c.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule template instances", err)
+	}
+	return response.JSON(http.StatusOK, ApiRuleTemplateInstancesFromRuleTemplateInstances(instances))
+}
+
+func (srv *ProvisioningSrv) RoutePostRuleTemplateInstance(c *contextmodel.ReqContext, body definitions.PostableRuleTemplateInstance, UID string) response.Response {
+	target := alerting_models.AlertRule{
+		OrgID:        c.GetOrgID(),
+		UID:          body.RuleUID,
+		NamespaceUID: body.FolderUID,
+		RuleGroup:    body.RuleGroup,
+	}
+	provenance := determineProvenance(c)
+	created, err := srv.ruleTemplates.CreateInstance(c.Req.Context(), c.SignedInUser, UID, target, body.Values, alerting_models.Provenance(provenance))
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, alerting_models.ErrQuotaReached) {
+			return ErrResp(http.StatusForbidden, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create alert rule from rule template", err)
+	}
+	return response.JSON(http.StatusCreated, ProvisionedAlertRuleFromAlertRule(created, alerting_models.Provenance(provenance)))
+}
+
+func (srv *ProvisioningSrv) RoutePutRuleTemplateInstances(c *contextmodel.ReqContext, body []definitions.RuleTemplateInstance, UID string) response.Response {
+	updates := make([]alerting_models.RuleTemplateInstance, 0, len(body))
+	for _, i := range body {
+		updates = append(updates, alerting_models.RuleTemplateInstance{RuleUID: i.RuleUID, Values: i.Values})
+	}
+	provenance := determineProvenance(c)
+	updated, err := srv.ruleTemplates.UpdateInstances(c.Req.Context(), c.SignedInUser, c.GetOrgID(), UID, updates, alerting_models.Provenance(provenance))
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update rule template instances", err)
+	}
+	result := make(definitions.ProvisionedAlertRules, 0, len(updated))
+	for _, rule := range updated {
+		result = append(result, ProvisionedAlertRuleFromAlertRule(rule, alerting_models.Provenance(provenance)))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RoutePostPauseAlertRules(c *contextmodel.ReqContext, body definitions.AlertRuleSelector) response.Response {
+	return srv.setAlertRulesPaused(c, body, true)
+}
+
+func (srv *ProvisioningSrv) RoutePostResumeAlertRules(c *contextmodel.ReqContext, body definitions.AlertRuleSelector) response.Response {
+	return srv.setAlertRulesPaused(c, body, false)
+}
+
+func (srv *ProvisioningSrv) setAlertRulesPaused(c *contextmodel.ReqContext, body definitions.AlertRuleSelector, paused bool) response.Response {
+	provenance := determineProvenance(c)
+	updated, err := srv.alertRules.SetAlertRulesPaused(c.Req.Context(), c.SignedInUser, AlertRuleSelectorFromApiAlertRuleSelector(body), paused, alerting_models.Provenance(provenance))
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update alert rules", err)
+	}
+	result := make(definitions.ProvisionedAlertRules, 0, len(updated))
+	for _, rule := range updated {
+		result = append(result, ProvisionedAlertRuleFromAlertRule(rule, alerting_models.Provenance(provenance)))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindows(c *contextmodel.ReqContext) response.Response {
+	windows, err := srv.maintenanceWindows.GetWindows(c.Req.Context(), c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get maintenance windows", err)
+	}
+	result := make(definitions.MaintenanceWindows, 0, len(windows))
+	for _, w := range windows {
+		result = append(result, ApiMaintenanceWindowFromMaintenanceWindow(w))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindow(c *contextmodel.ReqContext, UID string) response.Response {
+	w, err := srv.maintenanceWindows.GetWindow(c.Req.Context(), c.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get maintenance window", err)
+	}
+	return response.JSON(http.StatusOK, ApiMaintenanceWindowFromMaintenanceWindow(w))
+}
+
+func (srv *ProvisioningSrv) RoutePostMaintenanceWindow(c *contextmodel.ReqContext, body definitions.MaintenanceWindow) response.Response {
+	created, err := srv.maintenanceWindows.CreateWindow(c.Req.Context(), c.SignedInUser, MaintenanceWindowFromApiMaintenanceWindow(c.GetOrgID(), body))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create maintenance window", err)
+	}
+	return response.JSON(http.StatusCreated, ApiMaintenanceWindowFromMaintenanceWindow(created))
+}
+
+func (srv *ProvisioningSrv) RoutePutMaintenanceWindow(c *contextmodel.ReqContext, body definitions.MaintenanceWindow, UID string) response.Response {
+	body.UID = UID
+	updated, err := srv.maintenanceWindows.UpdateWindow(c.Req.Context(), MaintenanceWindowFromApiMaintenanceWindow(c.GetOrgID(), body))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update maintenance window", err)
+	}
+	return response.JSON(http.StatusOK, ApiMaintenanceWindowFromMaintenanceWindow(updated))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteMaintenanceWindow(c *contextmodel.ReqContext, UID string) response.Response {
+	if err := srv.maintenanceWindows.DeleteWindow(c.Req.Context(), c.GetOrgID(), UID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete maintenance window", err)
+	}
+	return response.JSON(http.StatusNoContent, "")
+}
+
 func determineProvenance(ctx *contextmodel.ReqContext) definitions.Provenance {
 	if _, disabled := ctx.Req.Header[disableProvenanceHeaderName]; disabled {
 		return definitions.Provenance(alerting_models.ProvenanceNone)
@@ -558,7 +753,7 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 		return exportHcl(params.Download, body)
 	}
 
-	body = escapeAlertingFileExport(body)
+	body = escapeAlertingFileExport(body, addEscapeCharactersToString)
 	if params.Download {
 		r := response.JSONDownload
 		if params.Format == "yaml" {
@@ -574,7 +769,7 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 	return r(http.StatusOK, body)
 }
 
-// escape all strings except:
+// escapeAlertingFileExport applies escape to all strings except:
 // Alert rule annotations: groups[].rules[].annotations
 // Alert rule time range: groups[].rules[].relativeTimeRange
 // Alert rule query model: groups[].rules[].data.model
@@ -582,70 +777,70 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 // Mute timings time intervals: muteTimes[].time_intervals[]
 // Notification template name: templates[].name
 // Notification template content: templates[].template
-func escapeAlertingFileExport(body definitions.AlertingFileExport) definitions.AlertingFileExport {
+func escapeAlertingFileExport(body definitions.AlertingFileExport, escape func(string) string) definitions.AlertingFileExport {
 	for i, group := range body.Groups {
-		body.Groups[i] = escapeRuleGroup(group)
+		body.Groups[i] = escapeRuleGroup(group, escape)
 	}
 	for i, cp := range body.ContactPoints {
-		body.ContactPoints[i] = escapeContactPoint(cp)
+		body.ContactPoints[i] = escapeContactPoint(cp, escape)
 	}
 	for i, np := range body.Policies {
-		body.Policies[i] = escapeNotificationPolicy(np)
+		body.Policies[i] = escapeNotificationPolicy(np, escape)
 	}
 	return body
 }
 
-func escapeRouteExport(r *definitions.RouteExport) {
-	r.Receiver = addEscapeCharactersToString(r.Receiver)
+func escapeRouteExport(r *definitions.RouteExport, escape func(string) string) {
+	r.Receiver = escape(r.Receiver)
 	if r.GroupByStr != nil {
 		groupByStr := make([]string, len(*r.GroupByStr))
 		for i, groupBy := range *r.GroupByStr {
-			groupByStr[i] = addEscapeCharactersToString(groupBy)
+			groupByStr[i] = escape(groupBy)
 		}
 		r.GroupByStr = &groupByStr
 	}
 	for k, v := range r.Match {
-		r.Match[k] = addEscapeCharactersToString(v)
+		r.Match[k] = escape(v)
 	}
 	for k, v := range r.MatchRE {
 		// convert regex to string, escape then covert back to regex
-		stringRepr := addEscapeCharactersToString(v.String())
+		stringRepr := escape(v.String())
 		mutated := regexp.MustCompile(stringRepr)
 		r.MatchRE[k] = alertmanager_config.Regexp{Regexp: mutated}
 	}
 	if r.MuteTimeIntervals != nil {
 		muteTimeIntervals := make([]string, len(*r.MuteTimeIntervals))
 		for i, muteTimeInterval := range *r.MuteTimeIntervals {
-			muteTimeIntervals[i] = addEscapeCharactersToString(muteTimeInterval)
+			muteTimeIntervals[i] = escape(muteTimeInterval)
 		}
 		r.MuteTimeIntervals = &muteTimeIntervals
 	}
 	if r.ActiveTimeIntervals != nil {
 		intervals := make([]string, len(*r.ActiveTimeIntervals))
 		for i, timeInterval := range *r.ActiveTimeIntervals {
-			intervals[i] = addEscapeCharactersToString(timeInterval)
+			intervals[i] = escape(timeInterval)
 		}
 		r.ActiveTimeIntervals = &intervals
 	}
 	for i := range r.Routes {
-		escapeRouteExport(r.Routes[i])
+		escapeRouteExport(r.Routes[i], escape)
 	}
 }
 
-func escapeNotificationPolicy(np definitions.NotificationPolicyExport) definitions.NotificationPolicyExport {
-	escapeRouteExport(np.RouteExport)
+func escapeNotificationPolicy(np definitions.NotificationPolicyExport, escape func(string) string) definitions.NotificationPolicyExport {
+	escapeRouteExport(np.RouteExport, escape)
 	return np
 }
 
-func escapeContactPoint(cp definitions.ContactPointExport) definitions.ContactPointExport {
-	cp.Name = addEscapeCharactersToString(cp.Name)
+func escapeContactPoint(cp definitions.ContactPointExport, escape func(string) string) definitions.ContactPointExport {
+	cp.Name = escape(cp.Name)
 	for i, receiver := range cp.Receivers {
 		settingsJson, err := receiver.Settings.MarshalJSON()
 		if err != nil {
 			// This should never happen, as the settings are already marshaled to JSON in the API
 			panic(fmt.Errorf("failed to marshal settings to JSON: %w", err))
 		}
-		settingsEscaped := []byte(addEscapeCharactersToString(string(settingsJson)))
+		settingsEscaped := []byte(escape(string(settingsJson)))
 		if err := cp.Receivers[i].Settings.UnmarshalJSON(settingsEscaped); err != nil {
 			// This should never happen, as the settings are already marshaled to JSON in the API
 			panic(fmt.Errorf("failed to unmarshal settings from JSON: %w", err))
@@ -658,48 +853,48 @@ func escapeContactPoint(cp definitions.ContactPointExport) definitions.ContactPo
 // Alert rule annotations: groups[].rules[].annotations
 // Alert rule time range: groups[].rules[].relativeTimeRange
 // Alert rule query model: groups[].rules[].data.model
-func escapeRuleGroup(group definitions.AlertRuleGroupExport) definitions.AlertRuleGroupExport {
-	group.Name = addEscapeCharactersToString(group.Name)
-	group.Folder = addEscapeCharactersToString(group.Folder)
+func escapeRuleGroup(group definitions.AlertRuleGroupExport, escape func(string) string) definitions.AlertRuleGroupExport {
+	group.Name = escape(group.Name)
+	group.Folder = escape(group.Folder)
 	for i, rule := range group.Rules {
-		group.Rules[i].Title = addEscapeCharactersToString(rule.Title)
+		group.Rules[i].Title = escape(rule.Title)
 		if rule.Labels != nil {
-			group.Rules[i].Labels = escapeMapValues(*rule.Labels)
+			group.Rules[i].Labels = escapeMapValues(*rule.Labels, escape)
 		}
 		if rule.NotificationSettings != nil {
-			notificationSettings := escapeRuleNotificationSettings(*rule.NotificationSettings)
+			notificationSettings := escapeRuleNotificationSettings(*rule.NotificationSettings, escape)
 			group.Rules[i].NotificationSettings = &notificationSettings
 		}
 	}
 	return group
 }
 
-func escapeRuleNotificationSettings(ns definitions.AlertRuleNotificationSettingsExport) definitions.AlertRuleNotificationSettingsExport {
-	ns.Receiver = addEscapeCharactersToString(ns.Receiver)
+func escapeRuleNotificationSettings(ns definitions.AlertRuleNotificationSettingsExport, escape func(string) string) definitions.AlertRuleNotificationSettingsExport {
+	ns.Receiver = escape(ns.Receiver)
 	if ns.GroupBy != nil {
 		for j := range *ns.GroupBy {
-			(*ns.GroupBy)[j] = addEscapeCharactersToString((*ns.GroupBy)[j])
+			(*ns.GroupBy)[j] = escape((*ns.GroupBy)[j])
 		}
 	}
 
 	if ns.MuteTimeIntervals != nil {
 		for k := range *ns.MuteTimeIntervals {
-			(*ns.MuteTimeIntervals)[k] = addEscapeCharactersToString((*ns.MuteTimeIntervals)[k])
+			(*ns.MuteTimeIntervals)[k] = escape((*ns.MuteTimeIntervals)[k])
 		}
 	}
 
 	if ns.ActiveTimeIntervals != nil {
 		for k := range *ns.ActiveTimeIntervals {
-			(*ns.ActiveTimeIntervals)[k] = addEscapeCharactersToString((*ns.ActiveTimeIntervals)[k])
+			(*ns.ActiveTimeIntervals)[k] = escape((*ns.ActiveTimeIntervals)[k])
 		}
 	}
 	return ns
 }
 
-func escapeMapValues(m map[string]string) *map[string]string {
+func escapeMapValues(m map[string]string, escape func(string) string) *map[string]string {
 	escapedMap := make(map[string]string, len(m))
 	for k, v := range m {
-		escapedMap[k] = addEscapeCharactersToString(v)
+		escapedMap[k] = escape(v)
 	}
 	return &escapedMap
 }
@@ -708,6 +903,10 @@ func addEscapeCharactersToString(s string) string {
 	return strings.ReplaceAll(s, "$", "$$")
 }
 
+func removeEscapeCharactersFromString(s string) string {
+	return strings.ReplaceAll(s, "$$", "$")
+}
+
 func exportHcl(download bool, body definitions.AlertingFileExport) response.Response {
 	resources := make([]hcl.Resource, 0, len(body.Groups)+len(body.ContactPoints)+len(body.Policies)+len(body.MuteTimings))
 	convertToResources := func() error {
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	. "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/hcl"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+var errImportInvalidBody = errutil.BadRequest("alerting.import.invalidBody")
+
+func (srv *ProvisioningSrv) RoutePostImport(c *contextmodel.ReqContext, body definitions.AlertingFileExport) response.Response {
+	resources, err := importResourcesFromAlertingFileExport(body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	dryRun := c.QueryBoolWithDefault("dryRun", false)
+	provenance := determineProvenance(c)
+	changes, err := srv.imports.Import(c.Req.Context(), c.SignedInUser, resources, alerting_models.Provenance(provenance), dryRun)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		if errors.Is(err, alerting_models.ErrQuotaReached) {
+			return ErrResp(http.StatusForbidden, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to import alerting resources", err)
+	}
+
+	result := definitions.ImportResult{
+		Applied: !dryRun,
+		Changes: make([]definitions.ImportChange, 0, len(changes)),
+	}
+	for _, change := range changes {
+		result.Changes = append(result.Changes, definitions.ImportChange{
+			Type:   string(change.Type),
+			Name:   change.Name,
+			Action: string(change.Action),
+			Diff:   change.Diff,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// parseImportRequest reads the body of an import request in the format given by the format query parameter, or else
+// by the Content-Type header. YAML is assumed if neither is set.
+func parseImportRequest(c *contextmodel.ReqContext) (definitions.AlertingFileExport, error) {
+	var result definitions.AlertingFileExport
+	body, err := io.ReadAll(c.Req.Body)
+	if err != nil {
+		return result, err
+	}
+	defer func() { _ = c.Req.Body.Close() }()
+
+	format := c.Query("format")
+	if format == "" {
+		if contentType := c.Req.Header.Get("Content-Type"); contentType != "" {
+			m, _, err := mime.ParseMediaType(contentType)
+			if err != nil {
+				return result, errImportInvalidBody.Errorf("invalid content type: %w", err)
+			}
+			switch m {
+			case "application/yaml", "application/x-yaml", "text/yaml":
+				format = "yaml"
+			case "application/json":
+				format = "json"
+			case "text/hcl", "application/terraform+hcl":
+				format = "hcl"
+			default:
+				return result, errorUnsupportedMediaType.Errorf("unsupported media type: %s, only yaml, json and hcl are supported", m)
+			}
+		}
+	}
+
+	switch format {
+	case "yaml", "":
+		err = yaml.Unmarshal(body, &result)
+	case "json":
+		err = json.Unmarshal(body, &result)
+	case "hcl":
+		result, err = alertingFileExportFromHcl(body)
+		if err != nil {
+			return result, errImportInvalidBody.Errorf("%w", err)
+		}
+		return result, nil
+	default:
+		return result, errorUnsupportedMediaType.Errorf("unsupported format: %s, only yaml, json and hcl are supported", format)
+	}
+	if err != nil {
+		return result, errImportInvalidBody.Errorf("failed to parse %s: %w", format, err)
+	}
+	// YAML and JSON exports escape the $ character, see exportResponse.
+	return escapeAlertingFileExport(result, removeEscapeCharactersFromString), nil
+}
+
+// alertingFileExportFromHcl converts the resources produced by exportHcl back to the file export model.
+func alertingFileExportFromHcl(data []byte) (definitions.AlertingFileExport, error) {
+	result := definitions.AlertingFileExport{APIVersion: 1}
+	resources, err := hcl.Decode(data, "import.tf", func(resourceType string) (interface{}, error) {
+		switch resourceType {
+		case "grafana_rule_group":
+			return &definitions.AlertRuleGroupExport{}, nil
+		case "grafana_contact_point":
+			return &definitions.ContactPoint{}, nil
+		case "grafana_notification_policy":
+			return &definitions.RouteExport{}, nil
+		case "grafana_mute_timing":
+			return &definitions.MuteTimeIntervalExportHcl{}, nil
+		}
+		return nil, fmt.Errorf("unsupported resource type %s", resourceType)
+	})
+	if err != nil {
+		return result, err
+	}
+	for _, resource := range resources {
+		switch body := resource.Body.(type) {
+		case *definitions.AlertRuleGroupExport:
+			result.Groups = append(result.Groups, *body)
+		case *definitions.ContactPoint:
+			cp, err := ContactPointExportFromContactPoint(*body)
+			if err != nil {
+				return result, fmt.Errorf("failed to convert contact point %s: %w", body.Name, err)
+			}
+			result.ContactPoints = append(result.ContactPoints, cp)
+		case *definitions.RouteExport:
+			result.Policies = append(result.Policies, definitions.NotificationPolicyExport{RouteExport: body})
+		case *definitions.MuteTimeIntervalExportHcl:
+			mt, err := MuteTimeIntervalFromMuteTimeIntervalExportHcl(*body)
+			if err != nil {
+				return result, fmt.Errorf("failed to convert mute timing %s: %w", body.Name, err)
+			}
+			result.MuteTimings = append(result.MuteTimings, definitions.MuteTimeIntervalExport{MuteTimeInterval: mt.MuteTimeInterval})
+		}
+	}
+	return result, nil
+}
+
+// importResourcesFromAlertingFileExport converts the file export model to the resources of the import service.
+// The organization IDs of the file are ignored, resources are always imported to the organization of the request.
+func importResourcesFromAlertingFileExport(body definitions.AlertingFileExport) (provisioning.ImportResources, error) {
+	var result provisioning.ImportResources
+	for _, g := range body.Groups {
+		group, err := AlertRuleGroupFromAlertRuleGroupExport(g)
+		if err != nil {
+			return result, fmt.Errorf("invalid rule group %s: %w", g.Name, err)
+		}
+		result.RuleGroups = append(result.RuleGroups, alerting_models.AlertRuleGroupWithFolderFullpath{
+			AlertRuleGroup: &group,
+			FolderFullpath: g.Folder,
+		})
+	}
+	for _, cp := range body.ContactPoints {
+		integrations, err := EmbeddedContactPointsFromContactPointExport(cp)
+		if err != nil {
+			return result, fmt.Errorf("invalid contact point %s: %w", cp.Name, err)
+		}
+		result.ContactPoints = append(result.ContactPoints, integrations...)
+	}
+	if len(body.Policies) > 1 {
+		return result, fmt.Errorf("expected at most one notification policy tree, got %d", len(body.Policies))
+	}
+	if len(body.Policies) == 1 && body.Policies[0].RouteExport != nil {
+		route, err := RouteFromRouteExport(body.Policies[0].RouteExport)
+		if err != nil {
+			return result, fmt.Errorf("invalid notification policy tree: %w", err)
+		}
+		result.Policies = &route
+	}
+	for _, mt := range body.MuteTimings {
+		result.MuteTimings = append(result.MuteTimings, definitions.MuteTimeInterval{MuteTimeInterval: mt.MuteTimeInterval})
+	}
+	return result, nil
+}
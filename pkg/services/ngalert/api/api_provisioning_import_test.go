@@ -0,0 +1,223 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+func TestParseImportRequest(t *testing.T) {
+	parse := func(t *testing.T, data []byte, format string) provisioning.ImportResources {
+		t.Helper()
+		rc := createTestRequestCtx()
+		rc.Req.Body = io.NopCloser(bytes.NewReader(data))
+		rc.Req.Form.Set("format", format)
+		body, err := parseImportRequest(&rc)
+		require.NoError(t, err)
+		resources, err := importResourcesFromAlertingFileExport(body)
+		require.NoError(t, err)
+		return resources
+	}
+	readFile := func(t *testing.T, name string) []byte {
+		t.Helper()
+		data, err := testData.ReadFile(path.Join("test-data", name))
+		require.NoError(t, err)
+		return data
+	}
+
+	t.Run("rule groups are the same in all formats", func(t *testing.T) {
+		fromYaml := parse(t, readFile(t, "post-rulegroup-101-export.yaml"), "yaml")
+		fromJson := parse(t, readFile(t, "post-rulegroup-101-export.json"), "json")
+		fromHcl := parse(t, readFile(t, "post-rulegroup-101-export.hcl"), "hcl")
+
+		require.Len(t, fromYaml.RuleGroups, 1)
+		require.Equal(t, "foo bar", fromYaml.RuleGroups[0].FolderFullpath)
+		require.Empty(t, fromYaml.RuleGroups[0].FolderUID)
+		require.Len(t, fromHcl.RuleGroups, 1)
+		require.Equal(t, "e4584834-1a87-4dff-8913-8a4748dfca79", fromHcl.RuleGroups[0].FolderUID)
+
+		// HCL refers to the folder by UID, and YAML and JSON by full path. HCL does not export rule UIDs,
+		// they are matched by title by the import service.
+		normalize := func(r provisioning.ImportResources) models.AlertRuleGroup {
+			g := *r.RuleGroups[0].AlertRuleGroup
+			g.FolderUID = ""
+			for i := range g.Rules {
+				g.Rules[i].NamespaceUID = ""
+				g.Rules[i].UID = ""
+				for j := range g.Rules[i].Data {
+					model := map[string]any{}
+					require.NoError(t, json.Unmarshal(g.Rules[i].Data[j].Model, &model))
+					g.Rules[i].Data[j].Model, _ = json.Marshal(model)
+				}
+			}
+			return g
+		}
+		require.Equal(t, normalize(fromYaml), normalize(fromJson))
+		require.Equal(t, normalize(fromYaml), normalize(fromHcl))
+		require.Equal(t, int64(10), fromYaml.RuleGroups[0].Interval)
+	})
+
+	t.Run("mute timings are the same in all formats", func(t *testing.T) {
+		fromYaml := parse(t, readFile(t, "alertmanager_default_mutetimings-export.yaml"), "yaml")
+		fromJson := parse(t, readFile(t, "alertmanager_default_mutetimings-export.json"), "json")
+		fromHcl := parse(t, readFile(t, "alertmanager_default_mutetimings-export.hcl"), "hcl")
+
+		require.Len(t, fromYaml.MuteTimings, 2)
+		require.Equal(t, fromYaml.MuteTimings, fromJson.MuteTimings)
+		// HCL does not distinguish between empty and missing blocks.
+		fromYaml.MuteTimings[0].TimeIntervals = nil
+		require.Equal(t, fromYaml.MuteTimings, fromHcl.MuteTimings)
+	})
+
+	t.Run("contact points are the same in YAML and JSON", func(t *testing.T) {
+		read := func(t *testing.T, format string) []definitions.EmbeddedContactPoint {
+			data, err := receiverExportResponses.ReadFile(path.Join("test-data", "receiver-exports", "redacted", "all-integrations."+format))
+			require.NoError(t, err)
+			return parse(t, data, format).ContactPoints
+		}
+		fromYaml := read(t, "yaml")
+		fromJson := read(t, "json")
+		require.NotEmpty(t, fromYaml)
+		require.Len(t, fromJson, len(fromYaml))
+		for i, expected := range fromYaml {
+			actual := fromJson[i]
+			require.Equal(t, expected.UID, actual.UID)
+			require.Equal(t, expected.Name, actual.Name)
+			require.Equal(t, expected.Type, actual.Type)
+			require.Equal(t, expected.DisableResolveMessage, actual.DisableResolveMessage)
+			expectedSettings, err := expected.Settings.MarshalJSON()
+			require.NoError(t, err)
+			actualSettings, err := actual.Settings.MarshalJSON()
+			require.NoError(t, err)
+			require.JSONEq(t, string(expectedSettings), string(actualSettings))
+		}
+	})
+
+	t.Run("HCL export is parsed back to the same resources", func(t *testing.T) {
+		files := map[string][]byte{
+			"rule group":         readFile(t, "post-rulegroup-101-export.hcl"),
+			"mute timings":       readFile(t, "alertmanager_default_mutetimings-export.hcl"),
+			"simplified routing": readFile(t, "post-rulegroup-simplified-routing-export.hcl"),
+		}
+		contactPoints, err := receiverExportResponses.ReadFile(path.Join("test-data", "receiver-exports", "redacted", "all-integrations.hcl"))
+		require.NoError(t, err)
+		files["contact points"] = contactPoints
+
+		for name, data := range files {
+			t.Run(name, func(t *testing.T) {
+				body, err := alertingFileExportFromHcl(data)
+				require.NoError(t, err)
+				_, err = importResourcesFromAlertingFileExport(body)
+				require.NoError(t, err)
+
+				response := exportHcl(false, body)
+				require.Equal(t, http.StatusOK, response.Status())
+				require.Equal(t, string(data), string(response.Body()))
+			})
+		}
+	})
+
+	t.Run("escaped characters are restored", func(t *testing.T) {
+		resources := parse(t, []byte(`apiVersion: 1
+policies:
+  - orgId: 1
+    receiver: receiver-$$1
+    group_by: ["$$label"]
+    matchers:
+      - team = $$team
+`), "yaml")
+		require.NotNil(t, resources.Policies)
+		require.Equal(t, "receiver-$1", resources.Policies.Receiver)
+		require.Equal(t, []string{"$label"}, resources.Policies.GroupByStr)
+	})
+
+	t.Run("format is taken from content type", func(t *testing.T) {
+		rc := createTestRequestCtx()
+		rc.Req.Body = io.NopCloser(bytes.NewReader(readFile(t, "alertmanager_default_mutetimings-export.hcl")))
+		rc.Req.Header.Set("Content-Type", "text/hcl")
+		body, err := parseImportRequest(&rc)
+		require.NoError(t, err)
+		require.Len(t, body.MuteTimings, 2)
+	})
+
+	t.Run("fails on invalid body", func(t *testing.T) {
+		rc := createTestRequestCtx()
+		rc.Req.Body = io.NopCloser(bytes.NewReader([]byte(`resource "grafana_unknown" "a" {}`)))
+		rc.Req.Form.Set("format", "hcl")
+		_, err := parseImportRequest(&rc)
+		require.ErrorIs(t, err, errImportInvalidBody)
+	})
+
+	t.Run("fails on unsupported content type", func(t *testing.T) {
+		rc := createTestRequestCtx()
+		rc.Req.Body = io.NopCloser(bytes.NewReader(nil))
+		rc.Req.Header.Set("Content-Type", "text/plain")
+		_, err := parseImportRequest(&rc)
+		require.ErrorIs(t, err, errorUnsupportedMediaType)
+	})
+}
+
+func TestRoutePostImport(t *testing.T) {
+	body := definitions.AlertingFileExport{
+		MuteTimings: []definitions.MuteTimeIntervalExport{{OrgID: 2}},
+	}
+	body.MuteTimings[0].Name = "weekends"
+
+	t.Run("returns the changes", func(t *testing.T) {
+		imports := &fakeImportService{changes: []provisioning.ImportChange{
+			{Type: provisioning.ImportResourceMuteTiming, Name: "weekends", Action: provisioning.ImportActionUpdate, Diff: []string{"time_intervals"}},
+		}}
+		sut := ProvisioningSrv{imports: imports}
+		rc := createTestRequestCtx()
+		rc.Req.Form.Set("dryRun", "true")
+
+		response := sut.RoutePostImport(&rc, body)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.JSONEq(t, `{"applied":false,"changes":[{"type":"mute_timing","name":"weekends","action":"update","diff":["time_intervals"]}]}`, string(response.Body()))
+		require.True(t, imports.dryRun)
+		require.Equal(t, models.ProvenanceAPI, imports.provenance)
+		require.Len(t, imports.resources.MuteTimings, 1)
+		require.Equal(t, "weekends", imports.resources.MuteTimings[0].Name)
+	})
+
+	t.Run("returns 400 on validation errors", func(t *testing.T) {
+		imports := &fakeImportService{err: provisioning.ErrValidation}
+		sut := ProvisioningSrv{imports: imports}
+		rc := createTestRequestCtx()
+		rc.Req.Header.Add(disableProvenanceHeaderName, "true")
+
+		response := sut.RoutePostImport(&rc, body)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.False(t, imports.dryRun)
+		require.Equal(t, models.ProvenanceNone, imports.provenance)
+	})
+}
+
+type fakeImportService struct {
+	changes []provisioning.ImportChange
+	err     error
+
+	resources  provisioning.ImportResources
+	provenance models.Provenance
+	dryRun     bool
+}
+
+func (f *fakeImportService) Import(_ context.Context, _ identity.Requester, resources provisioning.ImportResources, provenance models.Provenance, dryRun bool) ([]provisioning.ImportChange, error) {
+	f.resources = resources
+	f.provenance = provenance
+	f.dryRun = dryRun
+	return f.changes, f.err
+}
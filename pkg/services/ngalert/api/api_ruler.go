@@ -26,10 +26,12 @@ import (
 	. "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	apivalidation "github.com/grafana/grafana/pkg/services/ngalert/api/validation"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -50,6 +52,11 @@ type AMRefresher interface {
 	ApplyConfig(ctx context.Context, orgId int64, dbConfig *ngmodels.AlertConfiguration) error
 }
 
+// RuleBackfiller evaluates a recording rule over a past time range and writes the results.
+type RuleBackfiller interface {
+	Backfill(ctx context.Context, user identity.Requester, rule *ngmodels.AlertRule, from, to time.Time, writer schedule.RecordingWriter) (*backtesting.BackfillResult, error)
+}
+
 type RulerSrv struct {
 	xactManager        provisioning.TransactionManager
 	provenanceStore    provisioning.ProvisioningStore
@@ -64,6 +71,9 @@ type RulerSrv struct {
 	amConfigStore  AMConfigStore
 	amRefresher    AMRefresher
 	featureManager featuremgmt.FeatureToggles
+
+	backfiller      RuleBackfiller
+	recordingWriter schedule.RecordingWriter
 }
 
 var (
@@ -395,6 +405,60 @@ func (srv RulerSrv) RouteGetRuleVersionsByUID(c *contextmodel.ReqContext, ruleUI
 	return response.JSON(http.StatusOK, result)
 }
 
+// RoutePostRuleBackfillByUID evaluates the recording rule at every tick of the requested range and writes the results
+// to its target data sources.
+func (srv RulerSrv) RoutePostRuleBackfillByUID(c *contextmodel.ReqContext, body apimodels.PostableRuleBackfill, ruleUID string) response.Response {
+	if !srv.cfg.RecordingRules.Enabled || srv.backfiller == nil || srv.recordingWriter == nil {
+		return ErrResp(http.StatusBadRequest, errors.New("recording rules are not enabled"), "")
+	}
+
+	ctx := c.Req.Context()
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+	}
+	// Backfilling writes to the targets of the rule on behalf of the user, so it requires the same permissions as updating the rule.
+	if err := srv.authz.AuthorizeRuleChanges(ctx, c.SignedInUser, &store.GroupDelta{
+		GroupKey: rule.GetGroupKey(),
+		Update:   []store.RuleDelta{{Existing: &rule, New: &rule}},
+	}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize the backfill of the rule", err)
+	}
+	if err := srv.authz.AuthorizeDatasourceAccessForRule(ctx, c.SignedInUser, &rule); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize the backfill of the rule", err)
+	}
+
+	result, err := srv.backfiller.Backfill(ctx, c.SignedInUser, &rule, body.From, body.To, srv.recordingWriter)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to backfill rule")
+	}
+
+	targets := make([]apimodels.RuleBackfillTarget, 0, len(result.Targets))
+	for _, target := range result.Targets {
+		t := apimodels.RuleBackfillTarget{DatasourceUID: target.DatasourceUID, Written: target.Written}
+		if target.Error != nil {
+			t.Error = target.Error.Error()
+		}
+		targets = append(targets, t)
+	}
+
+	return response.JSON(http.StatusOK, apimodels.RuleBackfillResponse{
+		Evaluations: result.Evaluations,
+		Written:     result.Written,
+		NoData:      result.NoData,
+		From:        result.From,
+		To:          result.To,
+		Warnings:    result.Warnings,
+		Targets:     targets,
+	})
+}
+
 func alertRuleVersionsToAlertRules(vs []*ngmodels.AlertRuleVersion) []*ngmodels.AlertRule {
 	result := make([]*ngmodels.AlertRule, len(vs))
 	for i := range vs {
@@ -649,16 +713,19 @@ func toGettableRuleGroupConfig(groupName string, rules ngmodels.RulesGroup, prov
 	rules.SortByGroupIndex()
 	ruleNodes := make([]apimodels.GettableExtendedRuleNode, 0, len(rules))
 	var interval time.Duration
+	var sequential bool
 	if len(rules) > 0 {
 		interval = time.Duration(rules[0].IntervalSeconds) * time.Second
+		sequential = rules[0].Metadata.SequentialEvaluation
 	}
 	for _, r := range rules {
 		ruleNodes = append(ruleNodes, toGettableExtendedRuleNode(*r, provenanceRecords, userUIDmapping))
 	}
 	return apimodels.GettableRuleGroupConfig{
-		Name:     groupName,
-		Interval: model.Duration(interval),
-		Rules:    ruleNodes,
+		Name:                 groupName,
+		Interval:             model.Duration(interval),
+		Rules:                ruleNodes,
+		SequentialEvaluation: sequential,
 	}
 }
 
@@ -689,6 +756,9 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 			Metadata:                    AlertRuleMetadataFromModelMetadata(r.Metadata),
 			GUID:                        r.GUID,
 			MissingSeriesEvalsToResolve: r.MissingSeriesEvalsToResolve,
+			InhibitedBy:                 ApiRuleInhibitionsFromRuleInhibitions(r.InhibitedBy),
+			EvaluationSchedule:          ApiEvaluationScheduleFromEvaluationSchedule(r.EvaluationSchedule),
+			FlapDetection:               ApiFlapDetectionFromFlapDetection(r.FlapDetection),
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -14,10 +14,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
 	"github.com/grafana/grafana/pkg/infra/log"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -29,8 +31,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -686,6 +690,122 @@ func TestRouteGetRuleVersionsByUID(t *testing.T) {
 	})
 }
 
+func TestRoutePostRuleBackfillByUID(t *testing.T) {
+	orgID := rand.Int63()
+	f := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = f.UID
+	gen := models.RuleGen.With(models.RuleGen.WithGroupKey(groupKey), models.RuleGen.WithUniqueID(), models.RuleGen.WithAllRecordingRules())
+
+	from, to := time.Unix(0, 0), time.Unix(60, 0)
+	body := apimodels.PostableRuleBackfill{From: from, To: to}
+
+	setup := func(t *testing.T, backfiller *fakeRuleBackfiller) (*RulerSrv, *models.AlertRule) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], f)
+		rule := gen.GenerateRef()
+		ruleStore.PutRule(context.Background(), rule)
+
+		svc := createService(ruleStore, nil)
+		svc.cfg.RecordingRules.Enabled = true
+		svc.backfiller = backfiller
+		svc.recordingWriter = &fakeRecordingWriter{}
+		return svc, rule
+	}
+
+	t.Run("backfills the rule", func(t *testing.T) {
+		backfiller := &fakeRuleBackfiller{result: &backtesting.BackfillResult{Evaluations: 6, Written: 5, NoData: 1, From: from, To: to, Targets: []backtesting.BackfillTargetResult{
+			{DatasourceUID: "prom", Written: 5},
+			{DatasourceUID: "replica", Written: 2, Error: errors.New("write failed")},
+		}}}
+		svc, rule := setup(t, backfiller)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := svc.RoutePostRuleBackfillByUID(req, body, rule.UID)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var result apimodels.RuleBackfillResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, 6, result.Evaluations)
+		require.Equal(t, 5, result.Written)
+		require.Equal(t, 1, result.NoData)
+		require.Equal(t, []apimodels.RuleBackfillTarget{
+			{DatasourceUID: "prom", Written: 5},
+			{DatasourceUID: "replica", Written: 2, Error: "write failed"},
+		}, result.Targets)
+		require.Equal(t, rule.UID, backfiller.rule.UID)
+		require.Equal(t, from, backfiller.from)
+		require.Equal(t, to, backfiller.to)
+	})
+
+	t.Run("BadRequest when the input is invalid", func(t *testing.T) {
+		backfiller := &fakeRuleBackfiller{err: fmt.Errorf("%w: only recording rules can be backfilled", backtesting.ErrInvalidInputData)}
+		svc, rule := setup(t, backfiller)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := svc.RoutePostRuleBackfillByUID(req, body, rule.UID)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("BadRequest when recording rules are disabled", func(t *testing.T) {
+		backfiller := &fakeRuleBackfiller{}
+		svc, rule := setup(t, backfiller)
+		svc.cfg.RecordingRules.Enabled = false
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := svc.RoutePostRuleBackfillByUID(req, body, rule.UID)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Nil(t, backfiller.rule)
+	})
+
+	t.Run("Forbidden when user can only read rules in the folder", func(t *testing.T) {
+		backfiller := &fakeRuleBackfiller{}
+		svc, rule := setup(t, backfiller)
+		permissions := createPermissionsForRulesWithoutDS([]*models.AlertRule{rule}, orgID)
+		for _, query := range rule.Data {
+			permissions[orgID][datasources.ActionQuery] = append(permissions[orgID][datasources.ActionQuery], datasources.ScopeProvider.GetResourceScopeUID(query.DatasourceUID))
+		}
+		// the user can update rules, but only in another folder
+		permissions[orgID][ac.ActionAlertingRuleUpdate] = []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID("another-folder")}
+		req := createRequestContextWithPerms(orgID, permissions, nil)
+
+		response := svc.RoutePostRuleBackfillByUID(req, body, rule.UID)
+
+		require.Equal(t, http.StatusForbidden, response.Status())
+		require.Nil(t, backfiller.rule)
+	})
+
+	t.Run("NotFound when rule does not exist", func(t *testing.T) {
+		backfiller := &fakeRuleBackfiller{}
+		svc, rule := setup(t, backfiller)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := svc.RoutePostRuleBackfillByUID(req, body, "does-not-exist")
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
+type fakeRuleBackfiller struct {
+	result   *backtesting.BackfillResult
+	err      error
+	rule     *models.AlertRule
+	from, to time.Time
+}
+
+func (f *fakeRuleBackfiller) Backfill(_ context.Context, _ identity.Requester, rule *models.AlertRule, from, to time.Time, _ schedule.RecordingWriter) (*backtesting.BackfillResult, error) {
+	f.rule, f.from, f.to = rule, from, to
+	return f.result, f.err
+}
+
+type fakeRecordingWriter struct{}
+
+func (fakeRecordingWriter) WriteDatasource(context.Context, string, string, time.Time, data.Frames, int64, map[string]string) error {
+	return nil
+}
+
 func TestRouteGetRulesConfig(t *testing.T) {
 	gen := models.RuleGen
 	t.Run("fine-grained access is enabled", func(t *testing.T) {
@@ -827,6 +947,28 @@ func TestRouteGetRulesGroupConfig(t *testing.T) {
 			}
 		}
 	})
+	t.Run("should return the sequential evaluation of the group", func(t *testing.T) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		groupKey := models.GenerateGroupKey(orgID)
+		groupKey.NamespaceUID = folder.UID
+
+		expectedRules := gen.With(gen.WithGroupKey(groupKey), gen.WithUniqueGroupIndex(), gen.WithSequentialEvaluation(true)).GenerateManyRef(2, 5)
+		ruleStore.PutRule(context.Background(), expectedRules...)
+
+		perms := createPermissionsForRules(expectedRules, orgID)
+		req := createRequestContextWithPerms(orgID, perms, nil)
+
+		svc := createService(ruleStore, usertest.NewUserServiceFake())
+		response := svc.RouteGetRulesGroupConfig(req, folder.UID, groupKey.RuleGroup)
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		result := &apimodels.RuleGroupConfigResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), result))
+		require.True(t, result.SequentialEvaluation)
+	})
 	t.Run("should return a 404 when fetching a group that doesn't exist", func(t *testing.T) {
 		orgID := rand.Int63()
 		folder := randFolder()
@@ -241,6 +241,16 @@ func TestValidateRuleGroup(t *testing.T) {
 			require.True(t, alert.HasPause)
 		}
 	})
+
+	t.Run("should set sequential evaluation of the group on all rules", func(t *testing.T) {
+		g := validGroup(cfg, rules...)
+		g.SequentialEvaluation = true
+		alerts, err := ValidateRuleGroup(&g, orgId, folder.UID, limits)
+		require.NoError(t, err)
+		for _, alert := range alerts {
+			require.True(t, alert.Metadata.SequentialEvaluation)
+		}
+	})
 }
 
 func TestValidateRuleGroupFailures(t *testing.T) {
@@ -1122,3 +1132,24 @@ func TestValidateRuleNodeReservedLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRuleNodeEvaluationSchedule(t *testing.T) {
+	cfg := config(t)
+	limits := makeLimits(cfg)
+
+	t.Run("accepts a valid schedule", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.EvaluationSchedule = &apimodels.EvaluationSchedule{Cron: "0 8 * * 1-5", Timezone: "Europe/Berlin"}
+		newRule, err := ValidateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval*time.Duration(rand.Int63n(10)+1), rand.Int63(), randFolder().UID, limits)
+		require.NoError(t, err)
+		require.Equal(t, "0 8 * * 1-5", newRule.EvaluationSchedule.Cron)
+	})
+
+	t.Run("rejects an invalid schedule", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.EvaluationSchedule = &apimodels.EvaluationSchedule{Cron: "0 8 * *"}
+		_, err := ValidateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval*time.Duration(rand.Int63n(10)+1), rand.Int63(), randFolder().UID, limits)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "invalid evaluation schedule")
+	})
+}
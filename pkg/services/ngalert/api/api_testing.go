@@ -249,7 +249,7 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		}
 	}
 
-	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To, folderTitle)
+	result, err := srv.backtesting.Backtest(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To, folderTitle)
 	if err != nil {
 		if errors.Is(err, backtesting.ErrInvalidInputData) {
 			return ErrResp(400, err, "Failed to evaluate")
@@ -257,5 +257,8 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		return ErrResp(500, err, "Failed to evaluate")
 	}
 
-	return response.JSONStreaming(http.StatusOK, result)
+	if cmd.IncludeNotifications {
+		return response.JSONStreaming(http.StatusOK, data.Frames{result.States, result.Notifications})
+	}
+	return response.JSONStreaming(http.StatusOK, result.States)
 }
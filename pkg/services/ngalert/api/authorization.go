@@ -46,6 +46,13 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingRuleRead),
 			ac.EvalPermission(dashboards.ActionFoldersRead),
 		)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/backfill":
+		// the permissions to read and update rules in the folder of the rule are checked by the handler
+		eval = ac.EvalAll(
+			ac.EvalPermission(ac.ActionAlertingRuleRead),
+			ac.EvalPermission(dashboards.ActionFoldersRead),
+			ac.EvalPermission(ac.ActionAlertingRuleUpdate),
+		)
 	case http.MethodPost + "/api/ruler/grafana/api/v1/rules/{Namespace}/export":
 		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace"))
 		// more granular permissions are enforced by the handler via "authorizeRuleChanges"
@@ -254,6 +261,8 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingNotificationsWrite),
 			ac.EvalPermission(ac.ActionAlertingReceiversTest),
 		)
+	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/routing/simulate":
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/templates/test":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingNotificationsWrite),
@@ -323,7 +332,12 @@ func (api *API) authorize(method, path string) web.Handler {
 			),
 		)
 	case http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}",
-		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}/export":
+		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}/export",
+		http.MethodGet + "/api/v1/provisioning/rule-templates",
+		http.MethodGet + "/api/v1/provisioning/rule-templates/{UID}",
+		http.MethodGet + "/api/v1/provisioning/rule-templates/{UID}/instances",
+		http.MethodGet + "/api/v1/provisioning/maintenance-windows",
+		http.MethodGet + "/api/v1/provisioning/maintenance-windows/{UID}":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningRead),
 			ac.EvalPermission(ac.ActionAlertingRulesProvisioningRead),
@@ -361,7 +375,8 @@ func (api *API) authorize(method, path string) web.Handler {
 		)
 
 	// Grafana-only Provisioning Write Paths
-	case http.MethodPost + "/api/v1/provisioning/alert-rules":
+	case http.MethodPost + "/api/v1/provisioning/alert-rules",
+		http.MethodPost + "/api/v1/provisioning/rule-templates/{UID}/instances":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
 			ac.EvalPermission(ac.ActionAlertingRulesProvisioningWrite),
@@ -370,7 +385,11 @@ func (api *API) authorize(method, path string) web.Handler {
 				ac.EvalPermission(ac.ActionAlertingProvisioningSetStatus),
 			),
 		)
-	case http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}":
+	case http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}",
+		http.MethodPut + "/api/v1/provisioning/rule-templates/{UID}",
+		http.MethodPut + "/api/v1/provisioning/rule-templates/{UID}/instances",
+		http.MethodPost + "/api/v1/provisioning/alert-rules/pause",
+		http.MethodPost + "/api/v1/provisioning/alert-rules/resume":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
 			ac.EvalPermission(ac.ActionAlertingRulesProvisioningWrite),
@@ -388,6 +407,30 @@ func (api *API) authorize(method, path string) web.Handler {
 				ac.EvalPermission(ac.ActionAlertingProvisioningSetStatus),
 			),
 		)
+	case http.MethodPost + "/api/v1/provisioning/rule-templates",
+		http.MethodDelete + "/api/v1/provisioning/rule-templates/{UID}":
+		// rule templates do not belong to a folder, and alert rules are created from them via the instances endpoints
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
+			ac.EvalPermission(ac.ActionAlertingRulesProvisioningWrite),
+		)
+	case http.MethodPost + "/api/v1/provisioning/maintenance-windows",
+		http.MethodPut + "/api/v1/provisioning/maintenance-windows/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/maintenance-windows/{UID}":
+		// maintenance windows select rules of any folder by their labels
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
+			ac.EvalPermission(ac.ActionAlertingRulesProvisioningWrite),
+		)
+	case http.MethodPost + "/api/v1/provisioning/import":
+		// the file can contain rules of any folder as well as notification resources
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
+			ac.EvalAll(
+				ac.EvalPermission(ac.ActionAlertingRulesProvisioningWrite),
+				ac.EvalPermission(ac.ActionAlertingNotificationsProvisioningWrite),
+			),
+		)
 	case http.MethodDelete + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}":
 		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":FolderUID"))
 		eval = ac.EvalAny(
@@ -3,11 +3,14 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/common/model"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util"
@@ -35,6 +38,9 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		NotificationSettings:        NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:                      ModelRecordFromApiRecord(a.Record),
 		MissingSeriesEvalsToResolve: a.MissingSeriesEvalsToResolve,
+		InhibitedBy:                 RuleInhibitionsFromApiRuleInhibitions(a.InhibitedBy),
+		EvaluationSchedule:          EvaluationScheduleFromApiEvaluationSchedule(a.EvaluationSchedule),
+		FlapDetection:               FlapDetectionFromApiFlapDetection(a.FlapDetection),
 	}
 
 	if rule.Type() == models.RuleTypeRecording {
@@ -67,6 +73,9 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		NotificationSettings:        AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:                      ApiRecordFromModelRecord(rule.Record),
 		MissingSeriesEvalsToResolve: rule.MissingSeriesEvalsToResolve,
+		InhibitedBy:                 ApiRuleInhibitionsFromRuleInhibitions(rule.InhibitedBy),
+		EvaluationSchedule:          ApiEvaluationScheduleFromEvaluationSchedule(rule.EvaluationSchedule),
+		FlapDetection:               ApiFlapDetectionFromFlapDetection(rule.FlapDetection),
 	}
 }
 
@@ -202,6 +211,21 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		result.Labels = &rule.Labels
 	}
 
+	if rule.EvaluationSchedule != nil {
+		schedule, err := AlertRuleEvaluationScheduleExportFromEvaluationSchedule(*rule.EvaluationSchedule)
+		if err != nil {
+			return definitions.AlertRuleExport{}, err
+		}
+		result.EvaluationSchedule = schedule
+	}
+
+	if rule.FlapDetection != nil {
+		result.FlapDetection = &definitions.AlertRuleFlapDetectionExport{
+			Transitions: rule.FlapDetection.Transitions,
+			Window:      model.Duration(rule.FlapDetection.Window).String(),
+		}
+	}
+
 	if rule.Type() == models.RuleTypeRecording {
 		populateRecordingRuleExportFields(rule, &result)
 	} else {
@@ -247,6 +271,8 @@ func populateAlertingRuleExportFields(rule models.AlertRule, result *definitions
 	if rule.MissingSeriesEvalsToResolve != nil && *rule.MissingSeriesEvalsToResolve != -1 {
 		result.MissingSeriesEvalsToResolve = rule.MissingSeriesEvalsToResolve
 	}
+
+	result.InhibitedBy = AlertRuleInhibitionsExportFromRuleInhibitions(rule.InhibitedBy)
 }
 
 func encodeQueryModel(m map[string]any) (string, error) {
@@ -530,11 +556,15 @@ func AlertRuleRecordExportFromRecord(r *models.Record) *definitions.AlertRuleRec
 	if r == nil {
 		return nil
 	}
-	return &definitions.AlertRuleRecordExport{
+	e := &definitions.AlertRuleRecordExport{
 		Metric:              r.Metric,
 		From:                r.From,
 		TargetDatasourceUID: pointerOmitEmpty(r.TargetDatasourceUID),
 	}
+	if len(r.AdditionalTargetDatasourceUIDs) > 0 {
+		e.AdditionalTargetDatasourceUIDs = &r.AdditionalTargetDatasourceUIDs
+	}
+	return e
 }
 
 func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
@@ -545,6 +575,8 @@ func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
 		Metric:              r.Metric,
 		From:                r.From,
 		TargetDatasourceUID: r.TargetDatasourceUID,
+
+		AdditionalTargetDatasourceUIDs: r.AdditionalTargetDatasourceUIDs,
 	}
 }
 
@@ -556,5 +588,535 @@ func ApiRecordFromModelRecord(r *models.Record) *definitions.Record {
 		Metric:              r.Metric,
 		From:                r.From,
 		TargetDatasourceUID: r.TargetDatasourceUID,
+
+		AdditionalTargetDatasourceUIDs: r.AdditionalTargetDatasourceUIDs,
+	}
+}
+
+// RuleInhibitionsFromApiRuleInhibitions converts []definitions.RuleInhibition to []models.RuleInhibition
+func RuleInhibitionsFromApiRuleInhibitions(in []definitions.RuleInhibition) []models.RuleInhibition {
+	if len(in) == 0 {
+		return nil
+	}
+	result := make([]models.RuleInhibition, 0, len(in))
+	for _, i := range in {
+		result = append(result, models.RuleInhibition{
+			RuleUID: i.RuleUID,
+			Equal:   i.Equal,
+		})
+	}
+	return result
+}
+
+// ApiRuleInhibitionsFromRuleInhibitions converts []models.RuleInhibition to []definitions.RuleInhibition
+func ApiRuleInhibitionsFromRuleInhibitions(in []models.RuleInhibition) []definitions.RuleInhibition {
+	if len(in) == 0 {
+		return nil
+	}
+	result := make([]definitions.RuleInhibition, 0, len(in))
+	for _, i := range in {
+		result = append(result, definitions.RuleInhibition{
+			RuleUID: i.RuleUID,
+			Equal:   i.Equal,
+		})
+	}
+	return result
+}
+
+// AlertRuleInhibitionsExportFromRuleInhibitions converts []models.RuleInhibition to []definitions.AlertRuleInhibitionExport
+func AlertRuleInhibitionsExportFromRuleInhibitions(in []models.RuleInhibition) []definitions.AlertRuleInhibitionExport {
+	if len(in) == 0 {
+		return nil
+	}
+	result := make([]definitions.AlertRuleInhibitionExport, 0, len(in))
+	for _, i := range in {
+		e := definitions.AlertRuleInhibitionExport{
+			RuleUID: i.RuleUID,
+		}
+		if len(i.Equal) > 0 {
+			e.Equal = &i.Equal
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// EvaluationScheduleFromApiEvaluationSchedule converts definitions.EvaluationSchedule to models.EvaluationSchedule
+func EvaluationScheduleFromApiEvaluationSchedule(s *definitions.EvaluationSchedule) *models.EvaluationSchedule {
+	if s == nil {
+		return nil
+	}
+	return &models.EvaluationSchedule{
+		Cron:                s.Cron,
+		Timezone:            s.Timezone,
+		ActiveTimeIntervals: s.ActiveTimeIntervals,
+	}
+}
+
+// ApiEvaluationScheduleFromEvaluationSchedule converts models.EvaluationSchedule to definitions.EvaluationSchedule
+func ApiEvaluationScheduleFromEvaluationSchedule(s *models.EvaluationSchedule) *definitions.EvaluationSchedule {
+	if s == nil {
+		return nil
+	}
+	return &definitions.EvaluationSchedule{
+		Cron:                s.Cron,
+		Timezone:            s.Timezone,
+		ActiveTimeIntervals: s.ActiveTimeIntervals,
+	}
+}
+
+// FlapDetectionFromApiFlapDetection converts definitions.FlapDetection to models.FlapDetection
+func FlapDetectionFromApiFlapDetection(f *definitions.FlapDetection) *models.FlapDetection {
+	if f == nil {
+		return nil
+	}
+	return &models.FlapDetection{
+		Transitions: f.Transitions,
+		Window:      time.Duration(f.Window),
+	}
+}
+
+// ApiFlapDetectionFromFlapDetection converts models.FlapDetection to definitions.FlapDetection
+func ApiFlapDetectionFromFlapDetection(f *models.FlapDetection) *definitions.FlapDetection {
+	if f == nil {
+		return nil
+	}
+	return &definitions.FlapDetection{
+		Transitions: f.Transitions,
+		Window:      model.Duration(f.Window),
+	}
+}
+
+// AlertRuleEvaluationScheduleExportFromEvaluationSchedule converts models.EvaluationSchedule to definitions.AlertRuleEvaluationScheduleExport.
+// The time intervals are converted using JSON marshalling. Returns error if they could not be marshalled\unmarshalled
+func AlertRuleEvaluationScheduleExportFromEvaluationSchedule(s models.EvaluationSchedule) (*definitions.AlertRuleEvaluationScheduleExport, error) {
+	result := &definitions.AlertRuleEvaluationScheduleExport{}
+	if s.Cron != "" {
+		result.Cron = &s.Cron
+	}
+	if s.Timezone != "" {
+		result.Timezone = &s.Timezone
+	}
+	if len(s.ActiveTimeIntervals) > 0 {
+		j := jsoniter.ConfigCompatibleWithStandardLibrary
+		data, err := j.Marshal(s.ActiveTimeIntervals)
+		if err != nil {
+			return nil, err
+		}
+		if err := j.Unmarshal(data, &result.ActiveTimeIntervals); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// RuleTemplateFromApiRuleTemplate converts definitions.RuleTemplate to models.RuleTemplate
+func RuleTemplateFromApiRuleTemplate(orgID int64, t definitions.RuleTemplate) models.RuleTemplate {
+	result := models.RuleTemplate{
+		OrgID:       orgID,
+		UID:         t.UID,
+		Title:       t.Title,
+		Description: t.Description,
+		Rule:        t.Rule,
+		Version:     t.Version,
+	}
+	for _, p := range t.Parameters {
+		result.Parameters = append(result.Parameters, models.RuleTemplateParameter{
+			Name:        p.Name,
+			Type:        models.RuleTemplateParameterType(p.Type),
+			Description: p.Description,
+			Default:     p.Default,
+		})
+	}
+	return result
+}
+
+// ApiRuleTemplateFromRuleTemplate converts models.RuleTemplate to definitions.RuleTemplate
+func ApiRuleTemplateFromRuleTemplate(t models.RuleTemplate) definitions.RuleTemplate {
+	result := definitions.RuleTemplate{
+		UID:         t.UID,
+		Title:       t.Title,
+		Description: t.Description,
+		Rule:        t.Rule,
+		Version:     t.Version,
+		Updated:     t.Updated,
+	}
+	for _, p := range t.Parameters {
+		result.Parameters = append(result.Parameters, definitions.RuleTemplateParameter{
+			Name:        p.Name,
+			Type:        string(p.Type),
+			Description: p.Description,
+			Default:     p.Default,
+		})
+	}
+	return result
+}
+
+// AlertRuleSelectorFromApiAlertRuleSelector converts definitions.AlertRuleSelector to models.AlertRuleSelector
+func AlertRuleSelectorFromApiAlertRuleSelector(s definitions.AlertRuleSelector) models.AlertRuleSelector {
+	return models.AlertRuleSelector{
+		Matchers:   labels.Matchers(s.Matchers),
+		FolderUIDs: s.FolderUIDs,
+		RuleGroups: s.RuleGroups,
+	}
+}
+
+// ApiAlertRuleSelectorFromAlertRuleSelector converts models.AlertRuleSelector to definitions.AlertRuleSelector
+func ApiAlertRuleSelectorFromAlertRuleSelector(s models.AlertRuleSelector) definitions.AlertRuleSelector {
+	return definitions.AlertRuleSelector{
+		Matchers:   definitions.ObjectMatchers(s.Matchers),
+		FolderUIDs: s.FolderUIDs,
+		RuleGroups: s.RuleGroups,
+	}
+}
+
+// MaintenanceWindowFromApiMaintenanceWindow converts definitions.MaintenanceWindow to models.MaintenanceWindow
+func MaintenanceWindowFromApiMaintenanceWindow(orgID int64, w definitions.MaintenanceWindow) models.MaintenanceWindow {
+	return models.MaintenanceWindow{
+		OrgID:    orgID,
+		UID:      w.UID,
+		Reason:   w.Reason,
+		StartsAt: w.StartsAt,
+		EndsAt:   w.EndsAt,
+		Selector: AlertRuleSelectorFromApiAlertRuleSelector(w.Selector),
+	}
+}
+
+// ApiMaintenanceWindowFromMaintenanceWindow converts models.MaintenanceWindow to definitions.MaintenanceWindow
+func ApiMaintenanceWindowFromMaintenanceWindow(w models.MaintenanceWindow) definitions.MaintenanceWindow {
+	return definitions.MaintenanceWindow{
+		UID:       w.UID,
+		Reason:    w.Reason,
+		StartsAt:  w.StartsAt,
+		EndsAt:    w.EndsAt,
+		Selector:  ApiAlertRuleSelectorFromAlertRuleSelector(w.Selector),
+		CreatedBy: w.CreatedBy,
+		Updated:   w.Updated,
+	}
+}
+
+// ApiRuleTemplateInstancesFromRuleTemplateInstances converts []models.RuleTemplateInstance to definitions.RuleTemplateInstances
+func ApiRuleTemplateInstancesFromRuleTemplateInstances(in []models.RuleTemplateInstance) definitions.RuleTemplateInstances {
+	result := make(definitions.RuleTemplateInstances, 0, len(in))
+	for _, i := range in {
+		result = append(result, definitions.RuleTemplateInstance{
+			RuleUID:         i.RuleUID,
+			Values:          i.Values,
+			TemplateVersion: i.TemplateVersion,
+		})
+	}
+	return result
+}
+
+// AlertRuleGroupFromAlertRuleGroupExport converts definitions.AlertRuleGroupExport to models.AlertRuleGroup.
+// The folder of the group is taken from FolderUID, which is set only by the HCL format.
+func AlertRuleGroupFromAlertRuleGroupExport(d definitions.AlertRuleGroupExport) (models.AlertRuleGroup, error) {
+	interval := d.IntervalSeconds
+	if interval == 0 {
+		interval = int64(time.Duration(d.Interval).Seconds())
+	}
+	rules := make([]models.AlertRule, 0, len(d.Rules))
+	for _, r := range d.Rules {
+		rule, err := AlertRuleFromAlertRuleExport(r)
+		if err != nil {
+			return models.AlertRuleGroup{}, fmt.Errorf("rule %q: %w", r.Title, err)
+		}
+		rule.NamespaceUID = d.FolderUID
+		rule.RuleGroup = d.Name
+		rule.IntervalSeconds = interval
+		rules = append(rules, rule)
+	}
+	return models.AlertRuleGroup{
+		Title:     d.Name,
+		FolderUID: d.FolderUID,
+		Interval:  interval,
+		Rules:     rules,
+	}, nil
+}
+
+// AlertRuleFromAlertRuleExport converts definitions.AlertRuleExport to models.AlertRule.
+// It accepts both the YAML\JSON and the HCL representations of the fields.
+func AlertRuleFromAlertRuleExport(d definitions.AlertRuleExport) (models.AlertRule, error) {
+	data := make([]models.AlertQuery, 0, len(d.Data))
+	for _, q := range d.Data {
+		query, err := AlertQueryFromAlertQueryExport(q)
+		if err != nil {
+			return models.AlertRule{}, err
+		}
+		data = append(data, query)
+	}
+	forDuration, err := durationFromExport(d.For, d.ForString)
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("invalid for: %w", err)
 	}
+	keepFiringFor, err := durationFromExport(d.KeepFiringFor, d.KeepFiringForString)
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("invalid keep_firing_for: %w", err)
+	}
+	ns, err := NotificationSettingsFromAlertRuleNotificationSettingsExport(d.NotificationSettings)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+
+	rule := models.AlertRule{
+		UID:                         d.UID,
+		Title:                       d.Title,
+		Data:                        data,
+		DashboardUID:                d.DashboardUID,
+		PanelID:                     d.PanelID,
+		For:                         forDuration,
+		KeepFiringFor:               keepFiringFor,
+		IsPaused:                    d.IsPaused,
+		NotificationSettings:        ns,
+		MissingSeriesEvalsToResolve: d.MissingSeriesEvalsToResolve,
+		InhibitedBy:                 RuleInhibitionsFromAlertRuleInhibitionsExport(d.InhibitedBy),
+	}
+	if d.Condition != nil {
+		rule.Condition = *d.Condition
+	}
+	if d.NoDataState != nil {
+		rule.NoDataState = models.NoDataState(*d.NoDataState)
+	}
+	if d.ExecErrState != nil {
+		rule.ExecErrState = models.ExecutionErrorState(*d.ExecErrState)
+	}
+	if d.Annotations != nil {
+		rule.Annotations = *d.Annotations
+	}
+	if d.Labels != nil {
+		rule.Labels = *d.Labels
+	}
+	if d.Record != nil {
+		rule.Record = &models.Record{
+			Metric: d.Record.Metric,
+			From:   d.Record.From,
+		}
+		if d.Record.TargetDatasourceUID != nil {
+			rule.Record.TargetDatasourceUID = *d.Record.TargetDatasourceUID
+		}
+		if d.Record.AdditionalTargetDatasourceUIDs != nil {
+			rule.Record.AdditionalTargetDatasourceUIDs = *d.Record.AdditionalTargetDatasourceUIDs
+		}
+	}
+	if d.EvaluationSchedule != nil {
+		schedule, err := EvaluationScheduleFromAlertRuleEvaluationScheduleExport(*d.EvaluationSchedule)
+		if err != nil {
+			return models.AlertRule{}, err
+		}
+		rule.EvaluationSchedule = schedule
+	}
+	if d.FlapDetection != nil {
+		window, err := model.ParseDuration(d.FlapDetection.Window)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("invalid flap detection window %q: %w", d.FlapDetection.Window, err)
+		}
+		rule.FlapDetection = &models.FlapDetection{
+			Transitions: d.FlapDetection.Transitions,
+			Window:      time.Duration(window),
+		}
+	}
+
+	if rule.Type() == models.RuleTypeRecording {
+		models.ClearRecordingRuleIgnoredFields(&rule)
+	}
+	return rule, nil
+}
+
+// durationFromExport returns the duration from its HCL representation if it is set, and from its YAML\JSON representation otherwise.
+func durationFromExport(d model.Duration, s *string) (time.Duration, error) {
+	if s == nil {
+		return time.Duration(d), nil
+	}
+	parsed, err := model.ParseDuration(*s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(parsed), nil
+}
+
+// AlertQueryFromAlertQueryExport converts definitions.AlertQueryExport to models.AlertQuery.
+func AlertQueryFromAlertQueryExport(d definitions.AlertQueryExport) (models.AlertQuery, error) {
+	var mdl json.RawMessage
+	if d.ModelString != "" {
+		if !json.Valid([]byte(d.ModelString)) {
+			return models.AlertQuery{}, fmt.Errorf("model of query %s is not valid JSON", d.RefID)
+		}
+		mdl = json.RawMessage(d.ModelString)
+	} else {
+		encoded, err := encodeQueryModel(d.Model)
+		if err != nil {
+			return models.AlertQuery{}, err
+		}
+		mdl = json.RawMessage(encoded)
+	}
+	query := models.AlertQuery{
+		RefID: d.RefID,
+		RelativeTimeRange: models.RelativeTimeRange{
+			From: models.Duration(time.Duration(d.RelativeTimeRange.FromSeconds) * time.Second),
+			To:   models.Duration(time.Duration(d.RelativeTimeRange.ToSeconds) * time.Second),
+		},
+		DatasourceUID: d.DatasourceUID,
+		Model:         mdl,
+	}
+	if d.QueryType != nil {
+		query.QueryType = *d.QueryType
+	}
+	return query, nil
+}
+
+// NotificationSettingsFromAlertRuleNotificationSettingsExport converts definitions.AlertRuleNotificationSettingsExport to []models.NotificationSettings
+func NotificationSettingsFromAlertRuleNotificationSettingsExport(ns *definitions.AlertRuleNotificationSettingsExport) ([]models.NotificationSettings, error) {
+	if ns == nil {
+		return nil, nil
+	}
+	parseIfNotNil := func(s *string) (*model.Duration, error) {
+		if s == nil {
+			return nil, nil
+		}
+		d, err := model.ParseDuration(*s)
+		if err != nil {
+			return nil, err
+		}
+		return &d, nil
+	}
+	result := models.NotificationSettings{
+		Receiver: ns.Receiver,
+	}
+	var err error
+	if result.GroupWait, err = parseIfNotNil(ns.GroupWait); err != nil {
+		return nil, fmt.Errorf("invalid group_wait: %w", err)
+	}
+	if result.GroupInterval, err = parseIfNotNil(ns.GroupInterval); err != nil {
+		return nil, fmt.Errorf("invalid group_interval: %w", err)
+	}
+	if result.RepeatInterval, err = parseIfNotNil(ns.RepeatInterval); err != nil {
+		return nil, fmt.Errorf("invalid repeat_interval: %w", err)
+	}
+	if ns.GroupBy != nil {
+		result.GroupBy = *ns.GroupBy
+	}
+	if ns.MuteTimeIntervals != nil {
+		result.MuteTimeIntervals = *ns.MuteTimeIntervals
+	}
+	if ns.ActiveTimeIntervals != nil {
+		result.ActiveTimeIntervals = *ns.ActiveTimeIntervals
+	}
+	return []models.NotificationSettings{result}, nil
+}
+
+// RuleInhibitionsFromAlertRuleInhibitionsExport converts []definitions.AlertRuleInhibitionExport to []models.RuleInhibition
+func RuleInhibitionsFromAlertRuleInhibitionsExport(in []definitions.AlertRuleInhibitionExport) []models.RuleInhibition {
+	if len(in) == 0 {
+		return nil
+	}
+	result := make([]models.RuleInhibition, 0, len(in))
+	for _, i := range in {
+		r := models.RuleInhibition{
+			RuleUID: i.RuleUID,
+		}
+		if i.Equal != nil {
+			r.Equal = *i.Equal
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// EvaluationScheduleFromAlertRuleEvaluationScheduleExport converts definitions.AlertRuleEvaluationScheduleExport to models.EvaluationSchedule.
+// The time intervals are converted using JSON marshalling. Returns error if they could not be marshalled\unmarshalled
+func EvaluationScheduleFromAlertRuleEvaluationScheduleExport(s definitions.AlertRuleEvaluationScheduleExport) (*models.EvaluationSchedule, error) {
+	result := &models.EvaluationSchedule{}
+	if s.Cron != nil {
+		result.Cron = *s.Cron
+	}
+	if s.Timezone != nil {
+		result.Timezone = *s.Timezone
+	}
+	if len(s.ActiveTimeIntervals) > 0 {
+		j := jsoniter.ConfigCompatibleWithStandardLibrary
+		data, err := j.Marshal(s.ActiveTimeIntervals)
+		if err != nil {
+			return nil, err
+		}
+		if err := j.Unmarshal(data, &result.ActiveTimeIntervals); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// EmbeddedContactPointsFromContactPointExport converts definitions.ContactPointExport to []definitions.EmbeddedContactPoint, one per integration.
+func EmbeddedContactPointsFromContactPointExport(d definitions.ContactPointExport) ([]definitions.EmbeddedContactPoint, error) {
+	result := make([]definitions.EmbeddedContactPoint, 0, len(d.Receivers))
+	for _, r := range d.Receivers {
+		settings, err := simplejson.NewJson(r.Settings)
+		if err != nil {
+			return nil, fmt.Errorf("invalid settings of %s integration (uid:%s): %w", r.Type, r.UID, err)
+		}
+		result = append(result, definitions.EmbeddedContactPoint{
+			UID:                   r.UID,
+			Name:                  d.Name,
+			Type:                  r.Type,
+			Settings:              settings,
+			DisableResolveMessage: r.DisableResolveMessage,
+		})
+	}
+	return result, nil
+}
+
+// RouteFromRouteExport converts definitions.RouteExport to definitions.Route.
+// The matchers of the HCL representation are converted to object matchers.
+func RouteFromRouteExport(r *definitions.RouteExport) (definitions.Route, error) {
+	var normalize func(r *definitions.RouteExport) error
+	normalize = func(r *definitions.RouteExport) error {
+		for _, m := range r.ObjectMatchersSlice {
+			matcher, err := matcherFromMatcherExport(m)
+			if err != nil {
+				return err
+			}
+			r.ObjectMatchers = append(r.ObjectMatchers, matcher)
+		}
+		r.ObjectMatchersSlice = nil
+		for _, child := range r.Routes {
+			if err := normalize(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := normalize(r); err != nil {
+		return definitions.Route{}, err
+	}
+
+	var result definitions.Route
+	data, err := json.Marshal(r)
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return definitions.Route{}, err
+	}
+	return result, nil
+}
+
+func matcherFromMatcherExport(m *definitions.MatcherExport) (*labels.Matcher, error) {
+	for _, t := range []labels.MatchType{labels.MatchEqual, labels.MatchNotEqual, labels.MatchRegexp, labels.MatchNotRegexp} {
+		if t.String() == m.Match {
+			return labels.NewMatcher(t, m.Label, m.Value)
+		}
+	}
+	return nil, fmt.Errorf("invalid match type %q of matcher %s", m.Match, m.Label)
+}
+
+// MuteTimeIntervalFromMuteTimeIntervalExportHcl converts definitions.MuteTimeIntervalExportHcl to definitions.MuteTimeInterval using JSON marshalling.
+// Returns error if structure could not be marshalled\unmarshalled
+func MuteTimeIntervalFromMuteTimeIntervalExportHcl(m definitions.MuteTimeIntervalExportHcl) (definitions.MuteTimeInterval, error) {
+	result := definitions.MuteTimeInterval{}
+	j := jsoniter.ConfigCompatibleWithStandardLibrary
+	mdata, err := j.Marshal(m)
+	if err != nil {
+		return result, err
+	}
+	err = j.Unmarshal(mdata, &result.MuteTimeInterval)
+	return result, err
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/alertmanager/timeinterval"
 	prommodel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
@@ -123,6 +124,7 @@ func TestAlertRuleExportFromAlertRule(t *testing.T) {
 		models.RuleGen.WithFor(2*time.Minute),
 		models.RuleGen.WithKeepFiringFor(5*time.Minute),
 		models.RuleGen.WithNotificationSettingsGen(models.NotificationSettingsGen()),
+		models.RuleGen.WithInhibitedBy(models.RuleInhibition{RuleUID: "database-down", Equal: []string{"cluster"}}),
 	).Generate()
 	recordingRule := models.RuleGen.With(
 		models.RuleGen.WithAllRecordingRules(),
@@ -168,6 +170,9 @@ func TestAlertRuleExportFromAlertRule(t *testing.T) {
 		Labels:                      &alertingRule.Labels,
 		NotificationSettings:        AlertRuleNotificationSettingsExportFromNotificationSettings(alertingRule.NotificationSettings),
 		MissingSeriesEvalsToResolve: alertingRule.MissingSeriesEvalsToResolve,
+		InhibitedBy: []definitions.AlertRuleInhibitionExport{
+			{RuleUID: "database-down", Equal: &[]string{"cluster"}},
+		},
 	}
 
 	testCases := []struct {
@@ -196,6 +201,33 @@ func TestAlertRuleExportFromAlertRule(t *testing.T) {
 	}
 }
 
+func TestAlertRuleEvaluationScheduleExportFromEvaluationSchedule(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	schedule := models.EvaluationSchedule{
+		Cron:     "0 8 * * 1-5",
+		Timezone: "Europe/Berlin",
+		ActiveTimeIntervals: []timeinterval.TimeInterval{{
+			Times:    []timeinterval.TimeRange{{StartMinute: 8 * 60, EndMinute: 18 * 60}},
+			Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 1, End: 5}}},
+			Location: &timeinterval.Location{Location: loc},
+		}},
+	}
+
+	exported, err := AlertRuleEvaluationScheduleExportFromEvaluationSchedule(schedule)
+
+	require.NoError(t, err)
+	require.Equal(t, &definitions.AlertRuleEvaluationScheduleExport{
+		Cron:     util.Pointer("0 8 * * 1-5"),
+		Timezone: util.Pointer("Europe/Berlin"),
+		ActiveTimeIntervals: []definitions.TimeIntervalExportHcl{{
+			Times:    []definitions.TimeRangeExportHcl{{StartMinute: "08:00", EndMinute: "18:00"}},
+			Weekdays: &[]string{"monday:friday"},
+			Location: util.Pointer("Europe/Berlin"),
+		}},
+	}, exported)
+}
+
 func TestAlertQueryExportFromAlertQuery(t *testing.T) {
 	query := models.RuleGen.GenerateQuery()
 
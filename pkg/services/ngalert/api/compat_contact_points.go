@@ -229,6 +229,27 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 	return contactPoint, nil
 }
 
+// ContactPointExportFromContactPoint converts strongly typed definitions.ContactPoint, for example decoded from HCL,
+// to the file export model where settings are represented in JSON. The integrations do not have UIDs.
+func ContactPointExportFromContactPoint(cp definitions.ContactPoint) (definitions.ContactPointExport, error) {
+	receiver, err := ContactPointToContactPointExport(cp)
+	if err != nil {
+		return definitions.ContactPointExport{}, err
+	}
+	result := definitions.ContactPointExport{
+		Name:      cp.Name,
+		Receivers: make([]definitions.ReceiverExport, 0, len(receiver.Integrations)),
+	}
+	for _, integration := range receiver.Integrations {
+		result.Receivers = append(result.Receivers, definitions.ReceiverExport{
+			Type:                  integration.Type,
+			Settings:              definitions.RawMessage(integration.Settings),
+			DisableResolveMessage: integration.DisableResolveMessage,
+		})
+	}
+	return result, nil
+}
+
 // marshallIntegration converts the API model integration to the storage model that contains settings in the JSON format.
 // The secret fields are not encrypted.
 func marshallIntegration(json jsoniter.API, integrationType string, integration interface{}, disableResolveMessage *bool) (*alertingModels.IntegrationConfig, error) {
@@ -280,6 +280,10 @@ func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *contex
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
 
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaRoutingSimulation(ctx *contextmodel.ReqContext, body apimodels.RoutingSimulationBody) response.Response {
+	return f.GrafanaSvc.RoutePostGrafanaRoutingSimulation(ctx, body)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaTemplates(ctx *contextmodel.ReqContext, conf apimodels.TestTemplatesConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestTemplates(ctx, conf)
 }
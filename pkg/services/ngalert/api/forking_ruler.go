@@ -129,6 +129,10 @@ func (f *RulerApiHandler) handleRouteGetRuleVersionsByUID(ctx *contextmodel.ReqC
 	return f.GrafanaRuler.RouteGetRuleVersionsByUID(ctx, ruleUID)
 }
 
+func (f *RulerApiHandler) handleRoutePostRuleBackfillByUID(ctx *contextmodel.ReqContext, body apimodels.PostableRuleBackfill, ruleUID string) response.Response {
+	return f.GrafanaRuler.RoutePostRuleBackfillByUID(ctx, body, ruleUID)
+}
+
 func (f *RulerApiHandler) handleRouteDeleteRuleFromTrashByGUID(ctx *contextmodel.ReqContext, ruleGUID string) response.Response {
 	return f.GrafanaRuler.RouteDeleteAlertRuleFromTrashByGUID(ctx, ruleGUID)
 }
@@ -23,7 +23,9 @@ type ProvisioningApi interface {
 	RouteDeleteAlertRule(*contextmodel.ReqContext) response.Response
 	RouteDeleteAlertRuleGroup(*contextmodel.ReqContext) response.Response
 	RouteDeleteContactpoints(*contextmodel.ReqContext) response.Response
+	RouteDeleteMaintenanceWindow(*contextmodel.ReqContext) response.Response
 	RouteDeleteMuteTiming(*contextmodel.ReqContext) response.Response
+	RouteDeleteRuleTemplate(*contextmodel.ReqContext) response.Response
 	RouteDeleteTemplate(*contextmodel.ReqContext) response.Response
 	RouteExportMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteExportMuteTimings(*contextmodel.ReqContext) response.Response
@@ -35,20 +37,34 @@ type ProvisioningApi interface {
 	RouteGetAlertRulesExport(*contextmodel.ReqContext) response.Response
 	RouteGetContactpoints(*contextmodel.ReqContext) response.Response
 	RouteGetContactpointsExport(*contextmodel.ReqContext) response.Response
+	RouteGetMaintenanceWindow(*contextmodel.ReqContext) response.Response
+	RouteGetMaintenanceWindows(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTimings(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTree(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTreeExport(*contextmodel.ReqContext) response.Response
+	RouteGetRuleTemplate(*contextmodel.ReqContext) response.Response
+	RouteGetRuleTemplateInstances(*contextmodel.ReqContext) response.Response
+	RouteGetRuleTemplates(*contextmodel.ReqContext) response.Response
 	RouteGetTemplate(*contextmodel.ReqContext) response.Response
 	RouteGetTemplates(*contextmodel.ReqContext) response.Response
 	RoutePostAlertRule(*contextmodel.ReqContext) response.Response
 	RoutePostContactpoints(*contextmodel.ReqContext) response.Response
+	RoutePostImport(*contextmodel.ReqContext) response.Response
+	RoutePostMaintenanceWindow(*contextmodel.ReqContext) response.Response
 	RoutePostMuteTiming(*contextmodel.ReqContext) response.Response
+	RoutePostPauseAlertRules(*contextmodel.ReqContext) response.Response
+	RoutePostResumeAlertRules(*contextmodel.ReqContext) response.Response
+	RoutePostRuleTemplate(*contextmodel.ReqContext) response.Response
+	RoutePostRuleTemplateInstance(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRule(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRuleGroup(*contextmodel.ReqContext) response.Response
 	RoutePutContactpoint(*contextmodel.ReqContext) response.Response
+	RoutePutMaintenanceWindow(*contextmodel.ReqContext) response.Response
 	RoutePutMuteTiming(*contextmodel.ReqContext) response.Response
 	RoutePutPolicyTree(*contextmodel.ReqContext) response.Response
+	RoutePutRuleTemplate(*contextmodel.ReqContext) response.Response
+	RoutePutRuleTemplateInstances(*contextmodel.ReqContext) response.Response
 	RoutePutTemplate(*contextmodel.ReqContext) response.Response
 	RouteResetPolicyTree(*contextmodel.ReqContext) response.Response
 }
@@ -69,11 +85,21 @@ func (f *ProvisioningApiHandler) RouteDeleteContactpoints(ctx *contextmodel.ReqC
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	return f.handleRouteDeleteContactpoints(ctx, uIDParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteMaintenanceWindow(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.handleRouteDeleteMuteTiming(ctx, nameParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteRuleTemplate(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -121,6 +147,14 @@ func (f *ProvisioningApiHandler) RouteGetContactpoints(ctx *contextmodel.ReqCont
 func (f *ProvisioningApiHandler) RouteGetContactpointsExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetContactpointsExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetMaintenanceWindow(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetMaintenanceWindows(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetMaintenanceWindows(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -135,6 +169,19 @@ func (f *ProvisioningApiHandler) RouteGetPolicyTree(ctx *contextmodel.ReqContext
 func (f *ProvisioningApiHandler) RouteGetPolicyTreeExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetPolicyTreeExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetRuleTemplate(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetRuleTemplateInstances(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetRuleTemplateInstances(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetRuleTemplates(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -159,6 +206,17 @@ func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *contextmodel.ReqCon
 	}
 	return f.handleRoutePostContactpoints(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostImport(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRoutePostImport(ctx)
+}
+func (f *ProvisioningApiHandler) RoutePostMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.MaintenanceWindow{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostMaintenanceWindow(ctx, conf)
+}
 func (f *ProvisioningApiHandler) RoutePostMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.MuteTimeInterval{}
@@ -167,6 +225,40 @@ func (f *ProvisioningApiHandler) RoutePostMuteTiming(ctx *contextmodel.ReqContex
 	}
 	return f.handleRoutePostMuteTiming(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostPauseAlertRules(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.AlertRuleSelector{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostPauseAlertRules(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostResumeAlertRules(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.AlertRuleSelector{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostResumeAlertRules(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.RuleTemplate{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostRuleTemplate(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostRuleTemplateInstance(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.PostableRuleTemplateInstance{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostRuleTemplateInstance(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutAlertRule(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -198,6 +290,16 @@ func (f *ProvisioningApiHandler) RoutePutContactpoint(ctx *contextmodel.ReqConte
 	}
 	return f.handleRoutePutContactpoint(ctx, conf, uIDParam)
 }
+func (f *ProvisioningApiHandler) RoutePutMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.MaintenanceWindow{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutMaintenanceWindow(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -216,6 +318,26 @@ func (f *ProvisioningApiHandler) RoutePutPolicyTree(ctx *contextmodel.ReqContext
 	}
 	return f.handleRoutePutPolicyTree(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePutRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.RuleTemplate{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutRuleTemplate(ctx, conf, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePutRuleTemplateInstances(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := []apimodels.RuleTemplateInstance{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutRuleTemplateInstances(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -268,6 +390,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/maintenance-windows/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/maintenance-windows/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteMaintenanceWindow),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -280,6 +414,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteRuleTemplate),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -412,6 +558,30 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/maintenance-windows/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/maintenance-windows/{UID}",
+				api.Hooks.Wrap(srv.RouteGetMaintenanceWindow),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/maintenance-windows"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/maintenance-windows",
+				api.Hooks.Wrap(srv.RouteGetMaintenanceWindows),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -460,6 +630,42 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RouteGetRuleTemplate),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/rule-templates/{UID}/instances"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/rule-templates/{UID}/instances"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/rule-templates/{UID}/instances",
+				api.Hooks.Wrap(srv.RouteGetRuleTemplateInstances),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/rule-templates"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/rule-templates"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/rule-templates",
+				api.Hooks.Wrap(srv.RouteGetRuleTemplates),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -508,6 +714,30 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/import"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/import"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/import",
+				api.Hooks.Wrap(srv.RoutePostImport),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/maintenance-windows"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/maintenance-windows",
+				api.Hooks.Wrap(srv.RoutePostMaintenanceWindow),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/mute-timings"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -520,6 +750,54 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/pause"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/pause"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/pause",
+				api.Hooks.Wrap(srv.RoutePostPauseAlertRules),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/resume"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/resume"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/resume",
+				api.Hooks.Wrap(srv.RoutePostResumeAlertRules),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/rule-templates"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/rule-templates"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/rule-templates",
+				api.Hooks.Wrap(srv.RoutePostRuleTemplate),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/rule-templates/{UID}/instances"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/rule-templates/{UID}/instances"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/rule-templates/{UID}/instances",
+				api.Hooks.Wrap(srv.RoutePostRuleTemplateInstance),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -556,6 +834,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/maintenance-windows/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/maintenance-windows/{UID}",
+				api.Hooks.Wrap(srv.RoutePutMaintenanceWindow),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -580,6 +870,30 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RoutePutRuleTemplate),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/rule-templates/{UID}/instances"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/rule-templates/{UID}/instances"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/rule-templates/{UID}/instances",
+				api.Hooks.Wrap(srv.RoutePutRuleTemplateInstances),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
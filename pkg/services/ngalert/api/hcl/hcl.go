@@ -3,7 +3,9 @@ package hcl
 import (
 	"fmt"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
 
@@ -28,3 +30,38 @@ func Encode(resources ...Resource) (data []byte, err error) {
 	}
 	return f.Bytes(), nil
 }
+
+var fileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "resource", LabelNames: []string{"type", "name"}},
+	},
+}
+
+// Decode parses the resource blocks of an HCL document, such as the one produced by Encode.
+// The body of each resource is decoded into the value returned by newBody for the type of the resource,
+// which must be a pointer to a struct with hcl tags. Expressions must be literals, variables and functions are not supported.
+func Decode(data []byte, filename string, newBody func(resourceType string) (interface{}, error)) ([]Resource, error) {
+	f, diags := hclparse.NewParser().ParseHCL(data, filename)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse HCL: %w", diags)
+	}
+	content, diags := f.Body.Content(fileSchema)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse HCL: %w", diags)
+	}
+
+	resources := make([]Resource, 0, len(content.Blocks))
+	for _, block := range content.Blocks {
+		resource := Resource{Type: block.Labels[0], Name: block.Labels[1]}
+		body, err := newBody(resource.Type)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s.%s: %w", resource.Type, resource.Name, err)
+		}
+		if diags := gohcl.DecodeBody(block.Body, nil, body); diags.HasErrors() {
+			return nil, fmt.Errorf("failed to decode resource %s.%s: %w", resource.Type, resource.Name, diags)
+		}
+		resource.Body = body
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
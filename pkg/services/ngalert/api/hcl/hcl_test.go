@@ -1,6 +1,7 @@
 package hcl
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -73,3 +74,68 @@ func TestEncode(t *testing.T) {
 }
 `, string(encoded))
 }
+
+func TestDecode(t *testing.T) {
+	type data struct {
+		Name      string   `hcl:"name"`
+		Number    float64  `hcl:"number"`
+		NumberRef *float64 `hcl:"numberRef"`
+		Blocks    []data   `hcl:"blocks,block"`
+	}
+	type other struct {
+		Title string `hcl:"title"`
+	}
+	newBody := func(resourceType string) (interface{}, error) {
+		switch resourceType {
+		case "grafana_test":
+			return &data{}, nil
+		case "grafana_other":
+			return &other{}, nil
+		}
+		return nil, fmt.Errorf("unknown type %s", resourceType)
+	}
+
+	t.Run("decodes encoded resources", func(t *testing.T) {
+		expected := []Resource{
+			{
+				Type: "grafana_test",
+				Name: "test-01",
+				Body: &data{
+					Name:      "test",
+					Number:    123,
+					NumberRef: func(f float64) *float64 { return &f }(1333),
+					Blocks:    []data{{Name: "el-0", Number: 1}},
+				},
+			},
+			{
+				Type: "grafana_other",
+				Name: "test-02",
+				Body: &other{Title: "other"},
+			},
+		}
+		encoded, err := Encode(expected...)
+		require.NoError(t, err)
+
+		decoded, err := Decode(encoded, "test.tf", newBody)
+		require.NoError(t, err)
+		require.Equal(t, expected, decoded)
+	})
+
+	t.Run("fails on unknown resource type", func(t *testing.T) {
+		_, err := Decode([]byte(`resource "grafana_unknown" "a" {}`), "test.tf", newBody)
+		require.ErrorContains(t, err, "grafana_unknown.a")
+	})
+
+	t.Run("fails on unknown attribute", func(t *testing.T) {
+		_, err := Decode([]byte(`resource "grafana_other" "a" {
+  title = "a"
+  unknown = 1
+}`), "test.tf", newBody)
+		require.ErrorContains(t, err, "failed to decode resource grafana_other.a")
+	})
+
+	t.Run("fails on invalid syntax", func(t *testing.T) {
+		_, err := Decode([]byte(`resource "grafana_other" {`), "test.tf", newBody)
+		require.ErrorContains(t, err, "failed to parse HCL")
+	})
+}
@@ -163,9 +163,10 @@ func PrepareAlertStatuses(manager state.AlertInstanceManager, opts AlertStatuses
 
 			// TODO: or should we make this two fields? Using one field lets the
 			// frontend use the same logic for parsing text on annotations and this.
-			State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
-			ActiveAt: &startsAt,
-			Value:    valString,
+			State:         state.FormatStateAndReason(alertState.State, alertState.StateReason),
+			ActiveAt:      &startsAt,
+			Value:         valString,
+			FlappingSince: alertState.FlappingSince,
 		})
 	}
 
@@ -433,9 +434,10 @@ func RuleAlertStateMutatorGenerator(manager state.AlertInstanceManager) RuleAler
 
 					// TODO: or should we make this two fields? Using one field lets the
 					// frontend use the same logic for parsing text on annotations and this.
-					State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
-					ActiveAt: &activeAt,
-					Value:    valString,
+					State:         state.FormatStateAndReason(alertState.State, alertState.StateReason),
+					ActiveAt:      &activeAt,
+					Value:         valString,
+					FlappingSince: alertState.FlappingSince,
 				})
 			}
 		}
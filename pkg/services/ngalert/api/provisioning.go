@@ -139,3 +139,71 @@ func (f *ProvisioningApiHandler) handleRouteExportMuteTimings(ctx *contextmodel.
 func (f *ProvisioningApiHandler) handleRouteDeleteAlertRuleGroup(ctx *contextmodel.ReqContext, folderUID, group string) response.Response {
 	return f.svc.RouteDeleteAlertRuleGroup(ctx, folderUID, group)
 }
+
+func (f *ProvisioningApiHandler) handleRouteGetRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetRuleTemplates(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetRuleTemplate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetRuleTemplate(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostRuleTemplate(ctx *contextmodel.ReqContext, t apimodels.RuleTemplate) response.Response {
+	return f.svc.RoutePostRuleTemplate(ctx, t)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutRuleTemplate(ctx *contextmodel.ReqContext, t apimodels.RuleTemplate, UID string) response.Response {
+	return f.svc.RoutePutRuleTemplate(ctx, t, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteRuleTemplate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteDeleteRuleTemplate(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetRuleTemplateInstances(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetRuleTemplateInstances(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostRuleTemplateInstance(ctx *contextmodel.ReqContext, instance apimodels.PostableRuleTemplateInstance, UID string) response.Response {
+	return f.svc.RoutePostRuleTemplateInstance(ctx, instance, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutRuleTemplateInstances(ctx *contextmodel.ReqContext, instances []apimodels.RuleTemplateInstance, UID string) response.Response {
+	return f.svc.RoutePutRuleTemplateInstances(ctx, instances, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostImport(ctx *contextmodel.ReqContext) response.Response {
+	body, err := parseImportRequest(ctx)
+	if err != nil {
+		return errorToResponse(err)
+	}
+	return f.svc.RoutePostImport(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostPauseAlertRules(ctx *contextmodel.ReqContext, selector apimodels.AlertRuleSelector) response.Response {
+	return f.svc.RoutePostPauseAlertRules(ctx, selector)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostResumeAlertRules(ctx *contextmodel.ReqContext, selector apimodels.AlertRuleSelector) response.Response {
+	return f.svc.RoutePostResumeAlertRules(ctx, selector)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetMaintenanceWindows(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetMaintenanceWindows(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetMaintenanceWindow(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetMaintenanceWindow(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostMaintenanceWindow(ctx *contextmodel.ReqContext, w apimodels.MaintenanceWindow) response.Response {
+	return f.svc.RoutePostMaintenanceWindow(ctx, w)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutMaintenanceWindow(ctx *contextmodel.ReqContext, w apimodels.MaintenanceWindow, UID string) response.Response {
+	return f.svc.RoutePutMaintenanceWindow(ctx, w, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteMaintenanceWindow(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteDeleteMaintenanceWindow(ctx, UID)
+}
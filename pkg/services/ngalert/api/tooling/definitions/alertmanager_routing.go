@@ -0,0 +1,93 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /alertmanager/grafana/config/api/v1/routing/simulate alertmanager RoutePostGrafanaRoutingSimulation
+//
+// Simulate the routing of an alert through the notification policy tree of the Grafana Alertmanager.
+// Returns the matched routes with their effective settings, the state of their time intervals and the inhibition rules that target the alert.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RoutingSimulationResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+
+// swagger:parameters RoutePostGrafanaRoutingSimulation
+type RoutingSimulationParams struct {
+	// in:body
+	Body RoutingSimulationBody
+}
+
+// swagger:model
+type RoutingSimulationBody struct {
+	// Labels of the alert. If ruleUID is set, the labels of an alert instance of the rule, such as the labels of a query result.
+	// example: {"severity": "critical", "team": "platform"}
+	Labels map[string]string `json:"labels,omitempty"`
+	// UID of an alert rule. The labels of the rule, its built-in labels and the labels of its notification settings are added to the labels of the alert.
+	RuleUID string `json:"ruleUID,omitempty"`
+	// Time at which time intervals are evaluated. Defaults to the current time.
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// swagger:model
+type RoutingSimulationResult struct {
+	// Labels of the alert that were routed.
+	Labels map[string]string `json:"labels"`
+	// Time at which time intervals were evaluated.
+	Time time.Time `json:"time"`
+	// Matched routes, in the order in which notifications are sent.
+	Routes []SimulatedRoute `json:"routes"`
+	// Inhibition rules whose target matchers match the alert.
+	Inhibitions []SimulatedInhibition `json:"inhibitions"`
+}
+
+// SimulatedRoute is a matched route with the settings inherited from its parents.
+type SimulatedRoute struct {
+	// Identifier of the route in the tree.
+	ID string `json:"id"`
+	// Matchers of the route and of all its parents, starting with the root.
+	Path []string `json:"path"`
+	// Whether the route is generated from the notification settings of an alert rule.
+	Autogenerated bool `json:"autogenerated"`
+
+	Receiver       string         `json:"receiver"`
+	GroupBy        []string       `json:"group_by"`
+	GroupWait      model.Duration `json:"group_wait"`
+	GroupInterval  model.Duration `json:"group_interval"`
+	RepeatInterval model.Duration `json:"repeat_interval"`
+
+	MuteTimeIntervals   []SimulatedTimeInterval `json:"mute_time_intervals"`
+	ActiveTimeIntervals []SimulatedTimeInterval `json:"active_time_intervals"`
+	// Whether notifications of the route are muted by its time intervals at the time of the simulation.
+	Muted bool `json:"muted"`
+}
+
+// SimulatedTimeInterval is a time interval referenced by a route.
+type SimulatedTimeInterval struct {
+	Name string `json:"name"`
+	// Whether the time of the simulation is within the time interval.
+	Active bool `json:"active"`
+}
+
+// SimulatedInhibition is an inhibition that applies to the alert.
+type SimulatedInhibition struct {
+	// Source of the inhibition.
+	// enum: alertmanager,rule
+	Source string `json:"source"`
+	// Matchers of the inhibiting alerts of an Alertmanager inhibition rule.
+	SourceMatchers []string `json:"source_matchers,omitempty"`
+	// UID of the inhibiting alert rule of a rule inhibition.
+	RuleUID string `json:"rule_uid,omitempty"`
+	// Labels that must have the same values in the inhibiting alert and the alert.
+	Equal []string `json:"equal,omitempty"`
+	// Whether there is a current inhibiting alert, that is, whether the alert would be inhibited now.
+	Active bool `json:"active"`
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 )
 
@@ -44,6 +45,23 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route Post /ruler/grafana/api/v1/rule/{RuleUID}/backfill ruler RoutePostRuleBackfillByUID
+//
+// Backfill a recording rule over a past time range. The rule is evaluated at every tick of the range,
+// and the results are written to its target data sources.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleBackfillResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
 // swagger:route Get /ruler/grafana/api/v1/rules ruler RouteGetGrafanaRulesConfig
 //
 // List rule groups
@@ -256,12 +274,59 @@ type PathGetRulesParams struct {
 	PanelID int64
 }
 
-// swagger:parameters RouteGetRuleByUID RouteGetRuleVersionsByUID
+// swagger:parameters RouteGetRuleByUID RouteGetRuleVersionsByUID RoutePostRuleBackfillByUID
 type PathGetRuleByUIDParams struct {
 	// in: path
 	RuleUID string
 }
 
+// swagger:parameters RoutePostRuleBackfillByUID
+type PostableRuleBackfillParams struct {
+	// in:body
+	Body PostableRuleBackfill
+}
+
+// PostableRuleBackfill is the time range to backfill a recording rule for.
+// swagger:model
+type PostableRuleBackfill struct {
+	// The start of the range.
+	// required: true
+	// example: 2024-01-01T00:00:00Z
+	From time.Time `json:"from"`
+	// The end of the range. It must not be in the future.
+	// required: true
+	// example: 2024-01-02T00:00:00Z
+	To time.Time `json:"to"`
+}
+
+// swagger:model
+type RuleBackfillResponse struct {
+	// The number of times the rule was evaluated.
+	Evaluations int `json:"evaluations"`
+	// The number of evaluations whose result was written to at least one target data source.
+	Written int `json:"written"`
+	// The number of evaluations that returned no data.
+	NoData int `json:"noData"`
+	// The time of the first evaluation.
+	From time.Time `json:"from,omitempty"`
+	// The time of the last evaluation.
+	To time.Time `json:"to,omitempty"`
+	// Adjustments made to the requested range, for example when it had more evaluations than allowed.
+	Warnings []string `json:"warnings,omitempty"`
+	// The outcome of the backfill for each target data source of the rule.
+	Targets []RuleBackfillTarget `json:"targets,omitempty"`
+}
+
+// swagger:model
+type RuleBackfillTarget struct {
+	// The UID of the target data source.
+	DatasourceUID string `json:"datasourceUid"`
+	// The number of evaluations whose result was written to the data source.
+	Written int `json:"written"`
+	// The error that stopped the writes to the data source. The writes to the other targets continue.
+	Error string `json:"error,omitempty"`
+}
+
 // swagger:parameters RouteDeleteRuleFromTrashByGUID
 type PathDeleteRuleFromTrashByGUIDParams struct {
 	// in: path
@@ -281,6 +346,9 @@ type PostableRuleGroupConfig struct {
 	Name     string                     `yaml:"name" json:"name"`
 	Interval model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
 	Rules    []PostableExtendedRuleNode `yaml:"rules" json:"rules"`
+	// If true, the rules of a Grafana-managed group are evaluated one after another, in the order of the group,
+	// and rules that read the output metric of a recording rule in the group are evaluated after it.
+	SequentialEvaluation bool `yaml:"sequential_evaluation,omitempty" json:"sequential_evaluation,omitempty"`
 
 	// fields below are used by Mimir/Loki rulers
 
@@ -351,6 +419,9 @@ type GettableRuleGroupConfig struct {
 	Name     string                     `yaml:"name" json:"name"`
 	Interval model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
 	Rules    []GettableExtendedRuleNode `yaml:"rules" json:"rules"`
+	// If true, the rules of a Grafana-managed group are evaluated one after another, in the order of the group,
+	// and rules that read the output metric of a recording rule in the group are evaluated after it.
+	SequentialEvaluation bool `yaml:"sequential_evaluation,omitempty" json:"sequential_evaluation,omitempty"`
 
 	// fields below are used by Mimir/Loki rulers
 
@@ -588,6 +659,51 @@ type Record struct {
 	// required: false
 	// example: my-prom
 	TargetDatasourceUID string `json:"target_datasource_uid,omitempty" yaml:"target_datasource_uid,omitempty"`
+	// Data sources the output of the recording rule is written to in addition to the target data source, specified by UID.
+	// required: false
+	// example: ["my-prom-replica"]
+	AdditionalTargetDatasourceUIDs []string `json:"additional_target_datasource_uids,omitempty" yaml:"additional_target_datasource_uids,omitempty"`
+}
+
+// RuleInhibition references a rule whose firing alerts inhibit the alerts of another rule.
+// swagger:model
+type RuleInhibition struct {
+	// UID of the inhibiting rule. The rule must be in the same organization.
+	// required: true
+	// example: database-down
+	RuleUID string `json:"rule_uid" yaml:"rule_uid"`
+	// Labels that must have the same values in the alert of the inhibiting rule and in the inhibited alert.
+	// If empty, any firing alert of the inhibiting rule inhibits all alerts of the rule.
+	// example: ["cluster", "namespace"]
+	Equal []string `json:"equal,omitempty" yaml:"equal,omitempty"`
+}
+
+// EvaluationSchedule defines when a rule is evaluated, in addition to its evaluation interval.
+// swagger:model
+type EvaluationSchedule struct {
+	// Cron expression with five fields, or a descriptor such as @daily. If set, the rule is evaluated
+	// at the times that match the expression instead of every interval.
+	// example: 0 8 * * 1-5
+	Cron string `json:"cron,omitempty" yaml:"cron,omitempty"`
+	// IANA time zone the cron expression, and the active time intervals without a location, are evaluated in.
+	// Defaults to UTC.
+	// example: Europe/Berlin
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	// Time intervals during which the rule is evaluated. Evaluations outside these intervals are skipped.
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"active_time_intervals,omitempty" yaml:"active_time_intervals,omitempty"`
+}
+
+// FlapDetection holds the notifications of alerts whose state changes too often.
+// swagger:model
+type FlapDetection struct {
+	// Number of state changes within the window that marks an alert as flapping.
+	// minimum: 2
+	// example: 4
+	Transitions int64 `json:"transitions" yaml:"transitions"`
+	// Period in which the state changes are counted. The notifications of a flapping alert are held
+	// until its state has not changed for this period. Must not be shorter than the evaluation interval.
+	// example: 30m
+	Window model.Duration `json:"window" yaml:"window"`
 }
 
 // swagger:model
@@ -608,6 +724,15 @@ type PostableGrafanaRule struct {
 	// required: false
 	// example: 3
 	MissingSeriesEvalsToResolve *int64 `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
+	// Rules that inhibit the alerts of this rule while they are firing.
+	// required: false
+	InhibitedBy []RuleInhibition `json:"inhibited_by,omitempty" yaml:"inhibited_by,omitempty"`
+	// Restricts when the rule is evaluated. If not set, the rule is evaluated every interval.
+	// required: false
+	EvaluationSchedule *EvaluationSchedule `json:"evaluation_schedule,omitempty" yaml:"evaluation_schedule,omitempty"`
+	// Holds the notifications of alerts whose state changes too often. If not set, flapping is not detected.
+	// required: false
+	FlapDetection *FlapDetection `json:"flap_detection,omitempty" yaml:"flap_detection,omitempty"`
 }
 
 // swagger:model
@@ -631,6 +756,9 @@ type GettableGrafanaRule struct {
 	Metadata                    *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 	GUID                        string                         `json:"guid" yaml:"guid"`
 	MissingSeriesEvalsToResolve *int64                         `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
+	InhibitedBy                 []RuleInhibition               `json:"inhibited_by,omitempty" yaml:"inhibited_by,omitempty"`
+	EvaluationSchedule          *EvaluationSchedule            `json:"evaluation_schedule,omitempty" yaml:"evaluation_schedule,omitempty"`
+	FlapDetection               *FlapDetection                 `json:"flap_detection,omitempty" yaml:"flap_detection,omitempty"`
 
 	// Field is only populated when listing alert rule versions.
 	Message string `yaml:"message,omitempty" json:"message,omitempty"`
@@ -200,6 +200,8 @@ type Alert struct {
 	ActiveAt *time.Time `json:"activeAt"`
 	// required: true
 	Value string `json:"value"`
+	// FlappingSince is the time at which the alert started flapping. It is only set while the alert is flapping.
+	FlappingSince *time.Time `json:"flappingSince,omitempty"`
 }
 
 type StateByImportance int
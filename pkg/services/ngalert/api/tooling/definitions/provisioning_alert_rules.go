@@ -175,6 +175,12 @@ type ProvisionedAlertRule struct {
 	Record *Record `json:"record"`
 	// example: 2
 	MissingSeriesEvalsToResolve *int64 `json:"missingSeriesEvalsToResolve,omitempty"`
+	// example: [{"rule_uid":"database-down","equal":["cluster"]}]
+	InhibitedBy []RuleInhibition `json:"inhibitedBy,omitempty"`
+	// example: {"cron":"0 8 * * 1-5","timezone":"Europe/Berlin"}
+	EvaluationSchedule *EvaluationSchedule `json:"evaluationSchedule,omitempty"`
+	// example: {"transitions":4,"window":"30m"}
+	FlapDetection *FlapDetection `json:"flapDetection,omitempty"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -285,6 +291,9 @@ type AlertRuleExport struct {
 	NotificationSettings        *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
 	Record                      *AlertRuleRecordExport               `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
 	MissingSeriesEvalsToResolve *int64                               `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty" hcl:"missing_series_evals_to_resolve"`
+	InhibitedBy                 []AlertRuleInhibitionExport          `json:"inhibitedBy,omitempty" yaml:"inhibitedBy,omitempty" hcl:"inhibited_by,block"`
+	EvaluationSchedule          *AlertRuleEvaluationScheduleExport   `json:"evaluationSchedule,omitempty" yaml:"evaluationSchedule,omitempty" hcl:"evaluation_schedule,block"`
+	FlapDetection               *AlertRuleFlapDetectionExport        `json:"flapDetection,omitempty" yaml:"flapDetection,omitempty" hcl:"flap_detection,block"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -315,9 +324,29 @@ type AlertRuleNotificationSettingsExport struct {
 	ActiveTimeIntervals *[]string `yaml:"active_time_intervals,omitempty" json:"active_time_intervals,omitempty" hcl:"active_timings,optional"` // TF -> `active_timings`
 }
 
+// AlertRuleInhibitionExport is the provisioned export of models.RuleInhibition.
+type AlertRuleInhibitionExport struct {
+	RuleUID string    `json:"ruleUid" yaml:"ruleUid" hcl:"rule_uid"`
+	Equal   *[]string `json:"equal,omitempty" yaml:"equal,omitempty" hcl:"equal,optional"`
+}
+
+// AlertRuleEvaluationScheduleExport is the provisioned export of models.EvaluationSchedule.
+type AlertRuleEvaluationScheduleExport struct {
+	Cron                *string                 `json:"cron,omitempty" yaml:"cron,omitempty" hcl:"cron,optional"`
+	Timezone            *string                 `json:"timezone,omitempty" yaml:"timezone,omitempty" hcl:"timezone,optional"`
+	ActiveTimeIntervals []TimeIntervalExportHcl `json:"activeTimeIntervals,omitempty" yaml:"activeTimeIntervals,omitempty" hcl:"active_time_intervals,block"`
+}
+
+// AlertRuleFlapDetectionExport is the provisioned export of models.FlapDetection.
+type AlertRuleFlapDetectionExport struct {
+	Transitions int64  `json:"transitions" yaml:"transitions" hcl:"transitions"`
+	Window      string `json:"window" yaml:"window" hcl:"window"`
+}
+
 // Record is the provisioned export of models.Record.
 type AlertRuleRecordExport struct {
-	Metric              string  `json:"metric" yaml:"metric" hcl:"metric"`
-	From                string  `json:"from" yaml:"from" hcl:"from"`
-	TargetDatasourceUID *string `json:"targetDatasourceUid,omitempty" yaml:"targetDatasourceUid,omitempty" hcl:"target_datasource_uid,optional"`
+	Metric                         string    `json:"metric" yaml:"metric" hcl:"metric"`
+	From                           string    `json:"from" yaml:"from" hcl:"from"`
+	TargetDatasourceUID            *string   `json:"targetDatasourceUid,omitempty" yaml:"targetDatasourceUid,omitempty" hcl:"target_datasource_uid,optional"`
+	AdditionalTargetDatasourceUIDs *[]string `json:"additionalTargetDatasourceUids,omitempty" yaml:"additionalTargetDatasourceUids,omitempty" hcl:"additional_target_datasource_uids,optional"`
 }
@@ -0,0 +1,62 @@
+package definitions
+
+// swagger:route POST /v1/provisioning/import provisioning stable RoutePostImport
+//
+// Validate and import alerting resources in the format of the export endpoints: YAML, JSON or Terraform HCL.
+// Returns the changes to the current state. All changes are applied in a single transaction, unless dryRun is set.
+// Resources that are not in the file are not changed. Redacted secure settings keep their current value.
+//
+//     Consumes:
+//     - application/yaml
+//     - application/json
+//     - text/hcl
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: ImportResult
+//       400: ValidationError
+//
+//     Extensions:
+//       x-raw-request: true
+
+// swagger:parameters RoutePostImport
+type ImportParams struct {
+	// Validate the resources and return the changes without applying them.
+	// in: query
+	// required: false
+	// default: false
+	DryRun bool `json:"dryRun"`
+	// Format of the body. Content-Type header can also be used, but the query parameter will take precedence.
+	// in: query
+	// required: false
+	// default: yaml
+	// enum: yaml,json,hcl
+	Format string `json:"format"`
+	// in: header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+	// in: body
+	Body AlertingFileExport
+}
+
+// ImportResult is the result of an import.
+// swagger:model
+type ImportResult struct {
+	// Whether the changes were applied.
+	Applied bool           `json:"applied"`
+	Changes []ImportChange `json:"changes"`
+}
+
+// ImportChange is a change of a resource made by an import.
+type ImportChange struct {
+	// enum: alert_rule,contact_point,notification_policy,mute_timing
+	Type string `json:"type"`
+	// example: High CPU usage
+	Name string `json:"name"`
+	// enum: create,update,delete
+	Action string `json:"action"`
+	// Paths of the changed fields of an updated resource.
+	// example: ["for","labels.severity"]
+	Diff []string `json:"diff,omitempty"`
+}
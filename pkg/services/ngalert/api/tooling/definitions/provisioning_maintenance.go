@@ -0,0 +1,141 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /v1/provisioning/maintenance-windows provisioning stable RouteGetMaintenanceWindows
+//
+// Get all maintenance windows.
+//
+//     Responses:
+//       200: MaintenanceWindows
+
+// swagger:route GET /v1/provisioning/maintenance-windows/{UID} provisioning stable RouteGetMaintenanceWindow
+//
+// Get a maintenance window.
+//
+//     Responses:
+//       200: MaintenanceWindow
+//       404: PublicError
+
+// swagger:route POST /v1/provisioning/maintenance-windows provisioning stable RoutePostMaintenanceWindow
+//
+// Create a new maintenance window.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: MaintenanceWindow
+//       400: PublicError
+
+// swagger:route PUT /v1/provisioning/maintenance-windows/{UID} provisioning stable RoutePutMaintenanceWindow
+//
+// Update a maintenance window. Set the end time to the current time to end the window early.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: MaintenanceWindow
+//       400: PublicError
+//       404: PublicError
+
+// swagger:route DELETE /v1/provisioning/maintenance-windows/{UID} provisioning stable RouteDeleteMaintenanceWindow
+//
+// Delete a maintenance window.
+//
+//     Responses:
+//       204: description: The maintenance window was deleted successfully.
+//       404: PublicError
+
+// swagger:route POST /v1/provisioning/alert-rules/pause provisioning stable RoutePostPauseAlertRules
+//
+// Pause all alert rules that match the selector.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisionedAlertRules
+//       400: PublicError
+
+// swagger:route POST /v1/provisioning/alert-rules/resume provisioning stable RoutePostResumeAlertRules
+//
+// Resume all alert rules that match the selector.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisionedAlertRules
+//       400: PublicError
+
+// swagger:parameters RouteGetMaintenanceWindow RoutePutMaintenanceWindow RouteDeleteMaintenanceWindow
+type MaintenanceWindowUIDReference struct {
+	// Maintenance window UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostMaintenanceWindow RoutePutMaintenanceWindow
+type MaintenanceWindowPayload struct {
+	// in:body
+	Body MaintenanceWindow
+}
+
+// swagger:parameters RoutePostPauseAlertRules RoutePostResumeAlertRules
+type AlertRuleSelectorPayload struct {
+	// in:body
+	Body AlertRuleSelector
+}
+
+// swagger:parameters RoutePostPauseAlertRules RoutePostResumeAlertRules
+type AlertRuleSelectorHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:model
+type MaintenanceWindows []MaintenanceWindow
+
+// MaintenanceWindow is a period during which the selected alert rules are evaluated, but their alerts are marked
+// with the state reason Maintenance and no notifications are sent.
+// swagger:model
+type MaintenanceWindow struct {
+	// required: false
+	// minLength: 1
+	// maxLength: 40
+	// pattern: ^[a-zA-Z0-9-_]+$
+	UID string `json:"uid"`
+	// required: true
+	// example: Database upgrade
+	Reason string `json:"reason"`
+	// required: true
+	// example: 2024-06-01T22:00:00Z
+	StartsAt time.Time `json:"startsAt"`
+	// required: true
+	// example: 2024-06-02T02:00:00Z
+	EndsAt time.Time `json:"endsAt"`
+	// required: true
+	Selector AlertRuleSelector `json:"selector"`
+	// UID of the user that created the window.
+	// readonly: true
+	CreatedBy string `json:"createdBy,omitempty"`
+	// readonly: true
+	Updated time.Time `json:"updated,omitempty"`
+}
+
+// AlertRuleSelector selects alert rules by their labels, folders and rule groups. A rule is selected if it matches
+// all the criteria that are set. At least one criterion is required.
+// swagger:model
+type AlertRuleSelector struct {
+	// Matchers of the labels of the alert rules.
+	// example: [["team","=","db"]]
+	Matchers ObjectMatchers `json:"matchers,omitempty"`
+	// example: ["project_x"]
+	FolderUIDs []string `json:"folderUids,omitempty"`
+	// example: ["eval_group_1"]
+	RuleGroups []string `json:"ruleGroups,omitempty"`
+}
@@ -142,16 +142,16 @@ type MuteTimeIntervalExportHcl struct {
 
 // TimeIntervalExportHcl is a representation of the timeinterval.TimeInterval in HCL
 type TimeIntervalExportHcl struct {
-	Times       []TimeRangeExportHcl `json:"times,omitempty" hcl:"times,block"`
-	Weekdays    *[]string            `json:"weekdays,omitempty" hcl:"weekdays"`
-	DaysOfMonth *[]string            `json:"days_of_month,omitempty" hcl:"days_of_month"`
-	Months      *[]string            `json:"months,omitempty" hcl:"months"`
-	Years       *[]string            `json:"years,omitempty" hcl:"years"`
-	Location    *string              `json:"location,omitempty" hcl:"location"`
+	Times       []TimeRangeExportHcl `json:"times,omitempty" yaml:"times,omitempty" hcl:"times,block"`
+	Weekdays    *[]string            `json:"weekdays,omitempty" yaml:"weekdays,omitempty" hcl:"weekdays"`
+	DaysOfMonth *[]string            `json:"days_of_month,omitempty" yaml:"days_of_month,omitempty" hcl:"days_of_month"`
+	Months      *[]string            `json:"months,omitempty" yaml:"months,omitempty" hcl:"months"`
+	Years       *[]string            `json:"years,omitempty" yaml:"years,omitempty" hcl:"years"`
+	Location    *string              `json:"location,omitempty" yaml:"location,omitempty" hcl:"location"`
 }
 
 // TimeRangeExportHcl is a representation of the timeinterval.TimeRange in HCL
 type TimeRangeExportHcl struct {
-	StartMinute string `json:"start_time" hcl:"start"`
-	EndMinute   string `json:"end_time" hcl:"end"`
+	StartMinute string `json:"start_time" yaml:"start_time" hcl:"start"`
+	EndMinute   string `json:"end_time" yaml:"end_time" hcl:"end"`
 }
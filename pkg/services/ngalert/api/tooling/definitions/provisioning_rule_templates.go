@@ -0,0 +1,190 @@
+package definitions
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// swagger:route GET /v1/provisioning/rule-templates provisioning stable RouteGetRuleTemplates
+//
+// Get all rule templates.
+//
+//     Responses:
+//       200: RuleTemplates
+
+// swagger:route GET /v1/provisioning/rule-templates/{UID} provisioning stable RouteGetRuleTemplate
+//
+// Get a rule template.
+//
+//     Responses:
+//       200: RuleTemplate
+//       404: PublicError
+
+// swagger:route POST /v1/provisioning/rule-templates provisioning stable RoutePostRuleTemplate
+//
+// Create a new rule template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: RuleTemplate
+//       400: PublicError
+
+// swagger:route PUT /v1/provisioning/rule-templates/{UID} provisioning stable RoutePutRuleTemplate
+//
+// Update a rule template and the alert rules created from it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: RuleTemplate
+//       400: PublicError
+//       404: PublicError
+//       409: PublicError
+
+// swagger:route DELETE /v1/provisioning/rule-templates/{UID} provisioning stable RouteDeleteRuleTemplate
+//
+// Delete a rule template. The template must not have any alert rules created from it.
+//
+//     Responses:
+//       204: description: The rule template was deleted successfully.
+//       404: PublicError
+//       409: PublicError
+
+// swagger:route GET /v1/provisioning/rule-templates/{UID}/instances provisioning stable RouteGetRuleTemplateInstances
+//
+// Get the alert rules created from a rule template.
+//
+//     Responses:
+//       200: RuleTemplateInstances
+//       404: PublicError
+
+// swagger:route POST /v1/provisioning/rule-templates/{UID}/instances provisioning stable RoutePostRuleTemplateInstance
+//
+// Create an alert rule from a rule template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ProvisionedAlertRule
+//       400: PublicError
+//       404: PublicError
+
+// swagger:route PUT /v1/provisioning/rule-templates/{UID}/instances provisioning stable RoutePutRuleTemplateInstances
+//
+// Update the parameter values of alert rules created from a rule template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisionedAlertRules
+//       400: PublicError
+//       404: PublicError
+
+// swagger:parameters RouteGetRuleTemplate RoutePutRuleTemplate RouteDeleteRuleTemplate RouteGetRuleTemplateInstances RoutePostRuleTemplateInstance RoutePutRuleTemplateInstances
+type RuleTemplateUIDReference struct {
+	// Rule template UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostRuleTemplate RoutePutRuleTemplate
+type RuleTemplatePayload struct {
+	// in:body
+	Body RuleTemplate
+}
+
+// swagger:parameters RoutePostRuleTemplateInstance
+type RuleTemplateInstancePayload struct {
+	// in:body
+	Body PostableRuleTemplateInstance
+}
+
+// swagger:parameters RoutePutRuleTemplateInstances
+type RuleTemplateInstancesPayload struct {
+	// in:body
+	Body []RuleTemplateInstance
+}
+
+// swagger:parameters RoutePutRuleTemplate RoutePostRuleTemplateInstance RoutePutRuleTemplateInstances
+type RuleTemplateHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:model
+type RuleTemplates []RuleTemplate
+
+// RuleTemplate is a parameterized alert rule.
+// swagger:model
+type RuleTemplate struct {
+	// required: false
+	// minLength: 1
+	// maxLength: 40
+	// pattern: ^[a-zA-Z0-9-_]+$
+	UID string `json:"uid"`
+	// required: true
+	// example: High CPU usage
+	Title string `json:"title"`
+	// example: CPU usage of the team's services is above the threshold
+	Description string `json:"description,omitempty"`
+	// example: [{"name":"threshold","type":"number","default":"80"},{"name":"team","type":"string"}]
+	Parameters []RuleTemplateParameter `json:"parameters,omitempty"`
+	// The alert rule in the format of the provisioning API. String values can reference the parameters with ${name}
+	// placeholders. A placeholder of a number parameter that is the whole string is replaced with a number.
+	// required: true
+	// example: {"title":"High CPU usage of ${team}","condition":"B","data":[{"refId":"A","datasourceUid":"${datasource}","model":{"expr":"avg(rate(cpu_seconds_total{${selector}}[5m]))"}},{"refId":"B","datasourceUid":"__expr__","model":{"type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"gt","params":["${threshold}"]}}]}}],"for":"5m","labels":{"team":"${team}"}}
+	Rule json.RawMessage `json:"rule"`
+	// Version of the template, used for optimistic concurrency when the template is updated. Leave empty to disable validation.
+	Version int64 `json:"version,omitempty"`
+	// readonly: true
+	Updated time.Time `json:"updated,omitempty"`
+}
+
+// RuleTemplateParameter is an input of a rule template.
+type RuleTemplateParameter struct {
+	// required: true
+	// pattern: ^[a-zA-Z_][a-zA-Z0-9_]*$
+	Name string `json:"name"`
+	// required: true
+	// enum: string,number,label_selector,datasource
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	// Value used when an alert rule does not set the parameter. If not set, the parameter is required.
+	Default *string `json:"default,omitempty"`
+}
+
+// swagger:model
+type RuleTemplateInstances []RuleTemplateInstance
+
+// RuleTemplateInstance is an alert rule created from a rule template.
+type RuleTemplateInstance struct {
+	// required: true
+	RuleUID string `json:"ruleUid"`
+	// example: {"team":"sre","threshold":"90"}
+	Values map[string]string `json:"values,omitempty"`
+	// readonly: true
+	TemplateVersion int64 `json:"templateVersion,omitempty"`
+}
+
+// PostableRuleTemplateInstance is the request to create an alert rule from a rule template.
+type PostableRuleTemplateInstance struct {
+	// UID of the created alert rule. Generated if not set.
+	// required: false
+	// minLength: 1
+	// maxLength: 40
+	// pattern: ^[a-zA-Z0-9-_]+$
+	RuleUID string `json:"ruleUid,omitempty"`
+	// required: true
+	// example: project_x
+	FolderUID string `json:"folderUID"`
+	// required: true
+	// example: eval_group_1
+	RuleGroup string `json:"ruleGroup"`
+	// example: {"team":"sre","threshold":"90"}
+	Values map[string]string `json:"values,omitempty"`
+}
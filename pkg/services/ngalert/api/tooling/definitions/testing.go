@@ -59,6 +59,10 @@ import (
 //
 // Test rule
 //
+// Evaluates the rule over the given time range as the scheduler would have and returns the timeline of its state transitions
+// in the same shape as the state history. If include_notifications is set, the response is an array of two frames:
+// the state transitions and the alerts that would have been sent to the Alertmanager.
+//
 //     Consumes:
 //     - application/json
 //
@@ -226,8 +230,9 @@ type BacktestConfig struct {
 	For           *model.Duration `json:"for,omitempty"`
 	KeepFiringFor *model.Duration `json:"keep_firing_for,omitempty"`
 
-	Title  string            `json:"title"`
-	Labels map[string]string `json:"labels,omitempty"`
+	Title       string            `json:"title"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
 
 	NoDataState                 NoDataState         `json:"no_data_state"`
 	ExecErrState                ExecutionErrorState `json:"exec_err_state"`
@@ -236,6 +241,10 @@ type BacktestConfig struct {
 	UID          string `json:"uid,omitempty"`
 	RuleGroup    string `json:"rule_group,omitempty"`
 	NamespaceUID string `json:"namespace_uid,omitempty"`
+
+	// IncludeNotifications adds a second frame to the result with the alerts that would have been
+	// sent to the Alertmanager at each evaluation. The result is then an array of frames.
+	IncludeNotifications bool `json:"include_notifications,omitempty"`
 }
 
 // swagger:model
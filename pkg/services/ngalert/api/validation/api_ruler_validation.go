@@ -73,6 +73,14 @@ func ValidateRuleNode(
 		NamespaceUID:                namespaceUID,
 		RuleGroup:                   groupName,
 		MissingSeriesEvalsToResolve: ruleNode.GrafanaManagedAlert.MissingSeriesEvalsToResolve,
+		EvaluationSchedule:          EvaluationScheduleFromApiEvaluationSchedule(ruleNode.GrafanaManagedAlert.EvaluationSchedule),
+		FlapDetection:               FlapDetectionFromApiFlapDetection(ruleNode.GrafanaManagedAlert.FlapDetection),
+	}
+
+	if newAlertRule.EvaluationSchedule != nil {
+		if err := newAlertRule.EvaluationSchedule.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: invalid evaluation schedule: %s", ngmodels.ErrAlertRuleFailedValidation, err)
+		}
 	}
 
 	if isRecordingRule {
@@ -157,6 +165,8 @@ func validateAlertingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 		return ngmodels.AlertRule{}, err
 	}
 
+	newRule.InhibitedBy = RuleInhibitionsFromApiRuleInhibitions(in.GrafanaManagedAlert.InhibitedBy)
+
 	newRule.For, err = validateForInterval(in)
 	if err != nil {
 		return ngmodels.AlertRule{}, err
@@ -194,6 +204,7 @@ func validateRecordingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 	newRule.KeepFiringFor = 0
 	newRule.NotificationSettings = nil
 	newRule.MissingSeriesEvalsToResolve = nil
+	newRule.InhibitedBy = nil
 
 	return newRule, nil
 }
@@ -391,6 +402,7 @@ func ValidateRuleGroup(
 		ruleWithOptionals := ngmodels.AlertRuleWithOptionals{}
 		rule.IsPaused = isPaused
 		rule.RuleGroupIndex = idx + 1
+		rule.Metadata.SequentialEvaluation = ruleGroupConfig.SequentialEvaluation
 		ruleWithOptionals.AlertRule = *rule
 		ruleWithOptionals.HasPause = hasPause
 		ruleWithOptionals.HasEditorSettings = hasEditorSettings
@@ -434,7 +446,7 @@ func ValidateBacktestConfig(orgId int64, config apimodels.BacktestConfig, limits
 			For:           config.For,
 			KeepFiringFor: config.KeepFiringFor,
 			Labels:        config.Labels,
-			Annotations:   nil,
+			Annotations:   config.Annotations,
 		},
 		GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
 			Title:                       config.Title,
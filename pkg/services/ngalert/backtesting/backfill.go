@@ -0,0 +1,161 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// BackfillResult is the result of backfilling a recording rule.
+type BackfillResult struct {
+	// Evaluations is the number of times the rule was evaluated.
+	Evaluations int
+	// Written is the number of evaluations whose result was written to at least one target data source.
+	Written int
+	// NoData is the number of evaluations that returned no data, and therefore nothing was written.
+	NoData int
+	// From and To are the times of the first and the last evaluation.
+	From time.Time
+	To   time.Time
+	// Warnings describe adjustments made to the requested backfill.
+	Warnings []string
+	// Targets is the outcome of the backfill for each target data source of the rule.
+	Targets []BackfillTargetResult
+}
+
+// BackfillTargetResult is the result of backfilling a recording rule into one target data source.
+type BackfillTargetResult struct {
+	DatasourceUID string
+	// Written is the number of evaluations whose result was written to the data source.
+	Written int
+	// Error is the error that stopped the writes to the data source. Writes to the other targets continue.
+	Error error
+}
+
+// Backfill evaluates the recording rule at every tick between from and to, as the scheduler would have,
+// and writes the results to the target data sources of the rule with the timestamp of the tick.
+func (e *Engine) Backfill(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, writer schedule.RecordingWriter) (res *BackfillResult, err error) {
+	if rule == nil {
+		return nil, fmt.Errorf("%w: rule is not defined", ErrInvalidInputData)
+	}
+	if rule.Type() != models.RuleTypeRecording {
+		return nil, fmt.Errorf("%w: only recording rules can be backfilled", ErrInvalidInputData)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: invalid interval [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
+	}
+	if to.After(time.Now()) {
+		return nil, fmt.Errorf("%w: the end of the interval must not be in the future", ErrInvalidInputData)
+	}
+
+	// Backfills evaluate past time ranges on behalf of a user, so they must not share the results of the scheduler.
+	ruleCtx := eval.WithoutDatasourceResultCache(models.WithRuleKey(ctx, rule.GetKey()))
+	logger := logger.FromContext(ruleCtx).New("backfill", util.GenerateShortUID())
+
+	res = &BackfillResult{}
+	if rule.GetInterval() < e.minInterval {
+		logger.Warn("Interval adjusted to minimal interval", "originalInterval", rule.GetInterval(), "adjustedInterval", e.minInterval)
+		rule = rule.Copy()
+		rule.IntervalSeconds = int64(e.minInterval.Seconds())
+		res.Warnings = append(res.Warnings, fmt.Sprintf("Interval adjusted to minimal interval %ds", rule.IntervalSeconds))
+	}
+
+	// Use the same ticks as the scheduler so that backfilled samples line up with the ones written by the rule.
+	jitterOffset := schedule.JitterOffsetInDuration(rule, e.baseInterval, e.jitterStrategy)
+	firstEval, err := getFirstEvaluationTime(from, rule, e.baseInterval, jitterOffset)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInputData, err)
+	}
+
+	evaluations := calculateNumberOfEvaluations(firstEval, to, rule.GetInterval())
+	if e.maxEvaluations > 0 && evaluations > e.maxEvaluations {
+		logger.Warn("Evaluations adjusted to maximal number", "originalEvaluations", evaluations, "adjustedEvaluations", e.maxEvaluations)
+		res.Warnings = append(res.Warnings, fmt.Sprintf("Number of evaluations are adjusted to the limit of %d evaluations. Requested: %d", e.maxEvaluations, evaluations))
+		evaluations = e.maxEvaluations
+	}
+
+	evaluator, err := e.evalFactory.Create(eval.NewContext(ruleCtx, user), rule.GetEvalCondition().WithSource("backfill"))
+	if err != nil {
+		return nil, errors.Join(ErrInvalidInputData, err)
+	}
+
+	start := time.Now()
+	defer func() {
+		if err == nil {
+			logger.Info("Rule backfill finished successfully", "duration", time.Since(start), "evaluations", res.Evaluations, "written", res.Written)
+		} else {
+			logger.Error("Rule backfill finished with error", "duration", time.Since(start), "error", err)
+		}
+	}()
+
+	logger.Info("Start backfilling recording rule", "from", from, "to", to, "interval", rule.GetInterval(), "firstTick", firstEval, "evaluations", evaluations, "jitterOffset", jitterOffset)
+
+	for _, dsUID := range rule.Record.TargetDatasourceUIDs() {
+		res.Targets = append(res.Targets, BackfillTargetResult{DatasourceUID: dsUID})
+	}
+	failed := 0
+
+	extraLabels := models.WithoutPrivateLabels(rule.Labels)
+	for idx, now := 0, firstEval; idx < evaluations && !now.After(to); idx, now = idx+1, now.Add(rule.GetInterval()) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := evaluator.EvaluateRaw(ruleCtx, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate rule at %s: %w", now.Format(time.RFC3339), err)
+		}
+		if err := eval.FindConditionError(result, rule.Record.From); err != nil {
+			return nil, fmt.Errorf("the query failed with an error at %s: %w", now.Format(time.RFC3339), err)
+		}
+
+		if res.Evaluations == 0 {
+			res.From = now
+		}
+		res.Evaluations++
+		res.To = now
+
+		frames, err := schedule.FrameRef(rule.Record.From, result)
+		if err != nil {
+			logger.Debug("Query returned no data", "tick", now, "reason", err)
+			res.NoData++
+			continue
+		}
+
+		written := false
+		for i := range res.Targets {
+			target := &res.Targets[i]
+			if target.Error != nil {
+				continue
+			}
+			if err := writer.WriteDatasource(ruleCtx, target.DatasourceUID, rule.Record.Metric, now, frames, rule.OrgID, extraLabels); err != nil {
+				// Skip the target for the remaining ticks, but keep filling the others.
+				logger.Warn("Remote write failed, skipping the target", "datasourceUID", target.DatasourceUID, "tick", now, "error", err)
+				target.Error = fmt.Errorf("remote write failed at %s: %w", now.Format(time.RFC3339), err)
+				failed++
+				continue
+			}
+			target.Written++
+			written = true
+		}
+		if written {
+			res.Written++
+		}
+		if failed == len(res.Targets) {
+			errs := make([]error, 0, len(res.Targets))
+			for _, target := range res.Targets {
+				errs = append(errs, target.Error)
+			}
+			return nil, errors.Join(errs...)
+		}
+	}
+
+	return res, nil
+}
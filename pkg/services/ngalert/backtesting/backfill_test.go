@@ -0,0 +1,150 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type backfillWrite struct {
+	dsUID  string
+	t      time.Time
+	labels map[string]string
+}
+
+type fakeRecordingWriter struct {
+	err error
+	// failAt makes the writes to a data source fail from the given time on.
+	failAt map[string]time.Time
+	writes []backfillWrite
+}
+
+func (w *fakeRecordingWriter) WriteDatasource(_ context.Context, dsUID string, _ string, t time.Time, _ data.Frames, _ int64, extraLabels map[string]string) error {
+	if w.err != nil {
+		return w.err
+	}
+	if failAt, ok := w.failAt[dsUID]; ok && !t.Before(failAt) {
+		return errors.New("target unavailable")
+	}
+	w.writes = append(w.writes, backfillWrite{dsUID: dsUID, t: t, labels: extraLabels})
+	return nil
+}
+
+func TestEngineBackfill(t *testing.T) {
+	interval := 10 * time.Second
+	noDataAt := time.Unix(20, 0)
+
+	evaluator := &eval_mocks.ConditionEvaluatorMock{}
+	evaluator.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, now time.Time) (*backend.QueryDataResponse, error) {
+		frames := data.Frames{data.NewFrame("", data.NewField("value", nil, []float64{float64(now.Unix())}))}
+		if now.Equal(noDataAt) {
+			frames = data.Frames{}
+		}
+		return &backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: frames}}}, nil
+	})
+
+	appURL, err := url.Parse("http://localhost:3000")
+	require.NoError(t, err)
+	engine := NewEngine(appURL, eval_mocks.NewEvaluatorFactory(evaluator), tracing.InitializeTracerForTest(), setting.UnifiedAlertingSettings{
+		BaseInterval:              interval,
+		MinInterval:               interval,
+		DisableJitter:             true,
+		BacktestingMaxEvaluations: 100,
+	}, featuremgmt.WithFeatures())
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithAllRecordingRules(), gen.WithInterval(interval), gen.WithRecordFrom("A"), gen.WithLabels(map[string]string{
+		"team":                         "a",
+		models.AutogeneratedRouteLabel: "true",
+	})).GenerateRef()
+	rule.Record.TargetDatasourceUID = "prom"
+	rule.Record.AdditionalTargetDatasourceUIDs = []string{"replica"}
+
+	t.Run("writes every tick to every target", func(t *testing.T) {
+		writer := &fakeRecordingWriter{}
+		result, err := engine.Backfill(context.Background(), nil, rule, time.Unix(0, 0), time.Unix(50, 0), writer)
+		require.NoError(t, err)
+
+		require.Equal(t, 5, result.Evaluations)
+		require.Equal(t, 4, result.Written)
+		require.Equal(t, 1, result.NoData)
+		require.Equal(t, time.Unix(0, 0), result.From)
+		require.Equal(t, time.Unix(40, 0), result.To)
+
+		require.Len(t, writer.writes, 8)
+		for i, w := range writer.writes {
+			require.Equal(t, []string{"prom", "replica"}[i%2], w.dsUID)
+			require.NotEqual(t, noDataAt, w.t)
+			require.Equal(t, map[string]string{"team": "a"}, w.labels)
+		}
+		require.Equal(t, []BackfillTargetResult{{DatasourceUID: "prom", Written: 4}, {DatasourceUID: "replica", Written: 4}}, result.Targets)
+	})
+
+	t.Run("continues with the other targets if one fails", func(t *testing.T) {
+		writer := &fakeRecordingWriter{failAt: map[string]time.Time{"replica": time.Unix(10, 0)}}
+		result, err := engine.Backfill(context.Background(), nil, rule, time.Unix(0, 0), time.Unix(50, 0), writer)
+		require.NoError(t, err)
+
+		require.Equal(t, 5, result.Evaluations)
+		require.Equal(t, 4, result.Written)
+		require.Len(t, result.Targets, 2)
+		require.Equal(t, BackfillTargetResult{DatasourceUID: "prom", Written: 4}, result.Targets[0])
+		require.Equal(t, "replica", result.Targets[1].DatasourceUID)
+		require.Equal(t, 1, result.Targets[1].Written)
+		require.ErrorContains(t, result.Targets[1].Error, "target unavailable")
+
+		var replicaWrites int
+		for _, w := range writer.writes {
+			if w.dsUID == "replica" {
+				replicaWrites++
+			}
+		}
+		require.Equal(t, 1, replicaWrites, "the failed target should be skipped after the first error")
+	})
+
+	t.Run("limits the number of evaluations", func(t *testing.T) {
+		writer := &fakeRecordingWriter{}
+		result, err := engine.Backfill(context.Background(), nil, rule, time.Unix(0, 0), time.Unix(10000, 0), writer)
+		require.NoError(t, err)
+		require.Equal(t, 100, result.Evaluations)
+		require.Len(t, result.Warnings, 1)
+	})
+
+	t.Run("fails if the writes to all targets fail", func(t *testing.T) {
+		writer := &fakeRecordingWriter{err: errors.New("write failed")}
+		_, err := engine.Backfill(context.Background(), nil, rule, time.Unix(0, 0), time.Unix(50, 0), writer)
+		require.ErrorContains(t, err, "write failed")
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		alerting := rule.Copy()
+		alerting.Record = nil
+		testCases := map[string]struct {
+			rule     *models.AlertRule
+			from, to time.Time
+		}{
+			"alerting rule":   {rule: alerting, from: time.Unix(0, 0), to: time.Unix(50, 0)},
+			"empty range":     {rule: rule, from: time.Unix(50, 0), to: time.Unix(50, 0)},
+			"range in future": {rule: rule, from: time.Unix(0, 0), to: time.Now().Add(time.Hour)},
+		}
+		for name, tc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				_, err := engine.Backfill(context.Background(), nil, tc.rule, tc.from, tc.to, &fakeRecordingWriter{})
+				require.ErrorIs(t, err, ErrInvalidInputData)
+			})
+		}
+	})
+}
@@ -45,7 +45,17 @@ type stateManager interface {
 	schedule.RuleStateProvider
 }
 
+// Result is the result of backtesting an alert rule.
+type Result struct {
+	// States is the timeline of the state transitions of the rule, in the same shape as the state history.
+	States *data.Frame
+	// Notifications are the alerts that would have been sent to the Alertmanager at each evaluation,
+	// in the same shape as States.
+	Notifications *data.Frame
+}
+
 type Engine struct {
+	appURL               *url.URL
 	evalFactory          eval.EvaluatorFactory
 	createStateManager   func() stateManager
 	disableGrafanaFolder bool
@@ -58,19 +68,21 @@ type Engine struct {
 
 func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer, cfg setting.UnifiedAlertingSettings, toggles featuremgmt.FeatureToggles) *Engine {
 	return &Engine{
+		appURL:      appUrl,
 		evalFactory: evalFactory,
 		createStateManager: func() stateManager {
-			cfg := state.ManagerCfg{
-				Metrics:       nil,
-				ExternalURL:   appUrl,
-				InstanceStore: nil,
-				Images:        &NoopImageService{},
-				Clock:         clock.New(),
-				Historian:     nil,
-				Tracer:        tracer,
-				Log:           log.New("ngalert.state.manager"),
+			managerCfg := state.ManagerCfg{
+				Metrics:           nil,
+				ExternalURL:       appUrl,
+				InstanceStore:     nil,
+				Images:            &NoopImageService{},
+				Clock:             clock.New(),
+				Historian:         nil,
+				Tracer:            tracer,
+				Log:               log.New("ngalert.state.manager"),
+				ResolvedRetention: cfg.ResolvedAlertRetention,
 			}
-			return state.NewManager(cfg, state.NewNoopPersister())
+			return state.NewManager(managerCfg, state.NewNoopPersister())
 		},
 		disableGrafanaFolder: false,
 		featureToggles:       toggles,
@@ -81,7 +93,18 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 	}
 }
 
-func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, folderTitle string) (res *data.Frame, err error) {
+// Test backtests the rule and returns the timeline of its state transitions.
+func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, folderTitle string) (*data.Frame, error) {
+	res, err := e.Backtest(ctx, user, rule, from, to, folderTitle)
+	if err != nil {
+		return nil, err
+	}
+	return res.States, nil
+}
+
+// Backtest evaluates the rule at every tick between from and to, as the scheduler would have,
+// and returns the timeline of its state transitions and the notifications that would have been sent.
+func (e *Engine) Backtest(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, folderTitle string) (res *Result, err error) {
 	if rule == nil {
 		return nil, fmt.Errorf("%w: rule is not defined", ErrInvalidInputData)
 	}
@@ -89,7 +112,8 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 		return nil, fmt.Errorf("%w: invalid interval [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
 	}
 
-	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+	// Backtests evaluate past time ranges on behalf of a user, so they must not share the results of the scheduler.
+	ruleCtx := eval.WithoutDatasourceResultCache(models.WithRuleKey(ctx, rule.GetKey()))
 	logger := logger.FromContext(ruleCtx).New("backtesting", util.GenerateShortUID())
 
 	var warns []string
@@ -146,7 +170,7 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 
 	logger.Info("Start testing alert rule", "from", from, "to", to, "interval", rule.GetInterval(), "firstTick", firstEval, "evaluations", evaluations, "jitterOffset", jitterOffset, "jitterStrategy", effectiveStrategy)
 
-	var builder *historian.QueryResultBuilder
+	var builder, notifications *historian.QueryResultBuilder
 
 	ruleMeta := history_model.RuleMeta{
 		ID:           rule.ID,
@@ -175,6 +199,12 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	}
 	extraLabels := state.GetRuleExtraLabels(logger, rule, folderTitle, !e.disableGrafanaFolder, e.featureToggles)
 
+	// the sender collects the states that would have been sent to the Alertmanager after each evaluation
+	var toSend state.StateTransitions
+	sender := func(_ context.Context, states state.StateTransitions) {
+		toSend = states
+	}
+
 	processFn := func(idx int, currentTime time.Time, results eval.Results) (bool, error) {
 		// init the builder. Do the best guess for the size of the result
 		if builder == nil {
@@ -182,8 +212,10 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 			for _, warn := range warns {
 				builder.AddWarn(warn)
 			}
+			notifications = historian.NewQueryResultBuilder(0)
 		}
-		states := stateMgr.ProcessEvalResults(ruleCtx, currentTime, rule, results, extraLabels, nil)
+		toSend = nil
+		states := stateMgr.ProcessEvalResults(ruleCtx, currentTime, rule, results, extraLabels, sender)
 		for _, s := range states {
 			if !historian.ShouldRecord(s) {
 				continue
@@ -194,6 +226,13 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 				return false, err
 			}
 		}
+		for _, s := range toSend {
+			alert, err := json.Marshal(state.StateToPostableAlert(s, e.appURL, e.featureToggles))
+			if err != nil {
+				return false, err
+			}
+			notifications.AddRowRaw(currentTime, alert, labelsBytes)
+		}
 		return idx <= evaluations, nil
 	}
 
@@ -204,7 +243,12 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	if builder == nil {
 		return nil, errors.New("no results were produced")
 	}
-	return builder.ToFrame(), nil
+	notificationsFrame := notifications.ToFrame()
+	notificationsFrame.Name = "notifications"
+	return &Result{
+		States:        builder.ToFrame(),
+		Notifications: notificationsFrame,
+	}, nil
 }
 
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, reader eval.AlertingResultsReader) (backtestingEvaluator, error) {
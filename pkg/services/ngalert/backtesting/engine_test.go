@@ -5,20 +5,24 @@ import (
 	"encoding/json"
 	"errors"
 	"math/rand"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -248,6 +252,64 @@ func TestEvaluatorTest(t *testing.T) {
 	})
 }
 
+func TestEngineBacktestNotifications(t *testing.T) {
+	interval := 10 * time.Second
+	firingUntil := time.Unix(60, 0)
+	evaluator := &fakeBacktestingEvaluator{
+		evalCallback: func(now time.Time) (eval.Results, error) {
+			state := eval.Alerting
+			if !now.Before(firingUntil) {
+				state = eval.Normal
+			}
+			return eval.Results{{Instance: data.Labels{"instance": "a"}, State: state, EvaluatedAt: now}}, nil
+		},
+	}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, r eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	appURL, err := url.Parse("http://localhost:3000")
+	require.NoError(t, err)
+	engine := NewEngine(appURL, nil, tracing.InitializeTracerForTest(), setting.UnifiedAlertingSettings{
+		BaseInterval:           interval,
+		MinInterval:            interval,
+		ResolvedAlertRetention: 15 * time.Minute,
+		DisableJitter:          true,
+	}, featuremgmt.WithFeatures())
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithInterval(interval), gen.WithFor(0), gen.WithKeepFiringFor(0)).GenerateRef()
+
+	result, err := engine.Backtest(context.Background(), nil, rule, time.Unix(0, 0), time.Unix(100, 0), "")
+	require.NoError(t, err)
+	require.Equal(t, "states", result.States.Name)
+	require.Equal(t, "notifications", result.Notifications.Name)
+
+	var sentAt []int64
+	var alerts []amv2.PostableAlert
+	for i := 0; i < result.Notifications.Rows(); i++ {
+		sentAt = append(sentAt, result.Notifications.Fields[0].At(i).(time.Time).Unix())
+		var alert amv2.PostableAlert
+		require.NoError(t, json.Unmarshal(result.Notifications.Fields[1].At(i).(json.RawMessage), &alert))
+		alerts = append(alerts, alert)
+	}
+	// firing alerts are re-sent after the resend delay, and the resolved alert is sent once it resolves
+	// and again within the resolved retention
+	require.Equal(t, []int64{0, 30, 60, 90}, sentAt)
+	for _, alert := range alerts {
+		require.Equal(t, "a", alert.Labels["instance"])
+	}
+	require.True(t, time.Time(alerts[1].EndsAt).After(time.Unix(30, 0)), "firing alert should end in the future")
+	require.Equal(t, time.Unix(60, 0).Unix(), time.Time(alerts[2].EndsAt).Unix(), "resolved alert should end when it was resolved")
+
+	states, err := engine.Test(context.Background(), nil, rule, time.Unix(0, 0), time.Unix(100, 0), "")
+	require.NoError(t, err)
+	require.Equal(t, result.States.Rows(), states.Rows())
+}
+
 type fakeStateManager struct {
 	stateCallback func(now time.Time) []state.StateTransition
 }
@@ -42,7 +42,7 @@ func (d *dataEvaluator) Eval(_ context.Context, from time.Time, interval time.Du
 	to := from.Add(time.Duration(evaluations) * interval)
 	for _, s := range d.data {
 		// making sure the input data frame is aligned with the interval
-		r, err := s.Resample(d.refID, interval, d.downsampleFunction, d.upsampleFunction, from, to.Add(-interval)) // we want to query [from,to)
+		r, err := s.Resample(d.refID, interval, d.downsampleFunction, d.upsampleFunction, 0, from, to.Add(-interval)) // we want to query [from,to)
 		if err != nil {
 			return err
 		}
@@ -21,6 +21,19 @@ type EvaluationContext struct {
 	AlertingResultsReader AlertingResultsReader
 }
 
+type skipDatasourceResultCacheKey struct{}
+
+// WithoutDatasourceResultCache returns a context in which the evaluations of rules neither read nor fill the
+// data source result cache. It is used to evaluate rules outside of the scheduler, such as in backtests.
+func WithoutDatasourceResultCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipDatasourceResultCacheKey{}, true)
+}
+
+func skipsDatasourceResultCache(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipDatasourceResultCacheKey{}).(bool)
+	return skip
+}
+
 func NewContext(ctx context.Context, user identity.Requester) EvaluationContext {
 	return EvaluationContext{
 		Ctx:  ctx,
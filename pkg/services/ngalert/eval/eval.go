@@ -357,10 +357,14 @@ func buildDatasourceHeaders(ctx context.Context, metadata map[string]string) map
 
 // getExprRequest validates the condition, gets the datasource information and creates an expr.Request from it.
 func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheService datasources.CacheService, reader AlertingResultsReader) (*expr.Request, error) {
+	// Data source results are shared only between the evaluations of stored rules, which have a rule key,
+	// so that testing a rule always queries the data sources.
+	_, isRuleEvaluation := models.RuleKeyFromContext(ctx.Ctx)
 	req := &expr.Request{
-		OrgId:   ctx.User.GetOrgID(),
-		Headers: buildDatasourceHeaders(ctx.Ctx, condition.Metadata),
-		User:    ctx.User,
+		OrgId:                  ctx.User.GetOrgID(),
+		Headers:                buildDatasourceHeaders(ctx.Ctx, condition.Metadata),
+		User:                   ctx.User,
+		CacheDatasourceResults: isRuleEvaluation && !skipsDatasourceResultCache(ctx.Ctx),
 	}
 	datasources := make(map[string]*datasources.DataSource, len(condition.Data))
 
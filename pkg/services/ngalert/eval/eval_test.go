@@ -1558,6 +1558,38 @@ func TestCreate(t *testing.T) {
 
 		require.Equal(t, expectedHeaders, request.Headers)
 	})
+
+	t.Run("should share data source results only between scheduled evaluations", func(t *testing.T) {
+		q := models.CreateClassicConditionExpression("A", "B", "avg", "gt", 1)
+		condition := models.Condition{Condition: q.RefID, Data: []models.AlertQuery{q}}
+		ruleCtx := models.WithRuleKey(context.Background(), models.GenerateRuleKey(1))
+
+		for _, tc := range []struct {
+			name     string
+			ctx      context.Context
+			expected bool
+		}{
+			{name: "scheduled evaluation", ctx: ruleCtx, expected: true},
+			{name: "rule test", ctx: context.Background(), expected: false},
+			{name: "backtest", ctx: WithoutDatasourceResultCache(ruleCtx), expected: false},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				var request *expr.Request
+				factory := evaluatorImpl{
+					expressionService: fakeExpressionService{
+						buildHook: func(ctx context.Context, req *expr.Request) (expr.DataPipeline, error) {
+							request = req
+							return expr.DataPipeline{fakeNode{refID: q.RefID}}, nil
+						},
+					},
+				}
+
+				_, err := factory.Create(NewContext(tc.ctx, &user.SignedInUser{}), condition)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, request.CacheDatasourceResults)
+			})
+		}
+	})
 }
 
 type fakeExpressionService struct {
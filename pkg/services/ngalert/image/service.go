@@ -26,15 +26,22 @@ const (
 
 // DeleteExpiredService is a service to delete expired images.
 type DeleteExpiredService struct {
-	store store.ImageAdminStore
+	store      store.ImageAdminStore
+	imageStore *BlobImageStore
 }
 
+// DeleteExpired deletes expired images, and the stored screenshots whose URLs have expired.
 func (s *DeleteExpiredService) DeleteExpired(ctx context.Context) (int64, error) {
-	return s.store.DeleteExpiredImages(ctx)
+	n, err := s.store.DeleteExpiredImages(ctx)
+	if err != nil || s.imageStore == nil {
+		return n, err
+	}
+	deleted, err := s.imageStore.DeleteExpired(ctx)
+	return n + deleted, err
 }
 
-func ProvideDeleteExpiredService(store *store.DBstore) *DeleteExpiredService {
-	return &DeleteExpiredService{store: store}
+func ProvideDeleteExpiredService(store *store.DBstore, imageStore *BlobImageStore) *DeleteExpiredService {
+	return &DeleteExpiredService{store: store, imageStore: imageStore}
 }
 
 type ImageService interface {
@@ -78,9 +85,10 @@ func NewScreenshotImageService(
 }
 
 // NewScreenshotImageServiceFromCfg returns a new ScreenshotImageService
-// from the configuration.
+// from the configuration. If imageStore is not nil, screenshots are uploaded
+// to it instead of the external image storage.
 func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds dashboards.DashboardService,
-	rs rendering.Service, r prometheus.Registerer) (ImageService, error) {
+	rs rendering.Service, imageStore *BlobImageStore, r prometheus.Registerer) (ImageService, error) {
 	var (
 		cache             CacheService                 = &NoOpCacheService{}
 		limiter           screenshot.RateLimiter       = &screenshot.NoOpRateLimiter{}
@@ -97,7 +105,9 @@ func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds da
 		screenshotTimeout = cfg.UnifiedAlerting.Screenshots.CaptureTimeout
 
 		// Image uploading is an optional feature
-		if cfg.UnifiedAlerting.Screenshots.UploadExternalImageStorage {
+		if imageStore != nil {
+			uploads = NewUploadingService(imageStore, r)
+		} else if cfg.UnifiedAlerting.Screenshots.UploadExternalImageStorage {
 			m, err := imguploader.NewImageUploader(cfg)
 			if err != nil {
 				return nil, fmt.Errorf("failed to initialize uploading screenshot service: %w", err)
@@ -0,0 +1,264 @@
+package image
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/fileblob"
+	"gocloud.dev/gcerrors"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	// imageStoreRoute is the route from which stored images are served.
+	imageStoreRoute = "api/alerting/images"
+	// ImageStoreRoutePath is the path of the route, with the key of the image as parameter.
+	ImageStoreRoutePath = "/" + imageStoreRoute + "/:key"
+
+	// imageStoreDir is the directory of images in the data directory or in the blob bucket.
+	imageStoreDir = "alerting/images"
+)
+
+var (
+	ErrImageStoreInvalidSignature = errors.New("invalid or expired signature")
+	ErrImageStoreImageNotFound    = errors.New("image not found")
+)
+
+var _ imguploader.ImageUploader = (*BlobImageStore)(nil)
+
+// imageBucket is the part of the blob bucket used by the image store.
+type imageBucket interface {
+	WriteAll(ctx context.Context, key string, p []byte, opts *blob.WriterOptions) error
+	NewReader(ctx context.Context, key string, opts *blob.ReaderOptions) (*blob.Reader, error)
+	List(opts *blob.ListOptions) *blob.ListIterator
+	Delete(ctx context.Context, key string) error
+}
+
+// BlobImageStore stores screenshots in a blob bucket and serves them from Grafana through
+// signed URLs that expire. It implements imguploader.ImageUploader, and can be used instead
+// of an external image storage.
+type BlobImageStore struct {
+	bucket        imageBucket
+	appURL        string
+	secret        []byte
+	urlExpiration time.Duration
+	logger        log.Logger
+	now           func() time.Time
+}
+
+// NewBlobImageStore returns a new BlobImageStore. appURL is the URL of Grafana, and secret is used
+// to sign the URLs of the images.
+func NewBlobImageStore(bucket imageBucket, appURL string, secret string, urlExpiration time.Duration, logger log.Logger) *BlobImageStore {
+	return &BlobImageStore{
+		bucket:        bucket,
+		appURL:        strings.TrimSuffix(appURL, "/"),
+		secret:        []byte(secret),
+		urlExpiration: urlExpiration,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// ProvideBlobImageStore provides the BlobImageStore shared by the alerting screenshots and the
+// cleanup of expired images. It returns nil if the image store is disabled.
+func ProvideBlobImageStore(cfg *setting.Cfg) (*BlobImageStore, error) {
+	return NewBlobImageStoreFromCfg(context.Background(), cfg)
+}
+
+// NewBlobImageStoreFromCfg returns a new BlobImageStore from the configuration. It returns nil
+// if the image store is disabled.
+func NewBlobImageStoreFromCfg(ctx context.Context, cfg *setting.Cfg) (*BlobImageStore, error) {
+	screenshots := cfg.UnifiedAlerting.Screenshots
+	if screenshots.ImageStore == "" {
+		return nil, nil
+	}
+	if cfg.SecretKey == "" {
+		return nil, errors.New("image store requires a secret key to sign the URLs of images")
+	}
+
+	var (
+		bucket *blob.Bucket
+		err    error
+	)
+	switch screenshots.ImageStore {
+	case setting.ScreenshotsImageStoreLocal:
+		bucket, err = fileblob.OpenBucket(filepath.Join(cfg.DataPath, imageStoreDir), &fileblob.Options{
+			CreateDir: true,
+			NoTempDir: true,
+			Metadata:  fileblob.MetadataDontWrite,
+		})
+	case setting.ScreenshotsImageStoreBlob:
+		blobURL := screenshots.ImageStoreBlobURL
+		// Like unified storage, support a local directory relative to the data directory.
+		if strings.HasPrefix(blobURL, "./data/") {
+			dir := strings.Replace(blobURL, "./data", cfg.DataPath, 1)
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				return nil, err
+			}
+			blobURL = "file:///" + dir
+		}
+		bucket, err = resource.OpenBlobBucket(ctx, blobURL)
+		if err == nil {
+			// The bucket is shared with unified storage, so images are kept under a prefix.
+			bucket = blob.PrefixedBucket(bucket, imageStoreDir+"/")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported image store %q", screenshots.ImageStore)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bucket of image store: %w", err)
+	}
+
+	return NewBlobImageStore(bucket, cfg.AppURL, cfg.SecretKey, screenshots.ImageStoreURLExpiration, log.New("ngalert.image.store")), nil
+}
+
+// Upload stores the image at the path and returns its signed URL.
+func (s *BlobImageStore) Upload(ctx context.Context, path string) (string, error) {
+	// We can ignore the gosec G304 warning on this one because `path` comes
+	// from the screenshot service and is only used for images generated by alerting.
+	// nolint:gosec
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	ext := filepath.Ext(path)
+	key := uuid.NewString() + ext
+	if err := s.bucket.WriteAll(ctx, key, b, &blob.WriterOptions{ContentType: mime.TypeByExtension(ext)}); err != nil {
+		return "", fmt.Errorf("failed to store image: %w", err)
+	}
+	return s.SignedURL(key), nil
+}
+
+// SignedURL returns the URL of the image with the key. The URL expires after the URL expiration
+// of the store.
+func (s *BlobImageStore) SignedURL(key string) string {
+	expires := strconv.FormatInt(s.now().Add(s.urlExpiration).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("signature", s.sign(key, expires))
+	return s.appURL + "/" + imageStoreRoute + "/" + url.PathEscape(key) + "?" + q.Encode()
+}
+
+// GetImage returns the content and the content type of the image with the key if the signature is
+// valid and has not expired. It returns ErrImageStoreInvalidSignature if the signature is invalid
+// or has expired, and ErrImageStoreImageNotFound if the image does not exist.
+func (s *BlobImageStore) GetImage(ctx context.Context, key, expires, signature string) ([]byte, string, error) {
+	if err := s.verify(key, expires, signature); err != nil {
+		return nil, "", err
+	}
+
+	r, err := s.bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, "", ErrImageStoreImageNotFound
+		}
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+	defer func() {
+		if err := r.Close(); err != nil {
+			s.logger.Warn("Failed to close image reader", "key", key, "error", err)
+		}
+	}()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+	contentType := r.ContentType()
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(key))
+	}
+	return b, contentType, nil
+}
+
+// DeleteExpired deletes the images whose URLs have expired. It returns the number of deleted images.
+func (s *BlobImageStore) DeleteExpired(ctx context.Context) (int64, error) {
+	threshold := s.now().Add(-s.urlExpiration)
+	var n int64
+	it := s.bucket.List(nil)
+	for {
+		obj, err := it.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return n, fmt.Errorf("failed to list images: %w", err)
+		}
+		if obj.IsDir || !obj.ModTime.Before(threshold) {
+			continue
+		}
+		if err := s.bucket.Delete(ctx, obj.Key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return n, fmt.Errorf("failed to delete image: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// RouteGetImage serves the image with the key in the path. The request does not need to be
+// authenticated, as the URL of the image is signed.
+func (s *BlobImageStore) RouteGetImage(c *contextmodel.ReqContext) response.Response {
+	key := web.Params(c.Req)[":key"]
+	b, contentType, err := s.GetImage(c.Req.Context(), key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrImageStoreInvalidSignature):
+			return response.Error(http.StatusForbidden, err.Error(), nil)
+		case errors.Is(err, ErrImageStoreImageNotFound):
+			return response.Error(http.StatusNotFound, err.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get image", err)
+	}
+	return response.CreateNormalResponse(http.Header{
+		"Content-Type":  []string{contentType},
+		"Cache-Control": []string{"private, max-age=3600"},
+	}, b, http.StatusOK)
+}
+
+func (s *BlobImageStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *BlobImageStore) verify(key, expires, signature string) error {
+	if key == "" || strings.ContainsAny(key, "/\\") {
+		return ErrImageStoreImageNotFound
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > expiresAt {
+		return ErrImageStoreInvalidSignature
+	}
+	expected, err := hex.DecodeString(s.sign(key, expires))
+	if err != nil {
+		return err
+	}
+	actual, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, actual) {
+		return ErrImageStoreInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package image
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestBlobImageStore(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	s := NewBlobImageStore(bucket, "https://grafana.example.com/", "secret", time.Hour, log.NewNopLogger())
+
+	imagePath := filepath.Join(t.TempDir(), "screenshot.png")
+	require.NoError(t, os.WriteFile(imagePath, []byte("image"), 0600))
+
+	imageURL, err := s.Upload(ctx, imagePath)
+	require.NoError(t, err)
+	u, err := url.Parse(imageURL)
+	require.NoError(t, err)
+	assert.Equal(t, "grafana.example.com", u.Host)
+	assert.Equal(t, "/api/alerting/images", path.Dir(u.Path))
+	key := path.Base(u.Path)
+	assert.Equal(t, ".png", path.Ext(key))
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+
+	t.Run("returns the image if the signature is valid", func(t *testing.T) {
+		b, contentType, err := s.GetImage(ctx, key, expires, signature)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("image"), b)
+		assert.Equal(t, "image/png", contentType)
+	})
+
+	t.Run("returns an error if the signature is invalid", func(t *testing.T) {
+		_, _, err := s.GetImage(ctx, key, expires, "00"+signature[2:])
+		assert.ErrorIs(t, err, ErrImageStoreInvalidSignature)
+
+		_, _, err = s.GetImage(ctx, key, expires+"0", signature)
+		assert.ErrorIs(t, err, ErrImageStoreInvalidSignature)
+
+		other := NewBlobImageStore(bucket, "https://grafana.example.com/", "other", time.Hour, log.NewNopLogger())
+		_, _, err = other.GetImage(ctx, key, expires, signature)
+		assert.ErrorIs(t, err, ErrImageStoreInvalidSignature)
+	})
+
+	t.Run("returns an error if the signature has expired", func(t *testing.T) {
+		s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		t.Cleanup(func() { s.now = time.Now })
+
+		_, _, err := s.GetImage(ctx, key, expires, signature)
+		assert.ErrorIs(t, err, ErrImageStoreInvalidSignature)
+	})
+
+	t.Run("returns an error if the image does not exist", func(t *testing.T) {
+		signed, err := url.Parse(s.SignedURL("unknown.png"))
+		require.NoError(t, err)
+
+		_, _, err = s.GetImage(ctx, "unknown.png", signed.Query().Get("expires"), signed.Query().Get("signature"))
+		assert.ErrorIs(t, err, ErrImageStoreImageNotFound)
+	})
+
+	t.Run("deletes the images whose URLs have expired", func(t *testing.T) {
+		n, err := s.DeleteExpired(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+
+		s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		t.Cleanup(func() { s.now = time.Now })
+
+		n, err = s.DeleteExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		exists, err := bucket.Exists(ctx, key)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
@@ -8,6 +8,11 @@ import (
 type RemoteWriter struct {
 	WritesTotal   *prometheus.CounterVec
 	WriteDuration *prometheus.HistogramVec
+
+	WALPoints         *prometheus.GaugeVec
+	WALBytes          prometheus.Gauge
+	WALDroppedPoints  *prometheus.CounterVec
+	WALReplayedPoints *prometheus.CounterVec
 }
 
 func NewRemoteWriterMetrics(r prometheus.Registerer) *RemoteWriter {
@@ -26,5 +31,29 @@ func NewRemoteWriterMetrics(r prometheus.Registerer) *RemoteWriter {
 				Help:      "Histogram of remote write durations.",
 				Buckets:   prometheus.DefBuckets,
 			}, []string{"org", "backend"}),
+		WALPoints: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_writer_wal_points",
+			Help:      "The number of points buffered in the write-ahead log while their data source is not reachable.",
+		}, []string{"org"}),
+		WALBytes: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_writer_wal_bytes",
+			Help:      "The size of the write-ahead log on disk.",
+		}),
+		WALDroppedPoints: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_writer_wal_dropped_points_total",
+			Help:      "The total number of points that were not buffered because the write-ahead log was full, or were rejected when replayed.",
+		}, []string{"org", "reason"}),
+		WALReplayedPoints: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_writer_wal_replayed_points_total",
+			Help:      "The total number of points replayed from the write-ahead log.",
+		}, []string{"org"}),
 	}
 }
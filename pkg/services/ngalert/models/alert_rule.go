@@ -19,6 +19,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
 	prommodels "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -145,6 +146,14 @@ const (
 	// StateReasonAnnotation is the name of the annotation that explains the difference between evaluation state and alert state (i.e. changing state when NoData or Error).
 	StateReasonAnnotation = GrafanaReservedLabelPrefix + "state_reason"
 
+	// FlappingAnnotation is the name of the annotation that is set while an alert is flapping. Its value is the time
+	// at which the alert started flapping.
+	FlappingAnnotation = GrafanaReservedLabelPrefix + "flapping"
+
+	// MaintenanceWindowAnnotation is the name of the annotation that is set while an alert is in maintenance. Its value
+	// is the UID of the active maintenance window.
+	MaintenanceWindowAnnotation = GrafanaReservedLabelPrefix + "maintenance_window"
+
 	// MigratedLabelPrefix is a label prefix for all labels created during legacy migration.
 	MigratedLabelPrefix = "__legacy_"
 	// MigratedUseLegacyChannelsLabel is created during legacy migration to route to separate nested policies for migrated channels.
@@ -184,6 +193,9 @@ const (
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
 	StateReasonKeepLast      = "KeepLast"
+	StateReasonInhibited     = "Inhibited"
+	StateReasonFlapping      = "Flapping"
+	StateReasonMaintenance   = "Maintenance"
 )
 
 func ConcatReasons(reasons ...string) string {
@@ -372,6 +384,13 @@ type AlertRule struct {
 	// If nil, alerts resolve after 2 missing evaluation intervals
 	// (i.e., resolution occurs during the second evaluation where data is absent).
 	MissingSeriesEvalsToResolve *int64
+	// InhibitedBy lists the rules this rule depends on. While an alert of one of these rules is firing,
+	// the alerts of this rule with the same values of the equal labels are inhibited and not sent to the Alertmanager.
+	InhibitedBy []RuleInhibition
+	// EvaluationSchedule restricts when the rule is evaluated. If nil, the rule is evaluated every interval.
+	EvaluationSchedule *EvaluationSchedule
+	// FlapDetection holds the notifications of alerts whose state changes too often. If nil, flapping is not detected.
+	FlapDetection *FlapDetection
 }
 
 type AlertRuleVersion struct {
@@ -384,6 +403,9 @@ type AlertRuleVersion struct {
 type AlertRuleMetadata struct {
 	EditorSettings      EditorSettings       `json:"editor_settings"`
 	PrometheusStyleRule *PrometheusStyleRule `json:"prometheus_style_rule,omitempty"`
+	// SequentialEvaluation is a setting of the rule group that is stored on each of its rules.
+	// If set, the rules of the group are evaluated one after another instead of independently.
+	SequentialEvaluation bool `json:"sequential_evaluation,omitempty"`
 }
 
 type EditorSettings struct {
@@ -589,6 +611,12 @@ func (alertRule *AlertRule) Diff(rule *AlertRule, ignore ...string) cmputil.Diff
 		cmpopts.IgnoreFields(AlertQuery{}, "modelProps", "DatasourceType", "IsMTQuery"),
 		jsonCmp,
 		cmpopts.EquateEmpty(),
+		// the parsed schedule is derived from the exported fields
+		cmpopts.IgnoreUnexported(EvaluationSchedule{}),
+		// timeinterval.Location wraps time.Location that has unexported fields
+		cmp.Comparer(func(a, b timeinterval.Location) bool {
+			return a.String() == b.String()
+		}),
 	)
 
 	if len(ignore) > 0 {
@@ -782,6 +810,22 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		}
 	}
 
+	if err := validateRuleInhibitions(alertRule); err != nil {
+		return fmt.Errorf("%w: %s", ErrAlertRuleFailedValidation, err)
+	}
+
+	if alertRule.EvaluationSchedule != nil {
+		if err := alertRule.EvaluationSchedule.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid evaluation schedule: %s", ErrAlertRuleFailedValidation, err)
+		}
+	}
+
+	if alertRule.FlapDetection != nil {
+		if err := alertRule.FlapDetection.Validate(alertRule.IntervalSeconds); err != nil {
+			return fmt.Errorf("%w: invalid flap detection: %s", ErrAlertRuleFailedValidation, err)
+		}
+	}
+
 	if len(alertRule.NotificationSettings) > 0 {
 		if len(alertRule.NotificationSettings) != 1 {
 			return fmt.Errorf("%w: only one notification settings entry is allowed", ErrAlertRuleFailedValidation)
@@ -817,6 +861,11 @@ func validateRecordingRuleFields(rule *AlertRule) error {
 	if !prommodels.IsValidMetricName(metricName) { // nolint:staticcheck
 		return errors.New("metric name for recording rule must be a valid Prometheus metric name")
 	}
+	for _, uid := range rule.Record.AdditionalTargetDatasourceUIDs {
+		if uid == "" {
+			return errors.New("additional target data source UIDs of recording rule must not be empty")
+		}
+	}
 
 	ClearRecordingRuleIgnoredFields(rule)
 
@@ -918,6 +967,8 @@ func (alertRule *AlertRule) Copy() *AlertRule {
 			From:                alertRule.Record.From,
 			Metric:              alertRule.Record.Metric,
 			TargetDatasourceUID: alertRule.Record.TargetDatasourceUID,
+
+			AdditionalTargetDatasourceUIDs: slices.Clone(alertRule.Record.AdditionalTargetDatasourceUIDs),
 		}
 	}
 
@@ -930,6 +981,16 @@ func (alertRule *AlertRule) Copy() *AlertRule {
 		result.NotificationSettings = append(result.NotificationSettings, CopyNotificationSettings(s))
 	}
 
+	for _, i := range alertRule.InhibitedBy {
+		result.InhibitedBy = append(result.InhibitedBy, RuleInhibition{
+			RuleUID: i.RuleUID,
+			Equal:   slices.Clone(i.Equal),
+		})
+	}
+
+	result.EvaluationSchedule = alertRule.EvaluationSchedule.Copy()
+	result.FlapDetection = alertRule.FlapDetection.Copy()
+
 	return &result
 }
 
@@ -941,6 +1002,8 @@ func ClearRecordingRuleIgnoredFields(rule *AlertRule) {
 	rule.KeepFiringFor = 0
 	rule.NotificationSettings = nil
 	rule.MissingSeriesEvalsToResolve = nil
+	rule.InhibitedBy = nil
+	rule.FlapDetection = nil
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -1251,6 +1314,22 @@ type Record struct {
 	From string
 	// TargetDatasourceUID is the data source to write the result of the recording rule.
 	TargetDatasourceUID string
+	// AdditionalTargetDatasourceUIDs are the data sources the result of the recording rule is written to
+	// in addition to TargetDatasourceUID.
+	AdditionalTargetDatasourceUIDs []string `json:",omitempty"`
+}
+
+// TargetDatasourceUIDs returns all the data sources the result of the recording rule is written to.
+// The first one is TargetDatasourceUID, which is empty if the default data source is used.
+func (r *Record) TargetDatasourceUIDs() []string {
+	result := make([]string, 0, 1+len(r.AdditionalTargetDatasourceUIDs))
+	result = append(result, r.TargetDatasourceUID)
+	for _, uid := range r.AdditionalTargetDatasourceUIDs {
+		if !slices.Contains(result, uid) {
+			result = append(result, uid)
+		}
+	}
+	return result
 }
 
 func (r *Record) Fingerprint() data.Fingerprint {
@@ -1266,9 +1345,63 @@ func (r *Record) Fingerprint() data.Fingerprint {
 	writeString(r.Metric)
 	writeString(r.From)
 	writeString(r.TargetDatasourceUID)
+	for _, uid := range r.AdditionalTargetDatasourceUIDs {
+		writeString(uid)
+	}
 	return data.Fingerprint(h.Sum64())
 }
 
+// RuleInhibition references a rule that inhibits the alerts of another rule.
+type RuleInhibition struct {
+	// RuleUID is the UID of the inhibiting rule in the same organization.
+	RuleUID string `json:"rule_uid"`
+	// Equal is the list of labels that must have the same values in the alert of the inhibiting rule
+	// and in the inhibited alert. If empty, any firing alert of the inhibiting rule inhibits all alerts of the rule.
+	Equal []string `json:"equal,omitempty"`
+}
+
+// Inhibits returns true if the alert of the inhibiting rule with the given labels inhibits the alert with the target labels.
+func (i RuleInhibition) Inhibits(source, target map[string]string) bool {
+	for _, l := range i.Equal {
+		if source[l] != target[l] {
+			return false
+		}
+	}
+	return true
+}
+
+func (i RuleInhibition) Fingerprint() data.Fingerprint {
+	h := fnv.New64()
+	_, _ = h.Write([]byte(i.RuleUID))
+	for _, l := range i.Equal {
+		_, _ = h.Write([]byte{255})
+		_, _ = h.Write([]byte(l))
+	}
+	return data.Fingerprint(h.Sum64())
+}
+
+func validateRuleInhibitions(rule *AlertRule) error {
+	seen := make(map[string]struct{}, len(rule.InhibitedBy))
+	for _, i := range rule.InhibitedBy {
+		if i.RuleUID == "" {
+			return errors.New("inhibiting rule UID cannot be empty")
+		}
+		if rule.UID != "" && i.RuleUID == rule.UID {
+			return errors.New("rule cannot be inhibited by itself")
+		}
+		if _, ok := seen[i.RuleUID]; ok {
+			return fmt.Errorf("rule %s is referenced more than once in inhibited_by", i.RuleUID)
+		}
+		seen[i.RuleUID] = struct{}{}
+		for _, l := range i.Equal {
+			if !prommodels.LabelName(l).IsValid() {
+				return fmt.Errorf("invalid label name %q in the equal labels of inhibiting rule %s", l, i.RuleUID)
+			}
+		}
+	}
+	return nil
+}
+
 func hasAnyCondition(rule *AlertRuleWithOptionals) bool {
 	return rule.Condition != "" || (rule.Record != nil && rule.Record.From != "")
 }
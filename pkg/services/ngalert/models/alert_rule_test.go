@@ -12,6 +12,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -412,7 +413,7 @@ func TestDiff(t *testing.T) {
 			RuleGen.WithMissingSeriesEvalsToResolve(*rule1.MissingSeriesEvalsToResolve + 1),
 		).GenerateRef()
 
-		diffs := rule1.Diff(rule2, "Data", "Annotations", "Labels", "NotificationSettings", "Metadata") // these fields will be tested separately
+		diffs := rule1.Diff(rule2, "Data", "Annotations", "Labels", "NotificationSettings", "Metadata", "InhibitedBy") // these fields will be tested separately
 
 		difCnt := 0
 		if rule1.ID != rule2.ID {
@@ -566,6 +567,39 @@ func TestDiff(t *testing.T) {
 		}
 	})
 
+	t.Run("should detect changes in InhibitedBy", func(t *testing.T) {
+		rule1 := RuleGen.With(RuleMuts.WithInhibitedBy(RuleInhibition{RuleUID: "a", Equal: []string{"cluster"}})).GenerateRef()
+		rule2 := CopyRule(rule1)
+		rule2.InhibitedBy[0].Equal = []string{"namespace"}
+
+		diff := rule1.Diff(rule2)
+
+		assert.Len(t, diff, 1)
+		d := diff.GetDiffsForField("InhibitedBy[0].Equal[0]")
+		assert.Len(t, d, 1)
+		assert.Equal(t, "cluster", d[0].Left.String())
+		assert.Equal(t, "namespace", d[0].Right.String())
+	})
+
+	t.Run("should detect changes in EvaluationSchedule", func(t *testing.T) {
+		rule1 := RuleGen.With(RuleMuts.WithEvaluationSchedule(&EvaluationSchedule{
+			Cron:     "0 8 * * 1-5",
+			Timezone: "Europe/Berlin",
+			ActiveTimeIntervals: []timeinterval.TimeInterval{{
+				Location: &timeinterval.Location{Location: time.UTC},
+			}},
+		})).GenerateRef()
+		rule2 := CopyRule(rule1)
+		require.Empty(t, rule1.Diff(rule2))
+
+		rule2.EvaluationSchedule.Timezone = "UTC"
+		diff := rule1.Diff(rule2)
+
+		assert.Len(t, diff, 1)
+		d := diff.GetDiffsForField("EvaluationSchedule.Timezone")
+		assert.Len(t, d, 1)
+	})
+
 	t.Run("should not see difference between nil and empty Annotations", func(t *testing.T) {
 		rule1 := RuleGen.GenerateRef()
 		rule1.Annotations = make(map[string]string)
@@ -1040,9 +1074,11 @@ func TestAlertRuleCopy(t *testing.T) {
 // This test makes sure the default generator
 func TestGeneratorFillsAllFields(t *testing.T) {
 	ignoredFields := map[string]struct{}{
-		"ID":       {},
-		"IsPaused": {},
-		"Record":   {},
+		"ID":                 {},
+		"IsPaused":           {},
+		"Record":             {},
+		"EvaluationSchedule": {},
+		"FlapDetection":      {},
 	}
 
 	tpe := reflect.TypeOf(AlertRule{})
@@ -1085,6 +1121,9 @@ func TestGeneratorFillsAllRecordingRuleFields(t *testing.T) {
 		"MissingSeriesEvalsToResolve": {},
 		"For":                         {},
 		"NotificationSettings":        {},
+		"InhibitedBy":                 {},
+		"EvaluationSchedule":          {},
+		"FlapDetection":               {},
 	}
 
 	tpe := reflect.TypeOf(AlertRule{})
@@ -1254,6 +1293,68 @@ func TestValidateAlertRule(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("inhibitedBy", func(t *testing.T) {
+		testCases := []struct {
+			name                  string
+			inhibitedBy           []RuleInhibition
+			expectedErrorContains string
+		}{
+			{
+				name:        "should accept inhibitions",
+				inhibitedBy: []RuleInhibition{{RuleUID: "a", Equal: []string{"cluster"}}, {RuleUID: "b"}},
+			},
+			{
+				name:                  "should reject empty rule UID",
+				inhibitedBy:           []RuleInhibition{{Equal: []string{"cluster"}}},
+				expectedErrorContains: "inhibiting rule UID cannot be empty",
+			},
+			{
+				name:                  "should reject self reference",
+				inhibitedBy:           []RuleInhibition{{RuleUID: "self"}},
+				expectedErrorContains: "rule cannot be inhibited by itself",
+			},
+			{
+				name:                  "should reject duplicate rules",
+				inhibitedBy:           []RuleInhibition{{RuleUID: "a"}, {RuleUID: "a", Equal: []string{"cluster"}}},
+				expectedErrorContains: "rule a is referenced more than once",
+			},
+			{
+				name:                  "should reject invalid label names",
+				inhibitedBy:           []RuleInhibition{{RuleUID: "a", Equal: []string{""}}},
+				expectedErrorContains: "invalid label name",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				rule := RuleGen.With(
+					RuleMuts.WithIntervalSeconds(10),
+					RuleMuts.WithUID("self"),
+					RuleMuts.WithInhibitedBy(tc.inhibitedBy...),
+				).Generate()
+
+				err := rule.ValidateAlertRule(setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second})
+
+				if tc.expectedErrorContains != "" {
+					require.Error(t, err)
+					require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+					require.Contains(t, err.Error(), tc.expectedErrorContains)
+				} else {
+					require.NoError(t, err)
+				}
+			})
+		}
+	})
+}
+
+func TestRuleInhibitionInhibits(t *testing.T) {
+	source := map[string]string{"cluster": "a", "team": "x"}
+
+	require.True(t, RuleInhibition{RuleUID: "a"}.Inhibits(source, map[string]string{"cluster": "b"}))
+	require.True(t, RuleInhibition{RuleUID: "a", Equal: []string{"cluster"}}.Inhibits(source, map[string]string{"cluster": "a", "team": "y"}))
+	require.False(t, RuleInhibition{RuleUID: "a", Equal: []string{"cluster", "team"}}.Inhibits(source, map[string]string{"cluster": "a", "team": "y"}))
+	require.False(t, RuleInhibition{RuleUID: "a", Equal: []string{"cluster"}}.Inhibits(source, map[string]string{}))
 }
 
 func TestAlertRule_PrometheusRuleDefinition(t *testing.T) {
@@ -1448,3 +1549,33 @@ func TestWithoutPrivateLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestRecord_TargetDatasourceUIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   Record
+		expected []string
+	}{
+		{
+			name:     "only primary target",
+			record:   Record{TargetDatasourceUID: "prom"},
+			expected: []string{"prom"},
+		},
+		{
+			name:     "default target is kept",
+			record:   Record{AdditionalTargetDatasourceUIDs: []string{"replica"}},
+			expected: []string{"", "replica"},
+		},
+		{
+			name:     "duplicates are removed",
+			record:   Record{TargetDatasourceUID: "prom", AdditionalTargetDatasourceUIDs: []string{"replica", "prom", "replica"}},
+			expected: []string{"prom", "replica"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.record.TargetDatasourceUIDs())
+		})
+	}
+}
@@ -12,6 +12,16 @@ var (
 	ErrAlertRuleGroupNotFound       = errutil.NotFound("alerting.alert-rule.notFound")
 	ErrInvalidRelativeTimeRangeBase = errutil.BadRequest("alerting.alert-rule.invalidRelativeTime").MustTemplate("Invalid alert rule query {{ .Public.RefID }}: invalid relative time range [From: {{ .Public.From }}, To: {{ .Public.To }}]")
 	ErrConditionNotExistBase        = errutil.BadRequest("alerting.alert-rule.conditionNotExist").MustTemplate("Condition {{ .Public.Given }} does not exist, must be one of {{ .Public.Existing }}")
+
+	ErrRuleTemplateNotFound             = errutil.NotFound("alerting.rule-template.notFound", errutil.WithPublicMessage("Rule template not found"))
+	ErrRuleTemplateInvalidBase          = errutil.BadRequest("alerting.rule-template.invalid").MustTemplate("Invalid rule template: {{ .Public.Error }}", errutil.WithPublic("Invalid rule template: {{ .Public.Error }}"))
+	ErrRuleTemplateInstanceInvalidBase  = errutil.BadRequest("alerting.rule-template.invalidValues").MustTemplate("Invalid rule template parameters: {{ .Public.Error }}", errutil.WithPublic("Invalid rule template parameters: {{ .Public.Error }}"))
+	ErrRuleTemplateInUseBase            = errutil.Conflict("alerting.rule-template.inUse").MustTemplate("Rule template is used by {{ .Public.Count }} alert rules", errutil.WithPublic("Rule template is used by {{ .Public.Count }} alert rules. Delete the rules first."))
+	ErrRuleTemplateInstanceNotFoundBase = errutil.NotFound("alerting.rule-template.instanceNotFound").MustTemplate("Alert rule '{{ .Public.RuleUID }}' is not an instance of the rule template", errutil.WithPublic("Alert rule '{{ .Public.RuleUID }}' is not an instance of the rule template"))
+
+	ErrMaintenanceWindowNotFound    = errutil.NotFound("alerting.maintenance-window.notFound", errutil.WithPublicMessage("Maintenance window not found"))
+	ErrMaintenanceWindowInvalidBase = errutil.BadRequest("alerting.maintenance-window.invalid").MustTemplate("Invalid maintenance window: {{ .Public.Error }}", errutil.WithPublic("Invalid maintenance window: {{ .Public.Error }}"))
+	ErrAlertRuleSelectorInvalidBase = errutil.BadRequest("alerting.alert-rule.invalidSelector").MustTemplate("Invalid alert rule selector: {{ .Public.Error }}", errutil.WithPublic("Invalid alert rule selector: {{ .Public.Error }}"))
 )
 
 func ErrAlertRuleConflict(ruleUID string, orgID int64, err error) error {
@@ -25,3 +35,27 @@ func ErrInvalidRelativeTimeRange(refID string, rtr RelativeTimeRange) error {
 func ErrConditionNotExist(given string, existing []string) error {
 	return ErrConditionNotExistBase.Build(errutil.TemplateData{Public: map[string]any{"Given": given, "Existing": fmt.Sprintf("%v", existing)}})
 }
+
+func ErrRuleTemplateInvalid(err error) error {
+	return ErrRuleTemplateInvalidBase.Build(errutil.TemplateData{Public: map[string]any{"Error": err.Error()}, Error: err})
+}
+
+func ErrRuleTemplateInstanceInvalid(err error) error {
+	return ErrRuleTemplateInstanceInvalidBase.Build(errutil.TemplateData{Public: map[string]any{"Error": err.Error()}, Error: err})
+}
+
+func ErrRuleTemplateInUse(count int) error {
+	return ErrRuleTemplateInUseBase.Build(errutil.TemplateData{Public: map[string]any{"Count": count}})
+}
+
+func ErrRuleTemplateInstanceNotFound(ruleUID string) error {
+	return ErrRuleTemplateInstanceNotFoundBase.Build(errutil.TemplateData{Public: map[string]any{"RuleUID": ruleUID}})
+}
+
+func ErrMaintenanceWindowInvalid(err error) error {
+	return ErrMaintenanceWindowInvalidBase.Build(errutil.TemplateData{Public: map[string]any{"Error": err.Error()}, Error: err})
+}
+
+func ErrAlertRuleSelectorInvalid(err error) error {
+	return ErrAlertRuleSelectorInvalidBase.Build(errutil.TemplateData{Public: map[string]any{"Error": err.Error()}, Error: err})
+}
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/robfig/cron/v3"
+)
+
+// EvaluationSchedule defines when an alert rule is evaluated, in addition to its evaluation interval.
+type EvaluationSchedule struct {
+	// Cron is a cron expression with five fields (minute, hour, day of month, month, day of week) or a descriptor
+	// such as @daily. If set, the rule is evaluated at the times that match the expression instead of every interval.
+	Cron string `json:"cron,omitempty"`
+	// Timezone is the IANA time zone the cron expression, and the active time intervals without a location,
+	// are evaluated in. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// ActiveTimeIntervals are the time intervals during which the rule is evaluated.
+	// If set, evaluations outside these intervals are skipped.
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"active_time_intervals,omitempty"`
+
+	// cron and location are set by Parse.
+	cron     cron.Schedule
+	location *time.Location
+}
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Parse parses the cron expression and the time zone of the schedule once, so that CronSchedule and Location
+// do not parse them again every time they are called. It must be called before the schedule is shared.
+func (s *EvaluationSchedule) Parse() error {
+	loc, err := parseLocation(s.Timezone)
+	if err != nil {
+		return err
+	}
+	if s.Cron != "" {
+		sched, err := parseCron(s.Cron, loc)
+		if err != nil {
+			return err
+		}
+		s.cron = sched
+	}
+	s.location = loc
+	return nil
+}
+
+// CronSchedule returns the cron expression parsed in the configured time zone.
+func (s *EvaluationSchedule) CronSchedule() (cron.Schedule, error) {
+	if s.cron != nil {
+		return s.cron, nil
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return nil, err
+	}
+	return parseCron(s.Cron, loc)
+}
+
+// Location returns the time zone of the schedule.
+func (s *EvaluationSchedule) Location() (*time.Location, error) {
+	if s.location != nil {
+		return s.location, nil
+	}
+	return parseLocation(s.Timezone)
+}
+
+func parseCron(expr string, loc *time.Location) (cron.Schedule, error) {
+	if expr == "" {
+		return nil, errors.New("cron expression is empty")
+	}
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		return nil, errors.New("time zone must be set in the timezone field and not in the cron expression")
+	}
+	sched, err := cronParser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	spec, ok := sched.(*cron.SpecSchedule)
+	if !ok {
+		return nil, fmt.Errorf("cron expression %q is not supported, use the evaluation interval instead", expr)
+	}
+	spec.Location = loc
+	return spec, nil
+}
+
+func parseLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	return loc, nil
+}
+
+// IsActiveAt returns true if t is in one of the active time intervals, or if there are none.
+// Time intervals without a location are evaluated in the time zone of the schedule.
+func (s *EvaluationSchedule) IsActiveAt(t time.Time) bool {
+	if len(s.ActiveTimeIntervals) == 0 {
+		return true
+	}
+	if loc, err := s.Location(); err == nil {
+		t = t.In(loc)
+	}
+	for _, ti := range s.ActiveTimeIntervals {
+		if ti.ContainsTime(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextEvaluation returns the time of the first evaluation after t of a rule that has the schedule and is evaluated
+// every interval. The evaluations outside the active time intervals are skipped. It returns false if the rule is
+// not evaluated in the year after t.
+func (s *EvaluationSchedule) NextEvaluation(t time.Time, interval time.Duration) (time.Time, bool) {
+	var sched cron.Schedule
+	if s.Cron != "" {
+		var err error
+		if sched, err = s.CronSchedule(); err != nil {
+			return time.Time{}, false
+		}
+	} else if interval <= 0 {
+		return time.Time{}, false
+	}
+	limit := t.AddDate(1, 0, 0)
+	next := t
+	for {
+		if sched != nil {
+			next = sched.Next(next)
+		} else if next == t {
+			next = next.Add(interval)
+		} else {
+			// Time intervals have a precision of a minute, so while outside of them it is enough to look every minute.
+			next = next.Add(max(interval, time.Minute))
+		}
+		if next.IsZero() || next.After(limit) {
+			return time.Time{}, false
+		}
+		if s.IsActiveAt(next) {
+			return next, true
+		}
+	}
+}
+
+// Validate checks that the schedule has a valid cron expression, or active time intervals, and a valid time zone.
+func (s *EvaluationSchedule) Validate() error {
+	if s.Cron == "" && len(s.ActiveTimeIntervals) == 0 {
+		return errors.New("either a cron expression or active time intervals must be set")
+	}
+	loc, err := parseLocation(s.Timezone)
+	if err != nil || s.Cron == "" {
+		return err
+	}
+	_, err = parseCron(s.Cron, loc)
+	return err
+}
+
+func (s *EvaluationSchedule) Copy() *EvaluationSchedule {
+	if s == nil {
+		return nil
+	}
+	result := &EvaluationSchedule{
+		Cron:     s.Cron,
+		Timezone: s.Timezone,
+		cron:     s.cron,
+		location: s.location,
+	}
+	if s.ActiveTimeIntervals != nil {
+		result.ActiveTimeIntervals = make([]timeinterval.TimeInterval, 0, len(s.ActiveTimeIntervals))
+		for _, ti := range s.ActiveTimeIntervals {
+			result.ActiveTimeIntervals = append(result.ActiveTimeIntervals, copyTimeInterval(ti))
+		}
+	}
+	return result
+}
+
+func (s *EvaluationSchedule) Fingerprint() data.Fingerprint {
+	h := fnv.New64()
+	_, _ = h.Write([]byte(s.Cron))
+	_, _ = h.Write([]byte{255})
+	_, _ = h.Write([]byte(s.Timezone))
+	for _, ti := range s.ActiveTimeIntervals {
+		_, _ = h.Write([]byte{255})
+		_, _ = fmt.Fprintf(h, "%v%v%v%v%v", ti.Times, ti.Weekdays, ti.DaysOfMonth, ti.Months, ti.Years)
+		if ti.Location != nil {
+			_, _ = h.Write([]byte(ti.Location.String()))
+		}
+	}
+	return data.Fingerprint(h.Sum64())
+}
+
+func copyTimeInterval(ti timeinterval.TimeInterval) timeinterval.TimeInterval {
+	result := timeinterval.TimeInterval{
+		Times:       append([]timeinterval.TimeRange(nil), ti.Times...),
+		Weekdays:    append([]timeinterval.WeekdayRange(nil), ti.Weekdays...),
+		DaysOfMonth: append([]timeinterval.DayOfMonthRange(nil), ti.DaysOfMonth...),
+		Months:      append([]timeinterval.MonthRange(nil), ti.Months...),
+		Years:       append([]timeinterval.YearRange(nil), ti.Years...),
+	}
+	if ti.Location != nil {
+		loc := *ti.Location
+		result.Location = &loc
+	}
+	return result
+}
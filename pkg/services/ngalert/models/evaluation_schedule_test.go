@@ -0,0 +1,197 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestEvaluationScheduleValidate(t *testing.T) {
+	testCases := []struct {
+		name             string
+		schedule         EvaluationSchedule
+		expErrorContains string
+	}{
+		{
+			name:     "cron expression",
+			schedule: EvaluationSchedule{Cron: "0 8 * * 1-5"},
+		},
+		{
+			name:     "cron expression with timezone",
+			schedule: EvaluationSchedule{Cron: "0 8 * * MON-FRI", Timezone: "Europe/Berlin"},
+		},
+		{
+			name:     "cron descriptor",
+			schedule: EvaluationSchedule{Cron: "@daily"},
+		},
+		{
+			name:     "active time intervals",
+			schedule: EvaluationSchedule{ActiveTimeIntervals: []timeinterval.TimeInterval{{}}, Timezone: "America/New_York"},
+		},
+		{
+			name:             "empty schedule",
+			schedule:         EvaluationSchedule{},
+			expErrorContains: "either a cron expression or active time intervals must be set",
+		},
+		{
+			name:             "invalid cron expression",
+			schedule:         EvaluationSchedule{Cron: "0 8 * *"},
+			expErrorContains: "invalid cron expression",
+		},
+		{
+			name:             "cron expression with seconds",
+			schedule:         EvaluationSchedule{Cron: "0 0 8 * * 1-5"},
+			expErrorContains: "invalid cron expression",
+		},
+		{
+			name:             "constant delay",
+			schedule:         EvaluationSchedule{Cron: "@every 5m"},
+			expErrorContains: "is not supported",
+		},
+		{
+			name:             "time zone in the cron expression",
+			schedule:         EvaluationSchedule{Cron: "CRON_TZ=Europe/Berlin 0 8 * * *"},
+			expErrorContains: "time zone must be set in the timezone field",
+		},
+		{
+			name:             "invalid timezone",
+			schedule:         EvaluationSchedule{Cron: "0 8 * * *", Timezone: "Mars/Olympus_Mons"},
+			expErrorContains: "invalid timezone",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.schedule.Validate()
+			if tc.expErrorContains != "" {
+				require.ErrorContains(t, err, tc.expErrorContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEvaluationScheduleCronSchedule(t *testing.T) {
+	s := EvaluationSchedule{Cron: "0 8 * * 1-5", Timezone: "Europe/Berlin"}
+	sched, err := s.CronSchedule()
+	require.NoError(t, err)
+
+	// Friday 2024-03-01 10:00 UTC is 11:00 in Berlin, the next run is on Monday at 08:00 in Berlin.
+	next := sched.Next(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2024, 3, 4, 7, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestEvaluationScheduleParse(t *testing.T) {
+	s := EvaluationSchedule{Cron: "0 8 * * 1-5", Timezone: "Europe/Berlin"}
+	require.NoError(t, s.Parse())
+
+	sched, err := s.CronSchedule()
+	require.NoError(t, err)
+	require.Same(t, s.cron, sched)
+	loc, err := s.Location()
+	require.NoError(t, err)
+	require.Same(t, s.location, loc)
+
+	// the copy shares the parsed schedule
+	c := s.Copy()
+	require.Same(t, s.cron, c.cron)
+	require.Same(t, s.location, c.location)
+
+	invalid := EvaluationSchedule{Cron: "0 8 * *"}
+	require.ErrorContains(t, invalid.Parse(), "invalid cron expression")
+	require.Nil(t, invalid.cron)
+}
+
+func TestEvaluationScheduleIsActiveAt(t *testing.T) {
+	var businessHours []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- times:
+  - start_time: "09:00"
+    end_time: "17:00"
+  weekdays: ["monday:friday"]
+`), &businessHours))
+
+	s := EvaluationSchedule{ActiveTimeIntervals: businessHours, Timezone: "Europe/Berlin"}
+
+	// Monday 2024-03-04 08:30 UTC is 09:30 in Berlin
+	require.True(t, s.IsActiveAt(time.Date(2024, 3, 4, 8, 30, 0, 0, time.UTC)))
+	// Monday 2024-03-04 16:30 UTC is 17:30 in Berlin
+	require.False(t, s.IsActiveAt(time.Date(2024, 3, 4, 16, 30, 0, 0, time.UTC)))
+	// Saturday
+	require.False(t, s.IsActiveAt(time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC)))
+
+	require.True(t, (&EvaluationSchedule{Cron: "@daily"}).IsActiveAt(time.Now()))
+}
+
+func TestEvaluationScheduleNextEvaluation(t *testing.T) {
+	var businessHours []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- times:
+  - start_time: "09:00"
+    end_time: "17:00"
+  weekdays: ["monday:friday"]
+`), &businessHours))
+
+	// Monday 2024-03-04 10:00 UTC
+	monday := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	t.Run("next interval in the active time intervals", func(t *testing.T) {
+		s := EvaluationSchedule{ActiveTimeIntervals: businessHours}
+		next, ok := s.NextEvaluation(monday, time.Minute)
+		require.True(t, ok)
+		require.Equal(t, monday.Add(time.Minute), next)
+	})
+
+	t.Run("reopening of the active time intervals", func(t *testing.T) {
+		s := EvaluationSchedule{ActiveTimeIntervals: businessHours}
+		next, ok := s.NextEvaluation(time.Date(2024, 3, 8, 16, 59, 30, 0, time.UTC), 30*time.Second)
+		require.True(t, ok)
+		require.Equal(t, time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("next match of the cron expression", func(t *testing.T) {
+		s := EvaluationSchedule{Cron: "0 8 * * *", Timezone: "Europe/Berlin"}
+		next, ok := s.NextEvaluation(monday, time.Minute)
+		require.True(t, ok)
+		require.Equal(t, time.Date(2024, 3, 5, 7, 0, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("next match of the cron expression in the active time intervals", func(t *testing.T) {
+		s := EvaluationSchedule{Cron: "0 * * * *", ActiveTimeIntervals: businessHours}
+		next, ok := s.NextEvaluation(time.Date(2024, 3, 8, 16, 30, 0, 0, time.UTC), time.Minute)
+		require.True(t, ok)
+		require.Equal(t, time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("no evaluation in the next year", func(t *testing.T) {
+		var past []timeinterval.TimeInterval
+		require.NoError(t, yaml.Unmarshal([]byte(`[{years: ["2020"]}]`), &past))
+		s := EvaluationSchedule{ActiveTimeIntervals: past}
+		_, ok := s.NextEvaluation(monday, time.Minute)
+		require.False(t, ok)
+	})
+}
+
+func TestEvaluationScheduleCopy(t *testing.T) {
+	var intervals []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- times:
+  - start_time: "09:00"
+    end_time: "17:00"
+  location: Europe/Berlin
+`), &intervals))
+	s := &EvaluationSchedule{Cron: "@hourly", ActiveTimeIntervals: intervals}
+
+	c := s.Copy()
+	require.Equal(t, s, c)
+	require.NotSame(t, s.ActiveTimeIntervals[0].Location, c.ActiveTimeIntervals[0].Location)
+	require.Equal(t, s.Fingerprint(), c.Fingerprint())
+
+	c.ActiveTimeIntervals[0].Times[0].EndMinute = 0
+	require.Equal(t, 17*60, s.ActiveTimeIntervals[0].Times[0].EndMinute)
+	require.NotEqual(t, s.Fingerprint(), c.Fingerprint())
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// FlapDetection configures the detection of flapping alerts. An alert is flapping if its state changed
+// at least Transitions times within Window. The notifications of a flapping alert are held until its
+// state has not changed for Window. Alerts that were already sent as firing keep being re-sent, so that
+// the Alertmanager does not resolve them. The state changes are counted in memory, so flap detection
+// starts over when Grafana restarts.
+type FlapDetection struct {
+	// Transitions is the number of state changes within the window that marks an alert as flapping.
+	Transitions int64 `json:"transitions"`
+	// Window is the period in which the state changes are counted.
+	Window time.Duration `json:"window"`
+}
+
+const (
+	// FlapDetectionMinTransitions is the smallest number of state changes that can mark an alert as flapping.
+	FlapDetectionMinTransitions = 2
+)
+
+// Validate checks that the number of transitions and the window are valid. The window must be
+// at least the evaluation interval of the rule, otherwise no alert could ever be flapping.
+func (f *FlapDetection) Validate(intervalSeconds int64) error {
+	if f.Transitions < FlapDetectionMinTransitions {
+		return fmt.Errorf("transitions must be at least %d", FlapDetectionMinTransitions)
+	}
+	if f.Window <= 0 {
+		return errors.New("window must be greater than 0")
+	}
+	if interval := time.Duration(intervalSeconds) * time.Second; f.Window < interval {
+		return fmt.Errorf("window %s must not be shorter than the evaluation interval %s", f.Window, interval)
+	}
+	return nil
+}
+
+func (f *FlapDetection) Copy() *FlapDetection {
+	if f == nil {
+		return nil
+	}
+	result := *f
+	return &result
+}
+
+func (f *FlapDetection) Fingerprint() data.Fingerprint {
+	h := fnv.New64()
+	tmp := make([]byte, 8)
+	binary.LittleEndian.PutUint64(tmp, uint64(f.Transitions))
+	_, _ = h.Write(tmp)
+	binary.LittleEndian.PutUint64(tmp, uint64(f.Window))
+	_, _ = h.Write(tmp)
+	return data.Fingerprint(h.Sum64())
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlapDetectionValidate(t *testing.T) {
+	testCases := []struct {
+		name             string
+		flapDetection    FlapDetection
+		intervalSeconds  int64
+		expErrorContains string
+	}{
+		{
+			name:            "valid flap detection",
+			flapDetection:   FlapDetection{Transitions: 4, Window: 30 * time.Minute},
+			intervalSeconds: 60,
+		},
+		{
+			name:            "window equal to the interval",
+			flapDetection:   FlapDetection{Transitions: 2, Window: time.Minute},
+			intervalSeconds: 60,
+		},
+		{
+			name:             "too few transitions",
+			flapDetection:    FlapDetection{Transitions: 1, Window: 30 * time.Minute},
+			intervalSeconds:  60,
+			expErrorContains: "transitions must be at least 2",
+		},
+		{
+			name:             "empty window",
+			flapDetection:    FlapDetection{Transitions: 4},
+			intervalSeconds:  60,
+			expErrorContains: "window must be greater than 0",
+		},
+		{
+			name:             "window shorter than the interval",
+			flapDetection:    FlapDetection{Transitions: 4, Window: 30 * time.Second},
+			intervalSeconds:  60,
+			expErrorContains: "must not be shorter than the evaluation interval",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.flapDetection.Validate(tc.intervalSeconds)
+			if tc.expErrorContains != "" {
+				require.ErrorContains(t, err, tc.expErrorContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
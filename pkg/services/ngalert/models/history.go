@@ -20,3 +20,40 @@ type HistoryQuery struct {
 	Limit        int
 	SignedInUser identity.Requester
 }
+
+// StateHistoryEntry is a state transition of an alert instance as it is stored in the database
+// by the SQL state history backend.
+type StateHistoryEntry struct {
+	// ID is the ID of the entry in the database. It is set on the entries returned by queries.
+	ID           int64
+	OrgID        int64
+	RuleUID      string
+	RuleGroup    string
+	FolderUID    string
+	DashboardUID string
+	PanelID      int64
+	Previous     string
+	Current      string
+	EvaluatedAt  time.Time
+	// Line is the JSON encoded history entry, in the same format as the one written to Loki.
+	Line string
+}
+
+// StateHistoryEntriesQuery is a query for the state history entries stored in the database.
+// Previous and Current match the beginning of the state, so that a state matches with and without a reason.
+type StateHistoryEntriesQuery struct {
+	OrgID        int64
+	RuleUID      string
+	DashboardUID string
+	PanelID      int64
+	FolderUIDs   []string
+	Previous     string
+	Current      string
+	From         time.Time
+	To           time.Time
+	// Limit is the maximum number of the most recent entries to return. 0 means no limit.
+	Limit int
+	// Before is an entry returned by a previous query. If it is set, only the entries older than it are
+	// returned, so that the entries can be paged through from the most recent one.
+	Before *StateHistoryEntry
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// AlertRuleSelector selects alert rules by their labels, folders and groups. A rule is selected if it matches
+// all the criteria that are set.
+type AlertRuleSelector struct {
+	// Matchers are matched against the labels of the rule, not the labels of its alerts, so rules are selected
+	// even if the labels of their alerts are not known ahead of time.
+	Matchers labels.Matchers `json:"matchers,omitempty"`
+	// FolderUIDs selects the rules in any of the folders.
+	FolderUIDs []string `json:"folder_uids,omitempty"`
+	// RuleGroups selects the rules in any of the groups.
+	RuleGroups []string `json:"rule_groups,omitempty"`
+}
+
+// IsEmpty returns true if the selector does not have any criteria.
+func (s AlertRuleSelector) IsEmpty() bool {
+	return len(s.Matchers) == 0 && len(s.FolderUIDs) == 0 && len(s.RuleGroups) == 0
+}
+
+// Validate checks that the selector has at least one criterion, so that it does not select all the rules
+// of the organization by mistake, and that the names of the matchers are valid label names.
+func (s AlertRuleSelector) Validate() error {
+	if s.IsEmpty() {
+		return errors.New("at least one matcher, folder or rule group must be set")
+	}
+	for _, m := range s.Matchers {
+		if !model.LabelName(m.Name).IsValid() {
+			return fmt.Errorf("invalid label name %q in matcher", m.Name)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the rule is selected.
+func (s AlertRuleSelector) Matches(rule *AlertRule) bool {
+	if len(s.FolderUIDs) > 0 && !slices.Contains(s.FolderUIDs, rule.NamespaceUID) {
+		return false
+	}
+	if len(s.RuleGroups) > 0 && !slices.Contains(s.RuleGroups, rule.RuleGroup) {
+		return false
+	}
+	for _, m := range s.Matchers {
+		if !m.Matches(rule.Labels[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MaintenanceWindow is a period during which the selected alert rules are in maintenance. The rules are still
+// evaluated, but the states of their alerts are marked with the reason Maintenance and no notifications are sent.
+// The window ends automatically at EndsAt.
+type MaintenanceWindow struct {
+	ID       int64
+	OrgID    int64
+	UID      string
+	Reason   string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Selector AlertRuleSelector
+	// CreatedBy is the UID of the user that created the window.
+	CreatedBy string
+	Updated   time.Time
+}
+
+// Validate checks that the window has a reason, a valid time range and a valid selector.
+func (w *MaintenanceWindow) Validate() error {
+	if w.UID != "" {
+		if err := util.ValidateUID(w.UID); err != nil {
+			return ErrMaintenanceWindowInvalid(fmt.Errorf("invalid UID: %w", err))
+		}
+	}
+	if w.Reason == "" {
+		return ErrMaintenanceWindowInvalid(errors.New("reason is required"))
+	}
+	if w.StartsAt.IsZero() || w.EndsAt.IsZero() {
+		return ErrMaintenanceWindowInvalid(errors.New("start and end time are required"))
+	}
+	if !w.EndsAt.After(w.StartsAt) {
+		return ErrMaintenanceWindowInvalid(errors.New("end time must be after the start time"))
+	}
+	if err := w.Selector.Validate(); err != nil {
+		return ErrMaintenanceWindowInvalid(fmt.Errorf("invalid selector: %w", err))
+	}
+	return nil
+}
+
+// IsActive returns true if the window is active at the given time.
+func (w *MaintenanceWindow) IsActive(at time.Time) bool {
+	return !at.Before(w.StartsAt) && at.Before(w.EndsAt)
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleSelector(t *testing.T) {
+	team, err := labels.NewMatcher(labels.MatchEqual, "team", "db")
+	require.NoError(t, err)
+	rule := &AlertRule{
+		NamespaceUID: "folder",
+		RuleGroup:    "group",
+		Labels:       map[string]string{"team": "db"},
+	}
+
+	testCases := []struct {
+		name     string
+		selector AlertRuleSelector
+		matches  bool
+	}{
+		{name: "matcher", selector: AlertRuleSelector{Matchers: labels.Matchers{team}}, matches: true},
+		{name: "folder", selector: AlertRuleSelector{FolderUIDs: []string{"other", "folder"}}, matches: true},
+		{name: "group", selector: AlertRuleSelector{RuleGroups: []string{"group"}}, matches: true},
+		{name: "all criteria", selector: AlertRuleSelector{Matchers: labels.Matchers{team}, FolderUIDs: []string{"folder"}, RuleGroups: []string{"group"}}, matches: true},
+		{name: "other folder", selector: AlertRuleSelector{Matchers: labels.Matchers{team}, FolderUIDs: []string{"other"}}, matches: false},
+		{name: "other group", selector: AlertRuleSelector{RuleGroups: []string{"other"}}, matches: false},
+		{name: "missing label", selector: AlertRuleSelector{Matchers: labels.Matchers{{Type: labels.MatchEqual, Name: "env", Value: "prod"}}}, matches: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.selector.Validate())
+			require.Equal(t, tc.matches, tc.selector.Matches(rule))
+		})
+	}
+
+	t.Run("empty selector is invalid", func(t *testing.T) {
+		require.Error(t, AlertRuleSelector{}.Validate())
+	})
+	t.Run("invalid label name", func(t *testing.T) {
+		require.Error(t, AlertRuleSelector{Matchers: labels.Matchers{{Type: labels.MatchEqual, Name: "", Value: "db"}}}.Validate())
+	})
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	start := time.Date(2024, 6, 1, 22, 0, 0, 0, time.UTC)
+	valid := func() MaintenanceWindow {
+		return MaintenanceWindow{
+			UID:      "window",
+			Reason:   "database upgrade",
+			StartsAt: start,
+			EndsAt:   start.Add(4 * time.Hour),
+			Selector: AlertRuleSelector{FolderUIDs: []string{"folder"}},
+		}
+	}
+
+	t.Run("validate", func(t *testing.T) {
+		w := valid()
+		require.NoError(t, w.Validate())
+
+		testCases := map[string]func(w *MaintenanceWindow){
+			"invalid UID":       func(w *MaintenanceWindow) { w.UID = "invalid uid!" },
+			"no reason":         func(w *MaintenanceWindow) { w.Reason = "" },
+			"no end time":       func(w *MaintenanceWindow) { w.EndsAt = time.Time{} },
+			"ends before start": func(w *MaintenanceWindow) { w.EndsAt = w.StartsAt.Add(-time.Minute) },
+			"empty selector":    func(w *MaintenanceWindow) { w.Selector = AlertRuleSelector{} },
+		}
+		for name, mutate := range testCases {
+			t.Run(name, func(t *testing.T) {
+				w := valid()
+				mutate(&w)
+				require.ErrorIs(t, w.Validate(), ErrMaintenanceWindowInvalidBase)
+			})
+		}
+	})
+
+	t.Run("is active", func(t *testing.T) {
+		w := valid()
+		require.False(t, w.IsActive(start.Add(-time.Second)))
+		require.True(t, w.IsActive(start))
+		require.True(t, w.IsActive(start.Add(time.Hour)))
+		require.False(t, w.IsActive(w.EndsAt))
+	})
+}
@@ -0,0 +1,221 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// RuleTemplateParameterType is the type of the value of a rule template parameter.
+type RuleTemplateParameterType string
+
+const (
+	// RuleTemplateParameterTypeString accepts any string.
+	RuleTemplateParameterTypeString RuleTemplateParameterType = "string"
+	// RuleTemplateParameterTypeNumber accepts a number. A placeholder that is the whole value of a JSON string
+	// is replaced with a JSON number, so it can be used for thresholds.
+	RuleTemplateParameterTypeNumber RuleTemplateParameterType = "number"
+	// RuleTemplateParameterTypeLabelSelector accepts a list of label matchers, such as team="a",env=~"prod|staging",
+	// that can be used in the curly braces of a PromQL selector.
+	RuleTemplateParameterTypeLabelSelector RuleTemplateParameterType = "label_selector"
+	// RuleTemplateParameterTypeDatasource accepts the UID of a data source.
+	RuleTemplateParameterTypeDatasource RuleTemplateParameterType = "datasource"
+)
+
+var (
+	ruleTemplateParameterNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	ruleTemplatePlaceholderRegex   = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+)
+
+// RuleTemplateParameter is an input of a rule template.
+type RuleTemplateParameter struct {
+	Name        string                    `json:"name"`
+	Type        RuleTemplateParameterType `json:"type"`
+	Description string                    `json:"description,omitempty"`
+	// Default is the value used when an instance does not set the parameter. If nil, the parameter is required.
+	Default *string `json:"default,omitempty"`
+}
+
+// RuleTemplate is a parameterized alert rule. Rules created from a template are its instances,
+// and they are updated when the template changes.
+type RuleTemplate struct {
+	ID          int64
+	OrgID       int64
+	UID         string
+	Title       string
+	Description string
+	Parameters  []RuleTemplateParameter
+	// Rule is an alert rule in the format of the provisioning API. String values can reference the parameters
+	// with ${name} placeholders.
+	Rule    json.RawMessage
+	Version int64
+	Updated time.Time
+}
+
+// RuleTemplateInstance links an alert rule to the template it was created from.
+type RuleTemplateInstance struct {
+	OrgID       int64
+	TemplateUID string
+	RuleUID     string
+	// Values are the values of the template parameters set for the instance. Defaults are not included.
+	Values map[string]string
+	// TemplateVersion is the version of the template the rule was last rendered from.
+	TemplateVersion int64
+}
+
+// Validate checks that the template has a title, valid parameters and a rule that is a JSON object.
+func (t *RuleTemplate) Validate() error {
+	if t.UID != "" {
+		if err := util.ValidateUID(t.UID); err != nil {
+			return ErrRuleTemplateInvalid(fmt.Errorf("invalid UID: %w", err))
+		}
+	}
+	if t.Title == "" {
+		return ErrRuleTemplateInvalid(errors.New("title is required"))
+	}
+	names := make(map[string]struct{}, len(t.Parameters))
+	for _, p := range t.Parameters {
+		if !ruleTemplateParameterNameRegex.MatchString(p.Name) {
+			return ErrRuleTemplateInvalid(fmt.Errorf("invalid parameter name %q, it must match %s", p.Name, ruleTemplateParameterNameRegex.String()))
+		}
+		if _, ok := names[p.Name]; ok {
+			return ErrRuleTemplateInvalid(fmt.Errorf("parameter %q is defined more than once", p.Name))
+		}
+		names[p.Name] = struct{}{}
+		switch p.Type {
+		case RuleTemplateParameterTypeString, RuleTemplateParameterTypeNumber, RuleTemplateParameterTypeLabelSelector, RuleTemplateParameterTypeDatasource:
+		default:
+			return ErrRuleTemplateInvalid(fmt.Errorf("parameter %q has unknown type %q", p.Name, p.Type))
+		}
+		if p.Default != nil {
+			if err := p.validateValue(*p.Default); err != nil {
+				return ErrRuleTemplateInvalid(fmt.Errorf("invalid default value: %w", err))
+			}
+		}
+	}
+	var rule map[string]any
+	if err := json.Unmarshal(t.Rule, &rule); err != nil || rule == nil {
+		return ErrRuleTemplateInvalid(errors.New("rule must be a JSON object"))
+	}
+	return nil
+}
+
+func (p RuleTemplateParameter) validateValue(value string) error {
+	switch p.Type {
+	case RuleTemplateParameterTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("value of parameter %q must be a number", p.Name)
+		}
+	case RuleTemplateParameterTypeLabelSelector:
+		if _, err := parser.ParseMetricSelector("{" + value + "}"); err != nil {
+			return fmt.Errorf("value of parameter %q must be a list of label matchers: %w", p.Name, err)
+		}
+	case RuleTemplateParameterTypeDatasource:
+		if value == "" {
+			return fmt.Errorf("value of parameter %q must be a data source UID", p.Name)
+		}
+	}
+	return nil
+}
+
+// ResolveValues validates the values of an instance and returns them with the defaults of the missing parameters.
+func (t *RuleTemplate) ResolveValues(values map[string]string) (map[string]string, error) {
+	result := make(map[string]string, len(t.Parameters))
+	for _, p := range t.Parameters {
+		value, ok := values[p.Name]
+		if !ok {
+			if p.Default == nil {
+				return nil, ErrRuleTemplateInstanceInvalid(fmt.Errorf("value of parameter %q is required", p.Name))
+			}
+			value = *p.Default
+		}
+		if err := p.validateValue(value); err != nil {
+			return nil, ErrRuleTemplateInstanceInvalid(err)
+		}
+		result[p.Name] = value
+	}
+	for name := range values {
+		if _, ok := result[name]; !ok {
+			return nil, ErrRuleTemplateInstanceInvalid(fmt.Errorf("template has no parameter %q", name))
+		}
+	}
+	return result, nil
+}
+
+// Render replaces the placeholders in the rule of the template with the values of an instance,
+// and returns the resulting rule in the format of the provisioning API.
+// Placeholders of parameters that the template does not define are left as is.
+func (t *RuleTemplate) Render(values map[string]string) (json.RawMessage, error) {
+	resolved, err := t.ResolveValues(values)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[string]RuleTemplateParameterType, len(t.Parameters))
+	for _, p := range t.Parameters {
+		types[p.Name] = p.Type
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(t.Rule))
+	dec.UseNumber()
+	var rule any
+	if err := dec.Decode(&rule); err != nil {
+		return nil, ErrRuleTemplateInvalid(fmt.Errorf("failed to parse rule: %w", err))
+	}
+
+	var render func(v any) any
+	render = func(v any) any {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, item := range v {
+				v[k] = render(item)
+			}
+			return v
+		case []any:
+			for i, item := range v {
+				v[i] = render(item)
+			}
+			return v
+		case string:
+			if m := ruleTemplatePlaceholderRegex.FindStringSubmatch(v); m != nil && m[0] == v && types[m[1]] == RuleTemplateParameterTypeNumber {
+				return json.Number(resolved[m[1]])
+			}
+			return ruleTemplatePlaceholderRegex.ReplaceAllStringFunc(v, func(placeholder string) string {
+				name := placeholder[2 : len(placeholder)-1]
+				if value, ok := resolved[name]; ok {
+					return value
+				}
+				return placeholder
+			})
+		default:
+			return v
+		}
+	}
+
+	return json.Marshal(render(rule))
+}
+
+// Copy returns a deep copy of the template.
+func (t *RuleTemplate) Copy() *RuleTemplate {
+	result := *t
+	if t.Parameters != nil {
+		result.Parameters = make([]RuleTemplateParameter, 0, len(t.Parameters))
+		for _, p := range t.Parameters {
+			if p.Default != nil {
+				p.Default = util.Pointer(*p.Default)
+			}
+			result.Parameters = append(result.Parameters, p)
+		}
+	}
+	if t.Rule != nil {
+		result.Rule = append(json.RawMessage(nil), t.Rule...)
+	}
+	return &result
+}
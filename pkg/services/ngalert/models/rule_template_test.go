@@ -0,0 +1,181 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestRuleTemplateValidate(t *testing.T) {
+	valid := func() RuleTemplate {
+		return RuleTemplate{
+			UID:   "high-cpu",
+			Title: "High CPU",
+			Parameters: []RuleTemplateParameter{
+				{Name: "threshold", Type: RuleTemplateParameterTypeNumber, Default: util.Pointer("80")},
+				{Name: "selector", Type: RuleTemplateParameterTypeLabelSelector},
+				{Name: "datasource", Type: RuleTemplateParameterTypeDatasource},
+				{Name: "team", Type: RuleTemplateParameterTypeString},
+			},
+			Rule: json.RawMessage(`{"title":"High CPU ${team}"}`),
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		mutate           func(t *RuleTemplate)
+		expErrorContains string
+	}{
+		{
+			name:   "valid template",
+			mutate: func(t *RuleTemplate) {},
+		},
+		{
+			name:             "missing title",
+			mutate:           func(t *RuleTemplate) { t.Title = "" },
+			expErrorContains: "title is required",
+		},
+		{
+			name:             "invalid UID",
+			mutate:           func(t *RuleTemplate) { t.UID = "high cpu" },
+			expErrorContains: "invalid UID",
+		},
+		{
+			name:             "invalid parameter name",
+			mutate:           func(t *RuleTemplate) { t.Parameters[0].Name = "1threshold" },
+			expErrorContains: "invalid parameter name",
+		},
+		{
+			name:             "duplicate parameter",
+			mutate:           func(t *RuleTemplate) { t.Parameters[1].Name = "threshold" },
+			expErrorContains: "defined more than once",
+		},
+		{
+			name:             "unknown parameter type",
+			mutate:           func(t *RuleTemplate) { t.Parameters[0].Type = "duration" },
+			expErrorContains: "unknown type",
+		},
+		{
+			name:             "invalid default value",
+			mutate:           func(t *RuleTemplate) { t.Parameters[0].Default = util.Pointer("high") },
+			expErrorContains: "must be a number",
+		},
+		{
+			name:             "rule is not an object",
+			mutate:           func(t *RuleTemplate) { t.Rule = json.RawMessage(`[]`) },
+			expErrorContains: "rule must be a JSON object",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl := valid()
+			tc.mutate(&tmpl)
+			err := tmpl.Validate()
+			if tc.expErrorContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrRuleTemplateInvalidBase)
+			require.ErrorContains(t, err, tc.expErrorContains)
+		})
+	}
+}
+
+func TestRuleTemplateRender(t *testing.T) {
+	tmpl := RuleTemplate{
+		Title: "High CPU",
+		Parameters: []RuleTemplateParameter{
+			{Name: "threshold", Type: RuleTemplateParameterTypeNumber, Default: util.Pointer("80")},
+			{Name: "selector", Type: RuleTemplateParameterTypeLabelSelector},
+			{Name: "datasource", Type: RuleTemplateParameterTypeDatasource},
+			{Name: "team", Type: RuleTemplateParameterTypeString},
+		},
+		Rule: json.RawMessage(`{
+			"title": "High CPU ${team}",
+			"labels": {"team": "${team}"},
+			"data": [
+				{"refId": "A", "datasourceUid": "${datasource}", "model": {"expr": "cpu_usage{${selector}} > ${threshold}", "interval": "${__interval}"}},
+				{"refId": "B", "datasourceUid": "__expr__", "model": {"type": "threshold", "conditions": [{"evaluator": {"params": ["${threshold}", 1.5]}}]}}
+			]
+		}`),
+	}
+
+	t.Run("replaces the placeholders", func(t *testing.T) {
+		rule, err := tmpl.Render(map[string]string{
+			"threshold":  "95",
+			"selector":   `team="a",env=~"prod|staging"`,
+			"datasource": "prometheus",
+			"team":       "a",
+		})
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"title": "High CPU a",
+			"labels": {"team": "a"},
+			"data": [
+				{"refId": "A", "datasourceUid": "prometheus", "model": {"expr": "cpu_usage{team=\"a\",env=~\"prod|staging\"} > 95", "interval": "${__interval}"}},
+				{"refId": "B", "datasourceUid": "__expr__", "model": {"type": "threshold", "conditions": [{"evaluator": {"params": [95, 1.5]}}]}}
+			]
+		}`, string(rule))
+	})
+
+	t.Run("uses the default values", func(t *testing.T) {
+		rule, err := tmpl.Render(map[string]string{
+			"selector":   `team="a"`,
+			"datasource": "prometheus",
+			"team":       "a",
+		})
+		require.NoError(t, err)
+		require.Contains(t, string(rule), `"params":[80,1.5]`)
+	})
+
+	t.Run("does not change the template", func(t *testing.T) {
+		before := string(tmpl.Rule)
+		_, err := tmpl.Render(map[string]string{"selector": `team="a"`, "datasource": "prometheus", "team": "a"})
+		require.NoError(t, err)
+		require.Equal(t, before, string(tmpl.Rule))
+	})
+
+	testCases := []struct {
+		name             string
+		values           map[string]string
+		expErrorContains string
+	}{
+		{
+			name:             "missing required value",
+			values:           map[string]string{"selector": `team="a"`, "datasource": "prometheus"},
+			expErrorContains: `value of parameter "team" is required`,
+		},
+		{
+			name:             "unknown parameter",
+			values:           map[string]string{"selector": `team="a"`, "datasource": "prometheus", "team": "a", "env": "prod"},
+			expErrorContains: `template has no parameter "env"`,
+		},
+		{
+			name:             "invalid number",
+			values:           map[string]string{"threshold": "high", "selector": `team="a"`, "datasource": "prometheus", "team": "a"},
+			expErrorContains: `value of parameter "threshold" must be a number`,
+		},
+		{
+			name:             "invalid label selector",
+			values:           map[string]string{"selector": `team=a`, "datasource": "prometheus", "team": "a"},
+			expErrorContains: `value of parameter "selector" must be a list of label matchers`,
+		},
+		{
+			name:             "empty data source",
+			values:           map[string]string{"selector": `team="a"`, "datasource": "", "team": "a"},
+			expErrorContains: `value of parameter "datasource" must be a data source UID`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tmpl.Render(tc.values)
+			require.ErrorIs(t, err, ErrRuleTemplateInstanceInvalidBase)
+			require.ErrorContains(t, err, tc.expErrorContains)
+		})
+	}
+}
@@ -107,6 +107,14 @@ func (g *AlertRuleGenerator) Generate() AlertRule {
 		updatedBy = util.Pointer(UserUID(util.GenerateShortUID()))
 	}
 
+	var inhibitedBy []RuleInhibition
+	if rand.Int63()%2 == 0 {
+		inhibitedBy = append(inhibitedBy, RuleInhibition{
+			RuleUID: util.GenerateShortUID(),
+			Equal:   []string{"instance"},
+		})
+	}
+
 	rule := AlertRule{
 		ID:                          0,
 		GUID:                        uuid.NewString(),
@@ -133,6 +141,7 @@ func (g *AlertRuleGenerator) Generate() AlertRule {
 		NotificationSettings:        ns,
 		Metadata:                    GenerateMetadata(),
 		MissingSeriesEvalsToResolve: util.Pointer[int64](2),
+		InhibitedBy:                 inhibitedBy,
 	}
 
 	for _, mutator := range g.mutators {
@@ -233,6 +242,12 @@ func (a *AlertRuleMutators) WithPrometheusOriginalRuleDefinition(definition stri
 	}
 }
 
+func (a *AlertRuleMutators) WithSequentialEvaluation(enabled bool) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Metadata.SequentialEvaluation = enabled
+	}
+}
+
 func (a *AlertRuleMutators) WithGroupIndex(groupIndex int) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.RuleGroupIndex = groupIndex
@@ -543,6 +558,24 @@ func (a *AlertRuleMutators) WithMissingSeriesEvalsToResolve(timesOfInterval int6
 	}
 }
 
+func (a *AlertRuleMutators) WithEvaluationSchedule(schedule *EvaluationSchedule) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.EvaluationSchedule = schedule
+	}
+}
+
+func (a *AlertRuleMutators) WithFlapDetection(flapDetection *FlapDetection) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.FlapDetection = flapDetection
+	}
+}
+
+func (a *AlertRuleMutators) WithInhibitedBy(inhibitions ...RuleInhibition) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.InhibitedBy = inhibitions
+	}
+}
+
 func (a *AlertRuleMutators) WithNotificationSettingsGen(ns func() NotificationSettings) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.NotificationSettings = []NotificationSettings{ns()}
@@ -1496,6 +1529,7 @@ func ConvertToRecordingRule(rule *AlertRule) {
 	rule.KeepFiringFor = 0
 	rule.NotificationSettings = nil
 	rule.MissingSeriesEvalsToResolve = nil
+	rule.InhibitedBy = nil
 }
 
 func nameToUid(name string) string { // Avoid legacy_storage.NameToUid import cycle.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path/filepath"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -88,6 +89,7 @@ func ProvideService(
 	pluginContextProvider *plugincontext.Provider,
 	resourcePermissions accesscontrol.ReceiverPermissionsService,
 	userService user.Service,
+	imageStore *image.BlobImageStore,
 ) (*AlertNG, error) {
 	ng := &AlertNG{
 		Cfg:                   cfg,
@@ -118,6 +120,7 @@ func ProvideService(
 		pluginContextProvider: pluginContextProvider,
 		ResourcePermissions:   resourcePermissions,
 		userService:           userService,
+		imageStore:            imageStore,
 	}
 
 	if ng.IsDisabled() {
@@ -149,9 +152,11 @@ type AlertNG struct {
 	Log                   log.Logger
 	renderService         rendering.Service
 	ImageService          image.ImageService
+	imageStore            *image.BlobImageStore
 	RecordingWriter       schedule.RecordingWriter
 	schedule              schedule.ScheduleService
 	stateManager          *state.Manager
+	maintenanceWindows    *state.MaintenanceWindowCache
 	folderService         folder.Service
 	dashboardService      dashboards.DashboardService
 	Api                   *api.API
@@ -287,7 +292,7 @@ func (ng *AlertNG) init() error {
 	}
 	ng.MultiOrgAlertmanager = moa
 
-	imageService, err := image.NewScreenshotImageServiceFromCfg(ng.Cfg, ng.store, ng.dashboardService, ng.renderService, ng.Metrics.Registerer)
+	imageService, err := image.NewScreenshotImageServiceFromCfg(ng.Cfg, ng.store, ng.dashboardService, ng.renderService, ng.imageStore, ng.Metrics.Registerer)
 	if err != nil {
 		return err
 	}
@@ -319,7 +324,7 @@ func (ng *AlertNG) init() error {
 	evalFactory := eval.NewEvaluatorFactory(ng.Cfg.UnifiedAlerting, ng.DataSourceCache, ng.ExpressionService)
 	conditionValidator := eval.NewConditionValidator(ng.DataSourceCache, ng.ExpressionService, ng.pluginsStore)
 
-	recordingWriter, err := createRecordingWriter(ng.Cfg.UnifiedAlerting.RecordingRules, ng.httpClientProvider, ng.DataSourceService, ng.pluginContextProvider, clk, ng.Metrics.GetRemoteWriterMetrics())
+	recordingWriter, err := createRecordingWriter(ng.Cfg.UnifiedAlerting.RecordingRules, ng.Cfg.DataPath, ng.httpClientProvider, ng.DataSourceService, ng.pluginContextProvider, clk, ng.Metrics.GetRemoteWriterMetrics())
 	if err != nil {
 		return fmt.Errorf("failed to initialize recording writer: %w", err)
 	}
@@ -355,6 +360,7 @@ func (ng *AlertNG) init() error {
 		ng.annotationsRepo,
 		ng.dashboardService,
 		ng.store,
+		ng.store,
 		ng.Metrics.GetHistorianMetrics(),
 		ng.Log,
 		ng.tracer,
@@ -371,6 +377,8 @@ func (ng *AlertNG) init() error {
 
 	ng.InstanceStore, ng.StartupInstanceReader = initInstanceStore(ng.store.SQLStore, ng.Log, ng.FeatureToggles)
 
+	ng.maintenanceWindows = state.NewMaintenanceWindowCache(ng.store, ng.Cfg.UnifiedAlerting.BaseInterval, clk, log.New("ngalert.state.maintenance"))
+
 	stateManagerCfg := state.ManagerCfg{
 		Metrics:                        ng.Metrics.GetStateMetrics(),
 		ExternalURL:                    appUrl,
@@ -387,6 +395,7 @@ func (ng *AlertNG) init() error {
 		Tracer:                         ng.tracer,
 		Log:                            log.New("ngalert.state.manager"),
 		ResolvedRetention:              ng.Cfg.UnifiedAlerting.ResolvedAlertRetention,
+		MaintenanceWindows:             ng.maintenanceWindows,
 	}
 	statePersister := initStatePersister(ng.Cfg.UnifiedAlerting, stateManagerCfg, ng.FeatureToggles)
 	stateManager := state.NewManager(stateManagerCfg, statePersister)
@@ -437,6 +446,9 @@ func (ng *AlertNG) init() error {
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
 		ng.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit, ng.Log, notifier.NewNotificationSettingsValidationService(ng.store),
 		ac.NewRuleService(ng.accesscontrol))
+	ruleTemplateService := provisioning.NewRuleTemplateService(ng.store, alertRuleService, ng.store, ng.Log)
+	maintenanceWindowService := provisioning.NewMaintenanceWindowService(ng.store, ng.Log)
+	importService := provisioning.NewImportService(alertRuleService, contactPointService, policyService, muteTimingService, ng.folderService, ng.store, ng.Log)
 
 	ng.Api = &api.API{
 		Cfg:                  ng.Cfg,
@@ -460,8 +472,12 @@ func (ng *AlertNG) init() error {
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
 		AlertRules:           alertRuleService,
+		RuleTemplates:        ruleTemplateService,
+		MaintenanceWindows:   maintenanceWindowService,
+		Imports:              importService,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
+		RecordingWriter:      ng.RecordingWriter,
 		ConditionValidator:   conditionValidator,
 		FeatureManager:       ng.FeatureToggles,
 		AppUrl:               appUrl,
@@ -469,6 +485,7 @@ func (ng *AlertNG) init() error {
 		Hooks:                api.NewHooks(ng.Log),
 		Tracer:               ng.tracer,
 		UserService:          ng.userService,
+		ImageStore:           ng.imageStore,
 	}
 	ng.Api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -572,6 +589,11 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	if wal, ok := ng.RecordingWriter.(*writer.WALWriter); ok {
+		children.Go(func() error {
+			return wal.Run(subCtx)
+		})
+	}
 
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts {
 		// Only Warm() the state manager if we are actually executing alerts.
@@ -591,6 +613,9 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 		children.Go(func() error {
 			return ng.stateManager.Run(subCtx)
 		})
+		children.Go(func() error {
+			return ng.maintenanceWindows.Run(subCtx)
+		})
 	}
 	return children.Wait()
 }
@@ -610,6 +635,14 @@ func (ng *AlertNG) GetHooks() *api.Hooks {
 	return ng.Api.Hooks
 }
 
+// GetAlertInstanceManager returns the manager of the current alert instances, or nil if the alerting service is disabled.
+func (ng *AlertNG) GetAlertInstanceManager() state.AlertInstanceManager {
+	if ng.stateManager == nil {
+		return nil
+	}
+	return ng.stateManager
+}
+
 type Historian interface {
 	api.Historian
 	state.Historian
@@ -621,6 +654,7 @@ func configureHistorianBackend(
 	ar annotations.Repository,
 	ds dashboards.DashboardService,
 	rs historian.RuleStore,
+	hs historian.StateHistoryStore,
 	met *metrics.Historian,
 	l log.Logger,
 	tracer tracing.Tracer,
@@ -645,7 +679,7 @@ func configureHistorianBackend(
 	if backend == historian.BackendTypeMultiple {
 		primaryCfg := cfg
 		primaryCfg.Backend = cfg.MultiPrimary
-		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, ds, rs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw)
+		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, ds, rs, hs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw)
 		if err != nil {
 			return nil, fmt.Errorf("multi-backend target \"%s\" was misconfigured: %w", cfg.MultiPrimary, err)
 		}
@@ -654,7 +688,7 @@ func configureHistorianBackend(
 		for _, b := range cfg.MultiSecondaries {
 			secCfg := cfg
 			secCfg.Backend = b
-			sec, err := configureHistorianBackend(ctx, secCfg, ar, ds, rs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw)
+			sec, err := configureHistorianBackend(ctx, secCfg, ar, ds, rs, hs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw)
 			if err != nil {
 				return nil, fmt.Errorf("multi-backend target \"%s\" was miconfigured: %w", b, err)
 			}
@@ -709,6 +743,12 @@ func configureHistorianBackend(
 		return backend, nil
 	}
 
+	if backend == historian.BackendTypeSQL {
+		logCtx := log.WithContextualAttributes(ctx, []any{"backend", "sql"})
+		sqlBackendLogger := log.New("ngalert.state.historian").FromContext(logCtx)
+		return historian.NewSQLBackend(sqlBackendLogger, hs, rs, met, ac), nil
+	}
+
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
 
@@ -743,7 +783,7 @@ func configureNotificationHistorian(
 	return nh, nil
 }
 
-func createRecordingWriter(settings setting.RecordingRuleSettings, httpClientProvider httpclient.Provider, datasourceService datasources.DataSourceService, pluginContextProvider *plugincontext.Provider, clock clock.Clock, m *metrics.RemoteWriter) (schedule.RecordingWriter, error) {
+func createRecordingWriter(settings setting.RecordingRuleSettings, dataPath string, httpClientProvider httpclient.Provider, datasourceService datasources.DataSourceService, pluginContextProvider *plugincontext.Provider, clock clock.Clock, m *metrics.RemoteWriter) (schedule.RecordingWriter, error) {
 	logger := log.New("ngalert.writer")
 
 	if settings.Enabled {
@@ -756,7 +796,24 @@ func createRecordingWriter(settings setting.RecordingRuleSettings, httpClientPro
 		logger.Info("Setting up remote write using data sources",
 			"timeout", cfg.Timeout, "default_datasource_uid", cfg.DefaultDatasourceUID)
 
-		return writer.NewDatasourceWriter(cfg, datasourceService, httpClientProvider, pluginContextProvider, clock, logger, m), nil
+		dsWriter := writer.NewDatasourceWriter(cfg, datasourceService, httpClientProvider, pluginContextProvider, clock, logger, m)
+		if !settings.WALEnabled {
+			return dsWriter, nil
+		}
+
+		walCfg := writer.WALWriterConfig{
+			Dir:            settings.WALDirectory,
+			MaxSizeBytes:   settings.WALMaxSizeBytes,
+			ReplayInterval: settings.WALReplayInterval,
+		}
+		if walCfg.Dir == "" {
+			walCfg.Dir = filepath.Join(dataPath, "alerting", "recording-wal")
+		}
+
+		logger.Info("Buffering remote writes in a write-ahead log",
+			"dir", walCfg.Dir, "max_size_bytes", walCfg.MaxSizeBytes, "replay_interval", walCfg.ReplayInterval)
+
+		return writer.NewWALWriter(walCfg, dsWriter, clock, logger, m)
 	}
 
 	return writer.NoopWriter{}, nil
@@ -0,0 +1,229 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/inhibit"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RoutingSimulation is the result of routing an alert through the notification policy tree.
+type RoutingSimulation struct {
+	// Routes are the matched routes, in the order in which the Alertmanager would notify them.
+	Routes []SimulatedRoute
+	// Inhibitions are the inhibition rules that target the alert.
+	Inhibitions []SimulatedInhibition
+}
+
+// SimulatedRoute is a matched route with the settings inherited from its parents.
+type SimulatedRoute struct {
+	// ID identifies the route within the tree.
+	ID string
+	// Path contains the matchers of the route and of all its parents, starting with the root.
+	Path []string
+	// Autogenerated is true for the routes generated from the notification settings of alert rules.
+	Autogenerated bool
+
+	Receiver       string
+	GroupBy        []string
+	GroupWait      time.Duration
+	GroupInterval  time.Duration
+	RepeatInterval time.Duration
+
+	MuteTimeIntervals   []SimulatedTimeInterval
+	ActiveTimeIntervals []SimulatedTimeInterval
+	// Muted is true if the notifications of the route are muted by its time intervals at the time of the simulation.
+	Muted bool
+}
+
+// SimulatedTimeInterval is a time interval referenced by a route.
+type SimulatedTimeInterval struct {
+	Name string
+	// Active is true if the time of the simulation is within the time interval.
+	Active bool
+}
+
+// SimulatedInhibition is an inhibition rule whose target matchers match the alert.
+type SimulatedInhibition struct {
+	SourceMatchers []string
+	Equal          []string
+	// Active is true if one of the current alerts of the Alertmanager matches the source matchers and the equal labels.
+	Active bool
+}
+
+// SimulateRouting routes an alert with the given labels through the current notification policy tree of the organization,
+// including the routes autogenerated from the notification settings of alert rules.
+// Time intervals are evaluated at the given time, and inhibition rules against the current alerts of the Alertmanager.
+func (moa *MultiOrgAlertmanager) SimulateRouting(ctx context.Context, orgID int64, lset model.LabelSet, at time.Time) (RoutingSimulation, error) {
+	cfg, err := moa.GetAlertmanagerConfiguration(ctx, orgID, true, true)
+	if err != nil {
+		return RoutingSimulation{}, err
+	}
+
+	var alerts []model.LabelSet
+	am, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		// Routing does not depend on the Alertmanager, only the state of inhibitions does.
+		if !errors.Is(err, ErrAlertmanagerNotReady) {
+			return RoutingSimulation{}, err
+		}
+		moa.logger.Warn("Alertmanager is not ready, inhibitions are simulated without alerts", "org", orgID)
+	} else {
+		current, err := am.GetAlerts(ctx, true, true, true, nil, "")
+		if err != nil {
+			return RoutingSimulation{}, fmt.Errorf("failed to get alerts: %w", err)
+		}
+		for _, a := range current {
+			alert := make(model.LabelSet, len(a.Labels))
+			for k, v := range a.Labels {
+				alert[model.LabelName(k)] = model.LabelValue(v)
+			}
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return SimulateRouting(cfg.AlertmanagerConfig.Config, lset, at, alerts)
+}
+
+// SimulateRouting routes an alert with the given labels through the route tree of the configuration.
+// alerts are the labels of the current alerts, used to determine whether inhibition rules are active.
+func SimulateRouting(cfg definitions.Config, lset model.LabelSet, at time.Time, alerts []model.LabelSet) (RoutingSimulation, error) {
+	if cfg.Route == nil {
+		return RoutingSimulation{}, errors.New("configuration does not have a root route")
+	}
+	intervals := make(map[string][]timeinterval.TimeInterval, len(cfg.TimeIntervals)+len(cfg.MuteTimeIntervals))
+	for _, ti := range cfg.TimeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+	for _, ti := range cfg.MuteTimeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+	intervener := timeinterval.NewIntervener(intervals)
+	simulateIntervals := func(names []string) ([]SimulatedTimeInterval, bool, error) {
+		result := make([]SimulatedTimeInterval, 0, len(names))
+		anyActive := false
+		for _, name := range names {
+			active, err := intervener.Mutes([]string{name}, at)
+			if err != nil {
+				return nil, false, err
+			}
+			anyActive = anyActive || active
+			result = append(result, SimulatedTimeInterval{Name: name, Active: active})
+		}
+		return result, anyActive, nil
+	}
+
+	var result RoutingSimulation
+	root := dispatch.NewRoute(cfg.Route.AsAMRoute(), nil)
+	for _, r := range root.Match(lset) {
+		route := SimulatedRoute{
+			ID:             r.ID(),
+			Receiver:       r.RouteOpts.Receiver,
+			GroupWait:      r.RouteOpts.GroupWait,
+			GroupInterval:  r.RouteOpts.GroupInterval,
+			RepeatInterval: r.RouteOpts.RepeatInterval,
+		}
+		route.Path, route.Autogenerated = routePath(root, r)
+		if r.RouteOpts.GroupByAll {
+			route.GroupBy = []string{"..."}
+		} else {
+			for l := range r.RouteOpts.GroupBy {
+				route.GroupBy = append(route.GroupBy, string(l))
+			}
+			sort.Strings(route.GroupBy)
+		}
+
+		var muted, active bool
+		var err error
+		route.MuteTimeIntervals, muted, err = simulateIntervals(r.RouteOpts.MuteTimeIntervals)
+		if err != nil {
+			return RoutingSimulation{}, err
+		}
+		route.ActiveTimeIntervals, active, err = simulateIntervals(r.RouteOpts.ActiveTimeIntervals)
+		if err != nil {
+			return RoutingSimulation{}, err
+		}
+		route.Muted = muted || (len(route.ActiveTimeIntervals) > 0 && !active)
+		result.Routes = append(result.Routes, route)
+	}
+
+	for _, cr := range cfg.InhibitRules {
+		rule := inhibit.NewInhibitRule(cr)
+		if !rule.TargetMatchers.Matches(lset) {
+			continue
+		}
+		inhibition := SimulatedInhibition{}
+		for _, m := range rule.SourceMatchers {
+			inhibition.SourceMatchers = append(inhibition.SourceMatchers, m.String())
+		}
+		for l := range rule.Equal {
+			inhibition.Equal = append(inhibition.Equal, string(l))
+		}
+		sort.Strings(inhibition.Equal)
+		// Like the inhibitor of the Alertmanager, if the alert matches both sides of the rule,
+		// source alerts that also match both sides are ignored.
+		excludeTwoSidedMatch := rule.SourceMatchers.Matches(lset)
+		inhibition.Active = slices.ContainsFunc(alerts, func(source model.LabelSet) bool {
+			if excludeTwoSidedMatch && rule.TargetMatchers.Matches(source) {
+				return false
+			}
+			return inhibits(rule, source, lset)
+		})
+		result.Inhibitions = append(result.Inhibitions, inhibition)
+	}
+	return result, nil
+}
+
+func inhibits(rule *inhibit.InhibitRule, source, target model.LabelSet) bool {
+	if !rule.SourceMatchers.Matches(source) {
+		return false
+	}
+	for l := range rule.Equal {
+		if source[l] != target[l] {
+			return false
+		}
+	}
+	return true
+}
+
+// routePath returns the matchers of the route and of its parents, and whether the route belongs to the autogenerated tree.
+func routePath(root, target *dispatch.Route) ([]string, bool) {
+	var path []*dispatch.Route
+	var find func(r *dispatch.Route) bool
+	find = func(r *dispatch.Route) bool {
+		path = append(path, r)
+		if r == target {
+			return true
+		}
+		for _, child := range r.Routes {
+			if find(child) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	find(root)
+
+	result := make([]string, 0, len(path))
+	autogenerated := false
+	for _, r := range path {
+		result = append(result, r.Matchers.String())
+		for _, m := range r.Matchers {
+			if m.Name == models.AutogeneratedRouteLabel {
+				autogenerated = true
+			}
+		}
+	}
+	return result, autogenerated
+}
@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const routingSimulationConfig = `{
+	"route": {
+		"receiver": "default",
+		"group_by": ["alertname"],
+		"group_wait": "30s",
+		"routes": [
+			{
+				"receiver": "team-a",
+				"object_matchers": [["team", "=", "a"]],
+				"continue": true,
+				"routes": [{"receiver": "team-a-critical", "object_matchers": [["severity", "=", "critical"]], "group_by": ["..."], "mute_time_intervals": ["weekends"]}]
+			},
+			{
+				"receiver": "team-a-office",
+				"object_matchers": [["team", "=", "a"]],
+				"active_time_intervals": ["office"],
+				"repeat_interval": "1h"
+			}
+		]
+	},
+	"inhibit_rules": [
+		{"source_matchers": ["severity=critical"], "target_matchers": ["severity=warning"], "equal": ["team"]},
+		{"source_matchers": ["severity=critical"], "target_matchers": ["team=b"]}
+	],
+	"time_intervals": [{"name": "office", "time_intervals": [{"weekdays": ["monday:friday"], "times": [{"start_time": "09:00", "end_time": "17:00"}]}]}],
+	"mute_time_intervals": [{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]}],
+	"receivers": [{"name": "default"}, {"name": "team-a"}, {"name": "team-a-critical"}, {"name": "team-a-office"}]
+}`
+
+func TestSimulateRouting(t *testing.T) {
+	var cfg apimodels.PostableApiAlertingConfig
+	require.NoError(t, json.Unmarshal([]byte(routingSimulationConfig), &cfg))
+
+	saturday := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	monday := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	t.Run("returns the default route if no route matches", func(t *testing.T) {
+		result, err := SimulateRouting(cfg.Config, model.LabelSet{"team": "c"}, monday, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Routes, 1)
+		route := result.Routes[0]
+		require.Equal(t, "default", route.Receiver)
+		require.Equal(t, []string{"{}"}, route.Path)
+		require.Equal(t, []string{"alertname"}, route.GroupBy)
+		require.Equal(t, 30*time.Second, route.GroupWait)
+		require.False(t, route.Autogenerated)
+		require.False(t, route.Muted)
+		require.Empty(t, result.Inhibitions)
+	})
+
+	t.Run("returns all matched routes with inherited settings", func(t *testing.T) {
+		result, err := SimulateRouting(cfg.Config, model.LabelSet{"team": "a", "severity": "critical"}, monday, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Routes, 2)
+
+		critical := result.Routes[0]
+		require.Equal(t, "team-a-critical", critical.Receiver)
+		require.Equal(t, []string{"{}", `{team="a"}`, `{severity="critical"}`}, critical.Path)
+		require.Equal(t, []string{"..."}, critical.GroupBy)
+		require.Equal(t, 30*time.Second, critical.GroupWait)
+		require.Equal(t, []SimulatedTimeInterval{{Name: "weekends", Active: false}}, critical.MuteTimeIntervals)
+		require.False(t, critical.Muted)
+
+		office := result.Routes[1]
+		require.Equal(t, "team-a-office", office.Receiver)
+		require.Equal(t, time.Hour, office.RepeatInterval)
+		require.Equal(t, []SimulatedTimeInterval{{Name: "office", Active: true}}, office.ActiveTimeIntervals)
+		require.False(t, office.Muted)
+	})
+
+	t.Run("routes are muted by time intervals", func(t *testing.T) {
+		result, err := SimulateRouting(cfg.Config, model.LabelSet{"team": "a", "severity": "critical"}, saturday, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Routes, 2)
+		require.Equal(t, []SimulatedTimeInterval{{Name: "weekends", Active: true}}, result.Routes[0].MuteTimeIntervals)
+		require.True(t, result.Routes[0].Muted)
+		require.Equal(t, []SimulatedTimeInterval{{Name: "office", Active: false}}, result.Routes[1].ActiveTimeIntervals)
+		require.True(t, result.Routes[1].Muted)
+	})
+
+	t.Run("inhibitions are active if a current alert matches", func(t *testing.T) {
+		lset := model.LabelSet{"team": "a", "severity": "warning"}
+
+		result, err := SimulateRouting(cfg.Config, lset, monday, []model.LabelSet{{"team": "b", "severity": "critical"}})
+		require.NoError(t, err)
+		require.Equal(t, []SimulatedInhibition{{SourceMatchers: []string{`severity="critical"`}, Equal: []string{"team"}, Active: false}}, result.Inhibitions)
+
+		result, err = SimulateRouting(cfg.Config, lset, monday, []model.LabelSet{{"team": "a", "severity": "critical"}})
+		require.NoError(t, err)
+		require.Len(t, result.Inhibitions, 1)
+		require.True(t, result.Inhibitions[0].Active)
+	})
+
+	t.Run("alerts that match both sides of an inhibition rule do not inhibit each other", func(t *testing.T) {
+		lset := model.LabelSet{"team": "b", "severity": "critical"}
+
+		result, err := SimulateRouting(cfg.Config, lset, monday, []model.LabelSet{{"team": "b", "severity": "critical", "instance": "1"}})
+		require.NoError(t, err)
+		require.Len(t, result.Inhibitions, 1)
+		require.False(t, result.Inhibitions[0].Active)
+
+		result, err = SimulateRouting(cfg.Config, lset, monday, []model.LabelSet{{"team": "c", "severity": "critical"}})
+		require.NoError(t, err)
+		require.Len(t, result.Inhibitions, 1)
+		require.True(t, result.Inhibitions[0].Active)
+	})
+}
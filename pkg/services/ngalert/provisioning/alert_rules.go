@@ -809,6 +809,40 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, user ident
 	return rule, err
 }
 
+// SetAlertRulesPaused pauses or resumes the alert rules that the user can read and that match the selector.
+// Rules that are already paused or resumed are not changed. Either all the rules are updated, or none of them.
+// It returns the updated rules.
+func (service *AlertRuleService) SetAlertRulesPaused(ctx context.Context, user identity.Requester, selector models.AlertRuleSelector, paused bool, provenance models.Provenance) ([]models.AlertRule, error) {
+	if err := selector.Validate(); err != nil {
+		return nil, models.ErrAlertRuleSelectorInvalid(err)
+	}
+	rules, _, err := service.GetAlertRules(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	var result []models.AlertRule
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		for _, rule := range rules {
+			if rule.IsPaused == paused || !selector.Matches(rule) {
+				continue
+			}
+			update := rule.Copy()
+			update.IsPaused = paused
+			updated, err := service.UpdateAlertRule(ctx, user, *update, provenance)
+			if err != nil {
+				return err
+			}
+			result = append(result, updated)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	service.log.FromContext(ctx).Info("Changed paused state of alert rules", "paused", paused, "rules", len(result))
+	return result, nil
+}
+
 func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, user identity.Requester, ruleUID string, provenance models.Provenance) error {
 	rule := &models.AlertRule{
 		OrgID: user.GetOrgID(),
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -2470,6 +2471,77 @@ func TestDeleteRuleGroups(t *testing.T) {
 	})
 }
 
+func TestSetAlertRulesPaused(t *testing.T) {
+	orgID := rand.Int63()
+	u := &user.SignedInUser{OrgID: orgID}
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(orgID), models.RuleGen.WithGroupKey(models.GenerateGroupKey(orgID)))
+	running := gen.With(gen.WithLabel("team", "db"), gen.WithIsPaused(false)).GenerateRef()
+	paused := gen.With(gen.WithLabel("team", "db"), gen.WithIsPaused(true)).GenerateRef()
+	other := gen.With(gen.WithLabel("team", "web"), gen.WithIsPaused(false)).GenerateRef()
+
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "team", "db")
+	require.NoError(t, err)
+	selector := models.AlertRuleSelector{Matchers: labels.Matchers{matcher}}
+
+	initServiceWithData := func(t *testing.T) (*AlertRuleService, *fakes.RuleStore) {
+		service, ruleStore, _, ac := initService(t)
+		ruleStore.Rules = map[int64][]*models.AlertRule{
+			orgID: {models.CopyRule(running), models.CopyRule(paused), models.CopyRule(other)},
+		}
+		ac.CanReadAllRulesFunc = func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		}
+		ac.CanWriteAllRulesFunc = func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		}
+		return service, ruleStore
+	}
+	getUpdates := func(ruleStore *fakes.RuleStore) []models.UpdateRule {
+		var result []models.UpdateRule
+		for _, cmd := range ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			a, ok := cmd.([]models.UpdateRule)
+			return a, ok
+		}) {
+			result = append(result, cmd.([]models.UpdateRule)...)
+		}
+		return result
+	}
+
+	t.Run("should pause only the selected rules that are not paused", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+
+		updated, err := service.SetAlertRulesPaused(context.Background(), u, selector, true, models.ProvenanceNone)
+		require.NoError(t, err)
+		require.Len(t, updated, 1)
+		require.Equal(t, running.UID, updated[0].UID)
+		require.True(t, updated[0].IsPaused)
+
+		updates := getUpdates(ruleStore)
+		require.Len(t, updates, 1)
+		require.Equal(t, running.UID, updates[0].New.UID)
+		require.True(t, updates[0].New.IsPaused)
+	})
+
+	t.Run("should resume only the selected rules that are paused", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+
+		updated, err := service.SetAlertRulesPaused(context.Background(), u, selector, false, models.ProvenanceNone)
+		require.NoError(t, err)
+		require.Len(t, updated, 1)
+		require.Equal(t, paused.UID, updated[0].UID)
+		require.False(t, updated[0].IsPaused)
+		require.Len(t, getUpdates(ruleStore), 1)
+	})
+
+	t.Run("should fail if the selector is empty", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t)
+
+		_, err := service.SetAlertRulesPaused(context.Background(), u, models.AlertRuleSelector{}, true, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrAlertRuleSelectorInvalidBase)
+		require.Empty(t, getUpdates(ruleStore))
+	})
+}
+
 func getDeleteQueries(ruleStore *fakes.RuleStore) []fakes.GenericRecordedQuery {
 	generic := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
 		a, ok := cmd.(fakes.GenericRecordedQuery)
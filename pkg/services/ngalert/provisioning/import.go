@@ -0,0 +1,573 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// ImportResourceType is the type of resource changed by an import.
+type ImportResourceType string
+
+const (
+	ImportResourceAlertRule          ImportResourceType = "alert_rule"
+	ImportResourceContactPoint       ImportResourceType = "contact_point"
+	ImportResourceNotificationPolicy ImportResourceType = "notification_policy"
+	ImportResourceMuteTiming         ImportResourceType = "mute_timing"
+)
+
+// ImportAction is the change made to a resource by an import.
+type ImportAction string
+
+const (
+	ImportActionCreate ImportAction = "create"
+	ImportActionUpdate ImportAction = "update"
+	ImportActionDelete ImportAction = "delete"
+)
+
+// ImportResources are the alerting resources to import. Resources that are not part of the import are not changed,
+// with the exception of the rules of the imported rule groups, which are replaced like in ReplaceRuleGroup.
+type ImportResources struct {
+	// RuleGroups to import. If the folder UID of a group is empty, the folder is looked up by its full path.
+	RuleGroups []models.AlertRuleGroupWithFolderFullpath
+	// ContactPoints to import. Integrations with the same name belong to the same contact point, and replace
+	// all the integrations of the existing contact point.
+	ContactPoints []definitions.EmbeddedContactPoint
+	// Policies replace the notification policy tree, if set.
+	Policies    *definitions.Route
+	MuteTimings []definitions.MuteTimeInterval
+}
+
+// ImportChange describes the change of a single resource.
+type ImportChange struct {
+	Type   ImportResourceType
+	Name   string
+	Action ImportAction
+	// Diff contains the paths of the changed fields of updated resources.
+	Diff []string
+}
+
+type importRuleService interface {
+	GetRuleGroup(ctx context.Context, user identity.Requester, namespaceUID, group string) (models.AlertRuleGroup, error)
+	calcDelta(ctx context.Context, user identity.Requester, group models.AlertRuleGroup) (*store.GroupDelta, error)
+	ReplaceRuleGroup(ctx context.Context, user identity.Requester, group models.AlertRuleGroup, provenance models.Provenance, versionMessage string) error
+}
+
+type importContactPointService interface {
+	GetContactPoints(ctx context.Context, q ContactPointQuery, u identity.Requester) ([]definitions.EmbeddedContactPoint, error)
+	CreateContactPoint(ctx context.Context, orgID int64, user identity.Requester, contactPoint definitions.EmbeddedContactPoint, provenance models.Provenance) (definitions.EmbeddedContactPoint, error)
+	UpdateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, provenance models.Provenance) error
+	DeleteContactPoint(ctx context.Context, orgID int64, uid string) error
+}
+
+type importPolicyService interface {
+	GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, string, error)
+	UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance, version string) (definitions.Route, string, error)
+}
+
+type importMuteTimingService interface {
+	GetMuteTimings(ctx context.Context, orgID int64) ([]definitions.MuteTimeInterval, error)
+	CreateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (definitions.MuteTimeInterval, error)
+	UpdateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (definitions.MuteTimeInterval, error)
+}
+
+// ImportService compares alerting resources, usually exported by the provisioning API, with the current state and
+// applies the differences through the provisioning services. All changes are applied in a single transaction.
+type ImportService struct {
+	rules         importRuleService
+	contactPoints importContactPointService
+	policies      importPolicyService
+	muteTimings   importMuteTimingService
+	folderService folder.Service
+	xact          TransactionManager
+	log           log.Logger
+}
+
+func NewImportService(
+	rules importRuleService,
+	contactPoints importContactPointService,
+	policies importPolicyService,
+	muteTimings importMuteTimingService,
+	folderService folder.Service,
+	xact TransactionManager,
+	log log.Logger,
+) *ImportService {
+	return &ImportService{
+		rules:         rules,
+		contactPoints: contactPoints,
+		policies:      policies,
+		muteTimings:   muteTimings,
+		folderService: folderService,
+		xact:          xact,
+		log:           log,
+	}
+}
+
+// errImportDryRun is returned from the transaction of a dry run to roll back the changes.
+var errImportDryRun = errors.New("dry run")
+
+// Import applies the resources and returns the changes. If dryRun is true, the changes are calculated
+// and validated by the provisioning services, but not persisted.
+func (service *ImportService) Import(ctx context.Context, user identity.Requester, resources ImportResources, provenance models.Provenance, dryRun bool) ([]ImportChange, error) {
+	orgID := user.GetOrgID()
+	var changes []ImportChange
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		// Mute timings and contact points go first because policies and rules can reference them.
+		c, err := service.importMuteTimings(ctx, orgID, resources.MuteTimings, provenance)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, c...)
+
+		c, err = service.importContactPoints(ctx, user, resources.ContactPoints, provenance)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, c...)
+
+		if resources.Policies != nil {
+			c, err = service.importPolicies(ctx, orgID, *resources.Policies, provenance)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, c...)
+		}
+
+		for _, group := range resources.RuleGroups {
+			c, err = service.importRuleGroup(ctx, user, group, provenance)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, c...)
+		}
+
+		if dryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportDryRun) {
+		return nil, err
+	}
+	service.log.FromContext(ctx).Info("Imported alerting resources", "changes", len(changes), "dryRun", dryRun)
+	return changes, nil
+}
+
+func (service *ImportService) importMuteTimings(ctx context.Context, orgID int64, timings []definitions.MuteTimeInterval, provenance models.Provenance) ([]ImportChange, error) {
+	if len(timings) == 0 {
+		return nil, nil
+	}
+	existing, err := service.muteTimings.GetMuteTimings(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]definitions.MuteTimeInterval, len(existing))
+	for _, mt := range existing {
+		byName[mt.Name] = mt
+	}
+
+	var changes []ImportChange
+	for _, mt := range timings {
+		mt.Provenance = definitions.Provenance(provenance)
+		current, ok := byName[mt.Name]
+		if !ok {
+			mt.UID = ""
+			if _, err := service.muteTimings.CreateMuteTiming(ctx, mt, orgID); err != nil {
+				return nil, fmt.Errorf("failed to create mute timing %s: %w", mt.Name, err)
+			}
+			changes = append(changes, ImportChange{Type: ImportResourceMuteTiming, Name: mt.Name, Action: ImportActionCreate})
+			continue
+		}
+		diff, err := diffResources(current.MuteTimeInterval, mt.MuteTimeInterval)
+		if err != nil {
+			return nil, err
+		}
+		if len(diff) == 0 && current.Provenance == mt.Provenance {
+			continue
+		}
+		mt.UID = current.UID
+		mt.Version = ""
+		if _, err := service.muteTimings.UpdateMuteTiming(ctx, mt, orgID); err != nil {
+			return nil, fmt.Errorf("failed to update mute timing %s: %w", mt.Name, err)
+		}
+		changes = append(changes, ImportChange{Type: ImportResourceMuteTiming, Name: mt.Name, Action: ImportActionUpdate, Diff: diff})
+	}
+	return changes, nil
+}
+
+func (service *ImportService) importContactPoints(ctx context.Context, user identity.Requester, integrations []definitions.EmbeddedContactPoint, provenance models.Provenance) ([]ImportChange, error) {
+	orgID := user.GetOrgID()
+	var names []string
+	byName := make(map[string][]definitions.EmbeddedContactPoint)
+	for _, integration := range integrations {
+		if _, ok := byName[integration.Name]; !ok {
+			names = append(names, integration.Name)
+		}
+		byName[integration.Name] = append(byName[integration.Name], integration)
+	}
+
+	var changes []ImportChange
+	for _, name := range names {
+		existing, err := service.contactPoints.GetContactPoints(ctx, ContactPointQuery{Name: name, OrgID: orgID}, user)
+		if err != nil {
+			return nil, err
+		}
+		diff, err := service.applyContactPoint(ctx, user, existing, byName[name], provenance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import contact point %s: %w", name, err)
+		}
+		switch {
+		case len(existing) == 0:
+			changes = append(changes, ImportChange{Type: ImportResourceContactPoint, Name: name, Action: ImportActionCreate})
+		case len(diff) > 0:
+			changes = append(changes, ImportChange{Type: ImportResourceContactPoint, Name: name, Action: ImportActionUpdate, Diff: diff})
+		}
+	}
+	return changes, nil
+}
+
+// applyContactPoint replaces the existing integrations of a contact point with the desired ones. An existing
+// integration is matched by UID, or else by its position among the integrations of the same type.
+// Secure settings that are redacted in the desired integration keep their current value.
+func (service *ImportService) applyContactPoint(ctx context.Context, user identity.Requester, existing, desired []definitions.EmbeddedContactPoint, provenance models.Provenance) ([]string, error) {
+	orgID := user.GetOrgID()
+	matched := make([]bool, len(existing))
+	match := func(d definitions.EmbeddedContactPoint) int {
+		if d.UID != "" {
+			for i, e := range existing {
+				if !matched[i] && e.UID == d.UID {
+					return i
+				}
+			}
+			return -1
+		}
+		for i, e := range existing {
+			if !matched[i] && e.Type == d.Type {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var diff []string
+	for idx, d := range desired {
+		path := fmt.Sprintf("integrations[%d]", idx)
+		i := match(d)
+		if i < 0 {
+			if d.Settings != nil {
+				for key, value := range d.Settings.MustMap() {
+					if value == definitions.RedactedValue {
+						return nil, fmt.Errorf("%w: setting %s of new integration %s is redacted", ErrValidation, key, path)
+					}
+				}
+			}
+			if _, err := service.contactPoints.CreateContactPoint(ctx, orgID, user, d, provenance); err != nil {
+				return nil, err
+			}
+			diff = append(diff, path)
+			continue
+		}
+		matched[i] = true
+		current := existing[i]
+		changed, err := diffContactPoint(current, d)
+		if err != nil {
+			return nil, err
+		}
+		if len(changed) == 0 && current.Provenance == string(provenance) {
+			continue
+		}
+		d.UID = current.UID
+		if err := service.contactPoints.UpdateContactPoint(ctx, orgID, d, provenance); err != nil {
+			return nil, err
+		}
+		for _, c := range changed {
+			diff = append(diff, path+"."+c)
+		}
+	}
+	for i, e := range existing {
+		if matched[i] {
+			continue
+		}
+		if err := service.contactPoints.DeleteContactPoint(ctx, orgID, e.UID); err != nil {
+			return nil, err
+		}
+		diff = append(diff, fmt.Sprintf("integrations[%s]", e.UID))
+	}
+	return diff, nil
+}
+
+// diffContactPoint returns the changed fields of the integration. Redacted settings of the desired integration
+// are considered unchanged.
+func diffContactPoint(current, desired definitions.EmbeddedContactPoint) ([]string, error) {
+	var diff []string
+	if current.Type != desired.Type {
+		diff = append(diff, "type")
+	}
+	if current.DisableResolveMessage != desired.DisableResolveMessage {
+		diff = append(diff, "disableResolveMessage")
+	}
+	var currentSettings, desiredSettings map[string]interface{}
+	if current.Settings != nil {
+		currentSettings = current.Settings.MustMap()
+	}
+	if desired.Settings != nil {
+		desiredSettings = desired.Settings.MustMap()
+	}
+	for key, value := range desiredSettings {
+		if value == definitions.RedactedValue {
+			delete(desiredSettings, key)
+			delete(currentSettings, key)
+		}
+	}
+	settingsDiff, err := diffResources(currentSettings, desiredSettings)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range settingsDiff {
+		diff = append(diff, "settings."+d)
+	}
+	return diff, nil
+}
+
+func (service *ImportService) importPolicies(ctx context.Context, orgID int64, tree definitions.Route, provenance models.Provenance) ([]ImportChange, error) {
+	current, _, err := service.policies.GetPolicyTree(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	current.Provenance = ""
+	tree.Provenance = ""
+	diff, err := diffResources(current, tree)
+	if err != nil {
+		return nil, err
+	}
+	if len(diff) == 0 {
+		return nil, nil
+	}
+	if _, _, err := service.policies.UpdatePolicyTree(ctx, orgID, tree, provenance, ""); err != nil {
+		return nil, fmt.Errorf("failed to update notification policies: %w", err)
+	}
+	return []ImportChange{{Type: ImportResourceNotificationPolicy, Name: "root", Action: ImportActionUpdate, Diff: diff}}, nil
+}
+
+func (service *ImportService) importRuleGroup(ctx context.Context, user identity.Requester, group models.AlertRuleGroupWithFolderFullpath, provenance models.Provenance) ([]ImportChange, error) {
+	if group.AlertRuleGroup == nil {
+		return nil, nil
+	}
+	g := *group.AlertRuleGroup
+	if g.FolderUID == "" {
+		uid, err := service.folderUIDByFullpath(ctx, user, group.FolderFullpath)
+		if err != nil {
+			return nil, err
+		}
+		g.FolderUID = uid
+	}
+	for i := range g.Rules {
+		g.Rules[i].OrgID = user.GetOrgID()
+		g.Rules[i].NamespaceUID = g.FolderUID
+		g.Rules[i].RuleGroup = g.Title
+	}
+	if err := service.matchRuleUIDs(ctx, user, &g); err != nil {
+		return nil, err
+	}
+
+	delta, err := service.rules.calcDelta(ctx, user, g)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import rule group %s: %w", g.Title, err)
+	}
+	if delta.IsEmpty() {
+		return nil, nil
+	}
+	if err := service.rules.ReplaceRuleGroup(ctx, user, g, provenance, ""); err != nil {
+		return nil, fmt.Errorf("failed to import rule group %s: %w", g.Title, err)
+	}
+
+	changes := make([]ImportChange, 0, len(delta.New)+len(delta.Update)+len(delta.Delete))
+	for _, rule := range delta.New {
+		changes = append(changes, ImportChange{Type: ImportResourceAlertRule, Name: rule.Title, Action: ImportActionCreate})
+	}
+	for _, update := range delta.Update {
+		changes = append(changes, ImportChange{Type: ImportResourceAlertRule, Name: update.New.Title, Action: ImportActionUpdate, Diff: update.Diff.Paths()})
+	}
+	for _, rule := range delta.Delete {
+		changes = append(changes, ImportChange{Type: ImportResourceAlertRule, Name: rule.Title, Action: ImportActionDelete})
+	}
+	return changes, nil
+}
+
+// matchRuleUIDs sets the UID of the rules that have none, such as rules exported to HCL, to the UID of the existing
+// rule of the group with the same title. Otherwise, such rules would be deleted and created again.
+func (service *ImportService) matchRuleUIDs(ctx context.Context, user identity.Requester, g *models.AlertRuleGroup) error {
+	missing := false
+	for _, rule := range g.Rules {
+		if rule.UID == "" {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return nil
+	}
+	existing, err := service.rules.GetRuleGroup(ctx, user, g.FolderUID, g.Title)
+	if err != nil {
+		if errors.Is(err, models.ErrAlertRuleGroupNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get rule group %s: %w", g.Title, err)
+	}
+	used := make(map[string]struct{}, len(g.Rules))
+	for _, rule := range g.Rules {
+		if rule.UID != "" {
+			used[rule.UID] = struct{}{}
+		}
+	}
+	byTitle := make(map[string]string, len(existing.Rules))
+	for _, rule := range existing.Rules {
+		if _, ok := used[rule.UID]; !ok {
+			byTitle[rule.Title] = rule.UID
+		}
+	}
+	for i := range g.Rules {
+		if g.Rules[i].UID != "" {
+			continue
+		}
+		if uid, ok := byTitle[g.Rules[i].Title]; ok {
+			g.Rules[i].UID = uid
+			delete(byTitle, g.Rules[i].Title)
+		}
+	}
+	return nil
+}
+
+// folderUIDByFullpath returns the UID of the folder with the given full path, such as "parent/child".
+// A slash that is part of a title is escaped with a backslash.
+func (service *ImportService) folderUIDByFullpath(ctx context.Context, user identity.Requester, fullpath string) (string, error) {
+	titles := splitFolderFullpath(fullpath)
+	if len(titles) == 0 {
+		return "", fmt.Errorf("%w: folder of the rule group is not set", ErrValidation)
+	}
+	var parentUID *string
+	for _, title := range titles {
+		f, err := service.folderService.Get(ctx, &folder.GetFolderQuery{
+			Title:        &title,
+			ParentUID:    parentUID,
+			OrgID:        user.GetOrgID(),
+			SignedInUser: user,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to get folder %s: %w", fullpath, err)
+		}
+		parentUID = &f.UID
+	}
+	return *parentUID, nil
+}
+
+func splitFolderFullpath(fullpath string) []string {
+	var result []string
+	current := ""
+	for _, s := range strings.Split(fullpath, "/") {
+		if strings.HasSuffix(current, "\\") {
+			current = current[:len(current)-1] + "/" + s
+			continue
+		}
+		if current != "" {
+			result = append(result, current)
+		}
+		current = s
+	}
+	if current != "" {
+		result = append(result, current)
+	}
+	return result
+}
+
+// diffResources returns the paths of the fields that differ between the JSON representations of the resources.
+func diffResources(current, desired interface{}) ([]string, error) {
+	var a, b interface{}
+	if err := jsonRoundTrip(current, &a); err != nil {
+		return nil, err
+	}
+	if err := jsonRoundTrip(desired, &b); err != nil {
+		return nil, err
+	}
+	var diff []string
+	diffValues("", a, b, &diff)
+	return diff, nil
+}
+
+func jsonRoundTrip(v interface{}, out *interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func diffValues(path string, a, b interface{}, diff *[]string) {
+	// Missing and empty lists or objects are the same.
+	if isEmptyValue(a) && isEmptyValue(b) {
+		return
+	}
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			diffValues(p, av[k], bv[k], diff)
+		}
+		return
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			break
+		}
+		for i := range av {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), av[i], bv[i], diff)
+		}
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		if path == "" {
+			path = "."
+		}
+		*diff = append(*diff, path)
+	}
+}
+
+func isEmptyValue(v interface{}) bool {
+	switch vv := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(vv) == 0
+	case []interface{}:
+		return len(vv) == 0
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestImportService(t *testing.T) {
+	orgID := int64(1)
+	u := &user.SignedInUser{OrgID: orgID}
+
+	t.Run("mute timings are created and updated by name", func(t *testing.T) {
+		sut, fakes := createImportService()
+		fakes.muteTimings.existing = []definitions.MuteTimeInterval{
+			{UID: "uid-1", MuteTimeInterval: config.MuteTimeInterval{Name: "weekends"}, Provenance: definitions.Provenance(models.ProvenanceAPI)},
+			{UID: "uid-2", MuteTimeInterval: config.MuteTimeInterval{Name: "unchanged"}, Provenance: definitions.Provenance(models.ProvenanceAPI)},
+		}
+		weekends := config.MuteTimeInterval{Name: "weekends", TimeIntervals: []timeinterval.TimeInterval{{}}}
+
+		changes, err := sut.Import(context.Background(), u, ImportResources{
+			MuteTimings: []definitions.MuteTimeInterval{
+				{UID: "ignored", MuteTimeInterval: config.MuteTimeInterval{Name: "holidays"}},
+				{MuteTimeInterval: weekends},
+				{MuteTimeInterval: config.MuteTimeInterval{Name: "unchanged", TimeIntervals: []timeinterval.TimeInterval{}}},
+			},
+		}, models.ProvenanceAPI, false)
+
+		require.NoError(t, err)
+		require.Equal(t, []ImportChange{
+			{Type: ImportResourceMuteTiming, Name: "holidays", Action: ImportActionCreate},
+			{Type: ImportResourceMuteTiming, Name: "weekends", Action: ImportActionUpdate, Diff: []string{"time_intervals"}},
+		}, changes)
+		require.Len(t, fakes.muteTimings.created, 1)
+		require.Empty(t, fakes.muteTimings.created[0].UID)
+		require.Len(t, fakes.muteTimings.updated, 1)
+		require.Equal(t, "uid-1", fakes.muteTimings.updated[0].UID)
+	})
+
+	t.Run("contact point integrations are matched by type", func(t *testing.T) {
+		sut, fakes := createImportService()
+		fakes.contactPoints.existing = []definitions.EmbeddedContactPoint{
+			{UID: "slack-uid", Name: "team", Type: "slack", Settings: simplejson.NewFromAny(map[string]any{"url": "secret", "title": "old"}), Provenance: string(models.ProvenanceAPI)},
+			{UID: "email-uid", Name: "team", Type: "email", Settings: simplejson.NewFromAny(map[string]any{"addresses": "a@example.com"}), Provenance: string(models.ProvenanceAPI)},
+		}
+
+		changes, err := sut.Import(context.Background(), u, ImportResources{
+			ContactPoints: []definitions.EmbeddedContactPoint{
+				{Name: "team", Type: "slack", Settings: simplejson.NewFromAny(map[string]any{"url": definitions.RedactedValue, "title": "new"})},
+				{Name: "team", Type: "webhook", Settings: simplejson.NewFromAny(map[string]any{"url": "http://localhost"})},
+			},
+		}, models.ProvenanceAPI, false)
+
+		require.NoError(t, err)
+		require.Equal(t, []ImportChange{{
+			Type:   ImportResourceContactPoint,
+			Name:   "team",
+			Action: ImportActionUpdate,
+			Diff:   []string{"integrations[0].settings.title", "integrations[1]", "integrations[email-uid]"},
+		}}, changes)
+		require.Len(t, fakes.contactPoints.updated, 1)
+		require.Equal(t, "slack-uid", fakes.contactPoints.updated[0].UID)
+		require.Len(t, fakes.contactPoints.created, 1)
+		require.Equal(t, "webhook", fakes.contactPoints.created[0].Type)
+		require.Equal(t, []string{"email-uid"}, fakes.contactPoints.deleted)
+	})
+
+	t.Run("fails if a new integration has redacted settings", func(t *testing.T) {
+		sut, fakes := createImportService()
+
+		_, err := sut.Import(context.Background(), u, ImportResources{
+			ContactPoints: []definitions.EmbeddedContactPoint{
+				{Name: "team", Type: "slack", Settings: simplejson.NewFromAny(map[string]any{"url": definitions.RedactedValue})},
+			},
+		}, models.ProvenanceAPI, false)
+
+		require.ErrorIs(t, err, ErrValidation)
+		require.Empty(t, fakes.contactPoints.created)
+	})
+
+	t.Run("rule group folder is resolved by full path and rules are matched by title", func(t *testing.T) {
+		sut, fakes := createImportService()
+		fakes.folders.ExpectedFolder = &folder.Folder{UID: "folder-uid", OrgID: orgID}
+		fakes.rules.existing = models.AlertRuleGroup{Rules: []models.AlertRule{{UID: "rule-uid", Title: "rule"}}}
+		fakes.rules.delta = &store.GroupDelta{New: []*models.AlertRule{{Title: "new rule"}}}
+
+		changes, err := sut.Import(context.Background(), u, ImportResources{
+			RuleGroups: []models.AlertRuleGroupWithFolderFullpath{{
+				AlertRuleGroup: &models.AlertRuleGroup{Title: "group", Rules: []models.AlertRule{{Title: "rule"}, {Title: "new rule"}}},
+				FolderFullpath: "parent/child",
+			}},
+		}, models.ProvenanceAPI, false)
+
+		require.NoError(t, err)
+		require.Equal(t, []ImportChange{{Type: ImportResourceAlertRule, Name: "new rule", Action: ImportActionCreate}}, changes)
+		require.Len(t, fakes.rules.replaced, 1)
+		group := fakes.rules.replaced[0]
+		require.Equal(t, "folder-uid", group.FolderUID)
+		require.Equal(t, "rule-uid", group.Rules[0].UID)
+		require.Empty(t, group.Rules[1].UID)
+		require.Equal(t, "folder-uid", group.Rules[1].NamespaceUID)
+		require.Equal(t, "group", group.Rules[1].RuleGroup)
+	})
+
+	t.Run("rule group is not replaced if there are no changes", func(t *testing.T) {
+		sut, fakes := createImportService()
+		fakes.rules.delta = &store.GroupDelta{}
+
+		changes, err := sut.Import(context.Background(), u, ImportResources{
+			RuleGroups: []models.AlertRuleGroupWithFolderFullpath{{
+				AlertRuleGroup: &models.AlertRuleGroup{Title: "group", FolderUID: "folder-uid", Rules: []models.AlertRule{{UID: "rule-uid", Title: "rule"}}},
+			}},
+		}, models.ProvenanceAPI, false)
+
+		require.NoError(t, err)
+		require.Empty(t, changes)
+		require.Empty(t, fakes.rules.replaced)
+	})
+
+	t.Run("dry run returns the changes", func(t *testing.T) {
+		sut, _ := createImportService()
+
+		changes, err := sut.Import(context.Background(), u, ImportResources{
+			MuteTimings: []definitions.MuteTimeInterval{{MuteTimeInterval: config.MuteTimeInterval{Name: "holidays"}}},
+		}, models.ProvenanceAPI, true)
+
+		require.NoError(t, err)
+		require.Equal(t, []ImportChange{{Type: ImportResourceMuteTiming, Name: "holidays", Action: ImportActionCreate}}, changes)
+	})
+}
+
+func TestSplitFolderFullpath(t *testing.T) {
+	require.Equal(t, []string{"parent", "child"}, splitFolderFullpath("parent/child"))
+	require.Equal(t, []string{"a/b", "c"}, splitFolderFullpath(`a\/b/c`))
+	require.Empty(t, splitFolderFullpath(""))
+}
+
+type importFakes struct {
+	rules         *fakeImportRuleService
+	contactPoints *fakeImportContactPointService
+	muteTimings   *fakeImportMuteTimingService
+	folders       *foldertest.FakeService
+}
+
+func createImportService() (*ImportService, importFakes) {
+	fakes := importFakes{
+		rules:         &fakeImportRuleService{},
+		contactPoints: &fakeImportContactPointService{},
+		muteTimings:   &fakeImportMuteTimingService{},
+		folders:       foldertest.NewFakeService(),
+	}
+	return NewImportService(fakes.rules, fakes.contactPoints, nil, fakes.muteTimings, fakes.folders, newNopTransactionManager(), log.NewNopLogger()), fakes
+}
+
+type fakeImportRuleService struct {
+	existing models.AlertRuleGroup
+	delta    *store.GroupDelta
+	replaced []models.AlertRuleGroup
+}
+
+func (f *fakeImportRuleService) GetRuleGroup(_ context.Context, _ identity.Requester, _, _ string) (models.AlertRuleGroup, error) {
+	if len(f.existing.Rules) == 0 {
+		return models.AlertRuleGroup{}, models.ErrAlertRuleGroupNotFound.Errorf("")
+	}
+	return f.existing, nil
+}
+
+func (f *fakeImportRuleService) calcDelta(_ context.Context, _ identity.Requester, _ models.AlertRuleGroup) (*store.GroupDelta, error) {
+	return f.delta, nil
+}
+
+func (f *fakeImportRuleService) ReplaceRuleGroup(_ context.Context, _ identity.Requester, group models.AlertRuleGroup, _ models.Provenance, _ string) error {
+	f.replaced = append(f.replaced, group)
+	return nil
+}
+
+type fakeImportContactPointService struct {
+	existing []definitions.EmbeddedContactPoint
+	created  []definitions.EmbeddedContactPoint
+	updated  []definitions.EmbeddedContactPoint
+	deleted  []string
+}
+
+func (f *fakeImportContactPointService) GetContactPoints(_ context.Context, q ContactPointQuery, _ identity.Requester) ([]definitions.EmbeddedContactPoint, error) {
+	var result []definitions.EmbeddedContactPoint
+	for _, cp := range f.existing {
+		if cp.Name == q.Name {
+			result = append(result, cp)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeImportContactPointService) CreateContactPoint(_ context.Context, _ int64, _ identity.Requester, cp definitions.EmbeddedContactPoint, _ models.Provenance) (definitions.EmbeddedContactPoint, error) {
+	f.created = append(f.created, cp)
+	return cp, nil
+}
+
+func (f *fakeImportContactPointService) UpdateContactPoint(_ context.Context, _ int64, cp definitions.EmbeddedContactPoint, _ models.Provenance) error {
+	f.updated = append(f.updated, cp)
+	return nil
+}
+
+func (f *fakeImportContactPointService) DeleteContactPoint(_ context.Context, _ int64, uid string) error {
+	f.deleted = append(f.deleted, uid)
+	return nil
+}
+
+type fakeImportMuteTimingService struct {
+	existing []definitions.MuteTimeInterval
+	created  []definitions.MuteTimeInterval
+	updated  []definitions.MuteTimeInterval
+}
+
+func (f *fakeImportMuteTimingService) GetMuteTimings(_ context.Context, _ int64) ([]definitions.MuteTimeInterval, error) {
+	return f.existing, nil
+}
+
+func (f *fakeImportMuteTimingService) CreateMuteTiming(_ context.Context, mt definitions.MuteTimeInterval, _ int64) (definitions.MuteTimeInterval, error) {
+	f.created = append(f.created, mt)
+	return mt, nil
+}
+
+func (f *fakeImportMuteTimingService) UpdateMuteTiming(_ context.Context, mt definitions.MuteTimeInterval, _ int64) (definitions.MuteTimeInterval, error) {
+	f.updated = append(f.updated, mt)
+	return mt, nil
+}
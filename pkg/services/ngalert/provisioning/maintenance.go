@@ -0,0 +1,91 @@
+package provisioning
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// MaintenanceWindowStore represents the ability to persist and query maintenance windows.
+type MaintenanceWindowStore interface {
+	ListMaintenanceWindows(ctx context.Context, orgID int64) ([]*models.MaintenanceWindow, error)
+	GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (*models.MaintenanceWindow, error)
+	InsertMaintenanceWindow(ctx context.Context, w models.MaintenanceWindow) (*models.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(ctx context.Context, w models.MaintenanceWindow) (*models.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error
+}
+
+// MaintenanceWindowService manages the maintenance windows of alert rules. The scheduler picks up changes
+// to the windows within one evaluation interval.
+type MaintenanceWindowService struct {
+	store MaintenanceWindowStore
+	log   log.Logger
+}
+
+func NewMaintenanceWindowService(store MaintenanceWindowStore, log log.Logger) *MaintenanceWindowService {
+	return &MaintenanceWindowService{
+		store: store,
+		log:   log,
+	}
+}
+
+func (service *MaintenanceWindowService) GetWindows(ctx context.Context, orgID int64) ([]models.MaintenanceWindow, error) {
+	windows, err := service.store.ListMaintenanceWindows(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.MaintenanceWindow, 0, len(windows))
+	for _, w := range windows {
+		result = append(result, *w)
+	}
+	return result, nil
+}
+
+func (service *MaintenanceWindowService) GetWindow(ctx context.Context, orgID int64, uid string) (models.MaintenanceWindow, error) {
+	w, err := service.store.GetMaintenanceWindow(ctx, orgID, uid)
+	if err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	return *w, nil
+}
+
+// CreateWindow creates a new maintenance window. The UID is generated if it is not set.
+func (service *MaintenanceWindowService) CreateWindow(ctx context.Context, user identity.Requester, w models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	if w.UID == "" {
+		w.UID = util.GenerateShortUID()
+	}
+	if err := w.Validate(); err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	w.CreatedBy = string(*userUidOrFallback(user))
+	created, err := service.store.InsertMaintenanceWindow(ctx, w)
+	if err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	service.log.FromContext(ctx).Info("Created maintenance window", "uid", created.UID, "startsAt", created.StartsAt, "endsAt", created.EndsAt, "reason", created.Reason)
+	return *created, nil
+}
+
+// UpdateWindow updates the maintenance window. A window can be ended early by setting its end time to the current time.
+func (service *MaintenanceWindowService) UpdateWindow(ctx context.Context, w models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	if err := w.Validate(); err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	updated, err := service.store.UpdateMaintenanceWindow(ctx, w)
+	if err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	service.log.FromContext(ctx).Info("Updated maintenance window", "uid", updated.UID, "startsAt", updated.StartsAt, "endsAt", updated.EndsAt, "reason", updated.Reason)
+	return *updated, nil
+}
+
+func (service *MaintenanceWindowService) DeleteWindow(ctx context.Context, orgID int64, uid string) error {
+	if err := service.store.DeleteMaintenanceWindow(ctx, orgID, uid); err != nil {
+		return err
+	}
+	service.log.FromContext(ctx).Info("Deleted maintenance window", "uid", uid)
+	return nil
+}
@@ -0,0 +1,264 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	apicompat "github.com/grafana/grafana/pkg/services/ngalert/api/compat"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// RuleTemplateStore represents the ability to persist and query rule templates and their instances.
+type RuleTemplateStore interface {
+	ListRuleTemplates(ctx context.Context, orgID int64) ([]*models.RuleTemplate, error)
+	GetRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.RuleTemplate, error)
+	InsertRuleTemplate(ctx context.Context, t models.RuleTemplate) (*models.RuleTemplate, error)
+	UpdateRuleTemplate(ctx context.Context, t models.RuleTemplate) (*models.RuleTemplate, error)
+	DeleteRuleTemplate(ctx context.Context, orgID int64, uid string) error
+	ListRuleTemplateInstances(ctx context.Context, orgID int64, templateUID string) ([]models.RuleTemplateInstance, error)
+	UpsertRuleTemplateInstances(ctx context.Context, instances ...models.RuleTemplateInstance) error
+}
+
+// templateRuleService is the part of AlertRuleService that is used to manage the instances of rule templates.
+type templateRuleService interface {
+	GetAlertRule(ctx context.Context, user identity.Requester, ruleUID string) (models.AlertRule, models.Provenance, error)
+	CreateAlertRule(ctx context.Context, user identity.Requester, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, user identity.Requester, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error)
+}
+
+// RuleTemplateService manages rule templates and the alert rules created from them.
+// Alert rules are created and updated through the AlertRuleService, so they are authorized and validated
+// like any other alert rule.
+type RuleTemplateService struct {
+	store RuleTemplateStore
+	rules templateRuleService
+	xact  TransactionManager
+	log   log.Logger
+}
+
+func NewRuleTemplateService(store RuleTemplateStore, rules templateRuleService, xact TransactionManager, log log.Logger) *RuleTemplateService {
+	return &RuleTemplateService{
+		store: store,
+		rules: rules,
+		xact:  xact,
+		log:   log,
+	}
+}
+
+func (service *RuleTemplateService) GetTemplates(ctx context.Context, orgID int64) ([]models.RuleTemplate, error) {
+	templates, err := service.store.ListRuleTemplates(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.RuleTemplate, 0, len(templates))
+	for _, t := range templates {
+		result = append(result, *t)
+	}
+	return result, nil
+}
+
+func (service *RuleTemplateService) GetTemplate(ctx context.Context, orgID int64, uid string) (models.RuleTemplate, error) {
+	t, err := service.store.GetRuleTemplate(ctx, orgID, uid)
+	if err != nil {
+		return models.RuleTemplate{}, err
+	}
+	return *t, nil
+}
+
+// CreateTemplate creates a new rule template. The UID is generated if it is not set.
+func (service *RuleTemplateService) CreateTemplate(ctx context.Context, t models.RuleTemplate) (models.RuleTemplate, error) {
+	if t.UID == "" {
+		t.UID = util.GenerateShortUID()
+	}
+	if err := t.Validate(); err != nil {
+		return models.RuleTemplate{}, err
+	}
+	created, err := service.store.InsertRuleTemplate(ctx, t)
+	if err != nil {
+		return models.RuleTemplate{}, err
+	}
+	return *created, nil
+}
+
+// UpdateTemplate updates the rule template and renders its instances again, so that the alert rules created from the
+// template are updated too. If the version of the template is set, it must match the stored one.
+// Either the template and all the instances are updated, or none of them.
+func (service *RuleTemplateService) UpdateTemplate(ctx context.Context, user identity.Requester, t models.RuleTemplate, provenance models.Provenance) (models.RuleTemplate, error) {
+	if err := t.Validate(); err != nil {
+		return models.RuleTemplate{}, err
+	}
+	var updated *models.RuleTemplate
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		existing, err := service.store.GetRuleTemplate(ctx, t.OrgID, t.UID)
+		if err != nil {
+			return err
+		}
+		if t.Version == 0 {
+			t.Version = existing.Version
+		}
+		updated, err = service.store.UpdateRuleTemplate(ctx, t)
+		if err != nil {
+			return err
+		}
+		instances, err := service.store.ListRuleTemplateInstances(ctx, t.OrgID, t.UID)
+		if err != nil {
+			return err
+		}
+		for _, instance := range instances {
+			if _, err := service.applyInstance(ctx, user, *updated, instance, provenance); err != nil {
+				return err
+			}
+		}
+		service.log.FromContext(ctx).Info("Updated rule template", "uid", t.UID, "version", updated.Version, "instances", len(instances))
+		return nil
+	})
+	if err != nil {
+		return models.RuleTemplate{}, err
+	}
+	return *updated, nil
+}
+
+// DeleteTemplate deletes the rule template. It fails if there are alert rules created from the template.
+func (service *RuleTemplateService) DeleteTemplate(ctx context.Context, orgID int64, uid string) error {
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if _, err := service.store.GetRuleTemplate(ctx, orgID, uid); err != nil {
+			return err
+		}
+		instances, err := service.store.ListRuleTemplateInstances(ctx, orgID, uid)
+		if err != nil {
+			return err
+		}
+		if len(instances) > 0 {
+			return models.ErrRuleTemplateInUse(len(instances))
+		}
+		return service.store.DeleteRuleTemplate(ctx, orgID, uid)
+	})
+}
+
+// GetInstances returns the alert rules created from the rule template.
+func (service *RuleTemplateService) GetInstances(ctx context.Context, orgID int64, templateUID string) ([]models.RuleTemplateInstance, error) {
+	if _, err := service.store.GetRuleTemplate(ctx, orgID, templateUID); err != nil {
+		return nil, err
+	}
+	return service.store.ListRuleTemplateInstances(ctx, orgID, templateUID)
+}
+
+// CreateInstance creates an alert rule from the rule template. The UID, folder and group of the rule are taken from
+// the given rule, everything else is rendered from the template with the given values.
+func (service *RuleTemplateService) CreateInstance(ctx context.Context, user identity.Requester, templateUID string, target models.AlertRule, values map[string]string, provenance models.Provenance) (models.AlertRule, error) {
+	t, err := service.store.GetRuleTemplate(ctx, target.OrgID, templateUID)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	rule, err := renderTemplateRule(*t, values)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	rule.OrgID = target.OrgID
+	rule.UID = target.UID
+	rule.NamespaceUID = target.NamespaceUID
+	rule.RuleGroup = target.RuleGroup
+
+	var created models.AlertRule
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		created, err = service.rules.CreateAlertRule(ctx, user, rule, provenance)
+		if err != nil {
+			return err
+		}
+		return service.store.UpsertRuleTemplateInstances(ctx, models.RuleTemplateInstance{
+			OrgID:           created.OrgID,
+			TemplateUID:     t.UID,
+			RuleUID:         created.UID,
+			Values:          values,
+			TemplateVersion: t.Version,
+		})
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return created, nil
+}
+
+// UpdateInstances sets the parameter values of alert rules created from the rule template and updates the rules.
+// Either all the rules are updated, or none of them.
+func (service *RuleTemplateService) UpdateInstances(ctx context.Context, user identity.Requester, orgID int64, templateUID string, updates []models.RuleTemplateInstance, provenance models.Provenance) ([]models.AlertRule, error) {
+	t, err := service.store.GetRuleTemplate(ctx, orgID, templateUID)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := service.store.ListRuleTemplateInstances(ctx, orgID, templateUID)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]struct{}, len(instances))
+	for _, instance := range instances {
+		known[instance.RuleUID] = struct{}{}
+	}
+	for _, update := range updates {
+		if _, ok := known[update.RuleUID]; !ok {
+			return nil, models.ErrRuleTemplateInstanceNotFound(update.RuleUID)
+		}
+	}
+
+	result := make([]models.AlertRule, 0, len(updates))
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		for _, update := range updates {
+			update.OrgID = orgID
+			update.TemplateUID = templateUID
+			rule, err := service.applyInstance(ctx, user, *t, update, provenance)
+			if err != nil {
+				return err
+			}
+			result = append(result, rule)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyInstance renders the template with the values of the instance and updates the alert rule of the instance.
+func (service *RuleTemplateService) applyInstance(ctx context.Context, user identity.Requester, t models.RuleTemplate, instance models.RuleTemplateInstance, provenance models.Provenance) (models.AlertRule, error) {
+	existing, _, err := service.rules.GetAlertRule(ctx, user, instance.RuleUID)
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("failed to get alert rule %s: %w", instance.RuleUID, err)
+	}
+	rule, err := renderTemplateRule(t, instance.Values)
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("failed to render alert rule %s: %w", instance.RuleUID, err)
+	}
+	rule.OrgID = existing.OrgID
+	rule.UID = existing.UID
+	rule.NamespaceUID = existing.NamespaceUID
+	rule.RuleGroup = existing.RuleGroup
+
+	updated, err := service.rules.UpdateAlertRule(ctx, user, rule, provenance)
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("failed to update alert rule %s: %w", instance.RuleUID, err)
+	}
+	instance.TemplateVersion = t.Version
+	if err := service.store.UpsertRuleTemplateInstances(ctx, instance); err != nil {
+		return models.AlertRule{}, err
+	}
+	return updated, nil
+}
+
+// renderTemplateRule renders the rule of the template and converts it from the format of the provisioning API.
+func renderTemplateRule(t models.RuleTemplate, values map[string]string) (models.AlertRule, error) {
+	rendered, err := t.Render(values)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	var provisioned definitions.ProvisionedAlertRule
+	if err := json.Unmarshal(rendered, &provisioned); err != nil {
+		return models.AlertRule{}, models.ErrRuleTemplateInstanceInvalid(fmt.Errorf("rendered alert rule is invalid: %w", err))
+	}
+	return apicompat.AlertRuleFromProvisionedAlertRule(provisioned)
+}
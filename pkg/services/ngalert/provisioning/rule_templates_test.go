@@ -0,0 +1,314 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestRuleTemplateService(t *testing.T) {
+	orgID := int64(1)
+	u := &user.SignedInUser{OrgID: orgID}
+
+	template := func() models.RuleTemplate {
+		return models.RuleTemplate{
+			OrgID: orgID,
+			UID:   "high-cpu",
+			Title: "High CPU",
+			Parameters: []models.RuleTemplateParameter{
+				{Name: "threshold", Type: models.RuleTemplateParameterTypeNumber, Default: util.Pointer("80")},
+				{Name: "team", Type: models.RuleTemplateParameterTypeString},
+			},
+			Rule: json.RawMessage(`{
+				"title": "High CPU ${team}",
+				"condition": "A",
+				"data": [{"refId": "A", "datasourceUid": "__expr__", "model": {"type": "math", "expression": "1 > ${threshold}"}}],
+				"noDataState": "OK",
+				"execErrState": "Error",
+				"for": "5m",
+				"labels": {"team": "${team}"}
+			}`),
+		}
+	}
+
+	t.Run("CreateTemplate", func(t *testing.T) {
+		t.Run("generates the UID", func(t *testing.T) {
+			service, _, _ := createRuleTemplateService()
+			tmpl := template()
+			tmpl.UID = ""
+
+			created, err := service.CreateTemplate(context.Background(), tmpl)
+
+			require.NoError(t, err)
+			require.NotEmpty(t, created.UID)
+			require.Equal(t, int64(1), created.Version)
+		})
+
+		t.Run("validates the template", func(t *testing.T) {
+			service, _, _ := createRuleTemplateService()
+			tmpl := template()
+			tmpl.Title = ""
+
+			_, err := service.CreateTemplate(context.Background(), tmpl)
+
+			require.ErrorIs(t, err, models.ErrRuleTemplateInvalidBase)
+		})
+	})
+
+	t.Run("CreateInstance", func(t *testing.T) {
+		t.Run("creates a rule rendered from the template", func(t *testing.T) {
+			service, templates, rules := createRuleTemplateService()
+			_, err := service.CreateTemplate(context.Background(), template())
+			require.NoError(t, err)
+
+			created, err := service.CreateInstance(context.Background(), u, "high-cpu", models.AlertRule{
+				OrgID:        orgID,
+				UID:          "team-a-cpu",
+				NamespaceUID: "folder",
+				RuleGroup:    "group",
+			}, map[string]string{"team": "a"}, models.ProvenanceAPI)
+
+			require.NoError(t, err)
+			require.Equal(t, "team-a-cpu", created.UID)
+			require.Equal(t, "High CPU a", created.Title)
+			require.Equal(t, "folder", created.NamespaceUID)
+			require.Equal(t, "group", created.RuleGroup)
+			require.Equal(t, map[string]string{"team": "a"}, created.Labels)
+			require.JSONEq(t, `{"type": "math", "expression": "1 > 80"}`, string(created.Data[0].Model))
+			require.Contains(t, rules.rules, "team-a-cpu")
+
+			instances, err := templates.ListRuleTemplateInstances(context.Background(), orgID, "high-cpu")
+			require.NoError(t, err)
+			require.Equal(t, []models.RuleTemplateInstance{
+				{OrgID: orgID, TemplateUID: "high-cpu", RuleUID: "team-a-cpu", Values: map[string]string{"team": "a"}, TemplateVersion: 1},
+			}, instances)
+		})
+
+		t.Run("fails if values are invalid", func(t *testing.T) {
+			service, _, rules := createRuleTemplateService()
+			_, err := service.CreateTemplate(context.Background(), template())
+			require.NoError(t, err)
+
+			_, err = service.CreateInstance(context.Background(), u, "high-cpu", models.AlertRule{OrgID: orgID, NamespaceUID: "folder", RuleGroup: "group"}, map[string]string{}, models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, models.ErrRuleTemplateInstanceInvalidBase)
+			require.Empty(t, rules.rules)
+		})
+
+		t.Run("fails if the template does not exist", func(t *testing.T) {
+			service, _, _ := createRuleTemplateService()
+
+			_, err := service.CreateInstance(context.Background(), u, "high-cpu", models.AlertRule{OrgID: orgID}, nil, models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, models.ErrRuleTemplateNotFound)
+		})
+	})
+
+	t.Run("UpdateTemplate propagates the changes to the instances", func(t *testing.T) {
+		service, templates, rules := createRuleTemplateService()
+		_, err := service.CreateTemplate(context.Background(), template())
+		require.NoError(t, err)
+		for _, team := range []string{"a", "b"} {
+			_, err = service.CreateInstance(context.Background(), u, "high-cpu", models.AlertRule{
+				OrgID: orgID, UID: "team-" + team, NamespaceUID: "folder-" + team, RuleGroup: "group",
+			}, map[string]string{"team": team}, models.ProvenanceAPI)
+			require.NoError(t, err)
+		}
+
+		update := template()
+		update.Parameters[0].Default = util.Pointer("90")
+		update.Version = 1
+		updated, err := service.UpdateTemplate(context.Background(), u, update, models.ProvenanceAPI)
+
+		require.NoError(t, err)
+		require.Equal(t, int64(2), updated.Version)
+		for _, team := range []string{"a", "b"} {
+			rule := rules.rules["team-"+team]
+			require.JSONEq(t, `{"type": "math", "expression": "1 > 90"}`, string(rule.Data[0].Model))
+			require.Equal(t, "folder-"+team, rule.NamespaceUID)
+		}
+		instances, err := templates.ListRuleTemplateInstances(context.Background(), orgID, "high-cpu")
+		require.NoError(t, err)
+		for _, instance := range instances {
+			require.Equal(t, int64(2), instance.TemplateVersion)
+		}
+
+		t.Run("fails on version conflict", func(t *testing.T) {
+			_, err := service.UpdateTemplate(context.Background(), u, update, models.ProvenanceAPI)
+			require.ErrorIs(t, err, store.ErrOptimisticLock)
+		})
+
+		t.Run("fails if an instance cannot be rendered", func(t *testing.T) {
+			update := template()
+			update.Parameters = append(update.Parameters, models.RuleTemplateParameter{Name: "env", Type: models.RuleTemplateParameterTypeString})
+
+			_, err := service.UpdateTemplate(context.Background(), u, update, models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, models.ErrRuleTemplateInstanceInvalidBase)
+		})
+	})
+
+	t.Run("UpdateInstances updates the values of the instances", func(t *testing.T) {
+		service, templates, rules := createRuleTemplateService()
+		_, err := service.CreateTemplate(context.Background(), template())
+		require.NoError(t, err)
+		_, err = service.CreateInstance(context.Background(), u, "high-cpu", models.AlertRule{
+			OrgID: orgID, UID: "team-a", NamespaceUID: "folder", RuleGroup: "group",
+		}, map[string]string{"team": "a"}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		updated, err := service.UpdateInstances(context.Background(), u, orgID, "high-cpu", []models.RuleTemplateInstance{
+			{RuleUID: "team-a", Values: map[string]string{"team": "a", "threshold": "50"}},
+		}, models.ProvenanceAPI)
+
+		require.NoError(t, err)
+		require.Len(t, updated, 1)
+		require.JSONEq(t, `{"type": "math", "expression": "1 > 50"}`, string(rules.rules["team-a"].Data[0].Model))
+		instances, err := templates.ListRuleTemplateInstances(context.Background(), orgID, "high-cpu")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"team": "a", "threshold": "50"}, instances[0].Values)
+
+		t.Run("fails if the rule is not an instance of the template", func(t *testing.T) {
+			_, err := service.UpdateInstances(context.Background(), u, orgID, "high-cpu", []models.RuleTemplateInstance{
+				{RuleUID: "team-b", Values: map[string]string{"team": "b"}},
+			}, models.ProvenanceAPI)
+
+			require.ErrorIs(t, err, models.ErrRuleTemplateInstanceNotFoundBase)
+		})
+	})
+
+	t.Run("DeleteTemplate", func(t *testing.T) {
+		service, _, _ := createRuleTemplateService()
+		_, err := service.CreateTemplate(context.Background(), template())
+		require.NoError(t, err)
+		_, err = service.CreateInstance(context.Background(), u, "high-cpu", models.AlertRule{
+			OrgID: orgID, UID: "team-a", NamespaceUID: "folder", RuleGroup: "group",
+		}, map[string]string{"team": "a"}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		t.Run("fails if the template has instances", func(t *testing.T) {
+			err := service.DeleteTemplate(context.Background(), orgID, "high-cpu")
+			require.ErrorIs(t, err, models.ErrRuleTemplateInUseBase)
+		})
+
+		t.Run("fails if the template does not exist", func(t *testing.T) {
+			err := service.DeleteTemplate(context.Background(), orgID, "low-cpu")
+			require.ErrorIs(t, err, models.ErrRuleTemplateNotFound)
+		})
+	})
+}
+
+func createRuleTemplateService() (*RuleTemplateService, *fakeRuleTemplateStore, *fakeTemplateRuleService) {
+	templates := &fakeRuleTemplateStore{templates: map[string]*models.RuleTemplate{}}
+	rules := &fakeTemplateRuleService{rules: map[string]models.AlertRule{}}
+	return NewRuleTemplateService(templates, rules, newNopTransactionManager(), log.NewNopLogger()), templates, rules
+}
+
+type fakeRuleTemplateStore struct {
+	templates map[string]*models.RuleTemplate
+	instances []models.RuleTemplateInstance
+}
+
+func (f *fakeRuleTemplateStore) ListRuleTemplates(_ context.Context, orgID int64) ([]*models.RuleTemplate, error) {
+	var result []*models.RuleTemplate
+	for _, t := range f.templates {
+		if t.OrgID == orgID {
+			result = append(result, t.Copy())
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeRuleTemplateStore) GetRuleTemplate(_ context.Context, orgID int64, uid string) (*models.RuleTemplate, error) {
+	t, ok := f.templates[uid]
+	if !ok || t.OrgID != orgID {
+		return nil, models.ErrRuleTemplateNotFound.Errorf("not found")
+	}
+	return t.Copy(), nil
+}
+
+func (f *fakeRuleTemplateStore) InsertRuleTemplate(_ context.Context, t models.RuleTemplate) (*models.RuleTemplate, error) {
+	t.Version = 1
+	f.templates[t.UID] = t.Copy()
+	return &t, nil
+}
+
+func (f *fakeRuleTemplateStore) UpdateRuleTemplate(_ context.Context, t models.RuleTemplate) (*models.RuleTemplate, error) {
+	existing, ok := f.templates[t.UID]
+	if !ok || existing.Version != t.Version {
+		return nil, store.ErrOptimisticLock
+	}
+	t.Version++
+	f.templates[t.UID] = t.Copy()
+	return &t, nil
+}
+
+func (f *fakeRuleTemplateStore) DeleteRuleTemplate(_ context.Context, _ int64, uid string) error {
+	delete(f.templates, uid)
+	return nil
+}
+
+func (f *fakeRuleTemplateStore) ListRuleTemplateInstances(_ context.Context, orgID int64, templateUID string) ([]models.RuleTemplateInstance, error) {
+	var result []models.RuleTemplateInstance
+	for _, instance := range f.instances {
+		if instance.OrgID == orgID && instance.TemplateUID == templateUID {
+			result = append(result, instance)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeRuleTemplateStore) UpsertRuleTemplateInstances(_ context.Context, instances ...models.RuleTemplateInstance) error {
+outer:
+	for _, instance := range instances {
+		for i, existing := range f.instances {
+			if existing.OrgID == instance.OrgID && existing.RuleUID == instance.RuleUID {
+				f.instances[i] = instance
+				continue outer
+			}
+		}
+		f.instances = append(f.instances, instance)
+	}
+	return nil
+}
+
+type fakeTemplateRuleService struct {
+	rules map[string]models.AlertRule
+}
+
+func (f *fakeTemplateRuleService) GetAlertRule(_ context.Context, _ identity.Requester, ruleUID string) (models.AlertRule, models.Provenance, error) {
+	rule, ok := f.rules[ruleUID]
+	if !ok {
+		return models.AlertRule{}, models.ProvenanceNone, models.ErrAlertRuleNotFound
+	}
+	return rule, models.ProvenanceAPI, nil
+}
+
+func (f *fakeTemplateRuleService) CreateAlertRule(_ context.Context, _ identity.Requester, rule models.AlertRule, _ models.Provenance) (models.AlertRule, error) {
+	if rule.UID == "" {
+		rule.UID = util.GenerateShortUID()
+	}
+	if _, ok := f.rules[rule.UID]; ok {
+		return models.AlertRule{}, errors.New("rule exists")
+	}
+	f.rules[rule.UID] = rule
+	return rule, nil
+}
+
+func (f *fakeTemplateRuleService) UpdateAlertRule(_ context.Context, _ identity.Requester, rule models.AlertRule, _ models.Provenance) (models.AlertRule, error) {
+	if _, ok := f.rules[rule.UID]; !ok {
+		return models.AlertRule{}, models.ErrAlertRuleNotFound
+	}
+	f.rules[rule.UID] = rule
+	return rule, nil
+}
@@ -0,0 +1,24 @@
+package schedule
+
+import (
+	"time"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// isReadyToRunOnSchedule returns whether a rule with an evaluation schedule should be evaluated on the tick.
+// A rule with a cron expression is evaluated on the first tick at or after each time that matches the expression,
+// instead of every interval. Otherwise, it is evaluated every interval. In both cases, evaluations outside the
+// active time intervals of the rule are skipped.
+func isReadyToRunOnSchedule(schedule *ngmodels.EvaluationSchedule, tick time.Time, baseInterval time.Duration, onInterval bool) (bool, error) {
+	ready := onInterval
+	if schedule.Cron != "" {
+		cron, err := schedule.CronSchedule()
+		if err != nil {
+			return false, err
+		}
+		// the previous tick has already covered all times up to and including it
+		ready = !cron.Next(tick.Add(-baseInterval)).After(tick)
+	}
+	return ready && schedule.IsActiveAt(tick), nil
+}
@@ -0,0 +1,96 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestIsReadyToRunOnSchedule(t *testing.T) {
+	baseInterval := 10 * time.Second
+	// Monday
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("cron expression", func(t *testing.T) {
+		s := &ngmodels.EvaluationSchedule{Cron: "0 8 * * 1-5", Timezone: "Europe/Berlin"}
+		// 08:00 in Berlin is 07:00 UTC
+		at := monday.Add(7 * time.Hour)
+
+		for _, tc := range []struct {
+			tick       time.Time
+			onInterval bool
+			expected   bool
+		}{
+			{tick: at, onInterval: false, expected: true},
+			{tick: at.Add(-baseInterval), onInterval: true, expected: false},
+			{tick: at.Add(baseInterval), onInterval: true, expected: false},
+			{tick: at.Add(24 * time.Hour), expected: true},
+			// Saturday
+			{tick: at.Add(5 * 24 * time.Hour), expected: false},
+		} {
+			ready, err := isReadyToRunOnSchedule(s, tc.tick, baseInterval, tc.onInterval)
+			require.NoError(t, err)
+			require.Equalf(t, tc.expected, ready, "tick %s", tc.tick)
+		}
+	})
+
+	t.Run("ticks that are not aligned to the cron expression", func(t *testing.T) {
+		s := &ngmodels.EvaluationSchedule{Cron: "*/5 * * * *"}
+		ready := 0
+		for tick := monday.Add(3 * time.Second); tick.Before(monday.Add(time.Hour)); tick = tick.Add(baseInterval) {
+			r, err := isReadyToRunOnSchedule(s, tick, baseInterval, false)
+			require.NoError(t, err)
+			if r {
+				ready++
+			}
+		}
+		require.Equal(t, 12, ready)
+	})
+
+	t.Run("active time intervals", func(t *testing.T) {
+		s := &ngmodels.EvaluationSchedule{
+			Timezone: "Europe/Berlin",
+			ActiveTimeIntervals: []timeinterval.TimeInterval{{
+				Times: []timeinterval.TimeRange{{StartMinute: 9 * 60, EndMinute: 17 * 60}},
+			}},
+		}
+
+		ready, err := isReadyToRunOnSchedule(s, monday.Add(10*time.Hour), baseInterval, true)
+		require.NoError(t, err)
+		require.True(t, ready)
+
+		ready, err = isReadyToRunOnSchedule(s, monday.Add(10*time.Hour), baseInterval, false)
+		require.NoError(t, err)
+		require.False(t, ready)
+
+		ready, err = isReadyToRunOnSchedule(s, monday.Add(16*time.Hour), baseInterval, true)
+		require.NoError(t, err)
+		require.False(t, ready)
+	})
+
+	t.Run("cron expression outside the active time intervals", func(t *testing.T) {
+		s := &ngmodels.EvaluationSchedule{
+			Cron: "0 * * * *",
+			ActiveTimeIntervals: []timeinterval.TimeInterval{{
+				Times: []timeinterval.TimeRange{{StartMinute: 9 * 60, EndMinute: 17 * 60}},
+			}},
+		}
+
+		ready, err := isReadyToRunOnSchedule(s, monday.Add(9*time.Hour), baseInterval, false)
+		require.NoError(t, err)
+		require.True(t, ready)
+
+		ready, err = isReadyToRunOnSchedule(s, monday.Add(8*time.Hour), baseInterval, false)
+		require.NoError(t, err)
+		require.False(t, ready)
+	})
+
+	t.Run("invalid cron expression", func(t *testing.T) {
+		_, err := isReadyToRunOnSchedule(&ngmodels.EvaluationSchedule{Cron: "invalid"}, monday, baseInterval, true)
+		require.Error(t, err)
+	})
+}
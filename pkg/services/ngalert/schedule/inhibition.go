@@ -0,0 +1,163 @@
+package schedule
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	models "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// orderByInhibitions wraps runJobFn so that a rule that is inhibited by other rules evaluated on the same tick
+// is evaluated only after those rules. Otherwise, when both rules start firing on the same tick, the alerts of
+// the inhibited rule could be sent before the state manager knows that the inhibiting rule is firing.
+//
+// Rules in the same sequentially evaluated group are ordered by the sequence of the group instead.
+// An inhibited rule waits at most one base interval for the rules that inhibit it, in case their evaluation
+// is dropped or takes too long. Inhibitions that would form a cycle are not used for ordering.
+func (sch *schedule) orderByInhibitions(items []readyToRunItem, runJobFn func(next readyToRunItem, prev ...readyToRunItem) func()) func(next readyToRunItem, prev ...readyToRunItem) func() {
+	// the group of the rules that are chained in a sequence
+	chained := map[models.AlertRuleKey]groupKey{}
+	ready := make(map[models.AlertRuleKey]struct{}, len(items))
+	groups, keys := groupItems(items)
+	for _, key := range keys {
+		sequential := sch.shouldEvaluateSequentially(groups[key])
+		for _, item := range groups[key] {
+			ready[item.rule.GetKey()] = struct{}{}
+			if sequential {
+				chained[item.rule.GetKey()] = key
+			}
+		}
+	}
+
+	// inhibitors[k] contains the rules that inhibit the rule k and must be evaluated before it
+	inhibitors := map[models.AlertRuleKey][]models.AlertRuleKey{}
+	for _, item := range items {
+		key := item.rule.GetKey()
+		for _, inhibition := range item.rule.InhibitedBy {
+			source := models.AlertRuleKey{OrgID: key.OrgID, UID: inhibition.RuleUID}
+			if _, ok := ready[source]; !ok || source == key || slices.Contains(inhibitors[key], source) {
+				continue
+			}
+			if g, ok := chained[key]; ok && chained[source] == g {
+				continue
+			}
+			if evaluatedAfter(inhibitors, source, key) {
+				sch.log.Warn("Inhibitions of the rules form a cycle, the rule is evaluated without waiting for the rule that inhibits it", "org_id", key.OrgID, "rule_uid", key.UID, "inhibited_by", source.UID)
+				continue
+			}
+			inhibitors[key] = append(inhibitors[key], source)
+		}
+	}
+	if len(inhibitors) == 0 {
+		return runJobFn
+	}
+
+	barriers := make(map[models.AlertRuleKey]*inhibitionBarrier, len(inhibitors))
+	// dependents[k] contains the barriers of the rules inhibited by the rule k
+	dependents := map[models.AlertRuleKey][]*inhibitionBarrier{}
+	for key, sources := range inhibitors {
+		b := &inhibitionBarrier{pending: len(sources)}
+		barriers[key] = b
+		for _, source := range sources {
+			dependents[source] = append(dependents[source], b)
+		}
+	}
+
+	return func(next readyToRunItem, prev ...readyToRunItem) func() {
+		key := next.rule.GetKey()
+		if notify := dependents[key]; len(notify) > 0 {
+			afterEval := next.afterEval
+			next.afterEval = func() {
+				for _, b := range notify {
+					b.inhibitorEvaluated()
+				}
+				if afterEval != nil {
+					afterEval()
+				}
+			}
+		}
+		job := runJobFn(next, prev...)
+		b, ok := barriers[key]
+		if !ok {
+			return job
+		}
+		return func() {
+			if !b.due(job) {
+				sch.log.Debug("Rule waits for the rules that inhibit it to be evaluated", "org_id", key.OrgID, "rule_uid", key.UID)
+				time.AfterFunc(sch.baseInterval, b.expire)
+			}
+		}
+	}
+}
+
+// evaluatedAfter reports whether the rule a already waits, directly or indirectly, for the rule b.
+func evaluatedAfter(inhibitors map[models.AlertRuleKey][]models.AlertRuleKey, a, b models.AlertRuleKey) bool {
+	visited := map[models.AlertRuleKey]struct{}{}
+	stack := []models.AlertRuleKey{a}
+	for len(stack) > 0 {
+		k := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if k == b {
+			return true
+		}
+		if _, ok := visited[k]; ok {
+			continue
+		}
+		visited[k] = struct{}{}
+		stack = append(stack, inhibitors[k]...)
+	}
+	return false
+}
+
+// inhibitionBarrier holds the evaluation of a rule until the rules that inhibit it are evaluated.
+type inhibitionBarrier struct {
+	mtx     sync.Mutex
+	pending int
+	job     func()
+	started bool
+}
+
+// due is called when the rule is due to be evaluated. It runs the job if the rules that inhibit the rule
+// are already evaluated, and reports whether it did.
+func (b *inhibitionBarrier) due(job func()) bool {
+	b.mtx.Lock()
+	b.job = job
+	run := b.takeLocked()
+	b.mtx.Unlock()
+	if run == nil {
+		return false
+	}
+	run()
+	return true
+}
+
+// inhibitorEvaluated is called after one of the rules that inhibit the rule is evaluated.
+func (b *inhibitionBarrier) inhibitorEvaluated() {
+	b.mtx.Lock()
+	b.pending--
+	run := b.takeLocked()
+	b.mtx.Unlock()
+	if run != nil {
+		run()
+	}
+}
+
+// expire runs the job without waiting any longer for the rules that inhibit the rule.
+func (b *inhibitionBarrier) expire() {
+	b.mtx.Lock()
+	b.pending = 0
+	run := b.takeLocked()
+	b.mtx.Unlock()
+	if run != nil {
+		run()
+	}
+}
+
+func (b *inhibitionBarrier) takeLocked() func() {
+	if b.started || b.job == nil || b.pending > 0 {
+		return nil
+	}
+	b.started = true
+	return b.job
+}
@@ -2,6 +2,7 @@ package schedule
 
 import (
 	context "context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -287,7 +288,7 @@ func (r *recordingRule) tryEvaluation(ctx context.Context, ev *Evaluation, logge
 		attribute.Int64("results", int64(len(result.Responses))),
 	))
 
-	frames, err := r.frameRef(ev.rule.Record.From, result)
+	frames, err := FrameRef(ev.rule.Record.From, result)
 	if err != nil {
 		span.AddEvent("query returned no data, nothing to write", trace.WithAttributes(
 			attribute.String("reason", err.Error()),
@@ -299,7 +300,15 @@ func (r *recordingRule) tryEvaluation(ctx context.Context, ev *Evaluation, logge
 
 	filteredLabels := ngmodels.WithoutPrivateLabels(ev.rule.Labels)
 	writeStart := r.clock.Now()
-	err = r.writer.WriteDatasource(ctx, ev.rule.Record.TargetDatasourceUID, ev.rule.Record.Metric, ev.scheduledAt, frames, ev.rule.OrgID, filteredLabels)
+	// Write to every target, so that a target that cannot be reached does not hold back the others.
+	var writeErrs []error
+	for _, dsUID := range ev.rule.Record.TargetDatasourceUIDs() {
+		if err := r.writer.WriteDatasource(ctx, dsUID, ev.rule.Record.Metric, ev.scheduledAt, frames, ev.rule.OrgID, filteredLabels); err != nil {
+			logger.Debug("Failed to write metrics to target data source", "target_datasource_uid", dsUID, "error", err)
+			writeErrs = append(writeErrs, err)
+		}
+	}
+	err = errors.Join(writeErrs...)
 	writeDur := r.clock.Now().Sub(writeStart)
 
 	if err != nil {
@@ -338,8 +347,8 @@ func (r *recordingRule) evaluationDoneTestHook(ev *Evaluation) {
 	r.evalAppliedHook(r.key.AlertRuleKey, ev.scheduledAt)
 }
 
-// frameRef gets frames from a QueryDataResponse for a particular refID. It returns an error if the frames do not exist or have no data.
-func (r *recordingRule) frameRef(refID string, resp *backend.QueryDataResponse) (data.Frames, error) {
+// FrameRef gets frames from a QueryDataResponse for a particular refID. It returns an error if the frames do not exist or have no data.
+func FrameRef(refID string, resp *backend.QueryDataResponse) (data.Frames, error) {
 	if len(resp.Responses) == 0 {
 		return nil, fmt.Errorf("no responses returned from rule evaluation")
 	}
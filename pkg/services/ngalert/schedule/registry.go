@@ -326,6 +326,18 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 		binary.LittleEndian.PutUint64(tmp, uint64(rule.Record.Fingerprint()))
 		writeBytes(tmp)
 	}
+	for _, inhibition := range rule.InhibitedBy {
+		binary.LittleEndian.PutUint64(tmp, uint64(inhibition.Fingerprint()))
+		writeBytes(tmp)
+	}
+	if rule.EvaluationSchedule != nil {
+		binary.LittleEndian.PutUint64(tmp, uint64(rule.EvaluationSchedule.Fingerprint()))
+		writeBytes(tmp)
+	}
+	if rule.FlapDetection != nil {
+		binary.LittleEndian.PutUint64(tmp, uint64(rule.FlapDetection.Fingerprint()))
+		writeBytes(tmp)
+	}
 
 	return fingerprint(sum.Sum64())
 }
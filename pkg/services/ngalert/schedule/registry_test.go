@@ -215,6 +215,9 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				},
 			},
 			MissingSeriesEvalsToResolve: util.Pointer[int64](2),
+			InhibitedBy:                 []models.RuleInhibition{{RuleUID: "inhibiting-uid", Equal: []string{"key-label"}}},
+			EvaluationSchedule:          &models.EvaluationSchedule{Cron: "0 8 * * *"},
+			FlapDetection:               &models.FlapDetection{Transitions: 4, Window: 30 * time.Minute},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -261,6 +264,9 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				},
 			},
 			MissingSeriesEvalsToResolve: util.Pointer[int64](1),
+			InhibitedBy:                 []models.RuleInhibition{{RuleUID: "inhibiting-uid2"}},
+			EvaluationSchedule:          &models.EvaluationSchedule{Cron: "0 9 * * *", Timezone: "Europe/Berlin"},
+			FlapDetection:               &models.FlapDetection{Transitions: 5, Window: time.Hour},
 		}
 
 		excludedFields := map[string]struct{}{
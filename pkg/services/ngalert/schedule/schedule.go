@@ -368,6 +368,15 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
 		offset := jitterOffsetInTicks(item, sch.baseInterval, sch.jitterEvaluations)
 		isReadyToRun := item.IntervalSeconds != 0 && (tickNum%itemFrequency)-offset == 0
+		if item.EvaluationSchedule != nil {
+			ready, err := isReadyToRunOnSchedule(item.EvaluationSchedule, tick, sch.baseInterval, isReadyToRun)
+			if _, isUpdated := updated[key]; err != nil && (newRoutine || isUpdated) {
+				// this is expected to be always false given that we validate the schedule during alert rule updates,
+				// so it is only logged when the rule is scheduled or updated, and not on every tick
+				logger.Warn("Rule has an invalid evaluation schedule and will be ignored", "error", err)
+			}
+			isReadyToRun = ready
+		}
 
 		if isReadyToRun {
 			logger.Debug("Rule is ready to run on the current tick", "tick", tick, "frequency", itemFrequency, "offset", offset)
@@ -406,8 +415,9 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		step = sch.baseInterval.Nanoseconds() / int64(len(readyToRun))
 	}
 
-	sequences := sch.buildSequences(readyToRun, sch.runJobFn)
-	sch.runSequences(sequences, step)
+	runJobFn := sch.orderByInhibitions(readyToRun, sch.runJobFn)
+	sequences := sch.buildSequences(readyToRun, runJobFn)
+	sch.runSequences(sequences, step, runJobFn)
 
 	// Stop old routines for rules that got restarted.
 	for _, oldRoutine := range restartedRules {
@@ -444,8 +454,8 @@ func (sch *schedule) runJobFn(next readyToRunItem, prev ...readyToRunItem) func(
 	}
 }
 
-func (sch *schedule) runSequences(sequences []sequence, step int64) {
+func (sch *schedule) runSequences(sequences []sequence, step int64, runJobFn func(next readyToRunItem, prev ...readyToRunItem) func()) {
 	for i := range sequences {
-		time.AfterFunc(time.Duration(int64(i)*step), sch.runJobFn(readyToRunItem(sequences[i])))
+		time.AfterFunc(time.Duration(int64(i)*step), runJobFn(readyToRunItem(sequences[i])))
 	}
 }
@@ -2,9 +2,13 @@ package schedule
 
 import (
 	"cmp"
+	"encoding/json"
 	"slices"
 	"strings"
 
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
 	models "github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -31,32 +35,13 @@ type groupKey struct {
 // The function returns a slice of sequences, where each sequence represents a chain of rules
 // that should be evaluated in order.
 //
-// NOTE: This currently only chains rules in imported groups.
+// Rules in imported groups are always chained, in the order of the group. Rules in other groups are chained
+// only if sequential evaluation is enabled for the group, in the order of the group, except that rules that
+// read the output metric of a recording rule in the same group, or that are inhibited by another rule of the group,
+// are moved after that rule.
 func (sch *schedule) buildSequences(items []readyToRunItem, runJobFn func(next readyToRunItem, prev ...readyToRunItem) func()) []sequence {
-	// Step 1: Group rules by their folder and group name
-	groups := map[groupKey][]readyToRunItem{}
-	var keys []groupKey
-	for _, item := range items {
-		g := groupKey{
-			folderTitle: item.folderTitle,
-			folderUID:   item.rule.NamespaceUID,
-			groupName:   item.rule.RuleGroup,
-		}
-		i, ok := groups[g]
-		if !ok {
-			keys = append(keys, g)
-		}
-		groups[g] = append(i, item)
-	}
-
-	// Step 2: Sort group keys to ensure consistent ordering
-	slices.SortFunc(keys, func(a, b groupKey) int {
-		return cmp.Or(
-			cmp.Compare(a.folderTitle, b.folderTitle),
-			cmp.Compare(a.folderUID, b.folderUID),
-			cmp.Compare(a.groupName, b.groupName),
-		)
-	})
+	// Step 1 and 2: Group rules by their folder and group name, and sort the groups
+	groups, keys := groupItems(items)
 
 	// Step 3: Build evaluation sequences for each group
 	result := make([]sequence, 0, len(items))
@@ -81,6 +66,33 @@ func (sch *schedule) buildSequences(items []readyToRunItem, runJobFn func(next r
 	return result
 }
 
+// groupItems groups the rules by their folder and group name, and returns the groups in a consistent order.
+func groupItems(items []readyToRunItem) (map[groupKey][]readyToRunItem, []groupKey) {
+	groups := map[groupKey][]readyToRunItem{}
+	var keys []groupKey
+	for _, item := range items {
+		g := groupKey{
+			folderTitle: item.folderTitle,
+			folderUID:   item.rule.NamespaceUID,
+			groupName:   item.rule.RuleGroup,
+		}
+		i, ok := groups[g]
+		if !ok {
+			keys = append(keys, g)
+		}
+		groups[g] = append(i, item)
+	}
+
+	slices.SortFunc(keys, func(a, b groupKey) int {
+		return cmp.Or(
+			cmp.Compare(a.folderTitle, b.folderTitle),
+			cmp.Compare(a.folderUID, b.folderUID),
+			cmp.Compare(a.groupName, b.groupName),
+		)
+	})
+	return groups, keys
+}
+
 func (sch *schedule) buildSequence(groupKey groupKey, groupItems []readyToRunItem, runJobFn func(next readyToRunItem, prev ...readyToRunItem) func()) sequence {
 	if len(groupItems) < 2 {
 		return sequence(groupItems[0])
@@ -89,6 +101,10 @@ func (sch *schedule) buildSequence(groupKey groupKey, groupItems []readyToRunIte
 	slices.SortFunc(groupItems, func(a, b readyToRunItem) int {
 		return models.RulesGroupComparer(a.rule, b.rule)
 	})
+	// imported groups are evaluated in the order of the group, as in Prometheus
+	if !slices.ContainsFunc(groupItems, func(item readyToRunItem) bool { return item.rule.ImportedPrometheusRule() }) {
+		groupItems = sch.sortByDependencies(groupKey, groupItems)
+	}
 
 	// iterate over the group items backwards to set the afterEval callback
 	for i := len(groupItems) - 2; i >= 0; i-- {
@@ -120,13 +136,125 @@ func (sch *schedule) shouldEvaluateSequentially(groupItems []readyToRunItem) boo
 		return false
 	}
 
-	// only evaluate rules in imported groups sequentially
 	for _, item := range groupItems {
+		// rules in imported groups are always evaluated sequentially
 		if item.rule.ImportedPrometheusRule() {
 			return true
 		}
+		if item.rule.Metadata.SequentialEvaluation {
+			return true
+		}
 	}
 
 	// default to false
 	return false
 }
+
+// sortByDependencies reorders rules that are sorted by their group index so that every rule that reads
+// the output metric of a recording rule in the same group comes after that recording rule, and every rule
+// that is inhibited by another rule in the same group comes after that rule. Otherwise, the order of the
+// rules is preserved. If the dependencies form a cycle, the rules of the cycle keep their order.
+func (sch *schedule) sortByDependencies(groupKey groupKey, groupItems []readyToRunItem) []readyToRunItem {
+	// the metrics written by the recording rules of the group
+	written := map[string][]int{}
+	byUID := make(map[string]int, len(groupItems))
+	for i, item := range groupItems {
+		byUID[item.rule.UID] = i
+		if item.rule.Type() == models.RuleTypeRecording {
+			written[item.rule.Record.Metric] = append(written[item.rule.Record.Metric], i)
+		}
+	}
+
+	// dependsOn[i] contains the indices of the recording rules that rule i reads from, and of the rules that inhibit it
+	dependsOn := make([][]int, len(groupItems))
+	hasDependencies := false
+	for i, item := range groupItems {
+		for _, inhibition := range item.rule.InhibitedBy {
+			if j, ok := byUID[inhibition.RuleUID]; ok && j != i {
+				dependsOn[i] = append(dependsOn[i], j)
+				hasDependencies = true
+			}
+		}
+		if len(written) == 0 {
+			continue
+		}
+		for _, q := range item.rule.Data {
+			for _, metric := range queriedMetrics(q) {
+				for _, j := range written[metric] {
+					rec := groupItems[j].rule.Record
+					if j == i || (rec.TargetDatasourceUID != "" && !slices.Contains(rec.TargetDatasourceUIDs(), q.DatasourceUID)) {
+						continue
+					}
+					dependsOn[i] = append(dependsOn[i], j)
+					hasDependencies = true
+				}
+			}
+		}
+	}
+	if !hasDependencies {
+		return groupItems
+	}
+
+	// Pick the first rule in group order whose dependencies are all placed.
+	// This is a topological sort that keeps the order of the group wherever possible.
+	result := make([]readyToRunItem, 0, len(groupItems))
+	placed := make([]bool, len(groupItems))
+	for len(result) < len(groupItems) {
+		next := -1
+		for i := range groupItems {
+			if placed[i] {
+				continue
+			}
+			if !slices.ContainsFunc(dependsOn[i], func(j int) bool { return !placed[j] }) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			// there is a cycle, place the remaining rules in group order
+			sch.log.Warn("Rules in the group depend on each other in a cycle, evaluating them in the order of the group", "folder", groupKey.folderTitle, "group", groupKey.groupName)
+			for i := range groupItems {
+				if !placed[i] {
+					result = append(result, groupItems[i])
+				}
+			}
+			break
+		}
+		placed[next] = true
+		result = append(result, groupItems[next])
+	}
+	return result
+}
+
+// queriedMetrics returns the names of the metrics selected by the PromQL expression of the query.
+// It returns nil if the query does not have a PromQL expression, or the expression cannot be parsed.
+func queriedMetrics(q models.AlertQuery) []string {
+	var model struct {
+		Expr string `json:"expr"`
+	}
+	if err := json.Unmarshal(q.Model, &model); err != nil || model.Expr == "" {
+		return nil
+	}
+	expr, err := parser.ParseExpr(model.Expr)
+	if err != nil {
+		return nil
+	}
+	var metrics []string
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		vs, ok := node.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+		if vs.Name != "" {
+			metrics = append(metrics, vs.Name)
+			return nil
+		}
+		for _, m := range vs.LabelMatchers {
+			if m.Name == labels.MetricName && m.Type == labels.MatchEqual {
+				metrics = append(metrics, m.Value)
+			}
+		}
+		return nil
+	})
+	return metrics
+}
@@ -1,7 +1,9 @@
 package schedule
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/prometheus/client_golang/prometheus"
@@ -154,4 +156,255 @@ func TestSequence(t *testing.T) {
 		require.Equal(t, []string{"4", "5"}, nextByGroup["rg2"])
 		require.Equal(t, []string{"3", "4"}, prevByGroup["rg2"])
 	})
+	t.Run("should chain rules in groups with sequential evaluation", func(t *testing.T) {
+		sch := setupScheduler(t, ruleStore, nil, prometheus.NewPedanticRegistry(), nil, nil, nil)
+
+		var order []string
+		items := []readyToRunItem{
+			sequenceItem(gen.With(models.RuleGen.WithUID("1"), models.RuleGen.WithGroupIndex(1), models.RuleGen.WithGroupName("rg1"), models.RuleGen.WithSequentialEvaluation(true)).GenerateRef()),
+			sequenceItem(gen.With(models.RuleGen.WithUID("2"), models.RuleGen.WithGroupIndex(2), models.RuleGen.WithGroupName("rg1"), models.RuleGen.WithSequentialEvaluation(true)).GenerateRef()),
+			sequenceItem(gen.With(models.RuleGen.WithUID("3"), models.RuleGen.WithGroupIndex(1), models.RuleGen.WithGroupName("rg2")).GenerateRef()),
+			sequenceItem(gen.With(models.RuleGen.WithUID("4"), models.RuleGen.WithGroupIndex(2), models.RuleGen.WithGroupName("rg2")).GenerateRef()),
+		}
+		sequences := sch.buildSequences(items, recordOrder(&order))
+		// the rules of rg2 are evaluated independently
+		require.Len(t, sequences, 3)
+
+		runSequence(sequences[0], &order)
+		require.Equal(t, []string{"1", "2"}, order)
+	})
+
+	t.Run("should evaluate recording rules before the rules that read their output", func(t *testing.T) {
+		sch := setupScheduler(t, ruleStore, nil, prometheus.NewPedanticRegistry(), nil, nil, nil)
+
+		promQuery := func(dsUID, expr string) models.AlertQuery {
+			return models.AlertQuery{RefID: "A", DatasourceUID: dsUID, Model: json.RawMessage(`{"expr":"` + expr + `"}`)}
+		}
+		group := gen.With(models.RuleGen.WithGroupName("rg1"), models.RuleGen.WithSequentialEvaluation(true))
+		items := []readyToRunItem{
+			// reads the output of rule 3
+			sequenceItem(group.With(
+				models.RuleGen.WithUID("1"),
+				models.RuleGen.WithGroupIndex(1),
+				models.RuleGen.WithQuery(promQuery("prom", "sum(job:requests:rate5m) > 10")),
+			).GenerateRef()),
+			// reads a metric with the same name in another data source
+			sequenceItem(group.With(
+				models.RuleGen.WithUID("2"),
+				models.RuleGen.WithGroupIndex(2),
+				models.RuleGen.WithQuery(promQuery("other", `{__name__=\"job:errors:rate5m\"} > 0`)),
+			).GenerateRef()),
+			sequenceItem(group.With(
+				models.RuleGen.WithUID("3"),
+				models.RuleGen.WithGroupIndex(3),
+				models.RuleGen.WithAllRecordingRules(),
+				models.RuleGen.WithMetric("job:requests:rate5m"),
+				models.RuleGen.WithQuery(promQuery("prom", "rate(requests_total[5m])")),
+			).GenerateRef()),
+			sequenceItem(group.With(
+				models.RuleGen.WithUID("4"),
+				models.RuleGen.WithGroupIndex(4),
+				models.RuleGen.WithAllRecordingRules(),
+				models.RuleGen.WithMetric("job:errors:rate5m"),
+				models.RuleGen.WithQuery(promQuery("prom", "rate(errors_total[5m])")),
+			).GenerateRef()),
+		}
+		items[2].rule.Record.TargetDatasourceUID = "prom"
+		items[3].rule.Record.TargetDatasourceUID = "prom"
+
+		var order []string
+		sequences := sch.buildSequences(items, recordOrder(&order))
+		require.Len(t, sequences, 1)
+
+		runSequence(sequences[0], &order)
+		require.Equal(t, []string{"2", "3", "1", "4"}, order)
+	})
+
+	t.Run("should keep the group order of imported groups", func(t *testing.T) {
+		sch := setupScheduler(t, ruleStore, nil, prometheus.NewPedanticRegistry(), nil, nil, nil)
+
+		group := gen.With(models.RuleGen.WithGroupName("rg1"), models.RuleGen.WithPrometheusOriginalRuleDefinition("test"))
+		items := []readyToRunItem{
+			// reads the output of rule 2
+			sequenceItem(group.With(
+				models.RuleGen.WithUID("1"),
+				models.RuleGen.WithGroupIndex(1),
+				models.RuleGen.WithQuery(models.AlertQuery{RefID: "A", DatasourceUID: "prom", Model: json.RawMessage(`{"expr":"job:requests:rate5m > 10"}`)}),
+			).GenerateRef()),
+			sequenceItem(group.With(
+				models.RuleGen.WithUID("2"),
+				models.RuleGen.WithGroupIndex(2),
+				models.RuleGen.WithAllRecordingRules(),
+				models.RuleGen.WithoutTargetDataSource(),
+				models.RuleGen.WithMetric("job:requests:rate5m"),
+				models.RuleGen.WithQuery(models.AlertQuery{RefID: "A", DatasourceUID: "prom", Model: json.RawMessage(`{"expr":"rate(requests_total[5m])"}`)}),
+			).GenerateRef()),
+		}
+
+		var order []string
+		sequences := sch.buildSequences(items, recordOrder(&order))
+		require.Len(t, sequences, 1)
+
+		runSequence(sequences[0], &order)
+		require.Equal(t, []string{"1", "2"}, order)
+	})
+
+	t.Run("should keep the group order if rules depend on each other in a cycle", func(t *testing.T) {
+		sch := setupScheduler(t, ruleStore, nil, prometheus.NewPedanticRegistry(), nil, nil, nil)
+
+		recording := func(uid string, idx int, metric, expr string) *models.AlertRule {
+			return gen.With(
+				models.RuleGen.WithUID(uid),
+				models.RuleGen.WithGroupIndex(idx),
+				models.RuleGen.WithGroupName("rg1"),
+				models.RuleGen.WithSequentialEvaluation(true),
+				models.RuleGen.WithAllRecordingRules(),
+				models.RuleGen.WithoutTargetDataSource(),
+				models.RuleGen.WithMetric(metric),
+				models.RuleGen.WithQuery(models.AlertQuery{RefID: "A", DatasourceUID: "prom", Model: json.RawMessage(`{"expr":"` + expr + `"}`)}),
+			).GenerateRef()
+		}
+		items := []readyToRunItem{
+			sequenceItem(recording("1", 1, "a", "b")),
+			sequenceItem(recording("2", 2, "b", "a")),
+		}
+
+		var order []string
+		sequences := sch.buildSequences(items, recordOrder(&order))
+		require.Len(t, sequences, 1)
+
+		runSequence(sequences[0], &order)
+		require.Equal(t, []string{"1", "2"}, order)
+	})
+}
+
+func TestOrderByInhibitions(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	gen := models.RuleGen.With(models.RuleGen.WithNamespaceUID("ns1"), models.RuleGen.WithOrgID(1))
+
+	t.Run("should move the inhibited rule after the rule that inhibits it in a sequential group", func(t *testing.T) {
+		sch := setupScheduler(t, ruleStore, nil, prometheus.NewPedanticRegistry(), nil, nil, nil)
+
+		group := gen.With(models.RuleGen.WithGroupName("rg1"), models.RuleGen.WithSequentialEvaluation(true))
+		items := []readyToRunItem{
+			sequenceItem(group.With(
+				models.RuleGen.WithUID("1"),
+				models.RuleGen.WithGroupIndex(1),
+				models.RuleGen.WithInhibitedBy(models.RuleInhibition{RuleUID: "2"}),
+			).GenerateRef()),
+			sequenceItem(group.With(models.RuleGen.WithUID("2"), models.RuleGen.WithGroupIndex(2)).GenerateRef()),
+		}
+
+		var order []string
+		runJobFn := sch.orderByInhibitions(items, recordOrder(&order))
+		sequences := sch.buildSequences(items, runJobFn)
+		require.Len(t, sequences, 1)
+
+		runSequence(sequences[0], &order)
+		require.Equal(t, []string{"2", "1"}, order)
+	})
+
+	t.Run("should evaluate the inhibited rule after the rule that inhibits it in another group", func(t *testing.T) {
+		sch := setupScheduler(t, ruleStore, nil, prometheus.NewPedanticRegistry(), nil, nil, nil)
+
+		items := []readyToRunItem{
+			sequenceItem(gen.With(
+				models.RuleGen.WithUID("1"),
+				models.RuleGen.WithGroupName("rg1"),
+				models.RuleGen.WithInhibitedBy(models.RuleInhibition{RuleUID: "2"}),
+			).GenerateRef()),
+			sequenceItem(gen.With(models.RuleGen.WithUID("2"), models.RuleGen.WithGroupName("rg2")).GenerateRef()),
+		}
+
+		var order []string
+		runJobFn := sch.orderByInhibitions(items, recordOrder(&order))
+		sequences := sch.buildSequences(items, runJobFn)
+		require.Len(t, sequences, 2)
+
+		// the inhibited rule is due first, but waits for the rule that inhibits it
+		runJobFn(readyToRunItem(sequences[0]))()
+		require.Empty(t, order)
+		runJobFn(readyToRunItem(sequences[1]))()
+		require.Equal(t, []string{"2", "1"}, order)
+	})
+
+	t.Run("should evaluate the inhibited rule if the rule that inhibits it is not evaluated", func(t *testing.T) {
+		sch := setupScheduler(t, ruleStore, nil, prometheus.NewPedanticRegistry(), nil, nil, nil)
+
+		items := []readyToRunItem{
+			sequenceItem(gen.With(
+				models.RuleGen.WithUID("1"),
+				models.RuleGen.WithGroupName("rg1"),
+				models.RuleGen.WithInhibitedBy(models.RuleInhibition{RuleUID: "2"}),
+			).GenerateRef()),
+			sequenceItem(gen.With(models.RuleGen.WithUID("2"), models.RuleGen.WithGroupName("rg2")).GenerateRef()),
+		}
+
+		evaluated := make(chan string, 1)
+		runJobFn := sch.orderByInhibitions(items, func(next readyToRunItem, prev ...readyToRunItem) func() {
+			return func() { evaluated <- next.rule.UID }
+		})
+		sequences := sch.buildSequences(items, runJobFn)
+		require.Len(t, sequences, 2)
+
+		runJobFn(readyToRunItem(sequences[0]))()
+		select {
+		case uid := <-evaluated:
+			require.Equal(t, "1", uid)
+		case <-time.After(5 * sch.baseInterval):
+			t.Fatal("the inhibited rule was not evaluated")
+		}
+	})
+
+	t.Run("should ignore the inhibition that closes a cycle", func(t *testing.T) {
+		sch := setupScheduler(t, ruleStore, nil, prometheus.NewPedanticRegistry(), nil, nil, nil)
+
+		items := []readyToRunItem{
+			sequenceItem(gen.With(
+				models.RuleGen.WithUID("1"),
+				models.RuleGen.WithGroupName("rg1"),
+				models.RuleGen.WithInhibitedBy(models.RuleInhibition{RuleUID: "2"}),
+			).GenerateRef()),
+			sequenceItem(gen.With(
+				models.RuleGen.WithUID("2"),
+				models.RuleGen.WithGroupName("rg2"),
+				models.RuleGen.WithInhibitedBy(models.RuleInhibition{RuleUID: "1"}),
+			).GenerateRef()),
+		}
+
+		var order []string
+		runJobFn := sch.orderByInhibitions(items, recordOrder(&order))
+		sequences := sch.buildSequences(items, runJobFn)
+		require.Len(t, sequences, 2)
+
+		runJobFn(readyToRunItem(sequences[0]))()
+		runJobFn(readyToRunItem(sequences[1]))()
+		require.Equal(t, []string{"2", "1"}, order)
+	})
+}
+
+func sequenceItem(rule *models.AlertRule) readyToRunItem {
+	return readyToRunItem{
+		ruleRoutine: &fakeSequenceRule{UID: rule.UID, Group: rule.RuleGroup},
+		Evaluation: Evaluation{
+			rule:        rule,
+			folderTitle: "folder1",
+		},
+	}
+}
+
+// recordOrder returns a callback for buildSequences that records the UIDs of the rules in the order they are evaluated.
+func recordOrder(order *[]string) func(next readyToRunItem, prev ...readyToRunItem) func() {
+	return func(next readyToRunItem, prev ...readyToRunItem) func() {
+		return func() {
+			*order = append(*order, next.rule.UID)
+			next.ruleRoutine.Eval(&next.Evaluation)
+		}
+	}
+}
+
+// runSequence evaluates the first rule of the sequence, which triggers the evaluation of the rest.
+func runSequence(s sequence, order *[]string) {
+	*order = append(*order, s.rule.UID)
+	s.ruleRoutine.Eval(&s.Evaluation)
 }
@@ -0,0 +1,122 @@
+package historian
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// getFolderUIDsForFilter returns the UIDs of the folders whose state history the user of the query can read.
+// It returns nil if the history does not need to be filtered by folder.
+func getFolderUIDsForFilter(ctx context.Context, ac AccessControl, ruleStore RuleStore, logger log.Logger, query models.HistoryQuery) ([]string, error) {
+	bypass, err := ac.CanReadAllRules(ctx, query.SignedInUser)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.RuleUID != "" {
+		return getFolderUIDsForRuleFilter(ctx, ac, ruleStore, logger, query, bypass)
+	}
+
+	// If the query has no rule filter, we need to return all folder UIDs the user has access to.
+	// For a user with access to all rules and folders, the full list of folders will likely be too large to be an
+	// effective optimization in Loki, so we skip folderUID filtering entirely in that case.
+	if bypass {
+		return nil, nil
+	}
+
+	// All folders the user has access to.
+	folders, err := ruleStore.GetUserVisibleNamespaces(ctx, query.OrgID, query.SignedInUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch folders that user can access: %w", err)
+	}
+	uids := make([]string, 0, len(folders))
+	// Keep only UIDs of folder in which user can read rules.
+	for _, f := range folders {
+		hasAccess, err := ac.HasAccessInFolder(ctx, query.SignedInUser, models.NewNamespace(f))
+		if err != nil {
+			return nil, err
+		}
+		if !hasAccess {
+			continue
+		}
+		uids = append(uids, f.UID)
+	}
+	if len(uids) == 0 {
+		return nil, accesscontrol.NewAuthorizationErrorGeneric("read rules in any folder")
+	}
+	sort.Strings(uids)
+	return uids, nil
+}
+
+func getFolderUIDsForRuleFilter(ctx context.Context, ac AccessControl, ruleStore RuleStore, logger log.Logger, query models.HistoryQuery, canReadAll bool) ([]string, error) {
+	rule, err := ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{
+		UID:   query.RuleUID,
+		OrgID: query.OrgID,
+	})
+	if err != nil {
+		if canReadAll {
+			// When the user can read all rules, filtering by folder UID is purely an optimization, so we can ignore errors here.
+			logger.FromContext(ctx).Debug("failed to fetch alert rule by UID", "err", err)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch alert rule by UID: %w", err)
+	}
+
+	// First, we check if the user has access to the current version of the rule. If not, we can return early.
+	// Whether we should check historical folders they might still have access to is not 100% clear, but it seems more
+	// intuitive to deny access in this case.
+	if !canReadAll {
+		if err := ac.AuthorizeAccessInFolder(ctx, query.SignedInUser, rule); err != nil {
+			return nil, err
+		}
+	}
+
+	// We want to return folder UIDs when possible, as it's indexed in Loki and will help with query performance.
+	// However, by just returning the current folder UID the user can lose history when a rule is moved between folders.
+	// So, we attempt to get historical folder UIDs from the rule's history.
+	historicalFolders, err := ruleStore.GetAlertRuleVersionFolders(ctx, rule.OrgID, rule.GUID)
+	if err != nil {
+		// Including historical folders is an edge case enhancement, better to just log the error and continue
+		// with the current folder UID.
+		logger.FromContext(ctx).Debug("failed to include historical folder UIDs for rule", "err", err)
+	}
+
+	accessibleFolders := make([]string, 0, len(historicalFolders)+1)
+	dedup := make(map[string]struct{})
+
+	accessibleFolders = append(accessibleFolders, rule.GetNamespaceUID())
+	dedup[rule.GetNamespaceUID()] = struct{}{}
+
+	for _, folderUID := range historicalFolders {
+		if _, exists := dedup[folderUID]; exists {
+			continue
+		}
+
+		if canReadAll {
+			// If the user can read all rules, no need to check access to each folder.
+			accessibleFolders = append(accessibleFolders, folderUID)
+			continue
+		}
+
+		hasAccess, err := ac.HasAccessInFolder(ctx, query.SignedInUser, models.Namespace{
+			UID: folderUID,
+		})
+		if err != nil {
+			// Including historical folders is an edge case enhancement, better to just log the error and continue
+			// with the current folder UID.
+			logger.FromContext(ctx).Debug("failed to check access to folder", "err", err, "folderUID", folderUID)
+			continue
+		}
+		if !hasAccess {
+			continue
+		}
+		accessibleFolders = append(accessibleFolders, folderUID)
+	}
+
+	return accessibleFolders, nil
+}
@@ -0,0 +1,215 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+)
+
+// sqlQueryBatchSize is the number of entries fetched at a time by the queries that filter the entries by
+// instance labels, which are matched after the entries are fetched.
+var sqlQueryBatchSize = 1000
+
+// StateHistoryStore is the database store of the SQL state history backend.
+type StateHistoryStore interface {
+	SaveStateHistory(ctx context.Context, entries []models.StateHistoryEntry) error
+	GetStateHistory(ctx context.Context, query models.StateHistoryEntriesQuery) ([]models.StateHistoryEntry, error)
+}
+
+// SQLBackend is a state.Historian that records state history to the Grafana database.
+// Entries are stored in the same format as the Loki backend, and queries return the same data frame,
+// so the two backends can be used interchangeably.
+type SQLBackend struct {
+	store     StateHistoryStore
+	ruleStore RuleStore
+	ac        AccessControl
+	clock     clock.Clock
+	metrics   *metrics.Historian
+	log       log.Logger
+}
+
+func NewSQLBackend(logger log.Logger, store StateHistoryStore, ruleStore RuleStore, metrics *metrics.Historian, ac AccessControl) *SQLBackend {
+	return &SQLBackend{
+		store:     store,
+		ruleStore: ruleStore,
+		ac:        ac,
+		clock:     clock.New(),
+		metrics:   metrics,
+		log:       logger,
+	}
+}
+
+// Record writes a number of state transitions for a given rule to the database.
+func (h *SQLBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	entries := statesToHistoryEntries(rule, states, logger)
+
+	errCh := make(chan error, 1)
+	if len(entries) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We don't want grafana shutdowns or the evaluation context to interrupt the write.
+	writeCtx, cancel := context.WithTimeout(context.Background(), StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+		logger.Debug("Saving state history batch", "samples", len(entries))
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "sql").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(entries)))
+
+		if err := h.store.SaveStateHistory(ctx, entries); err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "sql").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(entries)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch", "samples", len(entries))
+	}(writeCtx)
+	return errCh
+}
+
+// Query retrieves state history entries from the database and formats the results into a dataframe.
+func (h *SQLBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	uids, err := getFolderUIDsForFilter(ctx, h.ac, h.ruleStore, h.log.FromContext(ctx), query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+
+	q := models.StateHistoryEntriesQuery{
+		OrgID:        query.OrgID,
+		RuleUID:      query.RuleUID,
+		DashboardUID: query.DashboardUID,
+		PanelID:      query.PanelID,
+		FolderUIDs:   uids,
+		Previous:     query.Previous,
+		Current:      query.Current,
+		From:         query.From,
+		To:           query.To,
+	}
+	var rows []models.StateHistoryEntry
+	if len(query.Labels) == 0 {
+		q.Limit = query.Limit
+		rows, err = h.store.GetStateHistory(ctx, q)
+	} else {
+		rows, err = h.queryByLabels(ctx, q, query.Labels, query.Limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := NewQueryResultBuilder(len(rows))
+	for _, e := range rows {
+		lbls, err := json.Marshal(map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(e.OrgID),
+			GroupLabel:           e.RuleGroup,
+			FolderUIDLabel:       e.FolderUID,
+		})
+		if err != nil {
+			h.log.Warn("Failed to serialize stream labels, continuing", "error", err)
+			continue
+		}
+		res.AddRowRaw(e.EvaluatedAt, json.RawMessage(e.Line), lbls)
+	}
+	return res.ToFrame(), nil
+}
+
+// queryByLabels returns the most recent entries of the query whose instance labels match the filter.
+// Instance labels are stored as part of the line, so the entries are fetched in batches, from the most
+// recent one, until the limit is reached.
+func (h *SQLBackend) queryByLabels(ctx context.Context, q models.StateHistoryEntriesQuery, filter map[string]string, limit int) ([]models.StateHistoryEntry, error) {
+	q.Limit = sqlQueryBatchSize
+	var matched []models.StateHistoryEntry
+	for {
+		batch, err := h.store.GetStateHistory(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		// Entries are in ascending order, so the batch is matched from the most recent entry.
+		for i := len(batch) - 1; i >= 0; i-- {
+			var line LokiEntry
+			if err := json.Unmarshal([]byte(batch[i].Line), &line); err != nil {
+				h.log.Warn("Failed to unmarshal entry, continuing", "error", err, "entry", batch[i].Line)
+				continue
+			}
+			if !matchesLabels(line.InstanceLabels, filter) {
+				continue
+			}
+			matched = append(matched, batch[i])
+			if limit > 0 && len(matched) == limit {
+				break
+			}
+		}
+		if len(batch) < sqlQueryBatchSize || (limit > 0 && len(matched) == limit) {
+			break
+		}
+		q.Before = &batch[0]
+	}
+	slices.Reverse(matched)
+	return matched, nil
+}
+
+func statesToHistoryEntries(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) []models.StateHistoryEntry {
+	entries := make([]models.StateHistoryEntry, 0, len(states))
+	for _, s := range states {
+		if !ShouldRecord(s) {
+			continue
+		}
+
+		entry := StateTransitionToLokiEntry(rule, s)
+		jsn, err := json.Marshal(entry)
+		if err != nil {
+			logger.Error("Failed to construct history record for state, skipping", "error", err)
+			continue
+		}
+
+		entries = append(entries, models.StateHistoryEntry{
+			OrgID:        rule.OrgID,
+			RuleUID:      rule.UID,
+			RuleGroup:    rule.Group,
+			FolderUID:    rule.NamespaceUID,
+			DashboardUID: rule.DashboardUID,
+			PanelID:      rule.PanelID,
+			Previous:     entry.Previous,
+			Current:      entry.Current,
+			EvaluatedAt:  s.LastEvaluationTime,
+			Line:         string(jsn),
+		})
+	}
+	return entries
+}
+
+func matchesLabels(instance map[string]string, filter map[string]string) bool {
+	for k, v := range filter {
+		if instance[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,271 @@
+package historian
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+type fakeStateHistoryStore struct {
+	entries []models.StateHistoryEntry
+	queries []models.StateHistoryEntriesQuery
+	err     error
+}
+
+func (f *fakeStateHistoryStore) SaveStateHistory(_ context.Context, entries []models.StateHistoryEntry) error {
+	if f.err != nil {
+		return f.err
+	}
+	for _, e := range entries {
+		e.ID = int64(len(f.entries) + 1)
+		f.entries = append(f.entries, e)
+	}
+	return nil
+}
+
+func (f *fakeStateHistoryStore) GetStateHistory(_ context.Context, query models.StateHistoryEntriesQuery) ([]models.StateHistoryEntry, error) {
+	f.queries = append(f.queries, query)
+	if f.err != nil {
+		return nil, f.err
+	}
+	res := make([]models.StateHistoryEntry, 0, len(f.entries))
+	for _, e := range f.entries {
+		if e.OrgID != query.OrgID || (query.RuleUID != "" && e.RuleUID != query.RuleUID) {
+			continue
+		}
+		if query.Before != nil && (!e.EvaluatedAt.Before(query.Before.EvaluatedAt) && !(e.EvaluatedAt.Equal(query.Before.EvaluatedAt) && e.ID < query.Before.ID)) {
+			continue
+		}
+		res = append(res, e)
+	}
+	if query.Limit > 0 && len(res) > query.Limit {
+		res = res[len(res)-query.Limit:]
+	}
+	return res, nil
+}
+
+func TestSQLBackendRecord(t *testing.T) {
+	t.Run("writes state transitions to the store", func(t *testing.T) {
+		store := &fakeStateHistoryStore{}
+		sql := createTestSQLBackend(t, store, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem))
+		rule := createTestRule()
+		now := time.Unix(1000, 0)
+		states := singleFromNormal(&state.State{
+			State:              eval.Alerting,
+			Labels:             data.Labels{"a": "b"},
+			LastEvaluationTime: now,
+		})
+
+		err := <-sql.Record(context.Background(), rule, states)
+
+		require.NoError(t, err)
+		require.Len(t, store.entries, 1)
+		e := store.entries[0]
+		require.Equal(t, int64(1), e.OrgID)
+		require.Equal(t, rule.UID, e.RuleUID)
+		require.Equal(t, rule.Group, e.RuleGroup)
+		require.Equal(t, rule.NamespaceUID, e.FolderUID)
+		require.Equal(t, rule.DashboardUID, e.DashboardUID)
+		require.Equal(t, rule.PanelID, e.PanelID)
+		require.Equal(t, "Normal", e.Previous)
+		require.Equal(t, "Alerting", e.Current)
+		require.Equal(t, now, e.EvaluatedAt)
+
+		var line LokiEntry
+		require.NoError(t, json.Unmarshal([]byte(e.Line), &line))
+		require.Equal(t, map[string]string{"a": "b"}, line.InstanceLabels)
+		require.Equal(t, rule.Title, line.RuleTitle)
+	})
+
+	t.Run("skips the write if nothing to record", func(t *testing.T) {
+		store := &fakeStateHistoryStore{err: errors.New("should not be called")}
+		sql := createTestSQLBackend(t, store, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem))
+
+		err := <-sql.Record(context.Background(), createTestRule(), []state.StateTransition{})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("emits expected write metrics", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		met := metrics.NewHistorianMetrics(reg, metrics.Subsystem)
+		sql := createTestSQLBackend(t, &fakeStateHistoryStore{}, met)
+		errSQL := createTestSQLBackend(t, &fakeStateHistoryStore{err: errors.New("database is locked")}, met)
+		rule := createTestRule()
+		states := singleFromNormal(&state.State{
+			State:  eval.Alerting,
+			Labels: data.Labels{"a": "b"},
+		})
+
+		require.NoError(t, <-sql.Record(context.Background(), rule, states))
+		require.ErrorContains(t, <-errSQL.Record(context.Background(), rule, states), "database is locked")
+
+		exp := bytes.NewBufferString(`
+# HELP grafana_alerting_state_history_transitions_failed_total The total number of state transitions that failed to be written - they are not retried.
+# TYPE grafana_alerting_state_history_transitions_failed_total counter
+grafana_alerting_state_history_transitions_failed_total{org="1"} 1
+# HELP grafana_alerting_state_history_transitions_total The total number of state transitions processed.
+# TYPE grafana_alerting_state_history_transitions_total counter
+grafana_alerting_state_history_transitions_total{org="1"} 2
+# HELP grafana_alerting_state_history_writes_failed_total The total number of failed writes of state history batches.
+# TYPE grafana_alerting_state_history_writes_failed_total counter
+grafana_alerting_state_history_writes_failed_total{backend="sql",org="1"} 1
+# HELP grafana_alerting_state_history_writes_total The total number of state history batches that were attempted to be written.
+# TYPE grafana_alerting_state_history_writes_total counter
+grafana_alerting_state_history_writes_total{backend="sql",org="1"} 2
+`)
+		err := testutil.GatherAndCompare(reg, exp,
+			"grafana_alerting_state_history_transitions_total",
+			"grafana_alerting_state_history_transitions_failed_total",
+			"grafana_alerting_state_history_writes_total",
+			"grafana_alerting_state_history_writes_failed_total",
+		)
+		require.NoError(t, err)
+	})
+}
+
+func TestSQLBackendQuery(t *testing.T) {
+	rule := createTestRule()
+	start := time.Unix(1000, 0).UTC()
+	record := func(t *testing.T, sql *SQLBackend) {
+		t.Helper()
+		for i, lbls := range []data.Labels{{"a": "1"}, {"a": "2"}, {"a": "1"}, {"a": "2"}} {
+			states := singleFromNormal(&state.State{
+				State:              eval.Alerting,
+				Labels:             lbls,
+				LastEvaluationTime: start.Add(time.Duration(i) * time.Minute),
+			})
+			require.NoError(t, <-sql.Record(context.Background(), rule, states))
+		}
+	}
+	query := models.HistoryQuery{
+		OrgID:        rule.OrgID,
+		RuleUID:      rule.UID,
+		From:         start,
+		To:           start.Add(time.Hour),
+		SignedInUser: &identity.StaticRequester{},
+	}
+
+	t.Run("returns entries in a loki compatible frame", func(t *testing.T) {
+		store := &fakeStateHistoryStore{}
+		sql := createTestSQLBackend(t, store, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem))
+		record(t, sql)
+
+		frame, err := sql.Query(context.Background(), query)
+
+		require.NoError(t, err)
+		require.Equal(t, "states", frame.Name)
+		require.Equal(t, 4, frame.Rows())
+		require.Equal(t, start, frame.Fields[0].At(0))
+		require.Equal(t, json.RawMessage(store.entries[0].Line), frame.Fields[1].At(0))
+		var lbls map[string]string
+		require.NoError(t, json.Unmarshal(frame.Fields[2].At(0).(json.RawMessage), &lbls))
+		require.Equal(t, map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           "1",
+			GroupLabel:           rule.Group,
+			FolderUIDLabel:       rule.NamespaceUID,
+		}, lbls)
+		require.Equal(t, []string{rule.NamespaceUID}, store.queries[0].FolderUIDs)
+	})
+
+	t.Run("applies the limit to the store query", func(t *testing.T) {
+		store := &fakeStateHistoryStore{}
+		sql := createTestSQLBackend(t, store, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem))
+		record(t, sql)
+		q := query
+		q.Limit = 3
+
+		frame, err := sql.Query(context.Background(), q)
+
+		require.NoError(t, err)
+		require.Equal(t, 3, store.queries[0].Limit)
+		require.Equal(t, 3, frame.Rows())
+		require.Equal(t, start.Add(time.Minute), frame.Fields[0].At(0))
+	})
+
+	t.Run("filters by instance labels before applying the limit", func(t *testing.T) {
+		store := &fakeStateHistoryStore{}
+		sql := createTestSQLBackend(t, store, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem))
+		record(t, sql)
+		q := query
+		q.Labels = map[string]string{"a": "2"}
+		q.Limit = 1
+
+		frame, err := sql.Query(context.Background(), q)
+
+		require.NoError(t, err)
+		require.Equal(t, sqlQueryBatchSize, store.queries[0].Limit)
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, start.Add(3*time.Minute), frame.Fields[0].At(0))
+	})
+
+	t.Run("pages through the entries when filtering by instance labels", func(t *testing.T) {
+		batchSize := sqlQueryBatchSize
+		sqlQueryBatchSize = 1
+		t.Cleanup(func() { sqlQueryBatchSize = batchSize })
+		store := &fakeStateHistoryStore{}
+		sql := createTestSQLBackend(t, store, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem))
+		record(t, sql)
+		q := query
+		q.Labels = map[string]string{"a": "1"}
+
+		frame, err := sql.Query(context.Background(), q)
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, start, frame.Fields[0].At(0))
+		require.Equal(t, start.Add(2*time.Minute), frame.Fields[0].At(1))
+		// the last batch is empty
+		require.Len(t, store.queries, 5)
+
+		store.queries = nil
+		q.Limit = 1
+		frame, err = sql.Query(context.Background(), q)
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, start.Add(2*time.Minute), frame.Fields[0].At(0))
+		require.Len(t, store.queries, 2)
+	})
+
+	t.Run("defaults the time range", func(t *testing.T) {
+		store := &fakeStateHistoryStore{}
+		sql := createTestSQLBackend(t, store, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem))
+		q := query
+		q.From = time.Time{}
+		q.To = time.Time{}
+
+		_, err := sql.Query(context.Background(), q)
+
+		require.NoError(t, err)
+		require.Equal(t, defaultQueryRange, store.queries[0].To.Sub(store.queries[0].From))
+	})
+}
+
+func createTestSQLBackend(t *testing.T, store StateHistoryStore, met *metrics.Historian) *SQLBackend {
+	rules := fakes.NewRuleStore(t)
+	rule := createTestRule()
+	rules.PutRule(context.Background(), models.RuleGen.With(
+		models.RuleGen.WithOrgID(rule.OrgID),
+		models.RuleGen.WithUID(rule.UID),
+		models.RuleGen.WithNamespaceUID(rule.NamespaceUID),
+	).GenerateRef())
+	ac := &acfakes.FakeRuleService{}
+	return NewSQLBackend(log.New("ngalert.state.historian", "backend", "sql"), store, rules, met, ac)
+}
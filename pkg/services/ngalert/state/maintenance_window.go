@@ -0,0 +1,90 @@
+package state
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// MaintenanceWindowReader returns the maintenance windows that are active for an alert rule.
+type MaintenanceWindowReader interface {
+	GetActiveMaintenanceWindows(rule *ngModels.AlertRule, at time.Time) []*ngModels.MaintenanceWindow
+}
+
+// MaintenanceWindowStore lists the maintenance windows that have not ended.
+type MaintenanceWindowStore interface {
+	ListMaintenanceWindowsForScheduling(ctx context.Context, now time.Time) ([]*ngModels.MaintenanceWindow, error)
+}
+
+// MaintenanceWindowCache keeps the maintenance windows that have not ended in memory, so they can be checked
+// on every evaluation without querying the database. It is refreshed periodically, so changes to the windows
+// are picked up within one refresh interval.
+type MaintenanceWindowCache struct {
+	store    MaintenanceWindowStore
+	interval time.Duration
+	clock    clock.Clock
+	log      log.Logger
+
+	mtx     sync.RWMutex
+	windows map[int64][]*ngModels.MaintenanceWindow
+}
+
+func NewMaintenanceWindowCache(store MaintenanceWindowStore, interval time.Duration, clock clock.Clock, log log.Logger) *MaintenanceWindowCache {
+	return &MaintenanceWindowCache{
+		store:    store,
+		interval: interval,
+		clock:    clock,
+		log:      log,
+		windows:  map[int64][]*ngModels.MaintenanceWindow{},
+	}
+}
+
+// Run refreshes the cache every interval until the context is cancelled.
+func (c *MaintenanceWindowCache) Run(ctx context.Context) error {
+	ticker := c.clock.Ticker(c.interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Refresh(ctx); err != nil {
+			c.log.Error("Failed to refresh maintenance windows", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh replaces the cached windows with the windows that have not ended.
+func (c *MaintenanceWindowCache) Refresh(ctx context.Context) error {
+	windows, err := c.store.ListMaintenanceWindowsForScheduling(ctx, c.clock.Now())
+	if err != nil {
+		return err
+	}
+	byOrg := make(map[int64][]*ngModels.MaintenanceWindow)
+	for _, w := range windows {
+		byOrg[w.OrgID] = append(byOrg[w.OrgID], w)
+	}
+	c.mtx.Lock()
+	c.windows = byOrg
+	c.mtx.Unlock()
+	return nil
+}
+
+// GetActiveMaintenanceWindows returns the windows that are active at the given time and select the rule.
+func (c *MaintenanceWindowCache) GetActiveMaintenanceWindows(rule *ngModels.AlertRule, at time.Time) []*ngModels.MaintenanceWindow {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	var result []*ngModels.MaintenanceWindow
+	for _, w := range c.windows[rule.OrgID] {
+		if w.IsActive(at) && w.Selector.Matches(rule) {
+			result = append(result, w)
+		}
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -58,6 +59,8 @@ type Manager struct {
 	rulesPerRuleGroupLimit int64
 
 	persister StatePersister
+
+	maintenanceWindows MaintenanceWindowReader
 }
 
 type ManagerCfg struct {
@@ -84,6 +87,9 @@ type ManagerCfg struct {
 	// Duration for which a resolved alert state transition will continue to be sent to the Alertmanager.
 	ResolvedRetention time.Duration
 
+	// MaintenanceWindows returns the active maintenance windows of a rule. Optional.
+	MaintenanceWindows MaintenanceWindowReader
+
 	Tracer tracing.Tracer
 	Log    log.Logger
 }
@@ -110,6 +116,7 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 		rulesPerRuleGroupLimit: cfg.RulesPerRuleGroupLimit,
 		persister:              statePersister,
 		tracer:                 cfg.Tracer,
+		maintenanceWindows:     cfg.MaintenanceWindows,
 	}
 
 	return m
@@ -360,8 +367,12 @@ func (st *Manager) ProcessEvalResults(
 
 	logger.Debug("State manager processing evaluation results", "resultCount", len(results))
 	states := st.setNextStateForRule(ctx, alertRule, results, extraLabels, logger, fn, evaluatedAt)
+	st.applyInhibitions(alertRule, states, logger)
+	applyFlapDetection(alertRule, states, evaluatedAt, logger)
+	st.applyMaintenanceWindows(alertRule, states, evaluatedAt, logger)
 
 	missingSeriesStates, staleCount := st.processMissingSeriesStates(logger, evaluatedAt, alertRule, states, fn)
+	st.applyMaintenanceWindows(alertRule, missingSeriesStates, evaluatedAt, logger)
 	span.AddEvent("results processed", trace.WithAttributes(
 		attribute.Int64("state_transitions", int64(len(states))),
 		attribute.Int64("stale_states", staleCount),
@@ -390,6 +401,136 @@ func (st *Manager) ProcessEvalResults(
 	return allChanges
 }
 
+// applyInhibitions marks the states of the rule that are inhibited by a firing state of one of the rules
+// in alertRule.InhibitedBy. Only states that would be sent to the Alertmanager as firing are inhibited.
+// The latest evaluated states of the inhibiting rules are used. When an inhibiting rule is due on the same tick,
+// the scheduler evaluates it first, unless its evaluation takes longer than the base interval of the scheduler.
+func (st *Manager) applyInhibitions(alertRule *ngModels.AlertRule, transitions []StateTransition, logger log.Logger) {
+	if len(alertRule.InhibitedBy) == 0 {
+		return
+	}
+	firing := make([][]*State, len(alertRule.InhibitedBy))
+	for i, inhibition := range alertRule.InhibitedBy {
+		for _, s := range st.cache.getStatesForRuleUID(alertRule.OrgID, inhibition.RuleUID) {
+			if s.IsFiring() {
+				firing[i] = append(firing[i], s)
+			}
+		}
+	}
+
+	for _, t := range transitions {
+		if t.State.State == eval.Normal || t.State.State == eval.Pending || t.State.IsInhibited() {
+			continue
+		}
+		uid, ok := findInhibitingRule(alertRule.InhibitedBy, firing, t.Labels)
+		if !ok {
+			continue
+		}
+		logger.Debug("Alert is inhibited", "instance", t.Labels, "inhibitingRuleUID", uid)
+		if t.StateReason == "" {
+			t.StateReason = ngModels.StateReasonInhibited
+		} else {
+			t.StateReason = ngModels.ConcatReasons(t.StateReason, ngModels.StateReasonInhibited)
+		}
+	}
+}
+
+// applyFlapDetection records the state changes of the rule and marks the states that changed at least
+// alertRule.FlapDetection.Transitions times within the window as flapping. A state stops flapping once it
+// has not changed for the whole window. Flapping states get the reason Flapping and the flapping annotation.
+func applyFlapDetection(alertRule *ngModels.AlertRule, transitions []StateTransition, evaluatedAt time.Time, logger log.Logger) {
+	for _, t := range transitions {
+		if alertRule.FlapDetection == nil {
+			// Flap detection might have been removed from the rule since the last evaluation.
+			t.FlapHistory = nil
+			t.FlappingSince = nil
+			delete(t.Annotations, ngModels.FlappingAnnotation)
+			continue
+		}
+
+		if t.PreviousState != t.State.State {
+			t.FlapHistory = append(t.FlapHistory, evaluatedAt)
+		}
+		threshold := evaluatedAt.Add(-alertRule.FlapDetection.Window)
+		t.FlapHistory = slices.DeleteFunc(t.FlapHistory, func(changedAt time.Time) bool {
+			return !changedAt.After(threshold)
+		})
+
+		switch {
+		case !t.IsFlapping() && int64(len(t.FlapHistory)) >= alertRule.FlapDetection.Transitions:
+			logger.Info("Alert started flapping", "instance", t.Labels, "transitions", len(t.FlapHistory))
+			t.FlappingSince = &evaluatedAt
+		case t.IsFlapping() && len(t.FlapHistory) == 0:
+			logger.Info("Alert stopped flapping", "instance", t.Labels, "flappingSince", *t.FlappingSince)
+			t.FlappingSince = nil
+		}
+
+		if !t.IsFlapping() {
+			delete(t.Annotations, ngModels.FlappingAnnotation)
+			continue
+		}
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[ngModels.FlappingAnnotation] = t.FlappingSince.Format(time.RFC3339)
+		if t.StateReason == "" {
+			t.StateReason = ngModels.StateReasonFlapping
+		} else {
+			t.StateReason = ngModels.ConcatReasons(t.StateReason, ngModels.StateReasonFlapping)
+		}
+	}
+}
+
+// applyMaintenanceWindows marks the states of the rule as in maintenance while one of the maintenance windows
+// that select the rule is active. The states get the reason Maintenance and the annotation with the UID of the window.
+// The reason is recorded by the state historian, so the beginning and the end of the maintenance can be audited.
+func (st *Manager) applyMaintenanceWindows(alertRule *ngModels.AlertRule, transitions []StateTransition, evaluatedAt time.Time, logger log.Logger) {
+	var windows []*ngModels.MaintenanceWindow
+	if st.maintenanceWindows != nil {
+		windows = st.maintenanceWindows.GetActiveMaintenanceWindows(alertRule, evaluatedAt)
+	}
+	for _, t := range transitions {
+		if len(windows) == 0 {
+			if _, ok := t.Annotations[ngModels.MaintenanceWindowAnnotation]; ok {
+				logger.Debug("Alert is no longer in maintenance", "instance", t.Labels)
+				delete(t.Annotations, ngModels.MaintenanceWindowAnnotation)
+			}
+			// Missing series keep the reason of their last evaluation, so it has to be removed here.
+			if t.IsInMaintenance() {
+				reasons := slices.DeleteFunc(strings.Split(t.StateReason, ", "), func(r string) bool {
+					return r == ngModels.StateReasonMaintenance
+				})
+				t.StateReason = ngModels.ConcatReasons(reasons...)
+			}
+			continue
+		}
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[ngModels.MaintenanceWindowAnnotation] = windows[0].UID
+		if t.IsInMaintenance() {
+			continue
+		}
+		if t.StateReason == "" {
+			t.StateReason = ngModels.StateReasonMaintenance
+		} else {
+			t.StateReason = ngModels.ConcatReasons(t.StateReason, ngModels.StateReasonMaintenance)
+		}
+	}
+}
+
+// findInhibitingRule returns the UID of the first rule with a firing state that inhibits an alert with the given labels.
+func findInhibitingRule(inhibitions []ngModels.RuleInhibition, firing [][]*State, lbls data.Labels) (string, bool) {
+	for i, inhibition := range inhibitions {
+		for _, source := range firing[i] {
+			if inhibition.Inhibits(source.Labels, lbls) {
+				return inhibition.RuleUID, true
+			}
+		}
+	}
+	return "", false
+}
+
 // updateLastSentAt returns the subset StateTransitions that need sending and updates their LastSentAt field.
 // Note: This is not idempotent, running this twice can (and usually will) return different results.
 func (st *Manager) updateLastSentAt(states StateTransitions, evaluatedAt time.Time) StateTransitions {
@@ -578,7 +719,7 @@ func (st *Manager) processMissingSeriesStates(logger log.Logger, evaluatedAt tim
 		} else if s.State == eval.Alerting {
 			// We need to update EndsAt for the state so that it will not be resolved by the
 			// Alertmanager automatically.
-			s.Maintain(alertRule.IntervalSeconds, evaluatedAt)
+			s.Maintain(alertRule, evaluatedAt)
 		}
 
 		record := StateTransition{
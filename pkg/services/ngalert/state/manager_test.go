@@ -16,6 +16,8 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	amlabels "github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -2084,6 +2086,361 @@ func TestResetStateByRuleUID(t *testing.T) {
 	})
 }
 
+func TestProcessEvalResultsInhibition(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	gen := models.RuleGen
+	source := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithOrgID(1)).GenerateRef()
+	rule := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithOrgID(1), gen.WithInhibitedBy(models.RuleInhibition{
+		RuleUID: source.UID,
+		Equal:   []string{"cluster"},
+	})).GenerateRef()
+
+	evaluate := func(r *models.AlertRule, results ...eval.Result) (state.StateTransitions, state.StateTransitions) {
+		var sent state.StateTransitions
+		processed := st.ProcessEvalResults(ctx, clk.Now(), r, results, nil, func(_ context.Context, states state.StateTransitions) {
+			sent = states
+		})
+		return processed, sent
+	}
+	result := func(s eval.State, lbls data.Labels) eval.Result {
+		return eval.ResultGen(eval.WithState(s), eval.WithLabels(lbls), eval.WithEvaluatedAt(clk.Now()))()
+	}
+	byCluster := func(transitions state.StateTransitions) map[string]state.StateTransition {
+		res := make(map[string]state.StateTransition, len(transitions))
+		for _, tr := range transitions {
+			res[tr.Labels["cluster"]] = tr
+		}
+		return res
+	}
+
+	// The inhibiting rule fires for cluster a only.
+	evaluate(source, result(eval.Alerting, data.Labels{"cluster": "a"}), result(eval.Normal, data.Labels{"cluster": "b"}))
+
+	processed, sent := evaluate(rule, result(eval.Alerting, data.Labels{"cluster": "a"}), result(eval.Alerting, data.Labels{"cluster": "b"}))
+	states := byCluster(processed)
+	require.Equal(t, eval.Alerting, states["a"].State.State)
+	require.Equal(t, models.StateReasonInhibited, states["a"].StateReason)
+	require.True(t, states["a"].IsInhibited())
+	require.Empty(t, states["b"].StateReason)
+	require.Len(t, sent, 1)
+	require.Equal(t, "b", sent[0].Labels["cluster"])
+
+	// The inhibition ends when the inhibiting rule resolves.
+	clk.Add(time.Minute)
+	evaluate(source, result(eval.Normal, data.Labels{"cluster": "a"}), result(eval.Normal, data.Labels{"cluster": "b"}))
+	processed, sent = evaluate(rule, result(eval.Alerting, data.Labels{"cluster": "a"}), result(eval.Alerting, data.Labels{"cluster": "b"}))
+	states = byCluster(processed)
+	require.Empty(t, states["a"].StateReason)
+	require.Equal(t, models.StateReasonInhibited, states["a"].PreviousStateReason)
+	require.Len(t, byCluster(sent), 2)
+}
+
+func TestProcessEvalResultsInhibitionSameTick(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	gen := models.RuleGen
+	source := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithOrgID(1)).GenerateRef()
+	rule := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithOrgID(1), gen.WithInhibitedBy(models.RuleInhibition{
+		RuleUID: source.UID,
+	})).GenerateRef()
+
+	// The scheduler evaluates the inhibiting rule first when both rules are evaluated on the same tick,
+	// so the alert of the inhibited rule is never sent even if both rules start firing on the same tick.
+	for i := 0; i < 3; i++ {
+		result := eval.ResultGen(eval.WithState(eval.Alerting), eval.WithLabels(data.Labels{"cluster": "a"}), eval.WithEvaluatedAt(clk.Now()))
+		st.ProcessEvalResults(ctx, clk.Now(), source, eval.Results{result()}, nil, nil)
+
+		var sent state.StateTransitions
+		processed := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{result()}, nil, func(_ context.Context, states state.StateTransitions) {
+			sent = states
+		})
+		require.Len(t, processed, 1)
+		require.Equal(t, eval.Alerting, processed[0].State.State)
+		require.Equal(t, models.StateReasonInhibited, processed[0].StateReason)
+		require.Empty(t, sent)
+
+		clk.Add(time.Minute)
+	}
+}
+
+func TestProcessEvalResultsEvaluationSchedule(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	businessHours := []timeinterval.TimeInterval{{
+		Times:    []timeinterval.TimeRange{{StartMinute: 9 * 60, EndMinute: 17 * 60}},
+		Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 1, End: 5}}},
+	}}
+	gen := models.RuleGen.With(models.RuleGen.WithFor(0), models.RuleGen.WithKeepFiringFor(0), models.RuleGen.WithOrgID(1), models.RuleGen.WithIntervalSeconds(60))
+
+	evaluate := func(r *models.AlertRule, evaluatedAt time.Time) *state.State {
+		result := eval.ResultGen(eval.WithState(eval.Alerting), eval.WithEvaluatedAt(evaluatedAt))()
+		processed := st.ProcessEvalResults(ctx, evaluatedAt, r, eval.Results{result}, nil, nil)
+		require.Len(t, processed, 1)
+		return processed[0].State
+	}
+
+	testCases := []struct {
+		name           string
+		schedule       *models.EvaluationSchedule
+		evaluatedAt    time.Time
+		expectedEndsAt time.Time
+	}{
+		{
+			name:           "without schedule",
+			evaluatedAt:    time.Date(2024, 3, 8, 16, 59, 0, 0, time.UTC),
+			expectedEndsAt: time.Date(2024, 3, 8, 17, 3, 0, 0, time.UTC),
+		},
+		{
+			name:           "daily cron expression",
+			schedule:       &models.EvaluationSchedule{Cron: "0 8 * * *"},
+			evaluatedAt:    time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC),
+			expectedEndsAt: time.Date(2024, 3, 5, 8, 4, 0, 0, time.UTC),
+		},
+		{
+			name:           "active time intervals that close for the weekend",
+			schedule:       &models.EvaluationSchedule{ActiveTimeIntervals: businessHours},
+			evaluatedAt:    time.Date(2024, 3, 8, 16, 59, 0, 0, time.UTC),
+			expectedEndsAt: time.Date(2024, 3, 11, 9, 4, 0, 0, time.UTC),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := gen.With(gen.WithEvaluationSchedule(tc.schedule)).GenerateRef()
+
+			// 4 x the interval of 60 seconds after the evaluation, or after the next scheduled evaluation
+			s := evaluate(rule, tc.evaluatedAt)
+			require.Equal(t, eval.Alerting, s.State)
+			require.Equal(t, tc.expectedEndsAt, s.EndsAt)
+
+			// the end time is maintained the same way while the alert keeps firing
+			s = evaluate(rule, tc.evaluatedAt)
+			require.Equal(t, tc.expectedEndsAt, s.EndsAt)
+		})
+	}
+}
+
+func TestProcessEvalResultsFlapDetection(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithOrgID(1), gen.WithFlapDetection(&models.FlapDetection{
+		Transitions: 3,
+		Window:      5 * time.Minute,
+	})).GenerateRef()
+
+	evaluate := func(s eval.State) (state.StateTransition, state.StateTransitions) {
+		var sent state.StateTransitions
+		result := eval.ResultGen(eval.WithState(s), eval.WithLabels(data.Labels{}), eval.WithEvaluatedAt(clk.Now()))()
+		processed := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{result}, nil, func(_ context.Context, states state.StateTransitions) {
+			sent = states
+		})
+		require.Len(t, processed, 1)
+		return processed[0], sent
+	}
+
+	// The first two state changes are sent.
+	processed, sent := evaluate(eval.Alerting)
+	require.False(t, processed.IsFlapping())
+	require.Len(t, sent, 1)
+	clk.Add(time.Minute)
+	processed, sent = evaluate(eval.Normal)
+	require.False(t, processed.IsFlapping())
+	require.Len(t, sent, 1)
+
+	// The third state change within the window marks the alert as flapping, and its notifications are held.
+	clk.Add(time.Minute)
+	flappingSince := clk.Now()
+	processed, sent = evaluate(eval.Alerting)
+	require.True(t, processed.IsFlapping())
+	require.Equal(t, flappingSince, *processed.FlappingSince)
+	require.Equal(t, models.StateReasonFlapping, processed.StateReason)
+	require.Equal(t, flappingSince.Format(time.RFC3339), processed.Annotations[models.FlappingAnnotation])
+	require.Empty(t, sent)
+
+	clk.Add(time.Minute)
+	processed, sent = evaluate(eval.Normal)
+	require.True(t, processed.IsFlapping())
+	require.Empty(t, sent)
+
+	// The alert keeps flapping until its state has not changed for the whole window.
+	for i := 0; i < 4; i++ {
+		clk.Add(time.Minute)
+		processed, sent = evaluate(eval.Normal)
+		require.True(t, processed.IsFlapping())
+		require.Empty(t, sent)
+	}
+
+	clk.Add(time.Minute)
+	processed, sent = evaluate(eval.Normal)
+	require.False(t, processed.IsFlapping())
+	require.Nil(t, processed.FlappingSince)
+	require.Empty(t, processed.StateReason)
+	require.Equal(t, models.StateReasonFlapping, processed.PreviousStateReason)
+	require.NotContains(t, processed.Annotations, models.FlappingAnnotation)
+	require.Len(t, sent, 1)
+	require.Equal(t, eval.Normal, sent[0].State.State)
+}
+
+type fakeMaintenanceWindowStore struct {
+	windows []*models.MaintenanceWindow
+}
+
+func (f *fakeMaintenanceWindowStore) ListMaintenanceWindowsForScheduling(_ context.Context, now time.Time) ([]*models.MaintenanceWindow, error) {
+	var result []*models.MaintenanceWindow
+	for _, w := range f.windows {
+		if w.EndsAt.After(now) {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+func TestProcessEvalResultsMaintenanceWindows(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithOrgID(1), gen.WithLabel("team", "db")).GenerateRef()
+	fresh := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithOrgID(1), gen.WithLabel("team", "db")).GenerateRef()
+	missing := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithOrgID(1), gen.WithLabel("team", "db"), gen.WithMissingSeriesEvalsToResolve(10)).GenerateRef()
+	other := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(0), gen.WithOrgID(1), gen.WithLabel("team", "web")).GenerateRef()
+
+	matcher, err := amlabels.NewMatcher(amlabels.MatchEqual, "team", "db")
+	require.NoError(t, err)
+	window := &models.MaintenanceWindow{
+		OrgID:    1,
+		UID:      "window",
+		Reason:   "database upgrade",
+		StartsAt: clk.Now().Add(time.Minute),
+		EndsAt:   clk.Now().Add(3 * time.Minute),
+		Selector: models.AlertRuleSelector{Matchers: amlabels.Matchers{matcher}},
+	}
+	windows := state.NewMaintenanceWindowCache(&fakeMaintenanceWindowStore{windows: []*models.MaintenanceWindow{window}}, time.Minute, clk, log.NewNopLogger())
+	require.NoError(t, windows.Refresh(ctx))
+
+	cfg := state.ManagerCfg{
+		Metrics:            metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore:      &state.FakeInstanceStore{},
+		Images:             &state.NoopImageService{},
+		Clock:              clk,
+		Historian:          &state.FakeHistorian{},
+		MaintenanceWindows: windows,
+		Tracer:             tracing.InitializeTracerForTest(),
+		Log:                log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	evaluate := func(r *models.AlertRule, lbls data.Labels) (state.StateTransitions, state.StateTransitions) {
+		var sent state.StateTransitions
+		result := eval.ResultGen(eval.WithState(eval.Alerting), eval.WithLabels(lbls), eval.WithEvaluatedAt(clk.Now()))()
+		processed := st.ProcessEvalResults(ctx, clk.Now(), r, eval.Results{result}, nil, func(_ context.Context, states state.StateTransitions) {
+			sent = states
+		})
+		return processed, sent
+	}
+
+	// The window has not started yet.
+	processed, sent := evaluate(rule, data.Labels{})
+	require.Len(t, processed, 1)
+	require.False(t, processed[0].IsInMaintenance())
+	require.Len(t, sent, 1)
+
+	processed, _ = evaluate(missing, data.Labels{})
+	require.Len(t, processed, 1)
+	require.False(t, processed[0].IsInMaintenance())
+
+	// While the window is active, the selected rule is evaluated but alerts that start firing are not sent.
+	clk.Add(time.Minute)
+	processed, sent = evaluate(fresh, data.Labels{})
+	require.Len(t, processed, 1)
+	require.Equal(t, eval.Alerting, processed[0].State.State)
+	require.True(t, processed[0].IsInMaintenance())
+	require.Equal(t, models.StateReasonMaintenance, processed[0].StateReason)
+	require.Equal(t, window.UID, processed[0].Annotations[models.MaintenanceWindowAnnotation])
+	require.Empty(t, sent)
+
+	// Alerts that were already firing are still re-sent, so that the Alertmanager does not resolve them.
+	processed, sent = evaluate(rule, data.Labels{})
+	require.Len(t, processed, 1)
+	require.True(t, processed[0].IsInMaintenance())
+	require.Len(t, sent, 1)
+
+	processed, sent = evaluate(other, data.Labels{})
+	require.Len(t, processed, 1)
+	require.False(t, processed[0].IsInMaintenance())
+	require.Len(t, sent, 1)
+
+	// Series that go missing during the window are in maintenance too.
+	processed, _ = evaluate(missing, data.Labels{"instance": "b"})
+	require.Len(t, processed, 2)
+	for _, p := range processed {
+		require.True(t, p.IsInMaintenance(), "state %s should be in maintenance", p.Labels)
+	}
+
+	// The window ends automatically.
+	clk.Add(2 * time.Minute)
+	require.NoError(t, windows.Refresh(ctx))
+	processed, sent = evaluate(rule, data.Labels{})
+	require.Len(t, processed, 1)
+	require.False(t, processed[0].IsInMaintenance())
+	require.Empty(t, processed[0].StateReason)
+	require.Equal(t, models.StateReasonMaintenance, processed[0].PreviousStateReason)
+	require.NotContains(t, processed[0].Annotations, models.MaintenanceWindowAnnotation)
+	require.Len(t, sent, 1)
+
+	processed, _ = evaluate(missing, data.Labels{"instance": "b"})
+	require.NotEmpty(t, processed)
+	for _, p := range processed {
+		require.False(t, p.IsInMaintenance(), "state %s should not be in maintenance", p.Labels)
+		require.NotContains(t, p.Annotations, models.MaintenanceWindowAnnotation)
+	}
+}
+
 func setCacheID(s *state.State) *state.State {
 	if s.CacheID != 0 {
 		return s
@@ -8,6 +8,7 @@ import (
 	"maps"
 	"math"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -79,6 +80,14 @@ type State struct {
 	LastEvaluationString string
 	LastEvaluationTime   time.Time
 	EvaluationDuration   time.Duration
+
+	// FlapHistory contains the times of the state changes within the flap detection window of the rule.
+	// It is empty if the rule does not have flap detection.
+	// FlapHistory and FlappingSince are kept in memory only and are not persisted with the alert instances,
+	// so flap detection starts over when Grafana restarts.
+	FlapHistory []time.Time
+	// FlappingSince is set when the state starts flapping, and is reset when it stops flapping.
+	FlappingSince *time.Time
 }
 
 func newState(ctx context.Context, log log.Logger, alertRule *models.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL) *State {
@@ -139,6 +148,8 @@ func (a *State) Copy() *State {
 		LastEvaluationString: a.LastEvaluationString,
 		LastEvaluationTime:   a.LastEvaluationTime,
 		EvaluationDuration:   a.EvaluationDuration,
+		FlapHistory:          slices.Clone(a.FlapHistory),
+		FlappingSince:        a.FlappingSince,
 	}
 }
 
@@ -216,8 +227,8 @@ func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 }
 
 // Maintain updates the end time using the most recent evaluation.
-func (a *State) Maintain(interval int64, evaluatedAt time.Time) {
-	a.EndsAt = nextEndsTime(interval, evaluatedAt)
+func (a *State) Maintain(rule *models.AlertRule, evaluatedAt time.Time) {
+	a.EndsAt = nextRuleEndsTime(rule, evaluatedAt)
 }
 
 // addErrorInfoToAnnotations adds annotations to the state to indicate that an error occurred.
@@ -365,14 +376,14 @@ func resultNormal(state *State, rule *models.AlertRule, result eval.Result, logg
 			// If the KeepFiringFor duration has not been observed then the state is kept as Recovering.
 			// We must also set the next endsAt to a future time for the Alertmanager,
 			// as for it the alert is still firing.
-			state.EndsAt = nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt)
+			state.EndsAt = nextRuleEndsTime(rule, result.EvaluatedAt)
 		}
 	case state.State == eval.Alerting && rule.KeepFiringFor > 0:
 		// If the old state is Alerting and the rule has a KeepFiringFor duration then
 		// the state should be set to Recovering when it transitions to Normal.
 		//
 		// EndsAt must be set to a future time for the Alertmanager, the same as for Alerting states.
-		nextEndsAt := nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt)
+		nextEndsAt := nextRuleEndsTime(rule, result.EvaluatedAt)
 		logger.Debug("Changing state",
 			"previous_state",
 			state.State,
@@ -405,7 +416,7 @@ func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, lo
 	switch state.State {
 	case eval.Alerting:
 		prevEndsAt := state.EndsAt
-		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+		state.Maintain(rule, result.EvaluatedAt)
 		// explicitly clear errors
 		state.Error = nil
 		logger.Debug("Keeping state",
@@ -418,7 +429,7 @@ func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, lo
 	case eval.Pending:
 		// If the previous state is Pending then check if the For duration has been observed
 		if result.EvaluatedAt.Sub(state.StartsAt) >= rule.For {
-			nextEndsAt := nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt)
+			nextEndsAt := nextRuleEndsTime(rule, result.EvaluatedAt)
 			logger.Debug("Changing state",
 				"previous_state",
 				state.State,
@@ -431,7 +442,7 @@ func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, lo
 			state.SetAlerting(reason, result.EvaluatedAt, nextEndsAt)
 		}
 	default:
-		nextEndsAt := nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt)
+		nextEndsAt := nextRuleEndsTime(rule, result.EvaluatedAt)
 		if state.State != eval.Recovering && rule.For > 0 {
 			// If the alert rule has a For duration that should be observed then the state should be set to Pending.
 			// If the alert is currently in the Recovering state then we skip Pending and set it directly to Alerting.
@@ -474,7 +485,7 @@ func resultError(state *State, rule *models.AlertRule, result eval.Result, logge
 		if state.State == eval.Error {
 			prevEndsAt := state.EndsAt
 			state.Error = result.Error
-			state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+			state.Maintain(rule, result.EvaluatedAt)
 			logger.Debug("Keeping state",
 				"state",
 				state.State,
@@ -483,7 +494,7 @@ func resultError(state *State, rule *models.AlertRule, result eval.Result, logge
 				"next_ends_at",
 				state.EndsAt)
 		} else {
-			nextEndsAt := nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt)
+			nextEndsAt := nextRuleEndsTime(rule, result.EvaluatedAt)
 			// This is the first occurrence of an error
 			logger.Debug("Changing state",
 				"previous_state",
@@ -510,7 +521,7 @@ func resultError(state *State, rule *models.AlertRule, result eval.Result, logge
 		state.addErrorInfoToAnnotations(result.Error, rule)
 	default:
 		err := fmt.Errorf("unsupported execution error state: %s", rule.ExecErrState)
-		state.SetError(err, state.StartsAt, nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt))
+		state.SetError(err, state.StartsAt, nextRuleEndsTime(rule, result.EvaluatedAt))
 		state.addErrorInfoToAnnotations(result.Error, rule)
 	}
 }
@@ -525,7 +536,7 @@ func resultNoData(state *State, rule *models.AlertRule, result eval.Result, logg
 	case models.NoData:
 		if state.State == eval.NoData {
 			prevEndsAt := state.EndsAt
-			state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+			state.Maintain(rule, result.EvaluatedAt)
 			logger.Debug("Keeping state",
 				"state",
 				state.State,
@@ -535,7 +546,7 @@ func resultNoData(state *State, rule *models.AlertRule, result eval.Result, logg
 				state.EndsAt)
 		} else {
 			// This is the first occurrence of no data
-			nextEndsAt := nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt)
+			nextEndsAt := nextRuleEndsTime(rule, result.EvaluatedAt)
 			logger.Debug("Changing state",
 				"previous_state",
 				state.State,
@@ -555,7 +566,7 @@ func resultNoData(state *State, rule *models.AlertRule, result eval.Result, logg
 		resultKeepLast(state, rule, result, logger)
 	default:
 		err := fmt.Errorf("unsupported no data state: %s", rule.NoDataState)
-		state.SetError(err, state.StartsAt, nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt))
+		state.SetError(err, state.StartsAt, nextRuleEndsTime(rule, result.EvaluatedAt))
 		state.Annotations["Error"] = err.Error()
 	}
 }
@@ -596,6 +607,24 @@ func (a *State) NeedsSending(now time.Time, resendDelay time.Duration, resolvedR
 		return false
 	}
 
+	if a.IsInhibited() && !a.isSentFiring() {
+		// Inhibited states are not sent until the inhibiting alert stops firing. Alerts that were already
+		// sent as firing are still re-sent, so that the Alertmanager does not resolve them while inhibited.
+		return false
+	}
+
+	if a.IsFlapping() && !a.isSentFiring() {
+		// Notifications of flapping states are held until the state stops flapping. Alerts that were already
+		// sent as firing are still re-sent, so that the Alertmanager does not resolve them while flapping.
+		return false
+	}
+
+	if a.IsInMaintenance() && !a.isSentFiring() {
+		// Notifications are suppressed while the rule is in a maintenance window. Alerts that were already
+		// sent as firing are still re-sent, so that the Alertmanager does not resolve them during the window.
+		return false
+	}
+
 	// We should send a notification if the state has been resolved since the last notification.
 	if a.ResolvedAt != nil && (a.LastSentAt == nil || a.ResolvedAt.After(*a.LastSentAt)) {
 		return true
@@ -624,6 +653,18 @@ func (a *State) Equals(b *State) bool {
 		data.Labels(a.Annotations).String() == data.Labels(b.Annotations).String()
 }
 
+// nextRuleEndsTime returns the end time of the alerts of the rule evaluated at evaluatedAt. The next evaluation of a rule
+// with an evaluation schedule can be much later than one interval, for example the next match of its cron expression
+// or the reopening of its active time intervals, so its alerts end relative to that evaluation instead.
+func nextRuleEndsTime(rule *models.AlertRule, evaluatedAt time.Time) time.Time {
+	if rule.EvaluationSchedule != nil {
+		if next, ok := rule.EvaluationSchedule.NextEvaluation(evaluatedAt, time.Duration(rule.IntervalSeconds)*time.Second); ok {
+			return nextEndsTime(rule.IntervalSeconds, next)
+		}
+	}
+	return nextEndsTime(rule.IntervalSeconds, evaluatedAt)
+}
+
 func nextEndsTime(interval int64, evaluatedAt time.Time) time.Time {
 	ends := ResendDelay
 	intv := time.Second * time.Duration(interval)
@@ -668,6 +709,33 @@ func (a *State) IsStale() bool {
 	return a.StateReason == models.StateReasonMissingSeries
 }
 
+// IsFiring returns true if the state is Alerting or Recovering.
+func (a *State) IsFiring() bool {
+	return a.State == eval.Alerting || a.State == eval.Recovering
+}
+
+// IsInhibited returns true if the state is inhibited by a firing alert of another rule.
+func (a *State) IsInhibited() bool {
+	return slices.Contains(strings.Split(a.StateReason, ", "), models.StateReasonInhibited)
+}
+
+// isSentFiring returns true if the state is firing and was sent to the Alertmanager since it started firing.
+// The Alertmanager resolves an alert when its EndsAt passes, so such a state must keep being re-sent
+// to refresh its EndsAt, even while its new notifications are held back.
+func (a *State) isSentFiring() bool {
+	return a.State != eval.Normal && a.State != eval.Pending && a.LastSentAt != nil && !a.LastSentAt.Before(a.StartsAt)
+}
+
+// IsFlapping returns true if the state changed too often within the flap detection window of the rule.
+func (a *State) IsFlapping() bool {
+	return a.FlappingSince != nil
+}
+
+// IsInMaintenance returns true if the rule of the state is in an active maintenance window.
+func (a *State) IsInMaintenance() bool {
+	return slices.Contains(strings.Split(a.StateReason, ", "), models.StateReasonMaintenance)
+}
+
 // If the state is Normal, and the previous state was Alerting, Error, NoData, or Recovering,
 // we can consider the state to be resolved. This is used to determine if we should send a resolved notification.
 func (a *State) ShouldBeResolved(oldState eval.State) bool {
@@ -777,6 +845,18 @@ func GetRuleExtraLabels(l log.Logger, rule *models.AlertRule, folderTitle string
 	return extraLabels
 }
 
+// GetAlertLabels returns the labels of an alert of the rule with the given instance labels, such as the labels of a query result,
+// as they would be sent to the Alertmanager. Templates in the labels of the rule are expanded with the instance labels.
+func GetAlertLabels(ctx context.Context, l log.Logger, rule *models.AlertRule, instance data.Labels, extraLabels data.Labels, externalURL *url.URL, evaluatedAt time.Time) data.Labels {
+	result := eval.Result{
+		Instance:    instance,
+		State:       eval.Alerting,
+		EvaluatedAt: evaluatedAt,
+	}
+	lbs, _ := expandAnnotationsAndLabels(ctx, l, rule, result, extraLabels, externalURL)
+	return lbs
+}
+
 func patch(newState, existingState *State, result eval.Result) {
 	// if there is existing state, copy over the current values that may be needed to determine the final state.
 	// TODO remove some unnecessary assignments below because they are overridden in setNextState
@@ -792,6 +872,8 @@ func patch(newState, existingState *State, result eval.Result) {
 	newState.FiredAt = existingState.FiredAt
 	newState.ResolvedAt = existingState.ResolvedAt
 	newState.LastSentAt = existingState.LastSentAt
+	newState.FlapHistory = existingState.FlapHistory
+	newState.FlappingSince = existingState.FlappingSince
 	// Annotations can change over time, however we also want to maintain
 	// certain annotations across evaluations
 	for key := range models.InternalAnnotationNameSet { // Changing in
@@ -262,13 +262,13 @@ func TestMaintain(t *testing.T) {
 
 	// the interval is less than the resend interval of 30 seconds
 	s := State{State: eval.Alerting, StartsAt: now, EndsAt: now.Add(time.Second)}
-	s.Maintain(10, now.Add(10*time.Second))
+	s.Maintain(&ngmodels.AlertRule{IntervalSeconds: 10}, now.Add(10*time.Second))
 	// 10 seconds + 4 x 30 seconds is 130 seconds
 	assert.Equal(t, now.Add(130*time.Second), s.EndsAt)
 
 	// the interval is above the resend interval of 30 seconds
 	s = State{State: eval.Alerting, StartsAt: now, EndsAt: now.Add(time.Second)}
-	s.Maintain(60, now.Add(10*time.Second))
+	s.Maintain(&ngmodels.AlertRule{IntervalSeconds: 60}, now.Add(10*time.Second))
 	// 10 seconds + 4 x 60 seconds is 250 seconds
 	assert.Equal(t, now.Add(250*time.Second), s.EndsAt)
 }
@@ -508,6 +508,102 @@ func TestNeedsSending(t *testing.T) {
 				LastSentAt:         util.Pointer(evaluationTime.Add(-time.Duration(rand.Int63n(59)+1) * time.Second)),
 			},
 		},
+		{
+			name:        "state: inhibited, not sent since it started firing",
+			expected:    false,
+			resendDelay: 1 * time.Minute,
+			testState: &State{
+				State:              eval.Alerting,
+				StateReason:        ngmodels.StateReasonInhibited,
+				StartsAt:           evaluationTime.Add(-1 * time.Minute),
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         util.Pointer(evaluationTime.Add(-2 * time.Minute)),
+			},
+		},
+		{
+			name:        "state: inhibited, already sent as firing, needs to be re-sent",
+			expected:    true,
+			resendDelay: 1 * time.Minute,
+			testState: &State{
+				State:              eval.Alerting,
+				StateReason:        ngmodels.StateReasonInhibited,
+				StartsAt:           evaluationTime.Add(-5 * time.Minute),
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         util.Pointer(evaluationTime.Add(-1 * time.Minute)),
+			},
+		},
+		{
+			name:        "state: inhibited, already sent as firing, should not be re-sent",
+			expected:    false,
+			resendDelay: 1 * time.Minute,
+			testState: &State{
+				State:              eval.Alerting,
+				StateReason:        ngmodels.StateReasonInhibited,
+				StartsAt:           evaluationTime.Add(-5 * time.Minute),
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         util.Pointer(evaluationTime.Add(-30 * time.Second)),
+			},
+		},
+		{
+			name:        "state: flapping, not sent since it started firing",
+			expected:    false,
+			resendDelay: 1 * time.Minute,
+			testState: &State{
+				State:              eval.Alerting,
+				StartsAt:           evaluationTime.Add(-1 * time.Minute),
+				FlappingSince:      util.Pointer(evaluationTime.Add(-1 * time.Minute)),
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         util.Pointer(evaluationTime.Add(-2 * time.Minute)),
+			},
+		},
+		{
+			name:        "state: flapping, already sent as firing, needs to be re-sent",
+			expected:    true,
+			resendDelay: 1 * time.Minute,
+			testState: &State{
+				State:              eval.Alerting,
+				StartsAt:           evaluationTime.Add(-5 * time.Minute),
+				FlappingSince:      util.Pointer(evaluationTime.Add(-3 * time.Minute)),
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         util.Pointer(evaluationTime.Add(-1 * time.Minute)),
+			},
+		},
+		{
+			name:        "state: flapping, resolved, should not be sent",
+			expected:    false,
+			resendDelay: 1 * time.Minute,
+			testState: &State{
+				State:              eval.Normal,
+				ResolvedAt:         util.Pointer(evaluationTime),
+				FlappingSince:      util.Pointer(evaluationTime.Add(-3 * time.Minute)),
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         util.Pointer(evaluationTime.Add(-1 * time.Minute)),
+			},
+		},
+		{
+			name:        "state: in maintenance, not sent since it started firing",
+			expected:    false,
+			resendDelay: 1 * time.Minute,
+			testState: &State{
+				State:              eval.Alerting,
+				StateReason:        ngmodels.StateReasonMaintenance,
+				StartsAt:           evaluationTime.Add(-1 * time.Minute),
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         util.Pointer(evaluationTime.Add(-2 * time.Minute)),
+			},
+		},
+		{
+			name:        "state: in maintenance, already sent as firing, needs to be re-sent",
+			expected:    true,
+			resendDelay: 1 * time.Minute,
+			testState: &State{
+				State:              eval.Alerting,
+				StateReason:        ngmodels.StateReasonMaintenance,
+				StartsAt:           evaluationTime.Add(-5 * time.Minute),
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         util.Pointer(evaluationTime.Add(-1 * time.Minute)),
+			},
+		},
 	}
 
 	for _, tc := range testCases {
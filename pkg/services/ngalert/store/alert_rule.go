@@ -76,6 +76,12 @@ func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, user *
 		}
 		logger.Debug("Deleted alert rule state", "count", rows)
 
+		rows, err = sess.Table(ruleTemplateInstance{}).Where("org_id = ?", orgID).In("rule_uid", ruleUID).Delete(ruleTemplateInstance{})
+		if err != nil {
+			return err
+		}
+		logger.Debug("Deleted rule template instances", "count", rows)
+
 		var versions []alertRuleVersion
 		//nolint:staticcheck // not yet migrated to OpenFeature
 		if st.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertRuleRestore) && st.Cfg.DeletedRuleRetention > 0 && !permanently { // save deleted version only if retention is greater than 0
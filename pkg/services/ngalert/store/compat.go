@@ -127,6 +127,33 @@ func convertAlertRuleToModel(ar alertRule, l log.Logger, opts AlertRuleConvertOp
 		result.NotificationSettings = ns
 	}
 
+	if ar.InhibitedBy != "" {
+		err = json.Unmarshal([]byte(ar.InhibitedBy), &result.InhibitedBy)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse inhibited by: %w", err)
+		}
+	}
+
+	if ar.EvaluationSchedule != "" {
+		result.EvaluationSchedule = &models.EvaluationSchedule{}
+		err = json.Unmarshal([]byte(ar.EvaluationSchedule), result.EvaluationSchedule)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse evaluation schedule: %w", err)
+		}
+		// Invalid schedules are rejected when the rule is saved, and the scheduler ignores the rule if it has one.
+		if err := result.EvaluationSchedule.Parse(); err != nil {
+			l.Warn("Rule has an invalid evaluation schedule", append(result.GetKey().LogContext(), "error", err)...)
+		}
+	}
+
+	if ar.FlapDetection != "" {
+		result.FlapDetection = &models.FlapDetection{}
+		err = json.Unmarshal([]byte(ar.FlapDetection), result.FlapDetection)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse flap detection: %w", err)
+		}
+	}
+
 	if !opts.ExcludeMetadata && ar.Metadata != "" {
 		err = json.Unmarshal([]byte(ar.Metadata), &result.Metadata)
 		if err != nil {
@@ -217,6 +244,30 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 		result.NotificationSettings = string(notificationSettingsData)
 	}
 
+	if len(ar.InhibitedBy) > 0 {
+		inhibitedByData, err := json.Marshal(ar.InhibitedBy)
+		if err != nil {
+			return alertRule{}, fmt.Errorf("failed to marshal inhibited by: %w", err)
+		}
+		result.InhibitedBy = string(inhibitedByData)
+	}
+
+	if ar.EvaluationSchedule != nil {
+		scheduleData, err := json.Marshal(ar.EvaluationSchedule)
+		if err != nil {
+			return alertRule{}, fmt.Errorf("failed to marshal evaluation schedule: %w", err)
+		}
+		result.EvaluationSchedule = string(scheduleData)
+	}
+
+	if ar.FlapDetection != nil {
+		flapDetectionData, err := json.Marshal(ar.FlapDetection)
+		if err != nil {
+			return alertRule{}, fmt.Errorf("failed to marshal flap detection: %w", err)
+		}
+		result.FlapDetection = string(flapDetectionData)
+	}
+
 	metadata, err := json.Marshal(ar.Metadata)
 	if err != nil {
 		return alertRule{}, fmt.Errorf("failed to metadata: %w", err)
@@ -255,6 +306,9 @@ func alertRuleToAlertRuleVersion(rule alertRule) alertRuleVersion {
 		NotificationSettings:        rule.NotificationSettings,
 		Metadata:                    rule.Metadata,
 		MissingSeriesEvalsToResolve: rule.MissingSeriesEvalsToResolve,
+		InhibitedBy:                 rule.InhibitedBy,
+		EvaluationSchedule:          rule.EvaluationSchedule,
+		FlapDetection:               rule.FlapDetection,
 	}
 }
 
@@ -289,6 +343,9 @@ func alertRuleVersionToAlertRule(version alertRuleVersion) alertRule {
 		NotificationSettings:        version.NotificationSettings,
 		Metadata:                    version.Metadata,
 		MissingSeriesEvalsToResolve: version.MissingSeriesEvalsToResolve,
+		InhibitedBy:                 version.InhibitedBy,
+		EvaluationSchedule:          version.EvaluationSchedule,
+		FlapDetection:               version.FlapDetection,
 	}
 }
 
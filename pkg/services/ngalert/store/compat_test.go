@@ -2,7 +2,9 @@ package store
 
 import (
 	"testing"
+	"time"
 
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log/logtest"
@@ -23,6 +25,45 @@ func TestAlertRuleToModelsAlertRule(t *testing.T) {
 		}
 	})
 
+	t.Run("make sure inhibiting rules are not lost between conversions", func(t *testing.T) {
+		rule := g.With(g.WithInhibitedBy(
+			ngmodels.RuleInhibition{RuleUID: "db-down", Equal: []string{"cluster", "namespace"}},
+			ngmodels.RuleInhibition{RuleUID: "maintenance"},
+		)).Generate()
+		r, err := alertRuleFromModelsAlertRule(rule)
+		require.NoError(t, err)
+		clone, err := alertRuleToModelsAlertRule(r, &logtest.Fake{})
+		require.NoError(t, err)
+		require.Equal(t, rule.InhibitedBy, clone.InhibitedBy)
+	})
+
+	t.Run("make sure evaluation schedule is not lost between conversions", func(t *testing.T) {
+		rule := g.With(g.WithEvaluationSchedule(&ngmodels.EvaluationSchedule{
+			Cron:     "0 8 * * 1-5",
+			Timezone: "Europe/Berlin",
+			ActiveTimeIntervals: []timeinterval.TimeInterval{{
+				Times:    []timeinterval.TimeRange{{StartMinute: 8 * 60, EndMinute: 18 * 60}},
+				Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 1, End: 5}}},
+			}},
+		})).Generate()
+		r, err := alertRuleFromModelsAlertRule(rule)
+		require.NoError(t, err)
+		clone, err := alertRuleToModelsAlertRule(r, &logtest.Fake{})
+		require.NoError(t, err)
+		// the schedule is parsed when the rule is loaded, so only the stored fields are compared
+		require.Equal(t, rule.EvaluationSchedule.Fingerprint(), clone.EvaluationSchedule.Fingerprint())
+		require.Equal(t, rule.EvaluationSchedule.ActiveTimeIntervals, clone.EvaluationSchedule.ActiveTimeIntervals)
+	})
+
+	t.Run("make sure flap detection is not lost between conversions", func(t *testing.T) {
+		rule := g.With(g.WithFlapDetection(&ngmodels.FlapDetection{Transitions: 4, Window: 30 * time.Minute})).Generate()
+		r, err := alertRuleFromModelsAlertRule(rule)
+		require.NoError(t, err)
+		clone, err := alertRuleToModelsAlertRule(r, &logtest.Fake{})
+		require.NoError(t, err)
+		require.Equal(t, rule.FlapDetection, clone.FlapDetection)
+	})
+
 	t.Run("should use NoData if NoDataState is not known", func(t *testing.T) {
 		rule, err := alertRuleFromModelsAlertRule(g.Generate())
 		require.NoError(t, err)
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// maintenanceWindow is a row of the alert_rule_maintenance_window table.
+type maintenanceWindow struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	OrgID     int64     `xorm:"org_id"`
+	UID       string    `xorm:"uid"`
+	Reason    string    `xorm:"reason"`
+	StartsAt  time.Time `xorm:"starts_at"`
+	EndsAt    time.Time `xorm:"ends_at"`
+	Selector  string    `xorm:"selector"`
+	CreatedBy string    `xorm:"created_by"`
+	Updated   time.Time `xorm:"'updated'"`
+}
+
+func (w maintenanceWindow) TableName() string {
+	return "alert_rule_maintenance_window"
+}
+
+// ListMaintenanceWindows returns the maintenance windows of the organization ordered by start time.
+func (st DBstore) ListMaintenanceWindows(ctx context.Context, orgID int64) ([]*models.MaintenanceWindow, error) {
+	var rows []maintenanceWindow
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("starts_at", "id").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return maintenanceWindowsToModel(rows)
+}
+
+// ListMaintenanceWindowsForScheduling returns the maintenance windows of all organizations that have not ended at the given time.
+func (st DBstore) ListMaintenanceWindowsForScheduling(ctx context.Context, now time.Time) ([]*models.MaintenanceWindow, error) {
+	var rows []maintenanceWindow
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("ends_at > ?", now.UTC()).Asc("starts_at", "id").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return maintenanceWindowsToModel(rows)
+}
+
+// GetMaintenanceWindow returns the maintenance window with the given UID, or models.ErrMaintenanceWindowNotFound.
+func (st DBstore) GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (*models.MaintenanceWindow, error) {
+	var result *models.MaintenanceWindow
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		row := maintenanceWindow{}
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return models.ErrMaintenanceWindowNotFound.Errorf("maintenance window %s not found", uid)
+		}
+		result, err = maintenanceWindowToModel(row)
+		return err
+	})
+	return result, err
+}
+
+// InsertMaintenanceWindow inserts a new maintenance window.
+func (st DBstore) InsertMaintenanceWindow(ctx context.Context, w models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	row, err := maintenanceWindowFromModel(w)
+	if err != nil {
+		return nil, err
+	}
+	row.ID = 0
+	row.Updated = TimeNow()
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(&row); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrMaintenanceWindowInvalid(fmt.Errorf("maintenance window with UID %s already exists", w.UID))
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return maintenanceWindowToModel(row)
+}
+
+// UpdateMaintenanceWindow updates the maintenance window, or returns models.ErrMaintenanceWindowNotFound.
+func (st DBstore) UpdateMaintenanceWindow(ctx context.Context, w models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	row, err := maintenanceWindowFromModel(w)
+	if err != nil {
+		return nil, err
+	}
+	row.Updated = TimeNow()
+	var result *models.MaintenanceWindow
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		updated, err := sess.Where("org_id = ? AND uid = ?", w.OrgID, w.UID).
+			Cols("reason", "starts_at", "ends_at", "selector", "updated").
+			Update(&row)
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return models.ErrMaintenanceWindowNotFound.Errorf("maintenance window %s not found", w.UID)
+		}
+		stored := maintenanceWindow{}
+		if _, err := sess.Where("org_id = ? AND uid = ?", w.OrgID, w.UID).Get(&stored); err != nil {
+			return err
+		}
+		result, err = maintenanceWindowToModel(stored)
+		return err
+	})
+	return result, err
+}
+
+// DeleteMaintenanceWindow deletes the maintenance window, or returns models.ErrMaintenanceWindowNotFound.
+func (st DBstore) DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		deleted, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&maintenanceWindow{})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return models.ErrMaintenanceWindowNotFound.Errorf("maintenance window %s not found", uid)
+		}
+		return nil
+	})
+}
+
+func maintenanceWindowsToModel(rows []maintenanceWindow) ([]*models.MaintenanceWindow, error) {
+	result := make([]*models.MaintenanceWindow, 0, len(rows))
+	for _, row := range rows {
+		w, err := maintenanceWindowToModel(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+	return result, nil
+}
+
+func maintenanceWindowToModel(row maintenanceWindow) (*models.MaintenanceWindow, error) {
+	w := &models.MaintenanceWindow{
+		ID:        row.ID,
+		OrgID:     row.OrgID,
+		UID:       row.UID,
+		Reason:    row.Reason,
+		StartsAt:  row.StartsAt,
+		EndsAt:    row.EndsAt,
+		CreatedBy: row.CreatedBy,
+		Updated:   row.Updated,
+	}
+	if err := json.Unmarshal([]byte(row.Selector), &w.Selector); err != nil {
+		return nil, fmt.Errorf("failed to parse selector of maintenance window %s: %w", row.UID, err)
+	}
+	return w, nil
+}
+
+func maintenanceWindowFromModel(w models.MaintenanceWindow) (maintenanceWindow, error) {
+	selector, err := json.Marshal(w.Selector)
+	if err != nil {
+		return maintenanceWindow{}, fmt.Errorf("failed to serialize selector of maintenance window %s: %w", w.UID, err)
+	}
+	return maintenanceWindow{
+		ID:        w.ID,
+		OrgID:     w.OrgID,
+		UID:       w.UID,
+		Reason:    w.Reason,
+		StartsAt:  w.StartsAt.UTC(),
+		EndsAt:    w.EndsAt.UTC(),
+		Selector:  string(selector),
+		CreatedBy: w.CreatedBy,
+		Updated:   w.Updated,
+	}, nil
+}
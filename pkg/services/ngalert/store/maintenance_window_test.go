@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+	tutil "github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationMaintenanceWindows(t *testing.T) {
+	tutil.SkipIntegrationTestInShortMode(t)
+
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore:       sqlStore,
+		Cfg:            setting.NewCfg().UnifiedAlerting,
+		Logger:         log.New("test-dbstore"),
+		FeatureToggles: featuremgmt.WithFeatures(),
+		Bus:            bus.ProvideBus(tracing.InitializeTracerForTest()),
+	}
+	ctx := context.Background()
+
+	matcher, err := labels.NewMatcher(labels.MatchRegexp, "team", "db|storage")
+	require.NoError(t, err)
+	start := time.Date(2024, 6, 1, 22, 0, 0, 0, time.UTC)
+	window := models.MaintenanceWindow{
+		OrgID:     1,
+		UID:       "upgrade",
+		Reason:    "database upgrade",
+		StartsAt:  start,
+		EndsAt:    start.Add(4 * time.Hour),
+		Selector:  models.AlertRuleSelector{Matchers: labels.Matchers{matcher}, FolderUIDs: []string{"folder"}},
+		CreatedBy: "user",
+	}
+
+	t.Run("insert and get window", func(t *testing.T) {
+		created, err := store.InsertMaintenanceWindow(ctx, window)
+		require.NoError(t, err)
+		require.NotZero(t, created.ID)
+
+		stored, err := store.GetMaintenanceWindow(ctx, 1, "upgrade")
+		require.NoError(t, err)
+		require.Equal(t, window.Reason, stored.Reason)
+		require.True(t, window.StartsAt.Equal(stored.StartsAt))
+		require.True(t, window.EndsAt.Equal(stored.EndsAt))
+		require.Equal(t, window.Selector.FolderUIDs, stored.Selector.FolderUIDs)
+		require.Equal(t, window.Selector.Matchers.String(), stored.Selector.Matchers.String())
+		require.Equal(t, "user", stored.CreatedBy)
+
+		_, err = store.GetMaintenanceWindow(ctx, 2, "upgrade")
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowNotFound)
+	})
+
+	t.Run("insert duplicate UID fails", func(t *testing.T) {
+		_, err := store.InsertMaintenanceWindow(ctx, window)
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowInvalidBase)
+	})
+
+	t.Run("list windows for scheduling", func(t *testing.T) {
+		other := window
+		other.OrgID = 2
+		other.UID = "ended"
+		other.EndsAt = start.Add(time.Hour)
+		_, err := store.InsertMaintenanceWindow(ctx, other)
+		require.NoError(t, err)
+
+		windows, err := store.ListMaintenanceWindowsForScheduling(ctx, start.Add(30*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, windows, 2)
+
+		windows, err = store.ListMaintenanceWindowsForScheduling(ctx, start.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+		require.Equal(t, "upgrade", windows[0].UID)
+
+		windows, err = store.ListMaintenanceWindows(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+		require.Equal(t, "ended", windows[0].UID)
+	})
+
+	t.Run("update window", func(t *testing.T) {
+		update := window
+		update.EndsAt = start.Add(time.Hour)
+		update.Reason = "ended early"
+		updated, err := store.UpdateMaintenanceWindow(ctx, update)
+		require.NoError(t, err)
+		require.Equal(t, "ended early", updated.Reason)
+		require.True(t, update.EndsAt.Equal(updated.EndsAt))
+		require.Equal(t, "user", updated.CreatedBy)
+
+		update.UID = "missing"
+		_, err = store.UpdateMaintenanceWindow(ctx, update)
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowNotFound)
+	})
+
+	t.Run("delete window", func(t *testing.T) {
+		require.NoError(t, store.DeleteMaintenanceWindow(ctx, 1, "upgrade"))
+		_, err := store.GetMaintenanceWindow(ctx, 1, "upgrade")
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowNotFound)
+
+		err = store.DeleteMaintenanceWindow(ctx, 1, "upgrade")
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowNotFound)
+	})
+}
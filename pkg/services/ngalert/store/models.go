@@ -31,6 +31,9 @@ type alertRule struct {
 	NotificationSettings        string `xorm:"notification_settings"`
 	Metadata                    string `xorm:"metadata"`
 	MissingSeriesEvalsToResolve *int64 `xorm:"missing_series_evals_to_resolve"`
+	InhibitedBy                 string `xorm:"inhibited_by"`
+	EvaluationSchedule          string `xorm:"evaluation_schedule"`
+	FlapDetection               string `xorm:"flap_detection"`
 }
 
 func (a alertRule) TableName() string {
@@ -69,6 +72,9 @@ type alertRuleVersion struct {
 	NotificationSettings        string `xorm:"notification_settings"`
 	Metadata                    string `xorm:"metadata"`
 	MissingSeriesEvalsToResolve *int64 `xorm:"missing_series_evals_to_resolve"`
+	InhibitedBy                 string `xorm:"inhibited_by"`
+	EvaluationSchedule          string `xorm:"evaluation_schedule"`
+	FlapDetection               string `xorm:"flap_detection"`
 	Message                     string
 }
 
@@ -94,7 +100,10 @@ func (a alertRuleVersion) EqualSpec(b alertRuleVersion) bool {
 		a.IsPaused == b.IsPaused &&
 		a.NotificationSettings == b.NotificationSettings &&
 		a.Metadata == b.Metadata &&
-		compareInt64Pointer(a.MissingSeriesEvalsToResolve, b.MissingSeriesEvalsToResolve)
+		compareInt64Pointer(a.MissingSeriesEvalsToResolve, b.MissingSeriesEvalsToResolve) &&
+		a.InhibitedBy == b.InhibitedBy &&
+		a.EvaluationSchedule == b.EvaluationSchedule &&
+		a.FlapDetection == b.FlapDetection
 }
 
 func compareInt64Pointer(a, b *int64) bool {
@@ -0,0 +1,242 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ruleTemplate is a row of the alert_rule_template table.
+type ruleTemplate struct {
+	ID          int64     `xorm:"pk autoincr 'id'"`
+	OrgID       int64     `xorm:"org_id"`
+	UID         string    `xorm:"uid"`
+	Title       string    `xorm:"title"`
+	Description string    `xorm:"description"`
+	Parameters  string    `xorm:"parameters"`
+	Rule        string    `xorm:"rule"`
+	Version     int64     `xorm:"'version'"`
+	Updated     time.Time `xorm:"'updated'"`
+}
+
+func (t ruleTemplate) TableName() string {
+	return "alert_rule_template"
+}
+
+// ruleTemplateInstance is a row of the alert_rule_template_instance table.
+type ruleTemplateInstance struct {
+	ID              int64  `xorm:"pk autoincr 'id'"`
+	OrgID           int64  `xorm:"org_id"`
+	TemplateUID     string `xorm:"template_uid"`
+	RuleUID         string `xorm:"rule_uid"`
+	ParameterValues string `xorm:"parameter_values"`
+	TemplateVersion int64  `xorm:"template_version"`
+}
+
+func (i ruleTemplateInstance) TableName() string {
+	return "alert_rule_template_instance"
+}
+
+// ListRuleTemplates returns the rule templates of the organization ordered by title.
+func (st DBstore) ListRuleTemplates(ctx context.Context, orgID int64) ([]*models.RuleTemplate, error) {
+	var rows []ruleTemplate
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("title", "id").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.RuleTemplate, 0, len(rows))
+	for _, row := range rows {
+		t, err := ruleTemplateToModel(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// GetRuleTemplate returns the rule template with the given UID, or models.ErrRuleTemplateNotFound.
+func (st DBstore) GetRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.RuleTemplate, error) {
+	var result *models.RuleTemplate
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		row := ruleTemplate{}
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return models.ErrRuleTemplateNotFound.Errorf("rule template %s not found", uid)
+		}
+		result, err = ruleTemplateToModel(row)
+		return err
+	})
+	return result, err
+}
+
+// InsertRuleTemplate inserts a new rule template with version 1.
+func (st DBstore) InsertRuleTemplate(ctx context.Context, t models.RuleTemplate) (*models.RuleTemplate, error) {
+	row, err := ruleTemplateFromModel(t)
+	if err != nil {
+		return nil, err
+	}
+	row.ID = 0
+	row.Version = 1
+	row.Updated = TimeNow()
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(&row); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrRuleTemplateInvalid(fmt.Errorf("rule template with UID %s already exists", t.UID))
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ruleTemplateToModel(row)
+}
+
+// UpdateRuleTemplate updates the rule template and increments its version. The version of the template must
+// match the stored one, otherwise ErrOptimisticLock is returned.
+func (st DBstore) UpdateRuleTemplate(ctx context.Context, t models.RuleTemplate) (*models.RuleTemplate, error) {
+	row, err := ruleTemplateFromModel(t)
+	if err != nil {
+		return nil, err
+	}
+	row.Version = t.Version + 1
+	row.Updated = TimeNow()
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		updated, err := sess.Where("org_id = ? AND uid = ? AND version = ?", t.OrgID, t.UID, t.Version).
+			Cols("title", "description", "parameters", "rule", "version", "updated").
+			Update(&row)
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return ErrOptimisticLock
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ruleTemplateToModel(row)
+}
+
+// DeleteRuleTemplate deletes the rule template. It does not delete the instances of the template.
+func (st DBstore) DeleteRuleTemplate(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&ruleTemplate{})
+		return err
+	})
+}
+
+// ListRuleTemplateInstances returns the instances of the rule template.
+func (st DBstore) ListRuleTemplateInstances(ctx context.Context, orgID int64, templateUID string) ([]models.RuleTemplateInstance, error) {
+	var rows []ruleTemplateInstance
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND template_uid = ?", orgID, templateUID).Asc("id").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.RuleTemplateInstance, 0, len(rows))
+	for _, row := range rows {
+		instance := models.RuleTemplateInstance{
+			OrgID:           row.OrgID,
+			TemplateUID:     row.TemplateUID,
+			RuleUID:         row.RuleUID,
+			TemplateVersion: row.TemplateVersion,
+		}
+		if row.ParameterValues != "" {
+			if err := json.Unmarshal([]byte(row.ParameterValues), &instance.Values); err != nil {
+				return nil, fmt.Errorf("failed to parse parameter values of rule %s: %w", row.RuleUID, err)
+			}
+		}
+		result = append(result, instance)
+	}
+	return result, nil
+}
+
+// UpsertRuleTemplateInstances inserts or updates the instances. An alert rule can be the instance of one template only.
+func (st DBstore) UpsertRuleTemplateInstances(ctx context.Context, instances ...models.RuleTemplateInstance) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, instance := range instances {
+			row := ruleTemplateInstance{
+				OrgID:           instance.OrgID,
+				TemplateUID:     instance.TemplateUID,
+				RuleUID:         instance.RuleUID,
+				TemplateVersion: instance.TemplateVersion,
+			}
+			if len(instance.Values) > 0 {
+				values, err := json.Marshal(instance.Values)
+				if err != nil {
+					return fmt.Errorf("failed to serialize parameter values of rule %s: %w", instance.RuleUID, err)
+				}
+				row.ParameterValues = string(values)
+			}
+			exists, err := sess.Where("org_id = ? AND rule_uid = ?", row.OrgID, row.RuleUID).Exist(&ruleTemplateInstance{})
+			if err != nil {
+				return err
+			}
+			if exists {
+				_, err = sess.Where("org_id = ? AND rule_uid = ?", row.OrgID, row.RuleUID).
+					Cols("template_uid", "parameter_values", "template_version").
+					Update(&row)
+			} else {
+				_, err = sess.Insert(&row)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func ruleTemplateToModel(row ruleTemplate) (*models.RuleTemplate, error) {
+	t := &models.RuleTemplate{
+		ID:          row.ID,
+		OrgID:       row.OrgID,
+		UID:         row.UID,
+		Title:       row.Title,
+		Description: row.Description,
+		Rule:        json.RawMessage(row.Rule),
+		Version:     row.Version,
+		Updated:     row.Updated,
+	}
+	if row.Parameters != "" {
+		if err := json.Unmarshal([]byte(row.Parameters), &t.Parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse parameters of rule template %s: %w", row.UID, err)
+		}
+	}
+	return t, nil
+}
+
+func ruleTemplateFromModel(t models.RuleTemplate) (ruleTemplate, error) {
+	row := ruleTemplate{
+		ID:          t.ID,
+		OrgID:       t.OrgID,
+		UID:         t.UID,
+		Title:       t.Title,
+		Description: t.Description,
+		Rule:        string(t.Rule),
+		Version:     t.Version,
+		Updated:     t.Updated,
+	}
+	if len(t.Parameters) > 0 {
+		params, err := json.Marshal(t.Parameters)
+		if err != nil {
+			return ruleTemplate{}, fmt.Errorf("failed to serialize parameters of rule template %s: %w", t.UID, err)
+		}
+		row.Parameters = string(params)
+	}
+	return row, nil
+}
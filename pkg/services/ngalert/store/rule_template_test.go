@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+	tutil "github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationRuleTemplates(t *testing.T) {
+	tutil.SkipIntegrationTestInShortMode(t)
+
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore:       sqlStore,
+		Cfg:            setting.NewCfg().UnifiedAlerting,
+		Logger:         log.New("test-dbstore"),
+		FeatureToggles: featuremgmt.WithFeatures(),
+		Bus:            bus.ProvideBus(tracing.InitializeTracerForTest()),
+	}
+	ctx := context.Background()
+
+	tmpl := models.RuleTemplate{
+		OrgID:       1,
+		UID:         "high-cpu",
+		Title:       "High CPU",
+		Description: "CPU usage is above the threshold",
+		Parameters: []models.RuleTemplateParameter{
+			{Name: "threshold", Type: models.RuleTemplateParameterTypeNumber, Default: util.Pointer("80")},
+			{Name: "team", Type: models.RuleTemplateParameterTypeString},
+		},
+		Rule: json.RawMessage(`{"title":"High CPU ${team}"}`),
+	}
+
+	t.Run("insert and get template", func(t *testing.T) {
+		created, err := store.InsertRuleTemplate(ctx, tmpl)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), created.Version)
+		require.NotZero(t, created.ID)
+
+		stored, err := store.GetRuleTemplate(ctx, 1, "high-cpu")
+		require.NoError(t, err)
+		require.Equal(t, tmpl.Parameters, stored.Parameters)
+		require.JSONEq(t, string(tmpl.Rule), string(stored.Rule))
+		require.Equal(t, tmpl.Description, stored.Description)
+
+		_, err = store.GetRuleTemplate(ctx, 2, "high-cpu")
+		require.ErrorIs(t, err, models.ErrRuleTemplateNotFound)
+	})
+
+	t.Run("insert fails if the UID exists", func(t *testing.T) {
+		_, err := store.InsertRuleTemplate(ctx, tmpl)
+		require.ErrorIs(t, err, models.ErrRuleTemplateInvalidBase)
+	})
+
+	t.Run("update checks the version", func(t *testing.T) {
+		update := tmpl
+		update.Title = "High CPU usage"
+		update.Version = 1
+		updated, err := store.UpdateRuleTemplate(ctx, update)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), updated.Version)
+
+		_, err = store.UpdateRuleTemplate(ctx, update)
+		require.ErrorIs(t, err, ErrOptimisticLock)
+
+		templates, err := store.ListRuleTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		require.Equal(t, "High CPU usage", templates[0].Title)
+	})
+
+	t.Run("upsert and list instances", func(t *testing.T) {
+		require.NoError(t, store.UpsertRuleTemplateInstances(ctx,
+			models.RuleTemplateInstance{OrgID: 1, TemplateUID: "high-cpu", RuleUID: "rule-1", Values: map[string]string{"team": "a"}, TemplateVersion: 1},
+			models.RuleTemplateInstance{OrgID: 1, TemplateUID: "high-cpu", RuleUID: "rule-2", Values: map[string]string{"team": "b"}, TemplateVersion: 1},
+		))
+		require.NoError(t, store.UpsertRuleTemplateInstances(ctx,
+			models.RuleTemplateInstance{OrgID: 1, TemplateUID: "high-cpu", RuleUID: "rule-1", Values: map[string]string{"team": "c"}, TemplateVersion: 2},
+		))
+
+		instances, err := store.ListRuleTemplateInstances(ctx, 1, "high-cpu")
+		require.NoError(t, err)
+		require.Equal(t, []models.RuleTemplateInstance{
+			{OrgID: 1, TemplateUID: "high-cpu", RuleUID: "rule-1", Values: map[string]string{"team": "c"}, TemplateVersion: 2},
+			{OrgID: 1, TemplateUID: "high-cpu", RuleUID: "rule-2", Values: map[string]string{"team": "b"}, TemplateVersion: 1},
+		}, instances)
+	})
+
+	t.Run("deleting rules deletes their instances", func(t *testing.T) {
+		require.NoError(t, store.DeleteAlertRulesByUID(ctx, 1, nil, true, "rule-2"))
+
+		instances, err := store.ListRuleTemplateInstances(ctx, 1, "high-cpu")
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+		require.Equal(t, "rule-1", instances[0].RuleUID)
+	})
+
+	t.Run("delete template", func(t *testing.T) {
+		require.NoError(t, store.DeleteRuleTemplate(ctx, 1, "high-cpu"))
+		_, err := store.GetRuleTemplate(ctx, 1, "high-cpu")
+		require.ErrorIs(t, err, models.ErrRuleTemplateNotFound)
+	})
+}
@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+const stateHistoryTable = "alert_state_history"
+
+// stateHistoryRow is a row of the alert_state_history table.
+type stateHistoryRow struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	OrgID        int64  `xorm:"org_id"`
+	RuleUID      string `xorm:"rule_uid"`
+	RuleGroup    string `xorm:"rule_group"`
+	FolderUID    string `xorm:"folder_uid"`
+	DashboardUID string `xorm:"dashboard_uid"`
+	PanelID      int64  `xorm:"panel_id"`
+	Previous     string `xorm:"previous_state"`
+	Current      string `xorm:"current_state"`
+	// EvaluatedAt is in nanoseconds since the epoch.
+	EvaluatedAt int64  `xorm:"evaluated_at"`
+	Line        string `xorm:"line"`
+}
+
+// SaveStateHistory inserts the state history entries to the database.
+func (st DBstore) SaveStateHistory(ctx context.Context, entries []models.StateHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	rows := make([]stateHistoryRow, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, stateHistoryRow{
+			OrgID:        e.OrgID,
+			RuleUID:      e.RuleUID,
+			RuleGroup:    e.RuleGroup,
+			FolderUID:    e.FolderUID,
+			DashboardUID: e.DashboardUID,
+			PanelID:      e.PanelID,
+			Previous:     e.Previous,
+			Current:      e.Current,
+			EvaluatedAt:  e.EvaluatedAt.UnixNano(),
+			Line:         e.Line,
+		})
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.BulkInsert(stateHistoryTable, rows, sqlstore.NativeSettingsForDialect(st.SQLStore.GetDialect()))
+		return err
+	})
+}
+
+// GetStateHistory returns the state history entries that match the query, ordered by evaluation time.
+// If the query has a limit, the most recent entries are returned.
+func (st DBstore) GetStateHistory(ctx context.Context, query models.StateHistoryEntriesQuery) ([]models.StateHistoryEntry, error) {
+	var rows []stateHistoryRow
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(stateHistoryTable).Where("org_id = ?", query.OrgID)
+		if !query.From.IsZero() {
+			q = q.And("evaluated_at >= ?", query.From.UnixNano())
+		}
+		if !query.To.IsZero() {
+			q = q.And("evaluated_at <= ?", query.To.UnixNano())
+		}
+		if query.RuleUID != "" {
+			q = q.And("rule_uid = ?", query.RuleUID)
+		}
+		if query.DashboardUID != "" {
+			q = q.And("dashboard_uid = ?", query.DashboardUID)
+		}
+		if query.PanelID != 0 {
+			q = q.And("panel_id = ?", query.PanelID)
+		}
+		if len(query.FolderUIDs) > 0 {
+			args, in := getINSubQueryArgs(query.FolderUIDs)
+			q = q.And(fmt.Sprintf("folder_uid IN (%s)", strings.Join(in, ",")), args...)
+		}
+		if query.Previous != "" {
+			q = q.And("previous_state LIKE ?", query.Previous+"%")
+		}
+		if query.Current != "" {
+			q = q.And("current_state LIKE ?", query.Current+"%")
+		}
+		if query.Before != nil {
+			before := query.Before.EvaluatedAt.UnixNano()
+			q = q.And("(evaluated_at < ? OR (evaluated_at = ? AND id < ?))", before, before, query.Before.ID)
+		}
+		q = q.Desc("evaluated_at", "id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		return q.Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.StateHistoryEntry, len(rows))
+	// rows are in descending order, entries are returned in ascending order
+	for i, r := range rows {
+		entries[len(rows)-1-i] = models.StateHistoryEntry{
+			ID:           r.ID,
+			OrgID:        r.OrgID,
+			RuleUID:      r.RuleUID,
+			RuleGroup:    r.RuleGroup,
+			FolderUID:    r.FolderUID,
+			DashboardUID: r.DashboardUID,
+			PanelID:      r.PanelID,
+			Previous:     r.Previous,
+			Current:      r.Current,
+			EvaluatedAt:  time.Unix(0, r.EvaluatedAt).UTC(),
+			Line:         r.Line,
+		}
+	}
+	return entries, nil
+}
+
+// CleanUpStateHistory deletes the state history entries that are older than the configured retention.
+// It returns the number of deleted entries.
+func (st DBstore) CleanUpStateHistory(ctx context.Context) (int64, error) {
+	retention := st.Cfg.StateHistory.SQLRetention
+	if retention <= 0 {
+		return 0, nil
+	}
+	affectedRows := int64(-1)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		expire := TimeNow().Add(-retention)
+		st.Logger.Debug("Remove expired alert state history", "evaluatedBefore", expire)
+		result, err := sess.Exec("DELETE FROM alert_state_history WHERE evaluated_at < ?", expire.UnixNano())
+		if err != nil {
+			return err
+		}
+		affectedRows, err = result.RowsAffected()
+		if err != nil {
+			st.Logger.Warn("Failed to get rows affected by the delete operation", "error", err)
+		}
+		return nil
+	})
+	return affectedRows, err
+}
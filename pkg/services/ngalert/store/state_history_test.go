@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+	tutil "github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationStateHistory(t *testing.T) {
+	tutil.SkipIntegrationTestInShortMode(t)
+
+	oldClk := TimeNow
+	t.Cleanup(func() {
+		TimeNow = oldClk
+	})
+	t0 := time.Now().UTC().Truncate(time.Second)
+	TimeNow = func() time.Time {
+		return t0
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.StateHistory.SQLRetention = time.Hour
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Cfg:      cfg.UnifiedAlerting,
+		Logger:   log.New("test-dbstore"),
+	}
+
+	entry := func(orgID int64, ruleUID, folderUID, current string, at time.Time) models.StateHistoryEntry {
+		return models.StateHistoryEntry{
+			OrgID:       orgID,
+			RuleUID:     ruleUID,
+			RuleGroup:   "group",
+			FolderUID:   folderUID,
+			Previous:    "Normal",
+			Current:     current,
+			EvaluatedAt: at,
+			Line:        `{"ruleUID":"` + ruleUID + `"}`,
+		}
+	}
+	require.NoError(t, store.SaveStateHistory(context.Background(), []models.StateHistoryEntry{
+		entry(1, "rule-1", "folder-1", "Alerting", t0.Add(-2*time.Hour)),
+		entry(1, "rule-1", "folder-1", "Alerting", t0.Add(-30*time.Minute)),
+		entry(1, "rule-1", "folder-1", "Normal (MissingSeries)", t0.Add(-20*time.Minute)),
+		entry(1, "rule-2", "folder-2", "Alerting", t0.Add(-10*time.Minute)),
+		entry(2, "rule-3", "folder-1", "Alerting", t0.Add(-10*time.Minute)),
+	}))
+
+	t.Run("returns entries of the org in ascending order", func(t *testing.T) {
+		res, err := store.GetStateHistory(context.Background(), models.StateHistoryEntriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, res, 4)
+		for i := 1; i < len(res); i++ {
+			require.True(t, res[i-1].EvaluatedAt.Before(res[i].EvaluatedAt))
+		}
+		require.Equal(t, t0.Add(-2*time.Hour), res[0].EvaluatedAt)
+	})
+
+	t.Run("filters entries", func(t *testing.T) {
+		res, err := store.GetStateHistory(context.Background(), models.StateHistoryEntriesQuery{
+			OrgID:      1,
+			RuleUID:    "rule-1",
+			FolderUIDs: []string{"folder-1"},
+			Current:    "Normal",
+			From:       t0.Add(-time.Hour),
+			To:         t0,
+		})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, "Normal (MissingSeries)", res[0].Current)
+	})
+
+	t.Run("limit returns the most recent entries", func(t *testing.T) {
+		res, err := store.GetStateHistory(context.Background(), models.StateHistoryEntriesQuery{OrgID: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		require.Equal(t, t0.Add(-20*time.Minute), res[0].EvaluatedAt)
+		require.Equal(t, t0.Add(-10*time.Minute), res[1].EvaluatedAt)
+	})
+
+	t.Run("before pages through the entries from the most recent one", func(t *testing.T) {
+		page, err := store.GetStateHistory(context.Background(), models.StateHistoryEntriesQuery{OrgID: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		require.NotZero(t, page[0].ID)
+
+		res, err := store.GetStateHistory(context.Background(), models.StateHistoryEntriesQuery{OrgID: 1, Limit: 2, Before: &page[0]})
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		require.Equal(t, t0.Add(-2*time.Hour), res[0].EvaluatedAt)
+		require.Equal(t, t0.Add(-30*time.Minute), res[1].EvaluatedAt)
+	})
+
+	t.Run("clean up deletes entries older than the retention", func(t *testing.T) {
+		affected, err := store.CleanUpStateHistory(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(1), affected)
+
+		res, err := store.GetStateHistory(context.Background(), models.StateHistoryEntriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, res, 3)
+	})
+}
@@ -269,9 +269,29 @@ func uidKey(orgID int64, uid string) string {
 }
 
 func (w *DatasourceWriter) WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	writer, err := w.getWriter(ctx, orgID, dsUID)
+	if err != nil {
+		return err
+	}
+
+	return writer.Write(ctx, name, t, frames, orgID, extraLabels)
+}
+
+// WritePointsDatasource writes the points to the data source. It is used to replay the points buffered while
+// the data source was not reachable.
+func (w *DatasourceWriter) WritePointsDatasource(ctx context.Context, dsUID string, points []Point, orgID int64) error {
+	writer, err := w.getWriter(ctx, orgID, dsUID)
+	if err != nil {
+		return err
+	}
+
+	return writer.WritePoints(ctx, points, orgID)
+}
+
+func (w *DatasourceWriter) getWriter(ctx context.Context, orgID int64, dsUID string) (*PrometheusWriter, error) {
 	if dsUID == "" {
 		if w.cfg.DefaultDatasourceUID == "" {
-			return errors.New("data source uid not specified and no default set")
+			return nil, errors.New("data source uid not specified and no default set")
 		}
 		dsUID = w.cfg.DefaultDatasourceUID
 		w.l.Debug("Using default data source for remote write",
@@ -280,26 +300,22 @@ func (w *DatasourceWriter) WriteDatasource(ctx context.Context, dsUID string, na
 
 	key := uidKey(orgID, dsUID)
 
-	var writer *PrometheusWriter
-
 	val, ok := w.writers.Get(key)
 	if ok {
-		var ok bool
-		writer, ok = val.(*PrometheusWriter)
+		writer, ok := val.(*PrometheusWriter)
 		if !ok {
-			return errors.New("type in cache not a Writer")
-		}
-	} else {
-		var err error
-		writer, err = w.makeWriter(ctx, orgID, dsUID)
-		if err != nil {
-			w.l.Error("Failed to create writer for data source",
-				"org_id", orgID, "datasource_uid", dsUID)
-			return err
+			return nil, errors.New("type in cache not a Writer")
 		}
+		return writer, nil
+	}
 
-		w.writers.Set(key, writer, 0)
+	writer, err := w.makeWriter(ctx, orgID, dsUID)
+	if err != nil {
+		w.l.Error("Failed to create writer for data source",
+			"org_id", orgID, "datasource_uid", dsUID)
+		return nil, err
 	}
 
-	return writer.Write(ctx, name, t, frames, orgID, extraLabels)
+	w.writers.Set(key, writer, 0)
+	return writer, nil
 }
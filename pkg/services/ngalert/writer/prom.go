@@ -302,14 +302,19 @@ func (w PrometheusWriter) WriteDatasource(ctx context.Context, dsUID string, nam
 
 // Write writes the given frames to the Prometheus remote write endpoint.
 func (w PrometheusWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
-	l := w.logger.FromContext(ctx)
-	lvs := []string{fmt.Sprint(orgID), string(w.backendType)}
-
 	points, err := PointsFromFrames(name, t, frames, extraLabels)
 	if err != nil {
 		return errors.Join(ErrBadFrame, err)
 	}
 
+	return w.WritePoints(ctx, points, orgID)
+}
+
+// WritePoints writes the given points to the Prometheus remote write endpoint.
+func (w PrometheusWriter) WritePoints(ctx context.Context, points []Point, orgID int64) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), string(w.backendType)}
+
 	series := make([]promremote.TimeSeries, 0, len(points))
 	for _, p := range points {
 		series = append(series, promremote.TimeSeries{
@@ -321,7 +326,7 @@ func (w PrometheusWriter) Write(ctx context.Context, name string, t time.Time, f
 		})
 	}
 
-	l.Debug("Writing metric", "name", name)
+	l.Debug("Writing points", "count", len(points))
 	writeStart := w.clock.Now()
 	res, writeErr := w.client.WriteTimeSeries(ctx, series, promremote.WriteOptions{})
 	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
@@ -0,0 +1,431 @@
+package writer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+const walFileExtension = ".wal"
+
+// PointsWriter writes recording rule results to a data source, either as frames or as points.
+type PointsWriter interface {
+	WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+	WritePointsDatasource(ctx context.Context, dsUID string, points []Point, orgID int64) error
+}
+
+type WALWriterConfig struct {
+	// Dir is the directory where the write-ahead log is stored.
+	Dir string
+
+	// MaxSizeBytes is the maximum size of the write-ahead log. Points that do not fit are discarded.
+	// Zero means no limit.
+	MaxSizeBytes int64
+
+	// ReplayInterval is the time between attempts to replay the buffered points.
+	ReplayInterval time.Duration
+}
+
+// walRecord is a single line of a write-ahead log file.
+type walRecord struct {
+	OrgID         int64   `json:"org_id"`
+	DatasourceUID string  `json:"datasource_uid"`
+	Points        []Point `json:"points"`
+}
+
+// walSegment is the write-ahead log of a single target data source. The mutex protects the log file and the
+// counters, and is never held while writing to the data source.
+type walSegment struct {
+	mtx       sync.Mutex
+	orgID     int64
+	dsUID     string
+	path      string
+	size      int64
+	points    int
+	replaying bool
+}
+
+// WALWriter writes recording rule results to data sources. When a data source cannot be reached,
+// the results are buffered in a write-ahead log on disk and replayed in order once it is reachable again.
+type WALWriter struct {
+	cfg     WALWriterConfig
+	writer  PointsWriter
+	clock   clock.Clock
+	l       log.Logger
+	metrics *metrics.RemoteWriter
+
+	mtx      sync.Mutex
+	segments map[string]*walSegment
+	size     int64
+}
+
+// NewWALWriter creates a WALWriter and loads the write-ahead log left over from a previous run.
+func NewWALWriter(cfg WALWriterConfig, writer PointsWriter, clock clock.Clock, l log.Logger, metrics *metrics.RemoteWriter) (*WALWriter, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("write-ahead log directory is not set")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create write-ahead log directory: %w", err)
+	}
+	w := &WALWriter{
+		cfg:      cfg,
+		writer:   writer,
+		clock:    clock,
+		l:        l,
+		metrics:  metrics,
+		segments: make(map[string]*walSegment),
+	}
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// WriteDatasource writes the frames to the data source. If the data source cannot be reached, or there are
+// points for it in the write-ahead log that are not replayed yet, the points are appended to the log instead.
+func (w *WALWriter) WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	return w.WritePointsDatasource(ctx, dsUID, points, orgID)
+}
+
+// WritePointsDatasource writes the points to the data source, or appends them to the write-ahead log.
+func (w *WALWriter) WritePointsDatasource(ctx context.Context, dsUID string, points []Point, orgID int64) error {
+	l := w.l.FromContext(ctx).New("org_id", orgID, "datasource_uid", dsUID)
+	seg := w.segment(orgID, dsUID)
+
+	// Keep the points in order if older points are still waiting to be replayed.
+	buffered, err := w.appendIfPending(seg, points)
+	if err != nil {
+		l.Warn("Failed to buffer points in the write-ahead log", "error", err, "points", len(points))
+		return err
+	}
+	if buffered {
+		l.Debug("Buffered points in the write-ahead log behind pending points", "points", len(points))
+		return nil
+	}
+
+	writeErr := w.writer.WritePointsDatasource(ctx, dsUID, points, orgID)
+	if writeErr == nil || !isRetryableWriteError(writeErr) {
+		return writeErr
+	}
+	seg.mtx.Lock()
+	err = w.appendLocked(seg, points)
+	seg.mtx.Unlock()
+	if err != nil {
+		l.Warn("Failed to buffer points in the write-ahead log", "error", err, "points", len(points))
+		return writeErr
+	}
+	l.Warn("Data source is not reachable, buffered points in the write-ahead log", "error", writeErr, "points", len(points))
+	return nil
+}
+
+// appendIfPending appends the points to the write-ahead log of the segment if it has points that are not replayed yet.
+func (w *WALWriter) appendIfPending(seg *walSegment, points []Point) (bool, error) {
+	seg.mtx.Lock()
+	defer seg.mtx.Unlock()
+	if seg.points == 0 {
+		return false, nil
+	}
+	return true, w.appendLocked(seg, points)
+}
+
+// Run replays the write-ahead log at every replay interval until the context is cancelled.
+func (w *WALWriter) Run(ctx context.Context) error {
+	w.Replay(ctx)
+
+	ticker := w.clock.Ticker(w.cfg.ReplayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.Replay(ctx)
+		}
+	}
+}
+
+// Replay writes the buffered points of every data source in the order they were buffered. It stops at the
+// first data source error that is worth retrying, and keeps the remaining points for the next attempt.
+func (w *WALWriter) Replay(ctx context.Context) {
+	w.mtx.Lock()
+	segments := make([]*walSegment, 0, len(w.segments))
+	for _, seg := range w.segments {
+		segments = append(segments, seg)
+	}
+	w.mtx.Unlock()
+
+	for _, seg := range segments {
+		if ctx.Err() != nil {
+			return
+		}
+		w.replaySegment(ctx, seg)
+	}
+}
+
+func (w *WALWriter) replaySegment(ctx context.Context, seg *walSegment) {
+	seg.mtx.Lock()
+	if seg.points == 0 || seg.replaying {
+		seg.mtx.Unlock()
+		return
+	}
+	seg.replaying = true
+	records, err := readWALRecords(seg.path)
+	seg.mtx.Unlock()
+	defer func() {
+		seg.mtx.Lock()
+		seg.replaying = false
+		seg.mtx.Unlock()
+	}()
+
+	l := w.l.FromContext(ctx).New("org_id", seg.orgID, "datasource_uid", seg.dsUID)
+	if err != nil {
+		l.Error("Failed to read the write-ahead log, the remaining points are discarded", "error", err)
+	}
+
+	// The records are written without holding the lock, so points written in the meantime are appended to the log
+	// behind them, and only the replayed records are removed from the log afterwards.
+	orgLabel := fmt.Sprint(seg.orgID)
+	replayed := 0
+	for _, rec := range records {
+		err := w.writer.WritePointsDatasource(ctx, seg.dsUID, rec.Points, seg.orgID)
+		if err != nil && isRetryableWriteError(err) {
+			l.Debug("Data source is still not reachable, stopped replaying the write-ahead log", "error", err)
+			break
+		}
+		if err != nil {
+			l.Error("Data source rejected points from the write-ahead log, the points are discarded", "error", err, "points", len(rec.Points))
+			w.metrics.WALDroppedPoints.WithLabelValues(orgLabel, "rejected").Add(float64(len(rec.Points)))
+		} else {
+			w.metrics.WALReplayedPoints.WithLabelValues(orgLabel).Add(float64(len(rec.Points)))
+		}
+		replayed++
+	}
+	if replayed == 0 && err == nil {
+		return
+	}
+
+	seg.mtx.Lock()
+	defer seg.mtx.Unlock()
+	if err := w.trimLocked(seg, replayed); err != nil {
+		l.Error("Failed to update the write-ahead log, points may be replayed again", "error", err)
+		return
+	}
+	l.Info("Replayed the write-ahead log", "batches", replayed, "pending_points", seg.points)
+}
+
+func (w *WALWriter) segment(orgID int64, dsUID string) *walSegment {
+	name := fmt.Sprintf("%d-%s%s", orgID, url.PathEscape(dsUID), walFileExtension)
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	seg, ok := w.segments[name]
+	if !ok {
+		seg = &walSegment{orgID: orgID, dsUID: dsUID, path: filepath.Join(w.cfg.Dir, name)}
+		w.segments[name] = seg
+	}
+	return seg
+}
+
+// appendLocked appends the points to the write-ahead log of the segment. The segment must be locked.
+func (w *WALWriter) appendLocked(seg *walSegment, points []Point) error {
+	b, err := json.Marshal(walRecord{OrgID: seg.orgID, DatasourceUID: seg.dsUID, Points: points})
+	if err != nil {
+		return fmt.Errorf("failed to encode points: %w", err)
+	}
+	b = append(b, '\n')
+
+	if !w.reserve(int64(len(b))) {
+		w.metrics.WALDroppedPoints.WithLabelValues(fmt.Sprint(seg.orgID), "full").Add(float64(len(points)))
+		return fmt.Errorf("write-ahead log is full (%d bytes)", w.cfg.MaxSizeBytes)
+	}
+
+	f, err := os.OpenFile(seg.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		w.release(int64(len(b)))
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		w.release(int64(len(b)))
+		return err
+	}
+	if err := f.Close(); err != nil {
+		w.release(int64(len(b)))
+		return err
+	}
+
+	seg.size += int64(len(b))
+	seg.points += len(points)
+	w.metrics.WALPoints.WithLabelValues(fmt.Sprint(seg.orgID)).Add(float64(len(points)))
+	return nil
+}
+
+// trimLocked removes the first n records from the write-ahead log of the segment, and the records that cannot be read.
+// The segment must be locked.
+func (w *WALWriter) trimLocked(seg *walSegment, n int) error {
+	// Errors are already reported when the log is replayed, the records after a corruption are lost.
+	records, _ := readWALRecords(seg.path)
+	return w.rewriteLocked(seg, records[min(n, len(records)):])
+}
+
+// rewriteLocked replaces the write-ahead log of the segment with the given records. The segment must be locked.
+func (w *WALWriter) rewriteLocked(seg *walSegment, records []walRecord) error {
+	var size int64
+	points := 0
+	if len(records) == 0 {
+		if err := os.Remove(seg.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	} else {
+		tmp := seg.path + ".tmp"
+		f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+		if err != nil {
+			return err
+		}
+		bw := bufio.NewWriter(f)
+		for _, rec := range records {
+			b, err := json.Marshal(rec)
+			if err != nil {
+				_ = f.Close()
+				return err
+			}
+			b = append(b, '\n')
+			if _, err := bw.Write(b); err != nil {
+				_ = f.Close()
+				return err
+			}
+			size += int64(len(b))
+			points += len(rec.Points)
+		}
+		if err := bw.Flush(); err != nil {
+			_ = f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, seg.path); err != nil {
+			return err
+		}
+	}
+
+	w.release(seg.size - size)
+	w.metrics.WALPoints.WithLabelValues(fmt.Sprint(seg.orgID)).Sub(float64(seg.points - points))
+	seg.size = size
+	seg.points = points
+	return nil
+}
+
+// reserve accounts for n more bytes in the write-ahead log, unless that would exceed the maximum size.
+func (w *WALWriter) reserve(n int64) bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.cfg.MaxSizeBytes > 0 && w.size+n > w.cfg.MaxSizeBytes {
+		return false
+	}
+	w.size += n
+	w.metrics.WALBytes.Set(float64(w.size))
+	return true
+}
+
+func (w *WALWriter) release(n int64) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.size -= n
+	w.metrics.WALBytes.Set(float64(w.size))
+}
+
+// load restores the segments of the write-ahead log files in the directory. It must be called before the writer is used.
+func (w *WALWriter) load() error {
+	entries, err := os.ReadDir(w.cfg.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read write-ahead log directory: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), walFileExtension) {
+			continue
+		}
+		path := filepath.Join(w.cfg.Dir, e.Name())
+		records, err := readWALRecords(path)
+		if err != nil {
+			w.l.Warn("Write-ahead log file is corrupted, keeping the readable points", "file", path, "error", err)
+		}
+		if len(records) == 0 {
+			_ = os.Remove(path)
+			continue
+		}
+
+		seg := w.segment(records[0].OrgID, records[0].DatasourceUID)
+		if seg.path != path {
+			w.l.Warn("Ignoring write-ahead log file with unexpected name", "file", path)
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return fmt.Errorf("failed to read write-ahead log file: %w", err)
+		}
+		seg.size = info.Size()
+		for _, rec := range records {
+			seg.points += len(rec.Points)
+		}
+		w.size += seg.size
+		w.metrics.WALBytes.Set(float64(w.size))
+		w.metrics.WALPoints.WithLabelValues(fmt.Sprint(seg.orgID)).Add(float64(seg.points))
+		w.l.Info("Loaded write-ahead log", "org_id", seg.orgID, "datasource_uid", seg.dsUID, "points", seg.points)
+	}
+	return nil
+}
+
+// readWALRecords reads the records of a write-ahead log file. If the file is corrupted, for example
+// because Grafana stopped in the middle of a write, it returns the records before the corruption and an error.
+func readWALRecords(path string) ([]walRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var records []walRecord
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var rec walRecord
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return records, nil
+			}
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// isRetryableWriteError returns true if the write failed because the data source could not be reached
+// or failed unexpectedly, and the same points are likely to be accepted later.
+func isRetryableWriteError(err error) bool {
+	return errors.Is(err, ErrConnectionFailure) || errors.Is(err, ErrUnexpectedWriteFailure)
+}
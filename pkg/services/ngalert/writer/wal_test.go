@@ -0,0 +1,278 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type fakePointsWriter struct {
+	err     error
+	written []Point
+}
+
+func (w *fakePointsWriter) WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return err
+	}
+	return w.WritePointsDatasource(ctx, dsUID, points, orgID)
+}
+
+func (w *fakePointsWriter) WritePointsDatasource(_ context.Context, _ string, points []Point, _ int64) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.written = append(w.written, points...)
+	return nil
+}
+
+// slowPointsWriter blocks the writes of the points of the slow metric until release is closed.
+type slowPointsWriter struct {
+	mtx     sync.Mutex
+	err     error
+	written []Point
+
+	slow    string
+	started chan struct{}
+	release chan struct{}
+}
+
+func newSlowPointsWriter(slow string) *slowPointsWriter {
+	return &slowPointsWriter{slow: slow, started: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (w *slowPointsWriter) WriteDatasource(context.Context, string, string, time.Time, data.Frames, int64, map[string]string) error {
+	return errors.New("not implemented")
+}
+
+func (w *slowPointsWriter) WritePointsDatasource(_ context.Context, _ string, points []Point, _ int64) error {
+	w.mtx.Lock()
+	err := w.err
+	w.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	if len(points) > 0 && points[0].Name == w.slow {
+		w.started <- struct{}{}
+		<-w.release
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.written = append(w.written, points...)
+	return nil
+}
+
+func (w *slowPointsWriter) setErr(err error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.err = err
+}
+
+func (w *slowPointsWriter) writtenNames() []string {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	names := make([]string, 0, len(w.written))
+	for _, p := range w.written {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// requireDone fails the test if fn does not return in time.
+func requireDone(t *testing.T, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the write")
+	}
+}
+
+func testPoints(name string, values ...float64) []Point {
+	points := make([]Point, 0, len(values))
+	for i, v := range values {
+		points = append(points, Point{
+			Name:   name,
+			Labels: map[string]string{"instance": fmt.Sprint(i)},
+			Metric: Metric{T: time.Unix(int64(i), 0).UTC(), V: v},
+		})
+	}
+	return points
+}
+
+func newTestWALWriter(t *testing.T, cfg WALWriterConfig, w PointsWriter) (*WALWriter, *metrics.RemoteWriter) {
+	t.Helper()
+	m := metrics.NewRemoteWriterMetrics(prometheus.NewRegistry())
+	wal, err := NewWALWriter(cfg, w, clock.NewMock(), log.NewNopLogger(), m)
+	require.NoError(t, err)
+	return wal, m
+}
+
+func TestWALWriter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("writes directly when the data source is reachable", func(t *testing.T) {
+		fake := &fakePointsWriter{}
+		wal, _ := newTestWALWriter(t, WALWriterConfig{Dir: t.TempDir()}, fake)
+
+		require.NoError(t, wal.WritePointsDatasource(ctx, "ds", testPoints("metric", 1, 2), 1))
+		require.Len(t, fake.written, 2)
+		files, err := os.ReadDir(wal.cfg.Dir)
+		require.NoError(t, err)
+		require.Empty(t, files)
+	})
+
+	t.Run("buffers points while the data source is not reachable and replays them in order", func(t *testing.T) {
+		fake := &fakePointsWriter{err: ErrConnectionFailure}
+		wal, m := newTestWALWriter(t, WALWriterConfig{Dir: t.TempDir()}, fake)
+
+		require.NoError(t, wal.WritePointsDatasource(ctx, "ds", testPoints("metric", 1), 1))
+		fake.err = nil
+		// The data source is reachable again, but older points are pending, so the new ones are buffered too.
+		require.NoError(t, wal.WritePointsDatasource(ctx, "ds", testPoints("metric", 2), 1))
+		require.Empty(t, fake.written)
+		require.Equal(t, 2.0, testutil.ToFloat64(m.WALPoints.WithLabelValues("1")))
+
+		wal.Replay(ctx)
+		require.Len(t, fake.written, 2)
+		require.Equal(t, 1.0, fake.written[0].Metric.V)
+		require.Equal(t, 2.0, fake.written[1].Metric.V)
+		require.Equal(t, 0.0, testutil.ToFloat64(m.WALPoints.WithLabelValues("1")))
+		require.Equal(t, 0.0, testutil.ToFloat64(m.WALBytes))
+		require.Equal(t, 2.0, testutil.ToFloat64(m.WALReplayedPoints.WithLabelValues("1")))
+
+		// Once the log is empty, points are written directly again.
+		require.NoError(t, wal.WritePointsDatasource(ctx, "ds", testPoints("metric", 3), 1))
+		require.Len(t, fake.written, 3)
+	})
+
+	t.Run("keeps points when the data source is still not reachable", func(t *testing.T) {
+		fake := &fakePointsWriter{err: ErrUnexpectedWriteFailure}
+		wal, m := newTestWALWriter(t, WALWriterConfig{Dir: t.TempDir()}, fake)
+
+		require.NoError(t, wal.WritePointsDatasource(ctx, "ds", testPoints("metric", 1, 2), 1))
+		wal.Replay(ctx)
+		require.Empty(t, fake.written)
+		require.Equal(t, 2.0, testutil.ToFloat64(m.WALPoints.WithLabelValues("1")))
+	})
+
+	t.Run("does not buffer points rejected by the data source", func(t *testing.T) {
+		fake := &fakePointsWriter{err: ErrRejectedWrite}
+		wal, m := newTestWALWriter(t, WALWriterConfig{Dir: t.TempDir()}, fake)
+
+		err := wal.WritePointsDatasource(ctx, "ds", testPoints("metric", 1), 1)
+		require.ErrorIs(t, err, ErrRejectedWrite)
+		require.Equal(t, 0.0, testutil.ToFloat64(m.WALPoints.WithLabelValues("1")))
+	})
+
+	t.Run("discards points that are rejected when replayed", func(t *testing.T) {
+		fake := &fakePointsWriter{err: ErrConnectionFailure}
+		wal, m := newTestWALWriter(t, WALWriterConfig{Dir: t.TempDir()}, fake)
+
+		require.NoError(t, wal.WritePointsDatasource(ctx, "ds", testPoints("metric", 1), 1))
+		fake.err = ErrRejectedWrite
+		wal.Replay(ctx)
+		require.Equal(t, 0.0, testutil.ToFloat64(m.WALPoints.WithLabelValues("1")))
+		require.Equal(t, 1.0, testutil.ToFloat64(m.WALDroppedPoints.WithLabelValues("1", "rejected")))
+	})
+
+	t.Run("returns the write error when the log is full", func(t *testing.T) {
+		fake := &fakePointsWriter{err: ErrConnectionFailure}
+		wal, m := newTestWALWriter(t, WALWriterConfig{Dir: t.TempDir(), MaxSizeBytes: 10}, fake)
+
+		err := wal.WritePointsDatasource(ctx, "ds", testPoints("metric", 1), 1)
+		require.ErrorIs(t, err, ErrConnectionFailure)
+		require.Equal(t, 1.0, testutil.ToFloat64(m.WALDroppedPoints.WithLabelValues("1", "full")))
+	})
+
+	t.Run("does not block other writes to the data source while a write is in progress", func(t *testing.T) {
+		slow := newSlowPointsWriter("slow")
+		wal, _ := newTestWALWriter(t, WALWriterConfig{Dir: t.TempDir()}, slow)
+
+		slowDone := make(chan error)
+		go func() { slowDone <- wal.WritePointsDatasource(ctx, "ds", testPoints("slow", 1), 1) }()
+		<-slow.started
+
+		requireDone(t, func() {
+			require.NoError(t, wal.WritePointsDatasource(ctx, "ds", testPoints("fast", 1), 1))
+		})
+		require.Equal(t, []string{"fast"}, slow.writtenNames())
+
+		close(slow.release)
+		require.NoError(t, <-slowDone)
+		require.Equal(t, []string{"fast", "slow"}, slow.writtenNames())
+	})
+
+	t.Run("buffers points written during a replay behind the replayed ones", func(t *testing.T) {
+		slow := newSlowPointsWriter("slow")
+		slow.setErr(ErrConnectionFailure)
+		wal, m := newTestWALWriter(t, WALWriterConfig{Dir: t.TempDir()}, slow)
+		require.NoError(t, wal.WritePointsDatasource(ctx, "ds", testPoints("slow", 1), 1))
+		slow.setErr(nil)
+
+		replayDone := make(chan struct{})
+		go func() {
+			defer close(replayDone)
+			wal.Replay(ctx)
+		}()
+		<-slow.started
+
+		requireDone(t, func() {
+			require.NoError(t, wal.WritePointsDatasource(ctx, "ds", testPoints("fast", 1), 1))
+		})
+		require.Empty(t, slow.writtenNames())
+
+		close(slow.release)
+		<-replayDone
+		require.Equal(t, []string{"slow"}, slow.writtenNames())
+		require.Equal(t, 1.0, testutil.ToFloat64(m.WALPoints.WithLabelValues("1")))
+
+		wal.Replay(ctx)
+		require.Equal(t, []string{"slow", "fast"}, slow.writtenNames())
+		require.Equal(t, 0.0, testutil.ToFloat64(m.WALPoints.WithLabelValues("1")))
+	})
+
+	t.Run("loads the log left over from a previous run", func(t *testing.T) {
+		dir := t.TempDir()
+		fake := &fakePointsWriter{err: ErrConnectionFailure}
+		wal, _ := newTestWALWriter(t, WALWriterConfig{Dir: dir}, fake)
+		require.NoError(t, wal.WritePointsDatasource(ctx, "ds/with/slashes", testPoints("metric", 1, 2), 2))
+
+		// Simulate a write that was interrupted.
+		path := filepath.Join(dir, "2-ds%2Fwith%2Fslashes.wal")
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o640)
+		require.NoError(t, err)
+		_, err = f.WriteString(`{"org_id":2,"datasource_uid":"ds/w`)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		fake.err = nil
+		restarted, m := newTestWALWriter(t, WALWriterConfig{Dir: dir}, fake)
+		require.Equal(t, 2.0, testutil.ToFloat64(m.WALPoints.WithLabelValues("2")))
+
+		restarted.Replay(ctx)
+		require.Len(t, fake.written, 2)
+		_, err = os.Stat(path)
+		require.True(t, errors.Is(err, os.ErrNotExist))
+	})
+}
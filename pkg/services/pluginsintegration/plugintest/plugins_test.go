@@ -166,7 +166,7 @@ func TestIntegrationPluginManager(t *testing.T) {
 	ms := mssql.ProvideService()
 	db := db.InitTestDB(t, sqlstore.InitTestDBOpt{Cfg: cfg})
 	sv2 := searchV2.ProvideService(cfg, db, nil, nil, tracer, features, nil, nil, nil)
-	graf := grafanads.ProvideService(sv2, nil, features)
+	graf := grafanads.ProvideService(sv2, nil, features, nil, nil)
 	pyroscope := pyroscope.ProvideService(hcp)
 	parca := parca.ProvideService(hcp)
 	zipkin := zipkin.ProvideService(hcp)
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -79,6 +80,9 @@ type AlertRuleV1 struct {
 	IsPaused                    values.BoolValue        `json:"isPaused" yaml:"isPaused"`
 	NotificationSettings        *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
 	Record                      *RecordV1               `json:"record" yaml:"record"`
+	InhibitedBy                 []InhibitionV1          `json:"inhibitedBy" yaml:"inhibitedBy"`
+	EvaluationSchedule          *EvaluationScheduleV1   `json:"evaluationSchedule" yaml:"evaluationSchedule"`
+	FlapDetection               *FlapDetectionV1        `json:"flapDetection" yaml:"flapDetection"`
 }
 
 func withFallback(value, fallback string) *string {
@@ -171,6 +175,27 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		}
 		alertRule.NotificationSettings = append(alertRule.NotificationSettings, ns)
 	}
+	for _, inhibitionV1 := range rule.InhibitedBy {
+		inhibition, err := inhibitionV1.mapToModel()
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.InhibitedBy = append(alertRule.InhibitedBy, inhibition)
+	}
+	if rule.EvaluationSchedule != nil {
+		schedule, err := rule.EvaluationSchedule.mapToModel()
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.EvaluationSchedule = &schedule
+	}
+	if rule.FlapDetection != nil {
+		flapDetection, err := rule.FlapDetection.mapToModel()
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.FlapDetection = &flapDetection
+	}
 	if rule.Record != nil {
 		record, err := rule.Record.mapToModel()
 		if err != nil {
@@ -306,12 +331,78 @@ type RecordV1 struct {
 	Metric              values.StringValue `json:"metric" yaml:"metric"`
 	From                values.StringValue `json:"from" yaml:"from"`
 	TargetDatasourceUID values.StringValue `json:"targetDatasourceUid" yaml:"targetDatasourceUid"`
+
+	AdditionalTargetDatasourceUIDs []values.StringValue `json:"additionalTargetDatasourceUids" yaml:"additionalTargetDatasourceUids"`
 }
 
 func (record *RecordV1) mapToModel() (models.Record, error) {
-	return models.Record{
+	result := models.Record{
 		Metric:              record.Metric.Value(),
 		From:                record.From.Value(),
 		TargetDatasourceUID: record.TargetDatasourceUID.Value(),
+	}
+	for _, uid := range record.AdditionalTargetDatasourceUIDs {
+		result.AdditionalTargetDatasourceUIDs = append(result.AdditionalTargetDatasourceUIDs, uid.Value())
+	}
+	return result, nil
+}
+
+type InhibitionV1 struct {
+	RuleUID values.StringValue   `json:"ruleUid" yaml:"ruleUid"`
+	Equal   []values.StringValue `json:"equal" yaml:"equal"`
+}
+
+func (inhibitionV1 *InhibitionV1) mapToModel() (models.RuleInhibition, error) {
+	ruleUID := inhibitionV1.RuleUID.Value()
+	if ruleUID == "" {
+		return models.RuleInhibition{}, fmt.Errorf("inhibiting rule UID must not be empty")
+	}
+	var equal []string
+	for _, l := range inhibitionV1.Equal {
+		equal = append(equal, l.Value())
+	}
+	return models.RuleInhibition{
+		RuleUID: ruleUID,
+		Equal:   equal,
 	}, nil
 }
+
+type EvaluationScheduleV1 struct {
+	Cron                values.StringValue          `json:"cron" yaml:"cron"`
+	Timezone            values.StringValue          `json:"timezone" yaml:"timezone"`
+	ActiveTimeIntervals []timeinterval.TimeInterval `json:"activeTimeIntervals" yaml:"activeTimeIntervals"`
+}
+
+func (scheduleV1 *EvaluationScheduleV1) mapToModel() (models.EvaluationSchedule, error) {
+	schedule := models.EvaluationSchedule{
+		Cron:                scheduleV1.Cron.Value(),
+		Timezone:            scheduleV1.Timezone.Value(),
+		ActiveTimeIntervals: scheduleV1.ActiveTimeIntervals,
+	}
+	if err := schedule.Validate(); err != nil {
+		return models.EvaluationSchedule{}, fmt.Errorf("invalid evaluation schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+type FlapDetectionV1 struct {
+	Transitions values.Int64Value  `json:"transitions" yaml:"transitions"`
+	Window      values.StringValue `json:"window" yaml:"window"`
+}
+
+func (flapDetectionV1 *FlapDetectionV1) mapToModel() (models.FlapDetection, error) {
+	window, err := model.ParseDuration(flapDetectionV1.Window.Value())
+	if err != nil {
+		return models.FlapDetection{}, fmt.Errorf("invalid flap detection window: %w", err)
+	}
+	flapDetection := models.FlapDetection{
+		Transitions: flapDetectionV1.Transitions.Value(),
+		Window:      time.Duration(window),
+	}
+	// The window is checked against the evaluation interval when the rule is stored,
+	// as the interval is set by the group.
+	if err := flapDetection.Validate(0); err != nil {
+		return models.FlapDetection{}, fmt.Errorf("invalid flap detection: %w", err)
+	}
+	return flapDetection, nil
+}
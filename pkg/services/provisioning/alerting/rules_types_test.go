@@ -202,6 +202,89 @@ func TestRules(t *testing.T) {
 	})
 }
 
+func TestRuleInhibitions(t *testing.T) {
+	t.Run("a rule with inhibiting rules should map them correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.InhibitedBy = []InhibitionV1{
+			{RuleUID: stringToStringValue("db_down"), Equal: []values.StringValue{stringToStringValue("cluster")}},
+			{RuleUID: stringToStringValue("maintenance")},
+		}
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, []models.RuleInhibition{
+			{RuleUID: "db_down", Equal: []string{"cluster"}},
+			{RuleUID: "maintenance"},
+		}, ruleMapped.InhibitedBy)
+	})
+	t.Run("an inhibiting rule without a uid should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.InhibitedBy = []InhibitionV1{{Equal: []values.StringValue{stringToStringValue("cluster")}}}
+		_, err := rule.mapToModel(1)
+		require.ErrorContains(t, err, "inhibiting rule UID must not be empty")
+	})
+}
+
+func TestRuleEvaluationSchedule(t *testing.T) {
+	t.Run("a rule with an evaluation schedule should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.EvaluationSchedule = &EvaluationScheduleV1{}
+		err := yaml.Unmarshal([]byte(`
+cron: 0 8 * * 1-5
+timezone: Europe/Berlin
+activeTimeIntervals:
+  - times:
+      - start_time: "08:00"
+        end_time: "18:00"
+    weekdays: ["monday:friday"]
+`), rule.EvaluationSchedule)
+		require.NoError(t, err)
+
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.NotNil(t, ruleMapped.EvaluationSchedule)
+		require.Equal(t, "0 8 * * 1-5", ruleMapped.EvaluationSchedule.Cron)
+		require.Equal(t, "Europe/Berlin", ruleMapped.EvaluationSchedule.Timezone)
+		require.Len(t, ruleMapped.EvaluationSchedule.ActiveTimeIntervals, 1)
+		require.Equal(t, 8*60, ruleMapped.EvaluationSchedule.ActiveTimeIntervals[0].Times[0].StartMinute)
+	})
+	t.Run("a rule with an invalid cron expression should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.EvaluationSchedule = &EvaluationScheduleV1{Cron: stringToStringValue("0 8 *")}
+		_, err := rule.mapToModel(1)
+		require.ErrorContains(t, err, "invalid evaluation schedule")
+	})
+}
+
+func TestRuleFlapDetection(t *testing.T) {
+	t.Run("a rule with flap detection should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.FlapDetection = &FlapDetectionV1{}
+		err := yaml.Unmarshal([]byte(`
+transitions: 4
+window: 30m
+`), rule.FlapDetection)
+		require.NoError(t, err)
+
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, &models.FlapDetection{Transitions: 4, Window: 30 * time.Minute}, ruleMapped.FlapDetection)
+	})
+	t.Run("a rule with an invalid window should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.FlapDetection = &FlapDetectionV1{}
+		require.NoError(t, yaml.Unmarshal([]byte("{transitions: 4, window: thirty minutes}"), rule.FlapDetection))
+		_, err := rule.mapToModel(1)
+		require.ErrorContains(t, err, "invalid flap detection window")
+	})
+	t.Run("a rule with too few transitions should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.FlapDetection = &FlapDetectionV1{}
+		require.NoError(t, yaml.Unmarshal([]byte("{transitions: 1, window: 30m}"), rule.FlapDetection))
+		_, err := rule.mapToModel(1)
+		require.ErrorContains(t, err, "invalid flap detection")
+	})
+}
+
 func TestRecordingRules(t *testing.T) {
 	t.Run("a valid rule should not error", func(t *testing.T) {
 		rule := validRecordingRuleV1(t)
@@ -169,5 +169,17 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 
 	ualert.CollateBinAlertRuleGroup(mg)
 
+	ualert.AddAlertStateHistoryTable(mg)
+
+	ualert.AddRuleInhibitedByColumns(mg)
+
+	ualert.AddRuleEvaluationScheduleColumns(mg)
+
+	ualert.AddRuleTemplateTables(mg)
+
+	ualert.AddRuleFlapDetectionColumns(mg)
+
+	ualert.AddMaintenanceWindowTable(mg)
+
 	accesscontrol.AddReceiverProtectedFieldsEditor(mg)
 }
@@ -0,0 +1,29 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddMaintenanceWindowTable adds the table of maintenance windows of alert rules.
+func AddMaintenanceWindowTable(mg *migrator.Migrator) {
+	table := migrator.Table{
+		Name: "alert_rule_maintenance_window",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "reason", Type: migrator.DB_Text, Nullable: false},
+			{Name: "starts_at", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "ends_at", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "selector", Type: migrator.DB_Text, Nullable: false},
+			{Name: "created_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: true},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"ends_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("add alert_rule_maintenance_window table", migrator.NewAddTableMigration(table))
+	mg.AddMigration("add unique index to alert_rule_maintenance_window on org_id and uid", migrator.NewAddIndexMigration(table, table.Indices[0]))
+	mg.AddMigration("add index to alert_rule_maintenance_window on ends_at", migrator.NewAddIndexMigration(table, table.Indices[1]))
+}
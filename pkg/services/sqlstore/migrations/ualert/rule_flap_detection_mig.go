@@ -0,0 +1,20 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleFlapDetectionColumns creates a column for the flap detection settings of a rule in the alert_rule and alert_rule_version tables.
+func AddRuleFlapDetectionColumns(mg *migrator.Migrator) {
+	mg.AddMigration("add flap_detection column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "flap_detection",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add flap_detection column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "flap_detection",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+}
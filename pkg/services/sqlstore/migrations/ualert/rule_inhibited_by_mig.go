@@ -0,0 +1,20 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleInhibitedByColumns creates a column for the rules that inhibit a rule in the alert_rule and alert_rule_version tables.
+func AddRuleInhibitedByColumns(mg *migrator.Migrator) {
+	mg.AddMigration("add inhibited_by column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "inhibited_by",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add inhibited_by column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "inhibited_by",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+}
@@ -0,0 +1,33 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertStateHistoryTable adds the table used by the SQL state history backend.
+func AddAlertStateHistoryTable(mg *migrator.Migrator) {
+	stateHistoryTable := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "dashboard_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: true},
+			{Name: "panel_id", Type: migrator.DB_BigInt, Nullable: true},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "current_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "evaluated_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "line", Type: migrator.DB_MediumText, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "evaluated_at"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "rule_uid", "evaluated_at"}, Type: migrator.IndexType},
+			{Cols: []string{"evaluated_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("add alert_state_history table", migrator.NewAddTableMigration(stateHistoryTable))
+	mg.AddMigration("add index to alert_state_history on org_id and evaluated_at", migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[0]))
+	mg.AddMigration("add index to alert_state_history on org_id, rule_uid and evaluated_at", migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[1]))
+	mg.AddMigration("add index to alert_state_history on evaluated_at", migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[2]))
+}
@@ -468,6 +468,13 @@ type Cfg struct {
 	// SQLExpressionTimeoutSeconds is the duration a SQL expression will run before timing out
 	SQLExpressionTimeout time.Duration
 
+	// ExpressionsDatasourceCacheTTL is how long data source results of cacheable expression requests are kept.
+	// 0 disables the cache.
+	ExpressionsDatasourceCacheTTL time.Duration
+
+	// ExpressionsDatasourceCacheMaxEntries is the maximum number of data source results kept in the cache.
+	ExpressionsDatasourceCacheMaxEntries int
+
 	ImageUploadProvider string
 
 	// LiveMaxConnections is a maximum number of WebSocket connections to
@@ -871,6 +878,8 @@ func (cfg *Cfg) readExpressionsSettings() {
 	cfg.SQLExpressionOutputCellLimit = expressions.Key("sql_expression_output_cell_limit").MustInt64(100000)
 	cfg.SQLExpressionTimeout = expressions.Key("sql_expression_timeout").MustDuration(time.Second * 10)
 	cfg.SQLExpressionQueryLengthLimit = expressions.Key("sql_expression_query_length_limit").MustInt64(10000)
+	cfg.ExpressionsDatasourceCacheTTL = expressions.Key("datasource_cache_ttl").MustDuration(0)
+	cfg.ExpressionsDatasourceCacheMaxEntries = expressions.Key("datasource_cache_max_entries").MustInt(1000)
 }
 
 type AnnotationCleanupSettings struct {
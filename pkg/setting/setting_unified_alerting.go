@@ -1,6 +1,7 @@
 package setting
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -60,6 +61,7 @@ const (
 	screenshotsMaxCaptureTimeout            = 30 * time.Second
 	screenshotsDefaultMaxConcurrent         = 5
 	screenshotsDefaultUploadImageStorage    = false
+	screenshotsDefaultImageStoreExpiration  = 7 * 24 * time.Hour
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -70,9 +72,12 @@ const (
 	notificationHistoryDefaultEnabled      = false
 	lokiDefaultMaxQueryLength              = 721 * time.Hour // 30d1h, matches the default value in Loki
 	defaultRecordingRequestTimeout         = 10 * time.Second
+	defaultRecordingWALReplayInterval      = 30 * time.Second
+	defaultRecordingWALMaxSizeBytes        = 100 * 1024 * 1024
 	lokiDefaultMaxQuerySize                = 65536 // 64kb
 	defaultHistorianPrometheusWriteTimeout = 10 * time.Second
 	defaultHistorianPrometheusMetricName   = "GRAFANA_ALERTS"
+	defaultHistorianSQLRetention           = 30 * 24 * time.Hour
 )
 
 var (
@@ -165,6 +170,15 @@ type RecordingRuleSettings struct {
 	CustomHeaders        map[string]string
 	Timeout              time.Duration
 	DefaultDatasourceUID string
+
+	// WALEnabled enables buffering of samples on disk while a target data source is not reachable.
+	WALEnabled bool
+	// WALDirectory is the directory of the write-ahead log. Empty means <data>/alerting/recording-wal.
+	WALDirectory string
+	// WALMaxSizeBytes is the maximum size of the write-ahead log. Samples are discarded once it is full.
+	WALMaxSizeBytes int64
+	// WALReplayInterval is the interval at which buffered samples are replayed.
+	WALReplayInterval time.Duration
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -182,8 +196,17 @@ type UnifiedAlertingScreenshotSettings struct {
 	CaptureTimeout             time.Duration
 	MaxConcurrentScreenshots   int64
 	UploadExternalImageStorage bool
+	// ImageStore is the built-in store for screenshots, either "local" or "blob". It is disabled if empty.
+	ImageStore              string
+	ImageStoreBlobURL       string
+	ImageStoreURLExpiration time.Duration
 }
 
+const (
+	ScreenshotsImageStoreLocal = "local"
+	ScreenshotsImageStoreBlob  = "blob"
+)
+
 type UnifiedAlertingReservedLabelSettings struct {
 	DisabledLabels map[string]struct{}
 }
@@ -220,6 +243,8 @@ type UnifiedAlertingStateHistorySettings struct {
 	MultiPrimary                  string
 	MultiSecondaries              []string
 	ExternalLabels                map[string]string
+	// SQLRetention is how long the SQL backend keeps state history. 0 keeps it forever.
+	SQLRetention time.Duration
 }
 
 type UnifiedAlertingNotificationHistorySettings struct {
@@ -485,6 +510,25 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfgScreenshots.MaxConcurrentScreenshots = screenshots.Key("max_concurrent_screenshots").MustInt64(screenshotsDefaultMaxConcurrent)
 	uaCfgScreenshots.UploadExternalImageStorage = screenshots.Key("upload_external_image_storage").MustBool(screenshotsDefaultUploadImageStorage)
+
+	uaCfgScreenshots.ImageStore = screenshots.Key("image_store").MustString("")
+	switch uaCfgScreenshots.ImageStore {
+	case "":
+	case ScreenshotsImageStoreLocal, ScreenshotsImageStoreBlob:
+		if uaCfgScreenshots.UploadExternalImageStorage {
+			return errors.New("setting 'image_store' cannot be used together with 'upload_external_image_storage'")
+		}
+	default:
+		return fmt.Errorf("unsupported value of setting 'image_store' %q, must be one of %q or %q", uaCfgScreenshots.ImageStore, ScreenshotsImageStoreLocal, ScreenshotsImageStoreBlob)
+	}
+	uaCfgScreenshots.ImageStoreBlobURL = screenshots.Key("image_store_blob_url").MustString(iniFile.Section("grafana-apiserver").Key("blob_url").MustString(""))
+	if uaCfgScreenshots.ImageStore == ScreenshotsImageStoreBlob && uaCfgScreenshots.ImageStoreBlobURL == "" {
+		return fmt.Errorf("setting 'image_store_blob_url' is required if 'image_store' is %q", ScreenshotsImageStoreBlob)
+	}
+	uaCfgScreenshots.ImageStoreURLExpiration = screenshots.Key("image_store_url_expiration").MustDuration(screenshotsDefaultImageStoreExpiration)
+	if uaCfgScreenshots.ImageStoreURLExpiration <= 0 {
+		return errors.New("value of setting 'image_store_url_expiration' must be greater than 0")
+	}
 	uaCfg.Screenshots = uaCfgScreenshots
 
 	reservedLabels := iniFile.Section("unified_alerting.reserved_labels")
@@ -517,6 +561,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		PrometheusTargetDatasourceUID: stateHistory.Key("prometheus_target_datasource_uid").MustString(""),
 		PrometheusWriteTimeout:        stateHistory.Key("prometheus_write_timeout").MustDuration(defaultHistorianPrometheusWriteTimeout),
 		ExternalLabels:                stateHistoryLabels.KeysHash(),
+		SQLRetention:                  stateHistory.Key("sql_retention").MustDuration(defaultHistorianSQLRetention),
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
@@ -549,6 +594,13 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		Enabled:              rr.Key("enabled").MustBool(true),
 		Timeout:              rr.Key("timeout").MustDuration(defaultRecordingRequestTimeout),
 		DefaultDatasourceUID: rr.Key("default_datasource_uid").MustString(""),
+		WALEnabled:           rr.Key("wal_enabled").MustBool(false),
+		WALDirectory:         rr.Key("wal_directory").MustString(""),
+		WALMaxSizeBytes:      rr.Key("wal_max_size_bytes").MustInt64(defaultRecordingWALMaxSizeBytes),
+		WALReplayInterval:    rr.Key("wal_replay_interval").MustDuration(defaultRecordingWALReplayInterval),
+	}
+	if uaCfgRecordingRules.WALReplayInterval <= 0 {
+		return fmt.Errorf("setting 'wal_replay_interval' in section 'recording_rules' is invalid. It must be greater than 0")
 	}
 
 	rrHeaders := iniFile.Section("recording_rules.custom_headers")
@@ -89,6 +89,8 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		return fmt.Sprintf("'%s'", timeRange.From.UTC().Format(time.RFC3339Nano)), nil
 	case "__timeTo":
 		return fmt.Sprintf("'%s'", timeRange.To.UTC().Format(time.RFC3339Nano)), nil
+	case "__lastSeen":
+		return fmt.Sprintf("'%s'", timeRange.From.UTC().Format(time.RFC3339Nano)), nil
 	case "__timeGroup":
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
@@ -119,6 +121,29 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 			return tg + " AS \"time\"", nil
 		}
 		return "", err
+	case "__timeBucket":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and calendar unit", name)
+		}
+		bucket, fill, err := sqleng.ParseTimeBucketArgs(args[1:])
+		if err != nil {
+			return "", err
+		}
+		if fill != "" {
+			err := sqleng.SetupTimeBucketFillmode(query, bucket, fill)
+			if err != nil {
+				return "", err
+			}
+		}
+		return bucket.Expr(args[0], timeRange, func(t time.Time) string {
+			return fmt.Sprintf("'%s'", t.UTC().Format(time.RFC3339))
+		})
+	case "__timeBucketAlias":
+		tb, err := m.evaluateMacro(timeRange, query, "__timeBucket", args)
+		if err == nil {
+			return tb + " AS \"time\"", nil
+		}
+		return "", err
 	case "__unixEpochFilter":
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
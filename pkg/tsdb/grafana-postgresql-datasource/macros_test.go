@@ -56,6 +56,27 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, "select '2018-04-12T18:05:00Z'", sql)
 		})
 
+		t.Run("interpolate __lastSeen function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, backend.TimeRange{From: from.Add(1500 * time.Microsecond), To: to}, "select * from t where time > $__lastSeen()")
+			require.NoError(t, err)
+
+			require.Equal(t, "select * from t where time > '2018-04-12T18:00:00.0015Z'", sql)
+		})
+
+		t.Run("interpolate __timeBucket function", func(t *testing.T) {
+			timeRange := backend.TimeRange{
+				From: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC),
+			}
+			sql, err := engine.Interpolate(query, timeRange, "SELECT $__timeBucket(time_column, month, 'Europe/Berlin')")
+			require.NoError(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "SELECT $__timeBucketAlias(time_column, month, 'Europe/Berlin')")
+			require.NoError(t, err)
+
+			require.Equal(t, "SELECT CASE WHEN time_column < '2024-01-31T23:00:00Z' THEN 1704063600 WHEN time_column < '2024-02-29T23:00:00Z' THEN 1706742000 ELSE 1709247600 END", sql)
+			require.Equal(t, sql+" AS \"time\"", sql2)
+		})
+
 		t.Run("interpolate __timeGroup function pre 5.3 compatibility", func(t *testing.T) {
 			sql, err := engine.Interpolate(query, timeRange, "SELECT $__timeGroup(time_column,'5m'), value")
 			require.NoError(t, err)
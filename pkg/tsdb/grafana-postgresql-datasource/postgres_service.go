@@ -40,6 +40,33 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	return dsInfo.QueryData(ctx, req)
 }
 
+// NOTE: do not put any business logic into this method. it's whole job is to forward the call "inside"
+func (s *Service) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return &backend.SubscribeStreamResponse{Status: backend.SubscribeStreamStatusNotFound}, err
+	}
+	return dsInfo.SubscribeStream(ctx, req)
+}
+
+// NOTE: do not put any business logic into this method. it's whole job is to forward the call "inside"
+func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+	return dsInfo.RunStream(ctx, req, sender)
+}
+
+// NOTE: do not put any business logic into this method. it's whole job is to forward the call "inside"
+func (s *Service) PublishStream(ctx context.Context, req *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return &backend.PublishStreamResponse{Status: backend.PublishStreamStatusNotFound}, err
+	}
+	return dsInfo.PublishStream(ctx, req)
+}
+
 func (s *Service) getDSInfo(ctx context.Context, pluginCtx backend.PluginContext) (*sqleng.DataSourceHandler, error) {
 	i, err := s.im.Get(ctx, pluginCtx)
 	if err != nil {
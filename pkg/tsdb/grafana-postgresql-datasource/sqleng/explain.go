@@ -0,0 +1,187 @@
+package sqleng
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/jackc/pgx/v5"
+)
+
+// QueryPlan is the plan of a query, as estimated by the database with EXPLAIN.
+type QueryPlan struct {
+	// Frame is the plan returned to the query editor.
+	Frame *data.Frame
+	// Cost is the estimated cost of the query, in the unit of the database.
+	Cost float64
+	// FullScanRows is the estimated number of rows of the largest table read with a full table scan.
+	FullScanRows int64
+}
+
+// checkQueryPlan returns an error if the plan exceeds the limits of the data source.
+func checkQueryPlan(plan *QueryPlan, jsonData JsonData) error {
+	if jsonData.MaxEstimatedCost > 0 && plan.Cost > jsonData.MaxEstimatedCost {
+		return backend.DownstreamErrorf("the estimated cost of the query (%.2f) exceeds the limit of the data source (%.2f)",
+			plan.Cost, jsonData.MaxEstimatedCost)
+	}
+	if jsonData.MaxFullScanRows > 0 && plan.FullScanRows > jsonData.MaxFullScanRows {
+		return backend.DownstreamErrorf("the query reads a table of about %d rows with a full table scan, which exceeds the limit of the data source (%d rows)",
+			plan.FullScanRows, jsonData.MaxFullScanRows)
+	}
+	return nil
+}
+
+// preflight explains the query when the query asks for its plan, or when the data source limits the queries
+// by their plan. It returns a response when the query must not run: either the plan of the query, or the
+// error of a query above the limits. Queries that cannot be explained run unchecked.
+func (e *DataSourceHandler) preflight(ctx context.Context, logger log.Logger, query string, queryJSON QueryJson) *backend.DataResponse {
+	jsonData := e.dsInfo.JsonData
+	if !queryJSON.Explain && jsonData.MaxEstimatedCost <= 0 && jsonData.MaxFullScanRows <= 0 {
+		return nil
+	}
+
+	plan, err := e.explain(ctx, query)
+	if err != nil {
+		if !queryJSON.Explain {
+			logger.Warn("Failed to explain query, running it without checking its plan", "error", err)
+			return nil
+		}
+		var emptyFrame data.Frame
+		emptyFrame.SetMeta(&data.FrameMeta{ExecutedQueryString: query})
+		return &backend.DataResponse{
+			Error:       fmt.Errorf("explain failed: %w", e.TransformQueryError(logger, err)),
+			ErrorSource: backend.ErrorSourceDownstream,
+			Frames:      data.Frames{&emptyFrame},
+		}
+	}
+
+	if queryJSON.Explain {
+		return &backend.DataResponse{Frames: data.Frames{plan.Frame}}
+	}
+	if err := checkQueryPlan(plan, jsonData); err != nil {
+		return &backend.DataResponse{
+			Error:       err,
+			ErrorSource: backend.ErrorSourceDownstream,
+			Frames:      data.Frames{plan.Frame},
+		}
+	}
+	return nil
+}
+
+// explain returns the plan of the query, from EXPLAIN (FORMAT JSON). The plan only has the estimated rows
+// returned by the scans, so the size of the fully scanned tables is read from the statistics of pg_class.
+func (e *DataSourceHandler) explain(ctx context.Context, query string) (*QueryPlan, error) {
+	explainQuery := "EXPLAIN (FORMAT JSON, VERBOSE) " + query
+	var raw []byte
+	// The extended protocol runs a single statement, so that the statements after the first one are never run.
+	if err := e.pool.QueryRow(ctx, explainQuery, pgx.QueryExecModeExec).Scan(&raw); err != nil {
+		return nil, err
+	}
+	plan, scans, err := parseQueryPlan(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scan := range scans {
+		var rows int64
+		err := e.pool.QueryRow(ctx, `SELECT coalesce(max(c.reltuples), -1)::bigint FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND c.relname = $2`,
+			scan.schema, scan.relation).Scan(&rows)
+		if err != nil {
+			return nil, err
+		}
+		// Tables that were never analyzed have no statistics.
+		if rows < 0 {
+			rows = scan.planRows
+		}
+		if rows > plan.FullScanRows {
+			plan.FullScanRows = rows
+		}
+	}
+
+	plan.Frame.Meta.ExecutedQueryString = explainQuery
+	return plan, nil
+}
+
+// planNode is a node of the output of EXPLAIN (FORMAT JSON).
+type planNode struct {
+	NodeType     string     `json:"Node Type"`
+	RelationName string     `json:"Relation Name"`
+	Schema       string     `json:"Schema"`
+	Alias        string     `json:"Alias"`
+	StartupCost  float64    `json:"Startup Cost"`
+	TotalCost    float64    `json:"Total Cost"`
+	PlanRows     float64    `json:"Plan Rows"`
+	Plans        []planNode `json:"Plans"`
+}
+
+// seqScan is a sequential scan of a table.
+type seqScan struct {
+	schema   string
+	relation string
+	planRows int64
+}
+
+// parseQueryPlan parses the output of EXPLAIN (FORMAT JSON). The plan has a row for every node of the plan,
+// and the sequential scans of the plan are returned to look up the size of their tables.
+func parseQueryPlan(raw []byte) (*QueryPlan, []seqScan, error) {
+	var explained []struct {
+		Plan *planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &explained); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+	if len(explained) == 0 || explained[0].Plan == nil {
+		return nil, nil, errors.New("failed to parse query plan: plan is missing")
+	}
+
+	root := explained[0].Plan
+	plan := &QueryPlan{Cost: root.TotalCost}
+	scans := []seqScan{}
+	nodes := []string{}
+	relations := []string{}
+	startupCosts := []float64{}
+	totalCosts := []float64{}
+	rows := []int64{}
+
+	var walk func(node *planNode, depth int)
+	walk = func(node *planNode, depth int) {
+		relation := node.RelationName
+		if node.Schema != "" && relation != "" {
+			relation = node.Schema + "." + relation
+		}
+		if node.NodeType == "Seq Scan" && node.RelationName != "" {
+			scans = append(scans, seqScan{schema: node.Schema, relation: node.RelationName, planRows: int64(node.PlanRows)})
+		}
+		nodes = append(nodes, strings.Repeat("  ", depth)+node.NodeType)
+		relations = append(relations, relation)
+		startupCosts = append(startupCosts, node.StartupCost)
+		totalCosts = append(totalCosts, node.TotalCost)
+		rows = append(rows, int64(node.PlanRows))
+		for i := range node.Plans {
+			walk(&node.Plans[i], depth+1)
+		}
+	}
+	walk(root, 0)
+
+	plan.Frame = data.NewFrame("plan",
+		data.NewField("node", nil, nodes),
+		data.NewField("relation", nil, relations),
+		data.NewField("startupCost", nil, startupCosts),
+		data.NewField("totalCost", nil, totalCosts),
+		data.NewField("rows", nil, rows),
+	)
+	plan.Frame.SetMeta(&data.FrameMeta{
+		PreferredVisualization: data.VisTypeTable,
+		Notices: []data.Notice{{
+			Severity: data.NoticeSeverityInfo,
+			Text:     fmt.Sprintf("Estimated query cost: %.2f", plan.Cost),
+		}},
+	})
+	return plan, scans, nil
+}
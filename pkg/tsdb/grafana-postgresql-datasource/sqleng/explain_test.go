@@ -0,0 +1,92 @@
+package sqleng
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const postgresPlan = `[
+  {
+    "Plan": {
+      "Node Type": "Sort",
+      "Startup Cost": 1894.32,
+      "Total Cost": 1906.82,
+      "Plan Rows": 5000,
+      "Plans": [
+        {
+          "Node Type": "Hash Join",
+          "Startup Cost": 1.09,
+          "Total Cost": 1587.09,
+          "Plan Rows": 5000,
+          "Plans": [
+            {
+              "Node Type": "Seq Scan",
+              "Relation Name": "metric",
+              "Schema": "public",
+              "Alias": "m",
+              "Startup Cost": 0.0,
+              "Total Cost": 1500.0,
+              "Plan Rows": 5000
+            },
+            {
+              "Node Type": "Index Scan",
+              "Relation Name": "host",
+              "Schema": "public",
+              "Alias": "h",
+              "Startup Cost": 0.29,
+              "Total Cost": 8.3,
+              "Plan Rows": 1
+            }
+          ]
+        }
+      ]
+    }
+  }
+]`
+
+func TestParseQueryPlan(t *testing.T) {
+	t.Run("parses the cost and the nodes of the plan", func(t *testing.T) {
+		plan, scans, err := parseQueryPlan([]byte(postgresPlan))
+		require.NoError(t, err)
+		require.Equal(t, 1906.82, plan.Cost)
+		require.Equal(t, []seqScan{{schema: "public", relation: "metric", planRows: 5000}}, scans)
+
+		frame := plan.Frame
+		require.Equal(t, 4, frame.Rows())
+		require.Equal(t, "Sort", frame.Fields[0].At(0))
+		require.Equal(t, "    Seq Scan", frame.Fields[0].At(2))
+		require.Equal(t, "public.metric", frame.Fields[1].At(2))
+		require.Equal(t, 0.29, frame.Fields[2].At(3))
+		require.Equal(t, 1500.0, frame.Fields[3].At(2))
+		require.Equal(t, int64(1), frame.Fields[4].At(3))
+	})
+
+	t.Run("fails without a plan", func(t *testing.T) {
+		_, _, err := parseQueryPlan([]byte(`[]`))
+		require.Error(t, err)
+	})
+}
+
+func TestCheckQueryPlan(t *testing.T) {
+	plan := &QueryPlan{Cost: 1612.5, FullScanRows: 15000}
+
+	require.NoError(t, checkQueryPlan(plan, JsonData{}))
+	require.NoError(t, checkQueryPlan(plan, JsonData{MaxEstimatedCost: 2000, MaxFullScanRows: 20000}))
+	require.ErrorContains(t, checkQueryPlan(plan, JsonData{MaxEstimatedCost: 1000}), "estimated cost")
+	require.ErrorContains(t, checkQueryPlan(plan, JsonData{MaxFullScanRows: 10000}), "full table scan")
+}
+
+func TestDataSourceRowLimit(t *testing.T) {
+	config := func(server, dataSource int64) DataPluginConfiguration {
+		return DataPluginConfiguration{
+			DSInfo:   DataSourceInfo{JsonData: JsonData{RowLimit: dataSource}},
+			RowLimit: server,
+		}
+	}
+
+	require.Equal(t, int64(1000), dataSourceRowLimit(config(1000, 0)))
+	require.Equal(t, int64(100), dataSourceRowLimit(config(1000, 100)))
+	require.Equal(t, int64(1000), dataSourceRowLimit(config(1000, 5000)))
+	require.Equal(t, int64(100), dataSourceRowLimit(config(-1, 100)))
+}
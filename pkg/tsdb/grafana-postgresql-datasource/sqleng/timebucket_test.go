@@ -0,0 +1,102 @@
+package sqleng
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeBucket(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	t.Run("parses the arguments of the macro", func(t *testing.T) {
+		bucket, fill, err := ParseTimeBucketArgs([]string{"'month'"})
+		require.NoError(t, err)
+		require.Equal(t, TimeBucket{Unit: "month", Location: time.UTC}, bucket)
+		require.Empty(t, fill)
+
+		bucket, fill, err = ParseTimeBucketArgs([]string{"day", "NULL"})
+		require.NoError(t, err)
+		require.Equal(t, TimeBucket{Unit: "day", Location: time.UTC}, bucket)
+		require.Equal(t, "NULL", fill)
+
+		bucket, fill, err = ParseTimeBucketArgs([]string{"week", "'Europe/Berlin'", "0"})
+		require.NoError(t, err)
+		require.Equal(t, TimeBucket{Unit: "week", Location: berlin}, bucket)
+		require.Equal(t, "0", fill)
+
+		_, _, err = ParseTimeBucketArgs([]string{"month", "'Mars/Olympus_Mons'"})
+		require.Error(t, err)
+		_, _, err = ParseTimeBucketArgs([]string{"hour"})
+		require.Error(t, err)
+	})
+
+	t.Run("buckets follow daylight saving time changes", func(t *testing.T) {
+		bucket := TimeBucket{Unit: "day", Location: berlin}
+		// 2024-03-31 has 23 hours in Berlin.
+		start := bucket.Start(time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC))
+		require.Equal(t, time.Date(2024, 3, 30, 23, 0, 0, 0, time.UTC), start.UTC())
+		require.Equal(t, time.Date(2024, 3, 31, 22, 0, 0, 0, time.UTC), bucket.Next(start).UTC())
+	})
+
+	t.Run("buckets start on calendar boundaries", func(t *testing.T) {
+		at := time.Date(2024, 8, 15, 10, 30, 0, 0, berlin) // a Thursday
+		for unit, expected := range map[string]time.Time{
+			"day":     time.Date(2024, 8, 15, 0, 0, 0, 0, berlin),
+			"week":    time.Date(2024, 8, 12, 0, 0, 0, 0, berlin),
+			"month":   time.Date(2024, 8, 1, 0, 0, 0, 0, berlin),
+			"quarter": time.Date(2024, 7, 1, 0, 0, 0, 0, berlin),
+			"year":    time.Date(2024, 1, 1, 0, 0, 0, 0, berlin),
+		} {
+			require.Equal(t, expected, TimeBucket{Unit: unit, Location: berlin}.Start(at), unit)
+		}
+		require.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, berlin),
+			TimeBucket{Unit: "quarter", Location: berlin}.Next(time.Date(2024, 7, 1, 0, 0, 0, 0, berlin)))
+	})
+
+	t.Run("limits the number of buckets", func(t *testing.T) {
+		bucket := TimeBucket{Unit: "day", Location: time.UTC}
+		from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		_, err := bucket.Starts(backend.TimeRange{From: from, To: from.AddDate(20, 0, 0)})
+		require.Error(t, err)
+	})
+
+	t.Run("nests the comparisons of many buckets", func(t *testing.T) {
+		bucket := TimeBucket{Unit: "day", Location: time.UTC}
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		expr, err := bucket.Expr("t", backend.TimeRange{From: from, To: from.AddDate(0, 0, 99)}, func(t time.Time) string {
+			return fmt.Sprint(t.Unix())
+		})
+		require.NoError(t, err)
+		// 100 buckets are split in 15 groups of up to 7 buckets, each with its own CASE.
+		require.True(t, strings.HasPrefix(expr, fmt.Sprintf("CASE WHEN t < %d THEN CASE WHEN t < %d THEN %d WHEN",
+			from.AddDate(0, 0, 7).Unix(), from.AddDate(0, 0, 1).Unix(), from.Unix())), expr)
+		require.Equal(t, 1+15, strings.Count(expr, "CASE"))
+	})
+}
+
+func TestResampleToTimeBuckets(t *testing.T) {
+	bucket := TimeBucket{Unit: "month", Location: time.UTC}
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	one := 1.0
+	three := 3.0
+	frame := data.NewFrame("",
+		data.NewField("time", nil, []*time.Time{&jan, &mar}),
+		data.NewField("value", nil, []*float64{&one, &three}),
+	)
+
+	resampled, err := resampleToTimeBuckets(frame, &data.FillMissing{Mode: data.FillModeValue, Value: 2},
+		backend.TimeRange{From: jan.AddDate(0, 0, 14), To: mar.AddDate(0, 0, 14)}, bucket)
+	require.NoError(t, err)
+	require.Equal(t, 3, resampled.Rows())
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, []*time.Time{&jan, &feb, &mar}, []*time.Time{resampled.At(0, 0).(*time.Time), resampled.At(0, 1).(*time.Time), resampled.At(0, 2).(*time.Time)})
+	require.Equal(t, []float64{1, 2, 3}, []float64{*resampled.At(1, 0).(*float64), *resampled.At(1, 1).(*float64), *resampled.At(1, 2).(*float64)})
+}
@@ -0,0 +1,164 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+var errAlertingDisabled = errors.New("alerting is disabled")
+
+// doAlertsQuery returns a single frame with a row for every current alert instance of the organization
+// whose rule is in a folder that the user can read alert rules in.
+func (s *Service) doAlertsQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	q := &alertsQueryModel{}
+	response := backend.DataResponse{}
+	err := json.Unmarshal(query.JSON, &q)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	if s.alertInstances == nil {
+		response.Error = errAlertingDisabled
+		return response
+	}
+
+	if err := s.authorize(ctx, accesscontrol.EvalPermission(accesscontrol.ActionAlertingInstanceRead)); err != nil {
+		response.Error = err
+		return response
+	}
+
+	var instances []*state.State
+	if q.Alerts.RuleUID != "" {
+		instances = s.alertInstances.GetStatesForRuleUID(req.PluginContext.OrgID, q.Alerts.RuleUID)
+	} else {
+		instances = s.alertInstances.GetAll(req.PluginContext.OrgID)
+	}
+	instances = slices.DeleteFunc(instances, func(st *state.State) bool {
+		return !matchesAlertsQuery(st, q.Alerts)
+	})
+	instances, err = s.filterReadableInstances(ctx, instances)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].AlertRuleUID != instances[j].AlertRuleUID {
+			return instances[i].AlertRuleUID < instances[j].AlertRuleUID
+		}
+		return instances[i].CacheID < instances[j].CacheID
+	})
+
+	activeAt := make([]time.Time, 0, len(instances))
+	ruleUIDs := make([]string, 0, len(instances))
+	states := make([]string, 0, len(instances))
+	labels := make([]json.RawMessage, 0, len(instances))
+	annotations := make([]json.RawMessage, 0, len(instances))
+	values := make([]json.RawMessage, 0, len(instances))
+	lastEvaluation := make([]time.Time, 0, len(instances))
+	for _, st := range instances {
+		l, err := json.Marshal(st.GetLabels(ngmodels.WithoutInternalLabels()))
+		if err != nil {
+			response.Error = err
+			return response
+		}
+		a, err := json.Marshal(st.Annotations)
+		if err != nil {
+			response.Error = err
+			return response
+		}
+		v, err := json.Marshal(st.Values)
+		if err != nil {
+			response.Error = err
+			return response
+		}
+		activeAt = append(activeAt, st.StartsAt)
+		ruleUIDs = append(ruleUIDs, st.AlertRuleUID)
+		states = append(states, state.FormatStateAndReason(st.State, st.StateReason))
+		labels = append(labels, l)
+		annotations = append(annotations, a)
+		values = append(values, v)
+		lastEvaluation = append(lastEvaluation, st.LastEvaluationTime)
+	}
+
+	frame := data.NewFrame("alerts",
+		data.NewField("activeAt", nil, activeAt),
+		data.NewField("ruleUID", nil, ruleUIDs),
+		data.NewField("state", nil, states),
+		data.NewField("labels", nil, labels),
+		data.NewField("annotations", nil, annotations),
+		data.NewField("values", nil, values),
+		data.NewField("lastEvaluation", nil, lastEvaluation),
+	)
+	response.Frames = data.Frames{frame}
+	return response
+}
+
+func matchesAlertsQuery(st *state.State, q alertsQuery) bool {
+	if len(q.States) > 0 && !slices.ContainsFunc(q.States, func(s string) bool {
+		return strings.EqualFold(s, st.State.String())
+	}) {
+		return false
+	}
+	for k, v := range q.Labels {
+		if st.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// filterReadableInstances returns the instances of the rules in folders that the user of the request can read
+// alert rules in, in the same way as the Prometheus rules API.
+func (s *Service) filterReadableInstances(ctx context.Context, instances []*state.State) ([]*state.State, error) {
+	user, err := identity.GetRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+	readable := map[string]bool{}
+	for _, st := range instances {
+		namespaceUID := st.Labels[alertingModels.NamespaceUIDLabel]
+		if _, ok := readable[namespaceUID]; ok {
+			continue
+		}
+		ok, err := s.ruleAuthz.HasAccessInFolder(ctx, user, ngmodels.Namespace{UID: namespaceUID})
+		if err != nil {
+			return nil, err
+		}
+		readable[namespaceUID] = ok
+	}
+	return slices.DeleteFunc(instances, func(st *state.State) bool {
+		return !readable[st.Labels[alertingModels.NamespaceUIDLabel]]
+	}), nil
+}
+
+// authorize checks that the user of the request has the permissions.
+func (s *Service) authorize(ctx context.Context, evaluator accesscontrol.Evaluator) error {
+	user, err := identity.GetRequester(ctx)
+	if err != nil {
+		return err
+	}
+	ok, err := s.accessControl.Evaluate(ctx, user, evaluator)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("access denied: missing permissions %s", evaluator.String())
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeAlertInstanceManager struct {
+	states []*state.State
+}
+
+func (f *fakeAlertInstanceManager) GetAll(orgID int64) []*state.State {
+	var result []*state.State
+	for _, st := range f.states {
+		if st.OrgID == orgID {
+			result = append(result, st)
+		}
+	}
+	return result
+}
+
+func (f *fakeAlertInstanceManager) GetStatesForRuleUID(orgID int64, alertRuleUID string) []*state.State {
+	var result []*state.State
+	for _, st := range f.GetAll(orgID) {
+		if st.AlertRuleUID == alertRuleUID {
+			result = append(result, st)
+		}
+	}
+	return result
+}
+
+func newAlertState(ruleUID, folderUID string, s eval.State, lbls data.Labels) *state.State {
+	l := data.Labels{
+		alertingModels.RuleUIDLabel:      ruleUID,
+		alertingModels.NamespaceUIDLabel: folderUID,
+	}
+	for k, v := range lbls {
+		l[k] = v
+	}
+	return &state.State{OrgID: 1, AlertRuleUID: ruleUID, State: s, Labels: l}
+}
+
+func readFolderPermissions(folderUIDs ...string) map[string][]string {
+	permissions := map[string][]string{
+		accesscontrol.ActionAlertingInstanceRead: nil,
+	}
+	for _, uid := range folderUIDs {
+		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(uid)
+		permissions[accesscontrol.ActionAlertingRuleRead] = append(permissions[accesscontrol.ActionAlertingRuleRead], scope)
+		permissions[dashboards.ActionFoldersRead] = append(permissions[dashboards.ActionFoldersRead], scope)
+	}
+	return permissions
+}
+
+func queryAlerts(t *testing.T, s *Service, permissions map[string][]string, query alertsQuery) backend.DataResponse {
+	t.Helper()
+	body, err := json.Marshal(alertsQueryModel{Alerts: query})
+	require.NoError(t, err)
+	ctx := identity.WithRequester(context.Background(), &user.SignedInUser{
+		OrgID:       1,
+		Permissions: map[int64]map[string][]string{1: permissions},
+	})
+	resp, err := s.QueryData(ctx, &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{OrgID: 1},
+		Queries:       []backend.DataQuery{{RefID: "A", QueryType: queryTypeAlerts, JSON: body}},
+	})
+	require.NoError(t, err)
+	return resp.Responses["A"]
+}
+
+func TestAlertsQuery(t *testing.T) {
+	s := newService(nil, nil, featuremgmt.WithFeatures(), acimpl.ProvideAccessControl(featuremgmt.WithFeatures()), nil)
+	s.alertInstances = &fakeAlertInstanceManager{states: []*state.State{
+		newAlertState("rule-b", "folder-1", eval.Normal, data.Labels{"team": "web"}),
+		newAlertState("rule-a", "folder-1", eval.Alerting, data.Labels{"team": "db"}),
+		newAlertState("rule-c", "folder-2", eval.Alerting, data.Labels{"team": "db"}),
+	}}
+
+	ruleUIDs := func(t *testing.T, resp backend.DataResponse) []string {
+		t.Helper()
+		require.NoError(t, resp.Error)
+		require.Len(t, resp.Frames, 1)
+		field, _ := resp.Frames[0].FieldByName("ruleUID")
+		require.NotNil(t, field)
+		var result []string
+		for i := 0; i < field.Len(); i++ {
+			result = append(result, field.At(i).(string))
+		}
+		return result
+	}
+
+	t.Run("returns the instances in the folders the user can read rules in", func(t *testing.T) {
+		resp := queryAlerts(t, s, readFolderPermissions("folder-1"), alertsQuery{})
+		require.Equal(t, []string{"rule-a", "rule-b"}, ruleUIDs(t, resp))
+
+		resp = queryAlerts(t, s, readFolderPermissions("folder-1", "folder-2"), alertsQuery{})
+		require.Equal(t, []string{"rule-a", "rule-b", "rule-c"}, ruleUIDs(t, resp))
+	})
+
+	t.Run("filters the instances by rule, state and labels", func(t *testing.T) {
+		permissions := readFolderPermissions("folder-1", "folder-2")
+
+		resp := queryAlerts(t, s, permissions, alertsQuery{RuleUID: "rule-b"})
+		require.Equal(t, []string{"rule-b"}, ruleUIDs(t, resp))
+
+		resp = queryAlerts(t, s, permissions, alertsQuery{States: []string{"alerting"}})
+		require.Equal(t, []string{"rule-a", "rule-c"}, ruleUIDs(t, resp))
+
+		resp = queryAlerts(t, s, permissions, alertsQuery{Labels: map[string]string{"team": "web"}})
+		require.Equal(t, []string{"rule-b"}, ruleUIDs(t, resp))
+	})
+
+	t.Run("does not return internal labels", func(t *testing.T) {
+		resp := queryAlerts(t, s, readFolderPermissions("folder-1"), alertsQuery{RuleUID: "rule-a"})
+		require.NoError(t, resp.Error)
+		field, _ := resp.Frames[0].FieldByName("labels")
+		require.NotNil(t, field)
+		require.JSONEq(t, `{"team":"db"}`, string(field.At(0).(json.RawMessage)))
+	})
+
+	t.Run("requires the permission to read alert instances", func(t *testing.T) {
+		permissions := readFolderPermissions("folder-1")
+		delete(permissions, accesscontrol.ActionAlertingInstanceRead)
+		resp := queryAlerts(t, s, permissions, alertsQuery{})
+		require.ErrorContains(t, resp.Error, "access denied")
+	})
+
+	t.Run("fails when alerting is disabled", func(t *testing.T) {
+		disabled := newService(nil, nil, featuremgmt.WithFeatures(), acimpl.ProvideAccessControl(featuremgmt.WithFeatures()), nil)
+		resp := queryAlerts(t, disabled, readFolderPermissions("folder-1"), alertsQuery{})
+		require.ErrorIs(t, resp.Error, errAlertingDisabled)
+	})
+}
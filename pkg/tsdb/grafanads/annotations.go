@@ -0,0 +1,101 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+const defaultAnnotationsLimit = 100
+
+// doAnnotationsQuery returns a single frame with a row for every annotation in the time range of the query
+// that the user can read.
+func (s *Service) doAnnotationsQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	q := &annotationsQueryModel{}
+	response := backend.DataResponse{}
+	err := json.Unmarshal(query.JSON, &q)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	if s.annotations == nil {
+		response.Error = errors.New("annotations are not available")
+		return response
+	}
+
+	user, err := identity.GetRequester(ctx)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	limit := q.Annotations.Limit
+	if limit <= 0 {
+		limit = defaultAnnotationsLimit
+	}
+
+	items, err := s.annotations.Find(ctx, &annotations.ItemQuery{
+		OrgID:        req.PluginContext.OrgID,
+		From:         query.TimeRange.From.UnixMilli(),
+		To:           query.TimeRange.To.UnixMilli(),
+		DashboardUID: q.Annotations.DashboardUID,
+		Tags:         q.Annotations.Tags,
+		MatchAny:     q.Annotations.MatchAny,
+		Type:         q.Annotations.Type,
+		Limit:        limit,
+		SignedInUser: user,
+	})
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	times := make([]time.Time, 0, len(items))
+	timeEnds := make([]time.Time, 0, len(items))
+	ids := make([]int64, 0, len(items))
+	texts := make([]string, 0, len(items))
+	tags := make([]json.RawMessage, 0, len(items))
+	dashboardUIDs := make([]string, 0, len(items))
+	panelIDs := make([]int64, 0, len(items))
+	logins := make([]string, 0, len(items))
+	for _, item := range items {
+		t, err := json.Marshal(item.Tags)
+		if err != nil {
+			response.Error = err
+			return response
+		}
+		dashboardUID := ""
+		if item.DashboardUID != nil {
+			dashboardUID = *item.DashboardUID
+		}
+		times = append(times, time.UnixMilli(item.Time))
+		timeEnds = append(timeEnds, time.UnixMilli(item.TimeEnd))
+		ids = append(ids, item.ID)
+		texts = append(texts, item.Text)
+		tags = append(tags, t)
+		dashboardUIDs = append(dashboardUIDs, dashboardUID)
+		panelIDs = append(panelIDs, item.PanelID)
+		logins = append(logins, item.Login)
+	}
+
+	frame := data.NewFrame("annotations",
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("id", nil, ids),
+		data.NewField("text", nil, texts),
+		data.NewField("tags", nil, tags),
+		data.NewField("dashboardUID", nil, dashboardUIDs),
+		data.NewField("panelId", nil, panelIDs),
+		data.NewField("login", nil, logins),
+	)
+	response.Frames = data.Frames{frame}
+	return response
+}
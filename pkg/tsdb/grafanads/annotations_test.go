@@ -0,0 +1,99 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeAnnotationsRepository struct {
+	annotations.Repository
+	items []*annotations.ItemDTO
+	query *annotations.ItemQuery
+}
+
+func (f *fakeAnnotationsRepository) Find(_ context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	f.query = query
+	return f.items, nil
+}
+
+func TestAnnotationsQuery(t *testing.T) {
+	dashboardUID := "dashboard"
+	repo := &fakeAnnotationsRepository{items: []*annotations.ItemDTO{
+		{ID: 1, Time: 1000, TimeEnd: 2000, Text: "deploy", Tags: []string{"deploy"}, DashboardUID: &dashboardUID, PanelID: 2, Login: "admin"},
+		{ID: 2, Time: 3000, TimeEnd: 3000, Text: "restart"},
+	}}
+	s := newService(nil, nil, featuremgmt.WithFeatures(), acimpl.ProvideAccessControl(featuremgmt.WithFeatures()), nil)
+	s.annotations = repo
+
+	signedInUser := &user.SignedInUser{OrgID: 1}
+	ctx := identity.WithRequester(context.Background(), signedInUser)
+	query := func(t *testing.T, q annotationsQuery) backend.DataResponse {
+		t.Helper()
+		body, err := json.Marshal(annotationsQueryModel{Annotations: q})
+		require.NoError(t, err)
+		resp, err := s.QueryData(ctx, &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{OrgID: 1},
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				QueryType: queryTypeSearchAnnotations,
+				JSON:      body,
+				TimeRange: backend.TimeRange{From: time.UnixMilli(500), To: time.UnixMilli(5000)},
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("returns a row for every annotation", func(t *testing.T) {
+		resp := query(t, annotationsQuery{})
+		require.NoError(t, resp.Error)
+		require.Len(t, resp.Frames, 1)
+		frame := resp.Frames[0]
+		rows, err := frame.RowLen()
+		require.NoError(t, err)
+		require.Equal(t, 2, rows)
+
+		text, _ := frame.FieldByName("text")
+		require.Equal(t, "deploy", text.At(0))
+		tags, _ := frame.FieldByName("tags")
+		require.JSONEq(t, `["deploy"]`, string(tags.At(0).(json.RawMessage)))
+		dashboard, _ := frame.FieldByName("dashboardUID")
+		require.Equal(t, "dashboard", dashboard.At(0))
+		require.Equal(t, "", dashboard.At(1))
+		timeEnd, _ := frame.FieldByName("timeEnd")
+		require.True(t, time.UnixMilli(2000).Equal(timeEnd.At(0).(time.Time)))
+	})
+
+	t.Run("passes the filters, time range and user to the repository", func(t *testing.T) {
+		resp := query(t, annotationsQuery{DashboardUID: "dashboard", Tags: []string{"deploy"}, MatchAny: true, Type: "annotation", Limit: 10})
+		require.NoError(t, resp.Error)
+		require.Equal(t, &annotations.ItemQuery{
+			OrgID:        1,
+			From:         500,
+			To:           5000,
+			DashboardUID: "dashboard",
+			Tags:         []string{"deploy"},
+			MatchAny:     true,
+			Type:         "annotation",
+			Limit:        10,
+			SignedInUser: signedInUser,
+		}, repo.query)
+	})
+
+	t.Run("limits the annotations by default", func(t *testing.T) {
+		resp := query(t, annotationsQuery{})
+		require.NoError(t, resp.Error)
+		require.Equal(t, int64(defaultAnnotationsLimit), repo.query.Limit)
+	})
+}
@@ -15,8 +15,12 @@ import (
 	"github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	alertingac "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/store"
 	testdatasource "github.com/grafana/grafana/pkg/tsdb/grafana-testdata-datasource"
@@ -53,16 +57,19 @@ var (
 	)
 )
 
-func ProvideService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
-	return newService(search, store, features)
+func ProvideService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles, accessControl accesscontrol.AccessControl, gatherer prometheus.Gatherer) *Service {
+	return newService(search, store, features, accessControl, gatherer)
 }
 
-func newService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
+func newService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles, accessControl accesscontrol.AccessControl, gatherer prometheus.Gatherer) *Service {
 	s := &Service{
-		search:   search,
-		store:    store,
-		log:      log.New("grafanads"),
-		features: features,
+		search:        search,
+		store:         store,
+		log:           log.New("grafanads"),
+		features:      features,
+		accessControl: accessControl,
+		ruleAuthz:     alertingac.NewRuleService(accessControl),
+		gatherer:      gatherer,
 	}
 
 	return s
@@ -70,10 +77,18 @@ func newService(search searchV2.SearchService, store store.StorageService, featu
 
 // Service exists regardless of user settings
 type Service struct {
-	search   searchV2.SearchService
-	store    store.StorageService
-	log      log.Logger
-	features featuremgmt.FeatureToggles
+	search        searchV2.SearchService
+	store         store.StorageService
+	log           log.Logger
+	features      featuremgmt.FeatureToggles
+	accessControl accesscontrol.AccessControl
+	ruleAuthz     *alertingac.RuleService
+	gatherer      prometheus.Gatherer
+
+	// alertInstances and annotations are set by ProvideRegistration, as the services
+	// that provide them depend on the plugin registry, and so on this service.
+	alertInstances state.AlertInstanceManager
+	annotations    annotations.Repository
 }
 
 func DataSourceModel(orgId int64) *datasources.DataSource {
@@ -101,6 +116,12 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 			response.Responses[q.RefID] = s.doReadQuery(ctx, q)
 		case queryTypeSearch, queryTypeSearchNext:
 			response.Responses[q.RefID] = s.doSearchQuery(ctx, req, q)
+		case queryTypeAlerts:
+			response.Responses[q.RefID] = s.doAlertsQuery(ctx, req, q)
+		case queryTypeSearchAnnotations:
+			response.Responses[q.RefID] = s.doAnnotationsQuery(ctx, req, q)
+		case queryTypeMetrics:
+			response.Responses[q.RefID] = s.doMetricsQuery(ctx, q)
 		default:
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
@@ -0,0 +1,94 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+// doMetricsQuery returns a frame with the current value of every series of Grafana's own metrics whose name
+// matches the query. Histograms and summaries are returned as their _count and _sum series.
+func (s *Service) doMetricsQuery(ctx context.Context, query backend.DataQuery) backend.DataResponse {
+	q := &metricsQueryModel{}
+	response := backend.DataResponse{}
+	err := json.Unmarshal(query.JSON, &q)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	// The metrics are not scoped to the organization, so they are only available to server admins.
+	if err := s.authorize(ctx, accesscontrol.EvalPermission(accesscontrol.ActionServerStatsRead)); err != nil {
+		response.Error = err
+		return response
+	}
+
+	name, err := regexp.Compile("^(?:" + q.Metrics.Name + ")$")
+	if err != nil {
+		response.Error = fmt.Errorf("invalid metric name: %w", err)
+		return response
+	}
+
+	families, err := s.gatherer.Gather()
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	now := time.Now()
+	for _, family := range families {
+		if !name.MatchString(family.GetName()) {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := data.Labels{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			for _, v := range metricValues(family.GetType(), m) {
+				frame := data.NewFrame(family.GetName()+v.suffix,
+					data.NewField(data.TimeSeriesTimeFieldName, nil, []time.Time{now}),
+					data.NewField(data.TimeSeriesValueFieldName, labels, []float64{v.value}),
+				)
+				frame.Meta = &data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti}
+				response.Frames = append(response.Frames, frame)
+			}
+		}
+	}
+	return response
+}
+
+type metricValue struct {
+	// suffix is appended to the name of the metric to get the name of the series.
+	suffix string
+	value  float64
+}
+
+func metricValues(typ dto.MetricType, m *dto.Metric) []metricValue {
+	switch typ {
+	case dto.MetricType_COUNTER:
+		return []metricValue{{value: m.GetCounter().GetValue()}}
+	case dto.MetricType_GAUGE:
+		return []metricValue{{value: m.GetGauge().GetValue()}}
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		return []metricValue{
+			{suffix: "_count", value: float64(m.GetHistogram().GetSampleCount())},
+			{suffix: "_sum", value: m.GetHistogram().GetSampleSum()},
+		}
+	case dto.MetricType_SUMMARY:
+		return []metricValue{
+			{suffix: "_count", value: float64(m.GetSummary().GetSampleCount())},
+			{suffix: "_sum", value: m.GetSummary().GetSampleSum()},
+		}
+	default:
+		return []metricValue{{value: m.GetUntyped().GetValue()}}
+	}
+}
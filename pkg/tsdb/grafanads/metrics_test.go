@@ -0,0 +1,86 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestMetricsQuery(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_requests_total"}, []string{"status"})
+	requests.WithLabelValues("200").Add(3)
+	duration := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_request_duration_seconds"})
+	duration.Observe(0.5)
+	duration.Observe(1.5)
+	other := prometheus.NewGauge(prometheus.GaugeOpts{Name: "other_gauge"})
+	registry.MustRegister(requests, duration, other)
+
+	s := newService(nil, nil, featuremgmt.WithFeatures(), acimpl.ProvideAccessControl(featuremgmt.WithFeatures()), registry)
+
+	query := func(t *testing.T, permissions map[string][]string, name string) backend.DataResponse {
+		t.Helper()
+		body, err := json.Marshal(metricsQueryModel{Metrics: metricsQuery{Name: name}})
+		require.NoError(t, err)
+		ctx := identity.WithRequester(context.Background(), &user.SignedInUser{
+			OrgID:       1,
+			Permissions: map[int64]map[string][]string{1: permissions},
+		})
+		resp, err := s.QueryData(ctx, &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{OrgID: 1},
+			Queries:       []backend.DataQuery{{RefID: "A", QueryType: queryTypeMetrics, JSON: body}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+	serverAdmin := map[string][]string{accesscontrol.ActionServerStatsRead: nil}
+
+	t.Run("returns a series for every matching metric", func(t *testing.T) {
+		resp := query(t, serverAdmin, "test_.*")
+		require.NoError(t, resp.Error)
+
+		values := map[string]float64{}
+		for _, frame := range resp.Frames {
+			require.Len(t, frame.Fields, 2)
+			values[frame.Name] = frame.Fields[1].At(0).(float64)
+		}
+		require.Equal(t, map[string]float64{
+			"test_requests_total":                 3,
+			"test_request_duration_seconds_count": 2,
+			"test_request_duration_seconds_sum":   2,
+		}, values)
+
+		for _, frame := range resp.Frames {
+			if frame.Name == "test_requests_total" {
+				require.Equal(t, data.Labels{"status": "200"}, frame.Fields[1].Labels)
+			}
+		}
+	})
+
+	t.Run("matches the whole name of the metric", func(t *testing.T) {
+		resp := query(t, serverAdmin, "test_requests")
+		require.NoError(t, resp.Error)
+		require.Empty(t, resp.Frames)
+	})
+
+	t.Run("fails for an invalid name", func(t *testing.T) {
+		resp := query(t, serverAdmin, "(")
+		require.ErrorContains(t, resp.Error, "invalid metric name")
+	})
+
+	t.Run("requires the permission to read server stats", func(t *testing.T) {
+		resp := query(t, map[string][]string{}, "test_.*")
+		require.ErrorContains(t, resp.Error, "access denied")
+	})
+}
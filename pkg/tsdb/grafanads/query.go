@@ -17,6 +17,16 @@ const (
 	// currently only .csv files are supported,
 	// other file types will eventually be supported (parquet, etc)
 	queryTypeRead = "read"
+
+	// queryTypeAlerts returns the current alert instances with their state and labels
+	queryTypeAlerts = "alerts"
+
+	// queryTypeSearchAnnotations returns the annotations in the time range of the query.
+	// It is not "annotations", as that is used by the frontend to query annotations without the backend.
+	queryTypeSearchAnnotations = "searchAnnotations"
+
+	// queryTypeMetrics returns the current value of Grafana's own internal metrics
+	queryTypeMetrics = "metrics"
 )
 
 type listQueryModel struct {
@@ -25,3 +35,38 @@ type listQueryModel struct {
 type readQueryModel struct {
 	Path string `json:"path"`
 }
+
+type alertsQueryModel struct {
+	Alerts alertsQuery `json:"alerts"`
+}
+
+type alertsQuery struct {
+	// RuleUID limits the instances to the ones of a single alert rule.
+	RuleUID string `json:"ruleUID,omitempty"`
+	// States limits the instances to the ones in any of the states, e.g. Alerting or Pending.
+	States []string `json:"states,omitempty"`
+	// Labels limits the instances to the ones that have all the labels.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type annotationsQueryModel struct {
+	Annotations annotationsQuery `json:"annotations"`
+}
+
+type annotationsQuery struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	MatchAny     bool     `json:"matchAny,omitempty"`
+	// Type is either "annotation" or "alert", and is empty to include both.
+	Type  string `json:"type,omitempty"`
+	Limit int64  `json:"limit,omitempty"`
+}
+
+type metricsQueryModel struct {
+	Metrics metricsQuery `json:"metrics"`
+}
+
+type metricsQuery struct {
+	// Name is a regular expression that the name of the metric must fully match.
+	Name string `json:"name,omitempty"`
+}
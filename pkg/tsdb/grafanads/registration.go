@@ -0,0 +1,19 @@
+package grafanads
+
+import (
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/ngalert"
+)
+
+// Registration is returned by ProvideRegistration so that it can be required by the background services.
+type Registration struct{}
+
+// ProvideRegistration connects the service to the current alert instances and the annotations. They cannot be
+// passed to ProvideService, as the services that provide them depend on the plugin registry, and so on this service.
+func ProvideRegistration(s *Service, ng *ngalert.AlertNG, annotationsRepo annotations.Repository) Registration {
+	if ng != nil && !ng.IsDisabled() {
+		s.alertInstances = ng.GetAlertInstanceManager()
+	}
+	s.annotations = annotationsRepo
+	return Registration{}
+}
@@ -0,0 +1,224 @@
+package sqleng
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// QueryPlan is the plan of a query, as estimated by the database with EXPLAIN.
+type QueryPlan struct {
+	// Frame is the plan returned to the query editor.
+	Frame *data.Frame
+	// Cost is the estimated cost of the query, in the unit of the database.
+	Cost float64
+	// FullScanRows is the estimated number of rows of the largest table read with a full table scan.
+	FullScanRows int64
+}
+
+// checkQueryPlan returns an error if the plan exceeds the limits of the data source.
+func checkQueryPlan(plan *QueryPlan, jsonData JsonData) error {
+	if jsonData.MaxEstimatedCost > 0 && plan.Cost > jsonData.MaxEstimatedCost {
+		return backend.DownstreamErrorf("the estimated cost of the query (%.2f) exceeds the limit of the data source (%.2f)",
+			plan.Cost, jsonData.MaxEstimatedCost)
+	}
+	if jsonData.MaxFullScanRows > 0 && plan.FullScanRows > jsonData.MaxFullScanRows {
+		return backend.DownstreamErrorf("the query reads a table of about %d rows with a full table scan, which exceeds the limit of the data source (%d rows)",
+			plan.FullScanRows, jsonData.MaxFullScanRows)
+	}
+	return nil
+}
+
+// preflight explains the query when the query asks for its plan, or when the data source limits the queries
+// by their plan. It returns a response when the query must not run: either the plan of the query, or the
+// error of a query above the limits. Queries that cannot be explained run unchecked.
+func (e *DataSourceHandler) preflight(ctx context.Context, logger log.Logger, db *sql.DB, query string, queryJson QueryJson) *backend.DataResponse {
+	jsonData := e.dsInfo.JsonData
+	if !queryJson.Explain && jsonData.MaxEstimatedCost <= 0 && jsonData.MaxFullScanRows <= 0 {
+		return nil
+	}
+
+	plan, err := e.explain(ctx, db, query)
+	if err != nil {
+		if !queryJson.Explain {
+			logger.Warn("Failed to explain query, running it without checking its plan", "error", err)
+			return nil
+		}
+		var emptyFrame data.Frame
+		emptyFrame.SetMeta(&data.FrameMeta{ExecutedQueryString: query})
+		return &backend.DataResponse{
+			Error:       fmt.Errorf("explain failed: %w", e.TransformQueryError(logger, err)),
+			ErrorSource: backend.ErrorSourceDownstream,
+			Frames:      data.Frames{&emptyFrame},
+		}
+	}
+
+	if queryJson.Explain {
+		return &backend.DataResponse{Frames: data.Frames{plan.Frame}}
+	}
+	if err := checkQueryPlan(plan, jsonData); err != nil {
+		return &backend.DataResponse{
+			Error:       err,
+			ErrorSource: backend.ErrorSourceDownstream,
+			Frames:      data.Frames{plan.Frame},
+		}
+	}
+	return nil
+}
+
+// explain returns the plan of the query, from SHOWPLAN_XML. The plan is only returned on the connection
+// SHOWPLAN_XML is set on, and the queries of the connection are not run until it is unset.
+func (e *DataSourceHandler) explain(ctx context.Context, db *sql.DB, query string) (*QueryPlan, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// A connection that still has SHOWPLAN_XML set must not go back to the pool, as it would not run queries.
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SET SHOWPLAN_XML OFF"); err != nil {
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		if err := conn.Close(); err != nil {
+			e.log.Warn("Failed to close connection", "err", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, "SET SHOWPLAN_XML ON"); err != nil {
+		return nil, err
+	}
+	var raw string
+	if err := conn.QueryRowContext(ctx, query).Scan(&raw); err != nil {
+		return nil, err
+	}
+	plan, err := parseQueryPlan(raw)
+	if err != nil {
+		return nil, err
+	}
+	plan.Frame.Meta.ExecutedQueryString = "SET SHOWPLAN_XML ON\n" + query
+	return plan, nil
+}
+
+// fullScanOperators are the physical operators of the plan that read all the rows of a table.
+var fullScanOperators = map[string]bool{
+	"Table Scan":           true,
+	"Clustered Index Scan": true,
+	"Index Scan":           true,
+}
+
+// parseQueryPlan parses the output of SHOWPLAN_XML. The plan has a row for every operator of the plan, and
+// its cost is the sum of the estimated subtree costs of its statements.
+func parseQueryPlan(raw string) (*QueryPlan, error) {
+	plan := &QueryPlan{}
+	operators := []string{}
+	objects := []string{}
+	estimatedRows := []float64{}
+	subtreeCosts := []float64{}
+
+	statements := 0
+	// depth is the number of the RelOp elements the decoder is in.
+	depth := 0
+	// objectOf is the row of the RelOp whose object is not known yet, or -1.
+	objectOf := -1
+	decoder := xml.NewDecoder(strings.NewReader(raw))
+	// The plan declares the UTF-16 encoding of the server, but the driver already decoded it.
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse query plan: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			attrs := make(map[string]string, len(element.Attr))
+			for _, attr := range element.Attr {
+				attrs[attr.Name.Local] = attr.Value
+			}
+			switch element.Name.Local {
+			case "StmtSimple":
+				statements++
+				plan.Cost += planNumber(attrs["StatementSubTreeCost"])
+			case "RelOp":
+				operator := attrs["PhysicalOp"]
+				if fullScanOperators[operator] {
+					if rows := int64(planNumber(attrs["TableCardinality"])); rows > plan.FullScanRows {
+						plan.FullScanRows = rows
+					}
+				}
+				objectOf = len(operators)
+				operators = append(operators, strings.Repeat("  ", depth)+operator)
+				objects = append(objects, "")
+				estimatedRows = append(estimatedRows, planNumber(attrs["EstimateRows"]))
+				subtreeCosts = append(subtreeCosts, planNumber(attrs["EstimatedTotalSubtreeCost"]))
+				depth++
+			case "Object":
+				if objectOf >= 0 {
+					objects[objectOf] = planObjectName(attrs)
+					objectOf = -1
+				}
+			}
+		case xml.EndElement:
+			if element.Name.Local == "RelOp" {
+				depth--
+				objectOf = -1
+			}
+		}
+	}
+	if statements == 0 {
+		return nil, errors.New("failed to parse query plan: statements are missing")
+	}
+
+	plan.Frame = data.NewFrame("plan",
+		data.NewField("operator", nil, operators),
+		data.NewField("object", nil, objects),
+		data.NewField("estimatedRows", nil, estimatedRows),
+		data.NewField("subtreeCost", nil, subtreeCosts),
+	)
+	plan.Frame.SetMeta(&data.FrameMeta{
+		PreferredVisualization: data.VisTypeTable,
+		Notices: []data.Notice{{
+			Severity: data.NoticeSeverityInfo,
+			Text:     fmt.Sprintf("Estimated query cost: %.2f", plan.Cost),
+		}},
+	})
+	return plan, nil
+}
+
+// planObjectName returns the name of the table or index of an Object element of the plan.
+func planObjectName(attrs map[string]string) string {
+	parts := []string{}
+	for _, attr := range []string{"Schema", "Table"} {
+		if attrs[attr] != "" {
+			parts = append(parts, attrs[attr])
+		}
+	}
+	name := strings.Join(parts, ".")
+	if attrs["Index"] != "" {
+		name += " " + attrs["Index"]
+	}
+	return name
+}
+
+// planNumber returns the value of a number attribute of the plan.
+func planNumber(v string) float64 {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
@@ -0,0 +1,85 @@
+package sqleng
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const mssqlPlan = `<?xml version="1.0" encoding="utf-16"?>
+<ShowPlanXML xmlns="http://schemas.microsoft.com/sqlserver/2004/07/showplan" Version="1.564" Build="16.0.1000.6">
+  <BatchSequence>
+    <Batch>
+      <Statements>
+        <StmtSimple StatementText="SELECT m.value FROM metric m JOIN host h ON h.id = m.host_id" StatementId="1" StatementType="SELECT" StatementSubTreeCost="1.26" StatementEstRows="15000">
+          <QueryPlan DegreeOfParallelism="1">
+            <RelOp NodeId="0" PhysicalOp="Hash Match" LogicalOp="Inner Join" EstimateRows="15000" EstimatedTotalSubtreeCost="1.26">
+              <OutputList>
+                <ColumnReference Database="[grafana]" Schema="[dbo]" Table="[metric]" Alias="[m]" Column="value" />
+              </OutputList>
+              <Hash>
+                <RelOp NodeId="1" PhysicalOp="Index Seek" LogicalOp="Index Seek" EstimateRows="10" EstimatedTotalSubtreeCost="0.0032" TableCardinality="10">
+                  <IndexScan Ordered="true">
+                    <Object Database="[grafana]" Schema="[dbo]" Table="[host]" Index="[PK_host]" />
+                  </IndexScan>
+                </RelOp>
+                <RelOp NodeId="2" PhysicalOp="Table Scan" LogicalOp="Table Scan" EstimateRows="15000" EstimatedTotalSubtreeCost="0.97" TableCardinality="15000">
+                  <TableScan Ordered="false">
+                    <Object Database="[grafana]" Schema="[dbo]" Table="[metric]" Alias="[m]" />
+                  </TableScan>
+                </RelOp>
+              </Hash>
+            </RelOp>
+          </QueryPlan>
+        </StmtSimple>
+      </Statements>
+    </Batch>
+  </BatchSequence>
+</ShowPlanXML>`
+
+func TestParseQueryPlan(t *testing.T) {
+	t.Run("parses the cost and the operators of the plan", func(t *testing.T) {
+		plan, err := parseQueryPlan(mssqlPlan)
+		require.NoError(t, err)
+		require.Equal(t, 1.26, plan.Cost)
+		require.Equal(t, int64(15000), plan.FullScanRows)
+
+		frame := plan.Frame
+		require.Equal(t, 3, frame.Rows())
+		require.Equal(t, "Hash Match", frame.Fields[0].At(0))
+		require.Equal(t, "  Table Scan", frame.Fields[0].At(2))
+		require.Equal(t, "", frame.Fields[1].At(0))
+		require.Equal(t, "[dbo].[host] [PK_host]", frame.Fields[1].At(1))
+		require.Equal(t, "[dbo].[metric]", frame.Fields[1].At(2))
+		require.Equal(t, 10.0, frame.Fields[2].At(1))
+		require.Equal(t, 0.97, frame.Fields[3].At(2))
+	})
+
+	t.Run("fails without statements", func(t *testing.T) {
+		_, err := parseQueryPlan(`<ShowPlanXML></ShowPlanXML>`)
+		require.Error(t, err)
+	})
+}
+
+func TestCheckQueryPlan(t *testing.T) {
+	plan := &QueryPlan{Cost: 1612.5, FullScanRows: 15000}
+
+	require.NoError(t, checkQueryPlan(plan, JsonData{}))
+	require.NoError(t, checkQueryPlan(plan, JsonData{MaxEstimatedCost: 2000, MaxFullScanRows: 20000}))
+	require.ErrorContains(t, checkQueryPlan(plan, JsonData{MaxEstimatedCost: 1000}), "estimated cost")
+	require.ErrorContains(t, checkQueryPlan(plan, JsonData{MaxFullScanRows: 10000}), "full table scan")
+}
+
+func TestDataSourceRowLimit(t *testing.T) {
+	config := func(server, dataSource int64) DataPluginConfiguration {
+		return DataPluginConfiguration{
+			DSInfo:   DataSourceInfo{JsonData: JsonData{RowLimit: dataSource}},
+			RowLimit: server,
+		}
+	}
+
+	require.Equal(t, int64(1000), dataSourceRowLimit(config(1000, 0)))
+	require.Equal(t, int64(100), dataSourceRowLimit(config(1000, 100)))
+	require.Equal(t, int64(1000), dataSourceRowLimit(config(1000, 5000)))
+	require.Equal(t, int64(100), dataSourceRowLimit(config(-1, 100)))
+}
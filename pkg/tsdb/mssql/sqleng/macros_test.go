@@ -67,6 +67,20 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, "select '2018-04-12T18:05:00Z'", sql)
 		})
 
+		t.Run("interpolate __timeBucket function", func(t *testing.T) {
+			timeRange := backend.TimeRange{
+				From: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC),
+			}
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeBucket(time_column, month, 'Europe/Berlin')")
+			require.Nil(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeBucketAlias(time_column, month, 'Europe/Berlin')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY CASE WHEN time_column < '2024-01-31T23:00:00Z' THEN 1704063600 WHEN time_column < '2024-02-29T23:00:00Z' THEN 1706742000 ELSE 1709247600 END", sql)
+			require.Equal(t, sql+" AS [time]", sql2)
+		})
+
 		t.Run("interpolate __timeGroup function", func(t *testing.T) {
 			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m')")
 			require.Nil(t, err)
@@ -46,26 +46,29 @@ type SqlQueryResultTransformer interface {
 }
 
 type JsonData struct {
-	MaxOpenConns            int    `json:"maxOpenConns"`
-	MaxIdleConns            int    `json:"maxIdleConns"`
-	ConnMaxLifetime         int    `json:"connMaxLifetime"`
-	ConnectionTimeout       int    `json:"connectionTimeout"`
-	Timescaledb             bool   `json:"timescaledb"`
-	Mode                    string `json:"sslmode"`
-	ConfigurationMethod     string `json:"tlsConfigurationMethod"`
-	TlsSkipVerify           bool   `json:"tlsSkipVerify"`
-	RootCertFile            string `json:"sslRootCertFile"`
-	CertFile                string `json:"sslCertFile"`
-	CertKeyFile             string `json:"sslKeyFile"`
-	Timezone                string `json:"timezone"`
-	Encrypt                 string `json:"encrypt"`
-	Servername              string `json:"servername"`
-	TimeInterval            string `json:"timeInterval"`
-	Database                string `json:"database"`
-	SecureDSProxy           bool   `json:"enableSecureSocksProxy"`
-	SecureDSProxyUsername   string `json:"secureSocksProxyUsername"`
-	AllowCleartextPasswords bool   `json:"allowCleartextPasswords"`
-	AuthenticationType      string `json:"authenticationType"`
+	MaxOpenConns            int     `json:"maxOpenConns"`
+	MaxIdleConns            int     `json:"maxIdleConns"`
+	ConnMaxLifetime         int     `json:"connMaxLifetime"`
+	ConnectionTimeout       int     `json:"connectionTimeout"`
+	Timescaledb             bool    `json:"timescaledb"`
+	Mode                    string  `json:"sslmode"`
+	ConfigurationMethod     string  `json:"tlsConfigurationMethod"`
+	TlsSkipVerify           bool    `json:"tlsSkipVerify"`
+	RootCertFile            string  `json:"sslRootCertFile"`
+	CertFile                string  `json:"sslCertFile"`
+	CertKeyFile             string  `json:"sslKeyFile"`
+	Timezone                string  `json:"timezone"`
+	Encrypt                 string  `json:"encrypt"`
+	Servername              string  `json:"servername"`
+	TimeInterval            string  `json:"timeInterval"`
+	Database                string  `json:"database"`
+	SecureDSProxy           bool    `json:"enableSecureSocksProxy"`
+	SecureDSProxyUsername   string  `json:"secureSocksProxyUsername"`
+	AllowCleartextPasswords bool    `json:"allowCleartextPasswords"`
+	AuthenticationType      string  `json:"authenticationType"`
+	RowLimit                int64   `json:"rowLimit"`
+	MaxEstimatedCost        float64 `json:"maxEstimatedCost"`
+	MaxFullScanRows         int64   `json:"maxFullScanRows"`
 }
 
 type DataSourceInfo struct {
@@ -111,7 +114,10 @@ type QueryJson struct {
 	FillInterval float64 `json:"fillInterval"`
 	FillMode     string  `json:"fillMode"`
 	FillValue    float64 `json:"fillValue"`
+	FillBucket   string  `json:"fillBucket"`
+	FillTimezone string  `json:"fillTimezone"`
 	Format       string  `json:"format"`
+	Explain      bool    `json:"explain"`
 }
 
 func (e *DataSourceHandler) TransformQueryError(logger log.Logger, err error) error {
@@ -160,7 +166,7 @@ func NewQueryDataHandler(ctx context.Context, settings backend.DataSourceInstanc
 		timeColumnNames:        []string{"time"},
 		log:                    log,
 		dsInfo:                 config.DSInfo,
-		rowLimit:               config.RowLimit,
+		rowLimit:               dataSourceRowLimit(config),
 		userError:              userFacingDefaultError,
 		azureSettings:          azureSettings,
 		azureCredentials:       azureCredentials,
@@ -184,7 +190,7 @@ func NewQueryDataHandler(ctx context.Context, settings backend.DataSourceInstanc
 			return nil, err
 		}
 
-		db, err := newMSSQL(driverName, config.RowLimit, config.DSInfo, cnnstr, log, proxyClient)
+		db, err := newMSSQL(driverName, queryDataHandler.rowLimit, config.DSInfo, cnnstr, log, proxyClient)
 		if err != nil {
 			logger.Error("Failed connecting to MSSQL", "err", err)
 			return nil, err
@@ -196,6 +202,16 @@ func NewQueryDataHandler(ctx context.Context, settings backend.DataSourceInstanc
 	return &queryDataHandler, nil
 }
 
+// dataSourceRowLimit returns the row limit of the queries. The row limit of the data source can only lower
+// the one of the server.
+func dataSourceRowLimit(config DataPluginConfiguration) int64 {
+	limit := config.DSInfo.JsonData.RowLimit
+	if limit > 0 && (config.RowLimit < 0 || limit < config.RowLimit) {
+		return limit
+	}
+	return config.RowLimit
+}
+
 type DBDataResponse struct {
 	dataResponse backend.DataResponse
 	refID        string
@@ -271,7 +287,7 @@ func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDat
 
 		// the fill-params are only stored inside this function, during query-interpolation. we do not support
 		// sending them in "from the outside"
-		if queryjson.Fill || queryjson.FillInterval != 0.0 || queryjson.FillMode != "" || queryjson.FillValue != 0.0 {
+		if queryjson.Fill || queryjson.FillInterval != 0.0 || queryjson.FillMode != "" || queryjson.FillValue != 0.0 || queryjson.FillBucket != "" {
 			return nil, fmt.Errorf("query fill-parameters not supported")
 		}
 
@@ -357,6 +373,13 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		errAppendDebug("retrieving database connection failed", e.TransformQueryError(logger, err), interpolatedQuery, backend.ErrorSourcePlugin)
 		return
 	}
+
+	if res := e.preflight(queryContext, logger, db, interpolatedQuery, queryJson); res != nil {
+		queryResult.dataResponse = *res
+		ch <- queryResult
+		return
+	}
+
 	rows, err := db.QueryContext(queryContext, interpolatedQuery)
 	if err != nil {
 		errAppendDebug("db query error", e.TransformQueryError(logger, err), interpolatedQuery, backend.ErrorSourceDownstream)
@@ -469,7 +492,11 @@ func (e *DataSourceHandler) processResponse(qm *dataQueryModel, rows *sql.Rows,
 			}
 
 			var err error
-			frame, err = sqlutil.ResampleWideFrame(frame, qm.FillMissing, alignedTimeRange, qm.Interval) //nolint:staticcheck
+			if qm.TimeBucket != nil {
+				frame, err = resampleToTimeBuckets(frame, qm.FillMissing, qm.TimeRange, *qm.TimeBucket)
+			} else {
+				frame, err = sqlutil.ResampleWideFrame(frame, qm.FillMissing, alignedTimeRange, qm.Interval) //nolint:staticcheck
+			}
 			if err != nil {
 				logger.Error("Failed to resample dataframe", "err", err)
 				frame.AppendNotices(data.Notice{Text: "Failed to resample dataframe", Severity: data.NoticeSeverityWarning})
@@ -532,6 +559,13 @@ func (e *DataSourceHandler) newProcessCfg(query backend.DataQuery, queryContext
 			qm.FillMissing.Value = queryJson.FillValue
 		default:
 		}
+		if queryJson.FillBucket != "" {
+			bucket, err := ParseTimeBucket(queryJson.FillBucket, queryJson.FillTimezone)
+			if err != nil {
+				return nil, err
+			}
+			qm.TimeBucket = &bucket
+		}
 	}
 
 	qm.TimeRange.From = query.TimeRange.From.UTC()
@@ -594,6 +628,7 @@ type dataQueryModel struct {
 	TimeRange         backend.TimeRange
 	FillMissing       *data.FillMissing // property not set until after Interpolate()
 	Interval          time.Duration
+	TimeBucket        *TimeBucket // property not set until after Interpolate()
 	columnNames       []string
 	columnTypes       []*sql.ColumnType
 	timeIndex         int
@@ -684,6 +719,21 @@ func convertSQLValueColumnToFloat(frame *data.Frame, Index int) (*data.Frame, er
 }
 
 func SetupFillmode(query *backend.DataQuery, interval time.Duration, fillmode string) error {
+	return setupFillmode(query, fillmode, map[string]any{
+		"fillInterval": interval.Seconds(),
+	})
+}
+
+// SetupTimeBucketFillmode sets up the fill mode of the $__timeBucket macro, which fills the missing calendar
+// buckets of the time range.
+func SetupTimeBucketFillmode(query *backend.DataQuery, bucket TimeBucket, fillmode string) error {
+	return setupFillmode(query, fillmode, map[string]any{
+		"fillBucket":   bucket.Unit,
+		"fillTimezone": bucket.Location.String(),
+	})
+}
+
+func setupFillmode(query *backend.DataQuery, fillmode string, fillProps map[string]any) error {
 	rawQueryProp := make(map[string]any)
 	queryBytes, err := query.JSON.MarshalJSON()
 	if err != nil {
@@ -694,7 +744,9 @@ func SetupFillmode(query *backend.DataQuery, interval time.Duration, fillmode st
 		return err
 	}
 	rawQueryProp["fill"] = true
-	rawQueryProp["fillInterval"] = interval.Seconds()
+	for k, v := range fillProps {
+		rawQueryProp[k] = v
+	}
 
 	switch fillmode {
 	case "NULL":
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
@@ -80,6 +81,10 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		return fmt.Sprintf("FROM_UNIXTIME(%d)", timeRange.From.UTC().Unix()), nil
 	case "__timeTo":
 		return fmt.Sprintf("FROM_UNIXTIME(%d)", timeRange.To.UTC().Unix()), nil
+	case "__lastSeen":
+		// In streaming queries the time range starts at the newest row sent so far, so it is not rounded to seconds.
+		from := timeRange.From.UTC()
+		return fmt.Sprintf("FROM_UNIXTIME(%d.%06d)", from.Unix(), from.Nanosecond()/int(time.Microsecond)), nil
 	case "__timeGroup":
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval", name)
@@ -101,6 +106,29 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 			return tg + " AS \"time\"", nil
 		}
 		return "", err
+	case "__timeBucket":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and calendar unit", name)
+		}
+		bucket, fill, err := sqleng.ParseTimeBucketArgs(args[1:])
+		if err != nil {
+			return "", err
+		}
+		if fill != "" {
+			err := sqleng.SetupTimeBucketFillmode(query, bucket, fill)
+			if err != nil {
+				return "", err
+			}
+		}
+		return bucket.Expr(args[0], timeRange, func(t time.Time) string {
+			return fmt.Sprintf("FROM_UNIXTIME(%d)", t.Unix())
+		})
+	case "__timeBucketAlias":
+		tb, err := m.evaluateMacro(timeRange, query, "__timeBucket", args)
+		if err == nil {
+			return tb + " AS \"time\"", nil
+		}
+		return "", err
 	case "__unixEpochFilter":
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
@@ -78,6 +78,43 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, fmt.Sprintf("select FROM_UNIXTIME(%d)", to.Unix()), sql)
 		})
 
+		t.Run("interpolate __lastSeen function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, backend.TimeRange{From: from.Add(1500 * time.Microsecond), To: to}, "select * from t where time > $__lastSeen()")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("select * from t where time > FROM_UNIXTIME(%d.001500)", from.Unix()), sql)
+		})
+
+		t.Run("interpolate __timeBucket function", func(t *testing.T) {
+			berlin, err := time.LoadLocation("Europe/Berlin")
+			require.NoError(t, err)
+			jan := time.Date(2024, 1, 1, 0, 0, 0, 0, berlin)
+			feb := time.Date(2024, 2, 1, 0, 0, 0, 0, berlin)
+			mar := time.Date(2024, 3, 1, 0, 0, 0, 0, berlin)
+			timeRange := backend.TimeRange{From: jan.AddDate(0, 0, 14), To: mar.AddDate(0, 0, 19)}
+
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeBucket(time_column, month, 'Europe/Berlin')")
+			require.Nil(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeBucketAlias(time_column, month, 'Europe/Berlin')")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("GROUP BY CASE WHEN time_column < FROM_UNIXTIME(%d) THEN %d WHEN time_column < FROM_UNIXTIME(%d) THEN %d ELSE %d END",
+				feb.Unix(), jan.Unix(), mar.Unix(), feb.Unix(), mar.Unix()), sql)
+			require.Equal(t, sql+" AS \"time\"", sql2)
+		})
+
+		t.Run("interpolate __timeBucket function with fill", func(t *testing.T) {
+			query := &backend.DataQuery{JSON: []byte(`{"rawSql": "select 1"}`)}
+			_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeBucket(time_column, week, 'Europe/Berlin', 0)")
+			require.Nil(t, err)
+			require.JSONEq(t, `{"rawSql": "select 1", "fill": true, "fillMode": "value", "fillValue": 0, "fillBucket": "week", "fillTimezone": "Europe/Berlin"}`, string(query.JSON))
+		})
+
+		t.Run("interpolate __timeBucket function with an unknown unit", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeBucket(time_column, fortnight)")
+			require.Error(t, err)
+		})
+
 		t.Run("interpolate __unixEpochFilter function", func(t *testing.T) {
 			sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochFilter(time)")
 			require.Nil(t, err)
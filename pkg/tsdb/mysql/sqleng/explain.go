@@ -0,0 +1,193 @@
+package sqleng
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// QueryPlan is the plan of a query, as estimated by the database with EXPLAIN.
+type QueryPlan struct {
+	// Frame is the plan returned to the query editor.
+	Frame *data.Frame
+	// Cost is the estimated cost of the query, in the unit of the database.
+	Cost float64
+	// FullScanRows is the estimated number of rows of the largest table read with a full table scan.
+	FullScanRows int64
+}
+
+// checkQueryPlan returns an error if the plan exceeds the limits of the data source.
+func checkQueryPlan(plan *QueryPlan, jsonData JsonData) error {
+	if jsonData.MaxEstimatedCost > 0 && plan.Cost > jsonData.MaxEstimatedCost {
+		return backend.DownstreamErrorf("the estimated cost of the query (%.2f) exceeds the limit of the data source (%.2f)",
+			plan.Cost, jsonData.MaxEstimatedCost)
+	}
+	if jsonData.MaxFullScanRows > 0 && plan.FullScanRows > jsonData.MaxFullScanRows {
+		return backend.DownstreamErrorf("the query reads a table of about %d rows with a full table scan, which exceeds the limit of the data source (%d rows)",
+			plan.FullScanRows, jsonData.MaxFullScanRows)
+	}
+	return nil
+}
+
+// preflight explains the query when the query asks for its plan, or when the data source limits the queries
+// by their plan. It returns a response when the query must not run: either the plan of the query, or the
+// error of a query above the limits. Queries that cannot be explained run unchecked.
+func (e *DataSourceHandler) preflight(ctx context.Context, logger log.Logger, query string, queryJson QueryJson) *backend.DataResponse {
+	jsonData := e.dsInfo.JsonData
+	if !queryJson.Explain && jsonData.MaxEstimatedCost <= 0 && jsonData.MaxFullScanRows <= 0 {
+		return nil
+	}
+
+	plan, err := e.explain(ctx, query)
+	if err != nil {
+		if !queryJson.Explain {
+			logger.Warn("Failed to explain query, running it without checking its plan", "error", err)
+			return nil
+		}
+		var emptyFrame data.Frame
+		emptyFrame.SetMeta(&data.FrameMeta{ExecutedQueryString: query})
+		return &backend.DataResponse{
+			Error:       fmt.Errorf("explain failed: %w", e.TransformQueryError(logger, err)),
+			ErrorSource: backend.ErrorSourceDownstream,
+			Frames:      data.Frames{&emptyFrame},
+		}
+	}
+
+	if queryJson.Explain {
+		return &backend.DataResponse{Frames: data.Frames{plan.Frame}}
+	}
+	if err := checkQueryPlan(plan, jsonData); err != nil {
+		return &backend.DataResponse{
+			Error:       err,
+			ErrorSource: backend.ErrorSourceDownstream,
+			Frames:      data.Frames{plan.Frame},
+		}
+	}
+	return nil
+}
+
+// explain returns the plan of the query, from EXPLAIN FORMAT=JSON.
+func (e *DataSourceHandler) explain(ctx context.Context, query string) (*QueryPlan, error) {
+	explainQuery := "EXPLAIN FORMAT=JSON " + query
+	var raw string
+	if err := e.db.QueryRowContext(ctx, explainQuery).Scan(&raw); err != nil {
+		return nil, err
+	}
+	plan, err := parseQueryPlan([]byte(raw))
+	if err != nil {
+		return nil, err
+	}
+	plan.Frame.Meta.ExecutedQueryString = explainQuery
+	return plan, nil
+}
+
+// parseQueryPlan parses the output of EXPLAIN FORMAT=JSON. The plan has a row for every table of the query.
+// Tables read with the ALL (full table scan) and index (full index scan) access types count as full scans.
+func parseQueryPlan(raw []byte) (*QueryPlan, error) {
+	var explained struct {
+		QueryBlock map[string]any `json:"query_block"`
+	}
+	if err := json.Unmarshal(raw, &explained); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+	if explained.QueryBlock == nil {
+		return nil, errors.New("failed to parse query plan: query_block is missing")
+	}
+
+	plan := &QueryPlan{}
+	if costInfo, ok := explained.QueryBlock["cost_info"].(map[string]any); ok {
+		plan.Cost = planNumber(costInfo["query_cost"])
+	}
+
+	tableNames := []string{}
+	accessTypes := []string{}
+	keys := []string{}
+	rows := []int64{}
+	filtered := []float64{}
+	costs := []float64{}
+	walkPlanTables(explained.QueryBlock, func(table map[string]any) {
+		name, _ := table["table_name"].(string)
+		accessType, _ := table["access_type"].(string)
+		key, _ := table["key"].(string)
+		examined := int64(planNumber(table["rows_examined_per_scan"]))
+		var cost float64
+		if costInfo, ok := table["cost_info"].(map[string]any); ok {
+			cost = planNumber(costInfo["prefix_cost"])
+		}
+
+		if (accessType == "ALL" || accessType == "index") && examined > plan.FullScanRows {
+			plan.FullScanRows = examined
+		}
+		tableNames = append(tableNames, name)
+		accessTypes = append(accessTypes, accessType)
+		keys = append(keys, key)
+		rows = append(rows, examined)
+		filtered = append(filtered, planNumber(table["filtered"]))
+		costs = append(costs, cost)
+	})
+
+	plan.Frame = data.NewFrame("plan",
+		data.NewField("table", nil, tableNames),
+		data.NewField("accessType", nil, accessTypes),
+		data.NewField("key", nil, keys),
+		data.NewField("rowsExaminedPerScan", nil, rows),
+		data.NewField("filtered", nil, filtered),
+		data.NewField("prefixCost", nil, costs),
+	)
+	plan.Frame.SetMeta(&data.FrameMeta{
+		PreferredVisualization: data.VisTypeTable,
+		Notices: []data.Notice{{
+			Severity: data.NoticeSeverityInfo,
+			Text:     fmt.Sprintf("Estimated query cost: %.2f", plan.Cost),
+		}},
+	})
+	return plan, nil
+}
+
+// nestedPlanKeys are the keys of the plan objects that can contain tables, in the order they are read.
+var nestedPlanKeys = []string{"table", "nested_loop", "ordering_operation", "grouping_operation", "duplicates_removal",
+	"windowing", "buffer_result", "union_result", "query_specifications", "query_block",
+	"materialized_from_subquery", "attached_subqueries", "optimized_away_subqueries", "subqueries"}
+
+// walkPlanTables calls fn for every table of the plan, in the order of the plan. Tables are nested in
+// joins, subqueries, unions and ordering or grouping operations.
+func walkPlanTables(v any, fn func(table map[string]any)) {
+	switch v := v.(type) {
+	case map[string]any:
+		if table, ok := v["table"].(map[string]any); ok {
+			if _, ok := table["table_name"]; ok {
+				fn(table)
+			}
+		}
+		for _, key := range nestedPlanKeys {
+			if nested, ok := v[key]; ok {
+				walkPlanTables(nested, fn)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			walkPlanTables(item, fn)
+		}
+	}
+}
+
+// planNumber returns the value of a number of the plan. MySQL formats costs as strings.
+func planNumber(v any) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0
+		}
+		return f
+	}
+	return 0
+}
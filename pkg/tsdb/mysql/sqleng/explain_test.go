@@ -0,0 +1,96 @@
+package sqleng
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const mysqlPlan = `{
+  "query_block": {
+    "select_id": 1,
+    "cost_info": {"query_cost": "1612.50"},
+    "ordering_operation": {
+      "using_filesort": true,
+      "nested_loop": [
+        {
+          "table": {
+            "table_name": "metric",
+            "access_type": "ALL",
+            "rows_examined_per_scan": 15000,
+            "rows_produced_per_join": 1500,
+            "filtered": "10.00",
+            "cost_info": {"read_cost": "1350.00", "eval_cost": "150.00", "prefix_cost": "1500.00"}
+          }
+        },
+        {
+          "table": {
+            "table_name": "host",
+            "access_type": "eq_ref",
+            "key": "PRIMARY",
+            "rows_examined_per_scan": 1,
+            "rows_produced_per_join": 1500,
+            "filtered": "100.00",
+            "cost_info": {"read_cost": "37.50", "eval_cost": "75.00", "prefix_cost": "1612.50"}
+          }
+        }
+      ]
+    }
+  }
+}`
+
+func TestParseQueryPlan(t *testing.T) {
+	t.Run("parses the cost and the tables of the plan", func(t *testing.T) {
+		plan, err := parseQueryPlan([]byte(mysqlPlan))
+		require.NoError(t, err)
+		require.Equal(t, 1612.5, plan.Cost)
+		require.Equal(t, int64(15000), plan.FullScanRows)
+
+		frame := plan.Frame
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "metric", frame.Fields[0].At(0))
+		require.Equal(t, "host", frame.Fields[0].At(1))
+		require.Equal(t, "ALL", frame.Fields[1].At(0))
+		require.Equal(t, "PRIMARY", frame.Fields[2].At(1))
+		require.Equal(t, int64(15000), frame.Fields[3].At(0))
+		require.Equal(t, 10.0, frame.Fields[4].At(0))
+		require.Equal(t, 1612.5, frame.Fields[5].At(1))
+	})
+
+	t.Run("finds the tables of subqueries", func(t *testing.T) {
+		plan, err := parseQueryPlan([]byte(`{"query_block": {"cost_info": {"query_cost": "3.00"}, "table": {"table_name": "t", "access_type": "ref",
+			"rows_examined_per_scan": 2, "materialized_from_subquery": {"query_block": {"table": {"table_name": "s", "access_type": "index", "rows_examined_per_scan": 40}}}}}}`))
+		require.NoError(t, err)
+		require.Equal(t, 2, plan.Frame.Rows())
+		require.Equal(t, "s", plan.Frame.Fields[0].At(1))
+		require.Equal(t, int64(40), plan.FullScanRows)
+	})
+
+	t.Run("fails without a query block", func(t *testing.T) {
+		_, err := parseQueryPlan([]byte(`{}`))
+		require.Error(t, err)
+	})
+}
+
+func TestCheckQueryPlan(t *testing.T) {
+	plan := &QueryPlan{Cost: 1612.5, FullScanRows: 15000}
+
+	require.NoError(t, checkQueryPlan(plan, JsonData{}))
+	require.NoError(t, checkQueryPlan(plan, JsonData{MaxEstimatedCost: 2000, MaxFullScanRows: 20000}))
+	require.ErrorContains(t, checkQueryPlan(plan, JsonData{MaxEstimatedCost: 1000}), "estimated cost")
+	require.ErrorContains(t, checkQueryPlan(plan, JsonData{MaxFullScanRows: 10000}), "full table scan")
+}
+
+func TestDataSourceRowLimit(t *testing.T) {
+	config := func(server, dataSource int64) DataPluginConfiguration {
+		return DataPluginConfiguration{
+			DSInfo:   DataSourceInfo{JsonData: JsonData{RowLimit: dataSource}},
+			RowLimit: server,
+		}
+	}
+
+	require.Equal(t, int64(1000), dataSourceRowLimit(config(1000, 0)))
+	require.Equal(t, int64(100), dataSourceRowLimit(config(1000, 100)))
+	require.Equal(t, int64(1000), dataSourceRowLimit(config(1000, 5000)))
+	require.Equal(t, int64(100), dataSourceRowLimit(config(-1, 100)))
+}
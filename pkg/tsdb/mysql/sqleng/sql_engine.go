@@ -38,26 +38,29 @@ type SqlQueryResultTransformer interface {
 }
 
 type JsonData struct {
-	MaxOpenConns            int    `json:"maxOpenConns"`
-	MaxIdleConns            int    `json:"maxIdleConns"`
-	ConnMaxLifetime         int    `json:"connMaxLifetime"`
-	ConnectionTimeout       int    `json:"connectionTimeout"`
-	Timescaledb             bool   `json:"timescaledb"`
-	Mode                    string `json:"sslmode"`
-	ConfigurationMethod     string `json:"tlsConfigurationMethod"`
-	TlsSkipVerify           bool   `json:"tlsSkipVerify"`
-	RootCertFile            string `json:"sslRootCertFile"`
-	CertFile                string `json:"sslCertFile"`
-	CertKeyFile             string `json:"sslKeyFile"`
-	Timezone                string `json:"timezone"`
-	Encrypt                 string `json:"encrypt"`
-	Servername              string `json:"servername"`
-	TimeInterval            string `json:"timeInterval"`
-	Database                string `json:"database"`
-	SecureDSProxy           bool   `json:"enableSecureSocksProxy"`
-	SecureDSProxyUsername   string `json:"secureSocksProxyUsername"`
-	AllowCleartextPasswords bool   `json:"allowCleartextPasswords"`
-	AuthenticationType      string `json:"authenticationType"`
+	MaxOpenConns            int     `json:"maxOpenConns"`
+	MaxIdleConns            int     `json:"maxIdleConns"`
+	ConnMaxLifetime         int     `json:"connMaxLifetime"`
+	ConnectionTimeout       int     `json:"connectionTimeout"`
+	Timescaledb             bool    `json:"timescaledb"`
+	Mode                    string  `json:"sslmode"`
+	ConfigurationMethod     string  `json:"tlsConfigurationMethod"`
+	TlsSkipVerify           bool    `json:"tlsSkipVerify"`
+	RootCertFile            string  `json:"sslRootCertFile"`
+	CertFile                string  `json:"sslCertFile"`
+	CertKeyFile             string  `json:"sslKeyFile"`
+	Timezone                string  `json:"timezone"`
+	Encrypt                 string  `json:"encrypt"`
+	Servername              string  `json:"servername"`
+	TimeInterval            string  `json:"timeInterval"`
+	Database                string  `json:"database"`
+	SecureDSProxy           bool    `json:"enableSecureSocksProxy"`
+	SecureDSProxyUsername   string  `json:"secureSocksProxyUsername"`
+	AllowCleartextPasswords bool    `json:"allowCleartextPasswords"`
+	AuthenticationType      string  `json:"authenticationType"`
+	RowLimit                int64   `json:"rowLimit"`
+	MaxEstimatedCost        float64 `json:"maxEstimatedCost"`
+	MaxFullScanRows         int64   `json:"maxFullScanRows"`
 }
 
 type DataSourceInfo struct {
@@ -88,6 +91,10 @@ type DataSourceHandler struct {
 	dsInfo                 DataSourceInfo
 	rowLimit               int64
 	userError              string
+
+	// streams caches the last frame sent on the channel of every running streaming query.
+	streamsMu sync.RWMutex
+	streams   map[string]data.FrameJSONCache
 }
 
 type QueryJson struct {
@@ -96,7 +103,10 @@ type QueryJson struct {
 	FillInterval float64 `json:"fillInterval"`
 	FillMode     string  `json:"fillMode"`
 	FillValue    float64 `json:"fillValue"`
+	FillBucket   string  `json:"fillBucket"`
+	FillTimezone string  `json:"fillTimezone"`
 	Format       string  `json:"format"`
+	Explain      bool    `json:"explain"`
 }
 
 func (e *DataSourceHandler) TransformQueryError(logger log.Logger, err error) error {
@@ -121,8 +131,9 @@ func NewQueryDataHandler(userFacingDefaultError string, db *sql.DB, config DataP
 		timeColumnNames:        []string{"time"},
 		log:                    log,
 		dsInfo:                 config.DSInfo,
-		rowLimit:               config.RowLimit,
+		rowLimit:               dataSourceRowLimit(config),
 		userError:              userFacingDefaultError,
+		streams:                make(map[string]data.FrameJSONCache),
 	}
 
 	if len(config.TimeColumnNames) > 0 {
@@ -137,6 +148,16 @@ func NewQueryDataHandler(userFacingDefaultError string, db *sql.DB, config DataP
 	return &queryDataHandler, nil
 }
 
+// dataSourceRowLimit returns the row limit of the queries. The row limit of the data source can only lower
+// the one of the server.
+func dataSourceRowLimit(config DataPluginConfiguration) int64 {
+	limit := config.DSInfo.JsonData.RowLimit
+	if limit > 0 && (config.RowLimit < 0 || limit < config.RowLimit) {
+		return limit
+	}
+	return config.RowLimit
+}
+
 type DBDataResponse struct {
 	dataResponse backend.DataResponse
 	refID        string
@@ -169,7 +190,7 @@ func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDat
 
 		// the fill-params are only stored inside this function, during query-interpolation. we do not support
 		// sending them in "from the outside"
-		if queryjson.Fill || queryjson.FillInterval != 0.0 || queryjson.FillMode != "" || queryjson.FillValue != 0.0 {
+		if queryjson.Fill || queryjson.FillInterval != 0.0 || queryjson.FillMode != "" || queryjson.FillValue != 0.0 || queryjson.FillBucket != "" {
 			return nil, fmt.Errorf("query fill-parameters not supported")
 		}
 
@@ -250,6 +271,12 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		return
 	}
 
+	if res := e.preflight(queryContext, logger, interpolatedQuery, queryJson); res != nil {
+		queryResult.dataResponse = *res
+		ch <- queryResult
+		return
+	}
+
 	rows, err := e.db.QueryContext(queryContext, interpolatedQuery)
 	if err != nil {
 		errAppendDebug("db query error", e.TransformQueryError(logger, err), interpolatedQuery, backend.ErrorSourceDownstream)
@@ -357,7 +384,11 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 			}
 
 			var err error
-			frame, err = sqlutil.ResampleWideFrame(frame, qm.FillMissing, alignedTimeRange, qm.Interval) //nolint:staticcheck
+			if qm.TimeBucket != nil {
+				frame, err = resampleToTimeBuckets(frame, qm.FillMissing, qm.TimeRange, *qm.TimeBucket)
+			} else {
+				frame, err = sqlutil.ResampleWideFrame(frame, qm.FillMissing, alignedTimeRange, qm.Interval) //nolint:staticcheck
+			}
 			if err != nil {
 				logger.Error("Failed to resample dataframe", "err", err)
 				frame.AppendNotices(data.Notice{Text: "Failed to resample dataframe", Severity: data.NoticeSeverityWarning})
@@ -421,6 +452,13 @@ func (e *DataSourceHandler) newProcessCfg(query backend.DataQuery, queryContext
 			qm.FillMissing.Value = queryJson.FillValue
 		default:
 		}
+		if queryJson.FillBucket != "" {
+			bucket, err := ParseTimeBucket(queryJson.FillBucket, queryJson.FillTimezone)
+			if err != nil {
+				return nil, err
+			}
+			qm.TimeBucket = &bucket
+		}
 	}
 
 	qm.TimeRange.From = query.TimeRange.From.UTC()
@@ -483,6 +521,7 @@ type dataQueryModel struct {
 	TimeRange         backend.TimeRange
 	FillMissing       *data.FillMissing // property not set until after Interpolate()
 	Interval          time.Duration
+	TimeBucket        *TimeBucket // property not set until after Interpolate()
 	columnNames       []string
 	columnTypes       []*sql.ColumnType
 	timeIndex         int
@@ -573,6 +612,21 @@ func convertSQLValueColumnToFloat(frame *data.Frame, Index int) (*data.Frame, er
 }
 
 func SetupFillmode(query *backend.DataQuery, interval time.Duration, fillmode string) error {
+	return setupFillmode(query, fillmode, map[string]any{
+		"fillInterval": interval.Seconds(),
+	})
+}
+
+// SetupTimeBucketFillmode sets up the fill mode of the $__timeBucket macro, which fills the missing calendar
+// buckets of the time range.
+func SetupTimeBucketFillmode(query *backend.DataQuery, bucket TimeBucket, fillmode string) error {
+	return setupFillmode(query, fillmode, map[string]any{
+		"fillBucket":   bucket.Unit,
+		"fillTimezone": bucket.Location.String(),
+	})
+}
+
+func setupFillmode(query *backend.DataQuery, fillmode string, fillProps map[string]any) error {
 	rawQueryProp := make(map[string]any)
 	queryBytes, err := query.JSON.MarshalJSON()
 	if err != nil {
@@ -583,7 +637,9 @@ func SetupFillmode(query *backend.DataQuery, interval time.Duration, fillmode st
 		return err
 	}
 	rawQueryProp["fill"] = true
-	rawQueryProp["fillInterval"] = interval.Seconds()
+	for k, v := range fillProps {
+		rawQueryProp[k] = v
+	}
 
 	switch fillmode {
 	case "NULL":
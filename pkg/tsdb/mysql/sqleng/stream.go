@@ -0,0 +1,249 @@
+package sqleng
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// StreamPathPrefix is the prefix of the paths of the Live channels of streaming queries.
+	StreamPathPrefix = "stream/"
+
+	defaultStreamInterval = 5 * time.Second
+	minStreamInterval     = time.Second
+)
+
+// StreamQueryJson is the query of a streaming query. It is sent as the data of the Live channel.
+type StreamQueryJson struct {
+	QueryJson
+	// StreamInterval is how often the query is run, e.g. "10s".
+	StreamInterval string `json:"streamInterval"`
+	// TimeRange is the time range of the panel, in epoch milliseconds. The first run of the query
+	// returns the rows since its start.
+	TimeRange struct {
+		From string `json:"from"`
+	} `json:"timeRange"`
+}
+
+func parseStreamQuery(raw json.RawMessage) (*StreamQueryJson, error) {
+	q := &StreamQueryJson{
+		QueryJson: QueryJson{
+			Format: "table",
+		},
+	}
+	if err := json.Unmarshal(raw, q); err != nil {
+		return nil, fmt.Errorf("error unmarshal query json: %w", err)
+	}
+	if q.RawSql == "" {
+		return nil, errors.New("missing rawSql in channel data")
+	}
+	if q.Fill || q.FillInterval != 0.0 || q.FillMode != "" || q.FillValue != 0.0 || q.FillBucket != "" {
+		return nil, errors.New("query fill-parameters not supported")
+	}
+	return q, nil
+}
+
+func (q *StreamQueryJson) interval() (time.Duration, error) {
+	if q.StreamInterval == "" {
+		return defaultStreamInterval, nil
+	}
+	interval, err := gtime.ParseDuration(q.StreamInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid stream interval: %w", err)
+	}
+	if interval < minStreamInterval {
+		return minStreamInterval, nil
+	}
+	return interval, nil
+}
+
+func (q *StreamQueryJson) from(now time.Time) time.Time {
+	ms, err := strconv.ParseInt(q.TimeRange.From, 10, 64)
+	if err != nil || ms <= 0 {
+		return now
+	}
+	return time.UnixMilli(ms)
+}
+
+// SubscribeStream allows subscribing to streaming queries. The last rows sent on the stream, if any,
+// are returned as the initial data.
+func (e *DataSourceHandler) SubscribeStream(_ context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	if !strings.HasPrefix(req.Path, StreamPathPrefix) {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, fmt.Errorf("expected %s in channel path", StreamPathPrefix)
+	}
+	if _, err := parseStreamQuery(req.Data); err != nil {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, err
+	}
+
+	e.streamsMu.RLock()
+	defer e.streamsMu.RUnlock()
+
+	if cache, ok := e.streams[req.Path]; ok {
+		msg, err := backend.NewInitialData(cache.Bytes(data.IncludeAll))
+		return &backend.SubscribeStreamResponse{
+			Status:      backend.SubscribeStreamStatusOK,
+			InitialData: msg,
+		}, err
+	}
+
+	return &backend.SubscribeStreamResponse{
+		Status: backend.SubscribeStreamStatusOK,
+	}, nil
+}
+
+// RunStream runs the query of the channel at the stream interval, and sends the rows that are newer than
+// the ones sent before. The time of the newest row sent so far is the start of the time range of the next run,
+// so that queries can select only new rows with the $__lastSeen() macro.
+func (e *DataSourceHandler) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	query, err := parseStreamQuery(req.Data)
+	if err != nil {
+		return err
+	}
+	interval, err := query.interval()
+	if err != nil {
+		return err
+	}
+	queryJSON, err := json.Marshal(query.QueryJson)
+	if err != nil {
+		return err
+	}
+
+	logger := e.log.FromContext(ctx).With("path", req.Path)
+	logger.Debug("Start streaming query", "interval", interval)
+
+	defer func() {
+		e.streamsMu.Lock()
+		delete(e.streams, req.Path)
+		e.streamsMu.Unlock()
+	}()
+
+	lastSeen := query.from(time.Now())
+	prev := data.FrameJSONCache{}
+	poll := func(now time.Time) error {
+		resp, err := e.QueryData(ctx, &backend.QueryDataRequest{
+			PluginContext: req.PluginContext,
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				JSON:      queryJSON,
+				Interval:  interval,
+				TimeRange: backend.TimeRange{From: lastSeen, To: now},
+			}},
+		})
+		if err != nil {
+			return err
+		}
+		res := resp.Responses["A"]
+		if res.Error != nil {
+			return res.Error
+		}
+		seen := lastSeen
+		for _, frame := range res.Frames {
+			frame, newest, err := rowsAfter(frame, lastSeen)
+			if err != nil {
+				return err
+			}
+			if frame.Rows() == 0 {
+				continue
+			}
+			if newest.After(seen) {
+				seen = newest
+			}
+
+			next, err := data.FrameToJSONCache(frame)
+			if err != nil {
+				return err
+			}
+			if next.SameSchema(&prev) {
+				err = sender.SendBytes(next.Bytes(data.IncludeDataOnly))
+			} else {
+				err = sender.SendFrame(frame, data.IncludeAll)
+			}
+			if err != nil {
+				return err
+			}
+			prev = next
+
+			e.streamsMu.Lock()
+			e.streams[req.Path] = prev
+			e.streamsMu.Unlock()
+		}
+		lastSeen = seen
+		return nil
+	}
+
+	if err := poll(time.Now()); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Debug("Stop streaming query (context canceled)")
+			return nil
+		case t := <-ticker.C:
+			if err := poll(t); err != nil {
+				logger.Warn("Streaming query failed", "error", err)
+				return err
+			}
+		}
+	}
+}
+
+// PublishStream does not allow publishing to the channels of streaming queries.
+func (e *DataSourceHandler) PublishStream(_ context.Context, _ *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return &backend.PublishStreamResponse{
+		Status: backend.PublishStreamStatusPermissionDenied,
+	}, nil
+}
+
+// rowsAfter returns the rows of the frame whose time is after lastSeen, and the time of the newest row.
+// Frames without a time column cannot be streamed incrementally.
+func rowsAfter(frame *data.Frame, lastSeen time.Time) (*data.Frame, time.Time, error) {
+	if frame.Rows() == 0 {
+		return frame, lastSeen, nil
+	}
+	timeIndices := frame.TypeIndices(data.FieldTypeTime, data.FieldTypeNullableTime)
+	if len(timeIndices) == 0 {
+		return nil, lastSeen, errors.New("time column is missing; streaming queries must return a time column")
+	}
+
+	newest := lastSeen
+	filtered, err := frame.FilterRowsByField(timeIndices[0], func(v any) (bool, error) {
+		var t time.Time
+		switch v := v.(type) {
+		case time.Time:
+			t = v
+		case *time.Time:
+			if v == nil {
+				return false, nil
+			}
+			t = *v
+		}
+		if !t.After(lastSeen) {
+			return false, nil
+		}
+		if t.After(newest) {
+			newest = t
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, lastSeen, err
+	}
+	return filtered, newest, nil
+}
@@ -0,0 +1,80 @@
+package sqleng
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStreamQuery(t *testing.T) {
+	t.Run("uses the defaults", func(t *testing.T) {
+		q, err := parseStreamQuery([]byte(`{"rawSql": "select 1"}`))
+		require.NoError(t, err)
+		require.Equal(t, "table", q.Format)
+		interval, err := q.interval()
+		require.NoError(t, err)
+		require.Equal(t, defaultStreamInterval, interval)
+		now := time.Now()
+		require.Equal(t, now, q.from(now))
+	})
+
+	t.Run("parses the interval and the start of the time range", func(t *testing.T) {
+		q, err := parseStreamQuery([]byte(`{"rawSql": "select 1", "format": "time_series", "streamInterval": "30s", "timeRange": {"from": "1000", "to": "2000"}}`))
+		require.NoError(t, err)
+		require.Equal(t, "time_series", q.Format)
+		interval, err := q.interval()
+		require.NoError(t, err)
+		require.Equal(t, 30*time.Second, interval)
+		require.Equal(t, time.UnixMilli(1000), q.from(time.Now()))
+	})
+
+	t.Run("limits the interval", func(t *testing.T) {
+		q, err := parseStreamQuery([]byte(`{"rawSql": "select 1", "streamInterval": "10ms"}`))
+		require.NoError(t, err)
+		interval, err := q.interval()
+		require.NoError(t, err)
+		require.Equal(t, minStreamInterval, interval)
+	})
+
+	t.Run("fails without a query", func(t *testing.T) {
+		_, err := parseStreamQuery([]byte(`{"format": "table"}`))
+		require.Error(t, err)
+	})
+}
+
+func TestRowsAfter(t *testing.T) {
+	t0 := time.Date(2018, 3, 14, 21, 20, 6, 0, time.UTC)
+	t1 := t0.Add(time.Second)
+	t2 := t0.Add(2 * time.Second)
+
+	t.Run("returns the rows after the last seen time", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("time", nil, []*time.Time{&t0, nil, &t2, &t1}),
+			data.NewField("value", nil, []float64{0, 1, 2, 3}),
+		)
+		filtered, newest, err := rowsAfter(frame, t0)
+		require.NoError(t, err)
+		require.Equal(t, t2, newest)
+		require.Equal(t, 2, filtered.Rows())
+		require.Equal(t, []float64{2, 3}, []float64{filtered.Fields[1].At(0).(float64), filtered.Fields[1].At(1).(float64)})
+	})
+
+	t.Run("keeps the last seen time when there are no new rows", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("Time", nil, []time.Time{t0}),
+			data.NewField("value", nil, []float64{0}),
+		)
+		filtered, newest, err := rowsAfter(frame, t1)
+		require.NoError(t, err)
+		require.Equal(t, t1, newest)
+		require.Equal(t, 0, filtered.Rows())
+	})
+
+	t.Run("fails without a time column", func(t *testing.T) {
+		frame := data.NewFrame("", data.NewField("value", nil, []float64{0}))
+		_, _, err := rowsAfter(frame, t0)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,228 @@
+package sqleng
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// maxTimeBuckets limits the number of buckets of the $__timeBucket macro in the time range of a query.
+	maxTimeBuckets = 4096
+	// timeBucketFanOut is the number of branches of every CASE of the $__timeBucket macro. Rows are
+	// compared to a few bucket starts at every level, instead of all of them.
+	timeBucketFanOut = 16
+)
+
+// TimeBucket is a calendar interval of the $__timeBucket macro. Buckets start at midnight in the time zone
+// of the bucket, so that days, weeks, months, quarters and years follow its daylight saving time changes.
+// Weeks start on Monday.
+type TimeBucket struct {
+	Unit     string
+	Location *time.Location
+}
+
+// ParseTimeBucketArgs parses the arguments of the $__timeBucket macro after the time column: the calendar
+// unit, then the optional time zone and fill mode. The fill mode can directly follow the unit for buckets
+// in UTC. It returns the bucket and the fill mode, if any.
+func ParseTimeBucketArgs(args []string) (TimeBucket, string, error) {
+	if len(args) == 0 || len(args) > 3 {
+		return TimeBucket{}, "", fmt.Errorf("macro $__timeBucket needs time column, calendar unit, and optional time zone and fill value")
+	}
+	timezone, fill := "", ""
+	switch len(args) {
+	case 2:
+		if isFillmode(args[1]) {
+			fill = args[1]
+		} else {
+			timezone = args[1]
+		}
+	case 3:
+		timezone, fill = args[1], args[2]
+	}
+	bucket, err := ParseTimeBucket(args[0], timezone)
+	return bucket, fill, err
+}
+
+// ParseTimeBucket returns the bucket of a calendar unit in a time zone. Buckets without a time zone are in UTC.
+func ParseTimeBucket(unit string, timezone string) (TimeBucket, error) {
+	unit = strings.ToLower(strings.Trim(unit, `'"`))
+	switch unit {
+	case "day", "week", "month", "quarter", "year":
+	default:
+		return TimeBucket{}, fmt.Errorf("unsupported calendar unit %q, expected day, week, month, quarter or year", unit)
+	}
+
+	timezone = strings.Trim(timezone, `'"`)
+	if timezone == "" || strings.EqualFold(timezone, "utc") {
+		return TimeBucket{Unit: unit, Location: time.UTC}, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return TimeBucket{}, fmt.Errorf("unknown time zone %q", timezone)
+	}
+	return TimeBucket{Unit: unit, Location: location}, nil
+}
+
+func isFillmode(arg string) bool {
+	if arg == "NULL" || arg == "previous" {
+		return true
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
+// Start returns the start of the bucket of t.
+func (b TimeBucket) Start(t time.Time) time.Time {
+	t = t.In(b.Location)
+	year, month, day := t.Date()
+	switch b.Unit {
+	case "week":
+		return time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, b.Location)
+	case "month":
+		return time.Date(year, month, 1, 0, 0, 0, 0, b.Location)
+	case "quarter":
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, b.Location)
+	case "year":
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, b.Location)
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, b.Location)
+	}
+}
+
+// Next returns the start of the bucket after the one starting at start.
+func (b TimeBucket) Next(start time.Time) time.Time {
+	year, month, day := start.In(b.Location).Date()
+	switch b.Unit {
+	case "week":
+		return time.Date(year, month, day+7, 0, 0, 0, 0, b.Location)
+	case "month":
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, b.Location)
+	case "quarter":
+		return time.Date(year, month+3, 1, 0, 0, 0, 0, b.Location)
+	case "year":
+		return time.Date(year+1, time.January, 1, 0, 0, 0, 0, b.Location)
+	default:
+		return time.Date(year, month, day+1, 0, 0, 0, 0, b.Location)
+	}
+}
+
+// Starts returns the starts of the buckets of the time range.
+func (b TimeBucket) Starts(timeRange backend.TimeRange) ([]time.Time, error) {
+	starts := []time.Time{}
+	for start := b.Start(timeRange.From); !start.After(timeRange.To); start = b.Next(start) {
+		if len(starts) == maxTimeBuckets {
+			return nil, fmt.Errorf("the time range has more than %d buckets of a %s, use a larger calendar unit", maxTimeBuckets, b.Unit)
+		}
+		starts = append(starts, start)
+	}
+	return starts, nil
+}
+
+// Expr returns the SQL expression of the start of the bucket of the time column, in epoch seconds. The bucket
+// starts are computed for the time range of the query, and literal formats them for the comparisons with the
+// time column. Rows before the time range are in its first bucket and rows after it in its last bucket.
+func (b TimeBucket) Expr(column string, timeRange backend.TimeRange, literal func(time.Time) string) (string, error) {
+	starts, err := b.Starts(timeRange)
+	if err != nil {
+		return "", err
+	}
+	return timeBucketExpr(column, starts, literal), nil
+}
+
+func timeBucketExpr(column string, starts []time.Time, literal func(time.Time) string) string {
+	if len(starts) == 1 {
+		return strconv.FormatInt(starts[0].Unix(), 10)
+	}
+
+	size := (len(starts) + timeBucketFanOut - 1) / timeBucketFanOut
+	var sb strings.Builder
+	sb.WriteString("CASE")
+	i := 0
+	for ; i+size < len(starts); i += size {
+		fmt.Fprintf(&sb, " WHEN %s < %s THEN %s", column, literal(starts[i+size]), timeBucketExpr(column, starts[i:i+size], literal))
+	}
+	fmt.Fprintf(&sb, " ELSE %s END", timeBucketExpr(column, starts[i:], literal))
+	return sb.String()
+}
+
+// resampleToTimeBuckets fills the missing buckets of a wide time series frame. It is the calendar-aware
+// equivalent of sqlutil.ResampleWideFrame: every bucket of the time range gets the last row in the bucket,
+// or the values of the fill mode.
+func resampleToTimeBuckets(f *data.Frame, fillMissing *data.FillMissing, timeRange backend.TimeRange, bucket TimeBucket) (*data.Frame, error) {
+	tsSchema := f.TimeSeriesSchema()
+	if tsSchema.Type == data.TimeSeriesTypeNot {
+		return f, fmt.Errorf("can not fill missing, not timeseries frame")
+	}
+	starts, err := bucket.Starts(timeRange)
+	if err != nil {
+		return f, err
+	}
+
+	newFields := make([]*data.Field, 0, len(f.Fields))
+	isValueField := make(map[int]bool, len(tsSchema.ValueIndices))
+	for _, idx := range tsSchema.ValueIndices {
+		isValueField[idx] = true
+	}
+	for _, field := range f.Fields {
+		newField := data.NewFieldFromFieldType(field.Type(), 0)
+		newField.Name = field.Name
+		newField.Labels = field.Labels
+		newFields = append(newFields, newField)
+	}
+	resampledFrame := data.NewFrame(f.Name, newFields...)
+	resampledFrame.Meta = f.Meta
+
+	timeField := f.Fields[tsSchema.TimeIndex]
+	rowLen := f.Rows()
+	row := 0
+	lastSeenRowIdx := -1
+	for _, start := range starts {
+		end := bucket.Next(start)
+		bucketRowIdx := -1
+		for ; row < rowLen; row++ {
+			t, ok := timeField.ConcreteAt(row)
+			if !ok {
+				return f, fmt.Errorf("time point is nil")
+			}
+			if !t.(time.Time).Before(end) {
+				break
+			}
+			if !t.(time.Time).Before(start) {
+				bucketRowIdx = row
+			}
+			lastSeenRowIdx = row
+		}
+
+		vals := make([]any, 0, len(f.Fields))
+		for i, field := range f.Fields {
+			switch {
+			case i == tsSchema.TimeIndex:
+				if field.Type() == data.FieldTypeTime {
+					vals = append(vals, start)
+				} else {
+					vals = append(vals, &start)
+				}
+			case isValueField[i] && bucketRowIdx >= 0:
+				vals = append(vals, f.At(i, bucketRowIdx))
+			case isValueField[i]:
+				val, err := data.GetMissing(fillMissing, field, lastSeenRowIdx)
+				if err != nil {
+					val = nil
+				}
+				vals = append(vals, val)
+			case lastSeenRowIdx >= 0:
+				vals = append(vals, f.At(i, lastSeenRowIdx))
+			default:
+				vals = append(vals, nil)
+			}
+		}
+		resampledFrame.AppendRow(vals...)
+	}
+
+	return resampledFrame, nil
+}
@@ -0,0 +1,86 @@
+package opentsdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ParseAnnotationResponse returns the annotations of the series of an annotation query as an annotation frame.
+// Global annotations are not attached to a series, and OpenTSDB repeats them for every series of the response.
+func ParseAnnotationResponse(logger log.Logger, res *http.Response, refID string, isGlobal bool) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "status", res.Status, "body", string(body))
+		return nil, fmt.Errorf("request failed, status: %s", res.Status)
+	}
+
+	var responseData []OpenTsdbCommon
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
+		return nil, err
+	}
+
+	annotations := []OpenTsdbAnnotation{}
+	seen := make(map[OpenTsdbAnnotation]bool)
+	for _, val := range responseData {
+		seriesAnnotations := val.Annotations
+		if isGlobal {
+			seriesAnnotations = val.GlobalAnnotations
+		}
+		for _, annotation := range seriesAnnotations {
+			if seen[annotation] {
+				continue
+			}
+			seen[annotation] = true
+			annotations = append(annotations, annotation)
+		}
+	}
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotations[i].StartTime < annotations[j].StartTime
+	})
+
+	times := make([]time.Time, 0, len(annotations))
+	timeEnds := make([]*time.Time, 0, len(annotations))
+	texts := make([]string, 0, len(annotations))
+	for _, annotation := range annotations {
+		times = append(times, time.Unix(int64(annotation.StartTime), 0).UTC())
+		var timeEnd *time.Time
+		if annotation.EndTime > 0 {
+			end := time.Unix(int64(annotation.EndTime), 0).UTC()
+			timeEnd = &end
+		}
+		timeEnds = append(timeEnds, timeEnd)
+		texts = append(texts, annotation.Description)
+	}
+
+	frame := data.NewFrame("annotations",
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+	)
+	frame.RefID = refID
+
+	result := resp.Responses[refID]
+	result.Frames = data.Frames{frame}
+	resp.Responses[refID] = result
+	return resp, nil
+}
@@ -255,7 +255,7 @@ func (s *Service) HandleKeyLookup(rw http.ResponseWriter, req *http.Request, que
 	u.Path = path.Join(u.Path, "api/search/lookup")
 	lookupQueryParams := u.Query()
 	lookupQueryParams.Set("m", metric)
-	lookupQueryParams.Set("limit", "1000")
+	lookupQueryParams.Set("limit", fmt.Sprintf("%d", dsInfo.LookupLimit))
 	u.RawQuery = lookupQueryParams.Encode()
 
 	httpReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, u.String(), nil)
@@ -282,6 +282,11 @@ func (s *Service) HandleKeyLookup(rw http.ResponseWriter, req *http.Request, que
 		return
 	}
 
+	if res.StatusCode/100 != 2 {
+		http.Error(rw, fmt.Sprintf("lookup failed: %s", responseBody), res.StatusCode)
+		return
+	}
+
 	var lookupResponse struct {
 		Results []struct {
 			Tags map[string]string `json:"tags"`
@@ -405,6 +410,11 @@ func (s *Service) HandleKeyValueLookup(rw http.ResponseWriter, req *http.Request
 		return
 	}
 
+	if res.StatusCode/100 != 2 {
+		http.Error(rw, fmt.Sprintf("lookup failed: %s", responseBody), res.StatusCode)
+		return
+	}
+
 	var lookupResponse struct {
 		Results []struct {
 			Tags map[string]string `json:"tags"`
@@ -451,3 +461,72 @@ func (s *Service) HandleKeyValueLookup(rw http.ResponseWriter, req *http.Request
 		return
 	}
 }
+
+// HandleAnnotationQuery returns the annotation of a time series, or a global annotation, at a start time.
+// Only reading annotations is allowed: the other methods of the endpoint change the annotations of OpenTSDB.
+func (s *Service) HandleAnnotationQuery(rw http.ResponseWriter, req *http.Request) {
+	logger := logger.FromContext(req.Context())
+
+	if req.Method != http.MethodGet {
+		http.Error(rw, fmt.Sprintf("unsupported method: %s", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	queryParams := req.URL.Query()
+	startTime := queryParams.Get("start_time")
+	if startTime == "" {
+		http.Error(rw, "missing 'start_time' parameter", http.StatusBadRequest)
+		return
+	}
+
+	dsInfo, err := s.getDSInfo(req.Context(), backend.PluginConfigFromContext(req.Context()))
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to get datasource info: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to parse datasource URL: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	u.Path = path.Join(u.Path, "api/annotation")
+	annotationQueryParams := u.Query()
+	annotationQueryParams.Set("start_time", startTime)
+	if tsuid := queryParams.Get("tsuid"); tsuid != "" {
+		annotationQueryParams.Set("tsuid", tsuid)
+	}
+	u.RawQuery = annotationQueryParams.Encode()
+
+	httpReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to create request: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	res, err := dsInfo.HTTPClient.Do(httpReq)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to execute request: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Error("Failed to close response body", "error", err)
+		}
+	}()
+
+	responseBody, err := DecodeResponseBody(res, logger)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to decode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(res.StatusCode)
+	if _, err := rw.Write(responseBody); err != nil {
+		logger.Error("Failed to write response", "error", err)
+		return
+	}
+}
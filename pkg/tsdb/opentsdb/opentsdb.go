@@ -17,6 +17,9 @@ import (
 
 var logger = backend.NewLoggerWith("tsdb.opentsdb")
 
+// defaultLookupLimit is the number of results of lookups when the data source does not set a limit.
+const defaultLookupLimit = 1000
+
 type Service struct {
 	im instancemgmt.InstanceManager
 }
@@ -57,6 +60,8 @@ type QueryModel struct {
 	CounterMax           string                 `json:"counterMax"`
 	CounterResetValue    string                 `json:"counterResetValue"`
 	ExplicitTags         bool                   `json:"explicitTags"`
+	FromAnnotations      bool                   `json:"fromAnnotations"`
+	IsGlobal             bool                   `json:"isGlobal"`
 }
 
 func newInstanceSettings(httpClientProvider *httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -77,6 +82,10 @@ func newInstanceSettings(httpClientProvider *httpclient.Provider) datasource.Ins
 			return nil, fmt.Errorf("error reading settings: %w", err)
 		}
 
+		if jsonData.LookupLimit <= 0 {
+			jsonData.LookupLimit = defaultLookupLimit
+		}
+
 		model := &datasourceInfo{
 			HTTPClient:     client,
 			URL:            settings.URL,
@@ -155,6 +164,7 @@ func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceReq
 	mux.HandleFunc("/api/aggregators", s.HandleAggregatorsQuery)
 	mux.HandleFunc("/api/config/filters", s.HandleFiltersQuery)
 	mux.HandleFunc("/api/search/lookup", s.HandleLookupQuery)
+	mux.HandleFunc("/api/annotation", s.HandleAnnotationQuery)
 
 	handler := httpadapter.New(mux)
 	return handler.CallResource(ctx, req, sender)
@@ -171,12 +181,23 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	result := backend.NewQueryDataResponse()
 
 	for _, query := range req.Queries {
+		var model QueryModel
+		if err := json.Unmarshal(query.JSON, &model); err != nil {
+			result.Responses[query.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to unmarshal query: %v", err))
+			continue
+		}
+		if err := ValidateQueryModel(model); err != nil {
+			result.Responses[query.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+			continue
+		}
+
 		tsdbQuery := OpenTsdbQuery{
 			Start: query.TimeRange.From.Unix(),
 			End:   query.TimeRange.To.Unix(),
 			Queries: []map[string]any{
 				BuildMetric(query),
 			},
+			GlobalAnnotations: model.FromAnnotations && model.IsGlobal,
 		}
 
 		httpReq, err := CreateRequest(ctx, logger, dsInfo, tsdbQuery)
@@ -195,7 +216,12 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 			}
 		}()
 
-		queryRes, err := ParseResponse(logger, httpRes, query.RefID, dsInfo.TSDBVersion)
+		var queryRes *backend.QueryDataResponse
+		if model.FromAnnotations {
+			queryRes, err = ParseAnnotationResponse(logger, httpRes, query.RefID, model.IsGlobal)
+		} else {
+			queryRes, err = ParseResponse(logger, httpRes, query.RefID, dsInfo.TSDBVersion)
+		}
 		if err != nil {
 			return nil, err
 		}
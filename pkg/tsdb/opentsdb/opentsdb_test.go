@@ -2,9 +2,11 @@ package opentsdb
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -711,3 +713,202 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Contains(t, bodies[1], `"end":4000`)
 	})
 }
+
+func TestValidateQueryModel(t *testing.T) {
+	require.NoError(t, ValidateQueryModel(QueryModel{}))
+	require.NoError(t, ValidateQueryModel(QueryModel{DownsampleInterval: "5m", DownsampleFillPolicy: "zero"}))
+	require.NoError(t, ValidateQueryModel(QueryModel{DownsampleInterval: "1dc"}))
+	require.NoError(t, ValidateQueryModel(QueryModel{DownsampleInterval: "0.5s"}))
+	require.NoError(t, ValidateQueryModel(QueryModel{DownsampleInterval: "0all"}))
+	require.NoError(t, ValidateQueryModel(QueryModel{DisableDownsampling: true, DownsampleInterval: "$interval"}))
+	require.NoError(t, ValidateQueryModel(QueryModel{ShouldComputeRate: true, CounterMax: "45", CounterResetValue: "0"}))
+	require.NoError(t, ValidateQueryModel(QueryModel{CounterMax: "abc"}))
+
+	require.ErrorContains(t, ValidateQueryModel(QueryModel{DownsampleInterval: "5 minutes"}), "downsample interval")
+	require.ErrorContains(t, ValidateQueryModel(QueryModel{DownsampleInterval: "1.5m"}), "downsample interval")
+	require.ErrorContains(t, ValidateQueryModel(QueryModel{DownsampleFillPolicy: "linear"}), "fill policy")
+	require.ErrorContains(t, ValidateQueryModel(QueryModel{ShouldComputeRate: true, CounterMax: "abc"}), "counter max")
+	require.ErrorContains(t, ValidateQueryModel(QueryModel{ShouldComputeRate: true, CounterResetValue: "-1"}), "counter reset value")
+}
+
+func TestQueryDataAnnotations(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"metric": "deploys", "tags": {"host": "a"}, "dps": [], "annotations": [{"description": "restart", "startTime": 1405544200}],
+				"globalAnnotations": [{"description": "release", "startTime": 1405544300, "endTime": 1405544400}, {"description": "outage", "startTime": 1405544100}]},
+			{"metric": "deploys", "tags": {"host": "b"}, "dps": [], "annotations": [{"description": "upgrade", "startTime": 1405544150}],
+				"globalAnnotations": [{"description": "release", "startTime": 1405544300, "endTime": 1405544400}, {"description": "outage", "startTime": 1405544100}]}
+		]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		im: datasource.NewInstanceManager(newInstanceSettings(httpclient.NewProvider())),
+	}
+	query := func(isGlobal bool) backend.DataResponse {
+		q := fmt.Sprintf(`{"metric":"deploys","aggregator":"sum","disableDownsampling":true,"fromAnnotations":true,"isGlobal":%t}`, isGlobal)
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: srv.URL, JSONData: []byte(`{"tsdbVersion":4}`)},
+			},
+			Queries: []backend.DataQuery{{
+				RefID:     "Anno",
+				JSON:      []byte(q),
+				TimeRange: backend.TimeRange{From: time.Unix(1405544000, 0), To: time.Unix(1405545000, 0)},
+			}},
+		})
+		require.NoError(t, err)
+		return res.Responses["Anno"]
+	}
+
+	t.Run("returns the annotations of the series", func(t *testing.T) {
+		res := query(false)
+		require.NoError(t, res.Error)
+		require.NotContains(t, body, "globalAnnotations")
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, time.Unix(1405544150, 0).UTC(), frame.Fields[0].At(0))
+		require.Equal(t, "upgrade", frame.Fields[2].At(0))
+		require.Equal(t, "restart", frame.Fields[2].At(1))
+	})
+
+	t.Run("returns the global annotations once", func(t *testing.T) {
+		res := query(true)
+		require.NoError(t, res.Error)
+		require.Contains(t, body, `"globalAnnotations":true`)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "outage", frame.Fields[2].At(0))
+		require.Nil(t, frame.Fields[1].At(0))
+		require.Equal(t, "release", frame.Fields[2].At(1))
+		end := time.Unix(1405544400, 0).UTC()
+		require.Equal(t, &end, frame.Fields[1].At(1))
+	})
+}
+
+func TestQueryDataValidation(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		im: datasource.NewInstanceManager(newInstanceSettings(httpclient.NewProvider())),
+	}
+	res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: srv.URL, JSONData: []byte(`{}`)},
+		},
+		Queries: []backend.DataQuery{
+			{RefID: "A", JSON: []byte(`{"metric":"cpu","aggregator":"avg","downsampleInterval":"5 minutes"}`)},
+			{RefID: "B", JSON: []byte(`{"metric":"cpu","aggregator":"avg","downsampleInterval":"5m","downsampleAggregator":"avg"}`)},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, hits)
+	require.ErrorContains(t, res.Responses["A"].Error, "invalid downsample interval")
+	require.Equal(t, backend.StatusBadRequest, res.Responses["A"].Status)
+	require.NoError(t, res.Responses["B"].Error)
+}
+
+func TestHandleLookupQuery(t *testing.T) {
+	var lookups []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/search/lookup", r.URL.Path)
+		lookups = append(lookups, r.URL.Query())
+		if r.URL.Query().Get("m") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"message":"No such name for 'metrics': 'missing'"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [{"tags": {"host": "b", "env": "prod"}}, {"tags": {"host": "a", "env": "prod"}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		im: datasource.NewInstanceManager(newInstanceSettings(httpclient.NewProvider())),
+	}
+	lookup := func(params string) *httptest.ResponseRecorder {
+		pluginCtx := backend.PluginContext{
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: srv.URL, JSONData: []byte(`{}`)},
+		}
+		ctx := backend.WithPluginContext(context.Background(), pluginCtx)
+		req := httptest.NewRequest(http.MethodGet, "/api/search/lookup?"+params, nil).WithContext(ctx)
+		rw := httptest.NewRecorder()
+		service.HandleLookupQuery(rw, req)
+		return rw
+	}
+
+	t.Run("returns the tag keys of a metric", func(t *testing.T) {
+		rw := lookup("type=key&metric=cpu")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.JSONEq(t, `["env", "host"]`, rw.Body.String())
+		require.Equal(t, "cpu", lookups[len(lookups)-1].Get("m"))
+		require.Equal(t, "1000", lookups[len(lookups)-1].Get("limit"))
+	})
+
+	t.Run("returns the tag values of a key", func(t *testing.T) {
+		rw := lookup("type=keyvalue&metric=cpu&keys=host,%20env=prod")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.JSONEq(t, `["a", "b"]`, rw.Body.String())
+		require.Equal(t, "cpu{host=*,env=prod}", lookups[len(lookups)-1].Get("m"))
+		require.Equal(t, "1000", lookups[len(lookups)-1].Get("limit"))
+	})
+
+	t.Run("returns the errors of OpenTSDB", func(t *testing.T) {
+		rw := lookup("type=key&metric=missing")
+		require.Equal(t, http.StatusNotFound, rw.Code)
+		require.Contains(t, rw.Body.String(), "No such name")
+	})
+}
+
+func TestHandleAnnotationQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/annotation", r.URL.Path)
+		assert.Equal(t, "000001000001000001", r.URL.Query().Get("tsuid"))
+		assert.Equal(t, "1405544146", r.URL.Query().Get("start_time"))
+		_, _ = w.Write([]byte(`{"tsuid": "000001000001000001", "description": "restart", "startTime": 1405544146}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		im: datasource.NewInstanceManager(newInstanceSettings(httpclient.NewProvider())),
+	}
+	pluginCtx := backend.PluginContext{
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: srv.URL, JSONData: []byte(`{}`)},
+	}
+	ctx := backend.WithPluginContext(context.Background(), pluginCtx)
+
+	t.Run("returns the annotation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/annotation?tsuid=000001000001000001&start_time=1405544146", nil).WithContext(ctx)
+		rw := httptest.NewRecorder()
+		service.HandleAnnotationQuery(rw, req)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Contains(t, rw.Body.String(), "restart")
+	})
+
+	t.Run("does not change annotations", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/annotation?start_time=1405544146", nil).WithContext(ctx)
+		rw := httptest.NewRecorder()
+		service.HandleAnnotationQuery(rw, req)
+		require.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	})
+
+	t.Run("needs a start time", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/annotation?tsuid=000001000001000001", nil).WithContext(ctx)
+		rw := httptest.NewRecorder()
+		service.HandleAnnotationQuery(rw, req)
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+	})
+}
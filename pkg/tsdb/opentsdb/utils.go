@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -44,6 +46,46 @@ func FormatDownsampleInterval(ms int64) string {
 	return strconv.FormatInt(days, 10) + "d"
 }
 
+// downsampleIntervalPattern matches the downsample intervals of OpenTSDB, with an optional calendar suffix.
+// Fractional seconds are converted to milliseconds by BuildMetric.
+var downsampleIntervalPattern = regexp.MustCompile(`^(\d+(ms|s|m|h|d|w|n|y)c?|\d+\.\d+s|0all)$`)
+
+var downsampleFillPolicies = []string{"", "none", "nan", "null", "zero"}
+
+// ValidateQueryModel checks the downsample and rate options of a query before it is sent to OpenTSDB.
+func ValidateQueryModel(model QueryModel) error {
+	if !model.DisableDownsampling {
+		if model.DownsampleInterval != "" && !downsampleIntervalPattern.MatchString(model.DownsampleInterval) {
+			return fmt.Errorf("invalid downsample interval %q, expected a number followed by ms, s, m, h, d, w, n or y", model.DownsampleInterval)
+		}
+		if !slices.Contains(downsampleFillPolicies, model.DownsampleFillPolicy) {
+			return fmt.Errorf("invalid downsample fill policy %q, expected none, nan, null or zero", model.DownsampleFillPolicy)
+		}
+	}
+
+	if model.ShouldComputeRate {
+		if err := validateRateOption("counter max", model.CounterMax); err != nil {
+			return err
+		}
+		if err := validateRateOption("counter reset value", model.CounterResetValue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateRateOption(name string, value string) error {
+	if value == "" {
+		return nil
+	}
+	val, err := strconv.ParseFloat(value, 64)
+	if err != nil || val < 0 {
+		return fmt.Errorf("invalid %s %q, expected a non-negative number", name, value)
+	}
+	return nil
+}
+
 func BuildMetric(query backend.DataQuery) map[string]any {
 	metric := make(map[string]any)
 
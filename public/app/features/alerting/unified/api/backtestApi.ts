@@ -25,12 +25,16 @@ export interface BacktestRequest {
 
   // Optional metadata fields
   labels?: Labels;
+  annotations?: Record<string, string>;
   missing_series_evals_to_resolve?: number;
 
   // Optional rule identification fields
   uid?: string;
   rule_group?: string;
   namespace_uid?: string;
+
+  // When set, the response is an array of frames: the state transitions and the notifications that would have been sent
+  include_notifications?: boolean;
 }
 
 export const BACKTEST_URL = '/api/v1/rule/backtest';
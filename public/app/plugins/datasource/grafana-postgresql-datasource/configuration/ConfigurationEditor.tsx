@@ -14,6 +14,7 @@ import {
   Divider,
   MaxLifetimeField,
   MaxOpenConnectionsField,
+  QueryLimits,
   TLSSecretsConfig,
   useMigrateDatabaseFields,
 } from '@grafana/sql';
@@ -420,6 +421,8 @@ export const PostgresConfigEditor = (props: DataSourcePluginOptionsEditorProps<P
           <MaxLifetimeField labelWidth={WIDTH_LONG} jsonData={jsonData} onMaxLifetimeChanged={onMaxLifetimeChanged} />
         </ConfigSubSection>
 
+        <QueryLimits options={options} onOptionsChange={onOptionsChange} />
+
         {config.secureSocksDSProxyEnabled && (
           <SecureSocksProxySettings options={options} onOptionsChange={onOptionsChange} />
         )}
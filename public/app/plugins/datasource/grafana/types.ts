@@ -20,6 +20,9 @@ export enum GrafanaQueryType {
   Read = 'read',
   Search = 'search',
   SearchNext = 'searchNext',
+  Alerts = 'alerts',
+  SearchAnnotations = 'searchAnnotations',
+  Metrics = 'metrics',
 }
 
 export interface GrafanaQuery extends DataQuery {
@@ -33,6 +36,9 @@ export interface GrafanaQuery extends DataQuery {
   snapshot?: DataFrameJSON[];
   timeRegion?: TimeRegionConfig;
   file?: GrafanaQueryFile;
+  alerts?: GrafanaAlertsQuery;
+  annotations?: GrafanaAnnotationsSearchQuery;
+  metrics?: GrafanaMetricsQuery;
   // Random walk configuration
   seriesCount?: number;
   startValue?: number;
@@ -43,6 +49,24 @@ export interface GrafanaQuery extends DataQuery {
   dropPercent?: number;
 }
 
+export interface GrafanaAlertsQuery {
+  ruleUID?: string;
+  states?: string[];
+  labels?: Record<string, string>;
+}
+
+export interface GrafanaAnnotationsSearchQuery {
+  dashboardUID?: string;
+  tags?: string[];
+  matchAny?: boolean;
+  type?: 'annotation' | 'alert';
+  limit?: number;
+}
+
+export interface GrafanaMetricsQuery {
+  name?: string; // regular expression that the metric name must match
+}
+
 export interface GrafanaQueryFile {
   name: string;
   size: number;
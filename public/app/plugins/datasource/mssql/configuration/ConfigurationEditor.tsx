@@ -13,7 +13,7 @@ import {
 import { Trans, t } from '@grafana/i18n';
 import { ConfigSection, ConfigSubSection, DataSourceDescription } from '@grafana/plugin-ui';
 import { config } from '@grafana/runtime';
-import { ConnectionLimits, useMigrateDatabaseFields } from '@grafana/sql';
+import { ConnectionLimits, QueryLimits, useMigrateDatabaseFields } from '@grafana/sql';
 import { NumberInput } from '@grafana/sql/src/components/configuration/NumberInput';
 import {
   Alert,
@@ -404,6 +404,8 @@ export const ConfigurationEditor = (props: DataSourcePluginOptionsEditorProps<Ms
       >
         <ConnectionLimits options={dsSettings} onOptionsChange={onOptionsChange} />
 
+        <QueryLimits options={dsSettings} onOptionsChange={onOptionsChange} />
+
         <ConfigSubSection
           title={t('configuration.configuration-editor.title-connection-details', 'Connection details')}
         >
@@ -12,6 +12,7 @@ import { MySQLOptions } from './types';
 
 export class MySqlDatasource extends SqlDatasource {
   sqlLanguageDefinition: LanguageDefinition | undefined;
+  supportsStreaming = true;
 
   constructor(private instanceSettings: DataSourceInstanceSettings<MySQLOptions>) {
     super(instanceSettings);
@@ -19,6 +19,7 @@ import { catchError, map } from 'rxjs/operators';
 import {
   AnnotationEvent,
   DataFrame,
+  DataFrameView,
   DataQueryRequest,
   DataQueryResponse,
   dateMath,
@@ -196,22 +197,22 @@ export default class OpenTsDatasource extends DataSourceWithBackend<OpenTsdbQuer
           map((response) => {
             const eventList: AnnotationEvent[] = [];
 
+            // The backend returns the annotations of the query, or its global annotations, as annotation frames.
             for (const frame of response.data) {
-              const annotationObject = annotation.isGlobal
-                ? frame.meta?.custom?.globalAnnotations
-                : frame.meta?.custom?.annotations;
-
-              if (annotationObject && isArray(annotationObject)) {
-                annotationObject.forEach((ann) => {
-                  const event: AnnotationEvent = {
-                    text: ann.description,
-                    time: Math.floor(ann.startTime) * 1000,
-                    annotation: annotation,
-                  };
-
-                  eventList.push(event);
-                });
-              }
+              const view = new DataFrameView<{ time: number; timeEnd: number | null; text: string }>(frame);
+              view.forEach((row) => {
+                const event: AnnotationEvent = {
+                  text: row.text,
+                  time: row.time,
+                  annotation: annotation,
+                };
+                if (row.timeEnd) {
+                  event.timeEnd = row.timeEnd;
+                  event.isRegion = true;
+                }
+
+                eventList.push(event);
+              });
             }
 
             return eventList;
@@ -2,6 +2,7 @@
 
 import { DataQuery, RelativeTimeRange } from '@grafana/data';
 import { ExpressionQuery } from 'app/features/expressions/types';
+import { TimeInterval } from 'app/plugins/datasource/alertmanager/types';
 
 import { AlertGroupTotals, AlertInstanceTotals } from './unified-alerting';
 
@@ -142,6 +143,7 @@ export interface PromAlertingRuleDTO extends PromRuleDTOBase {
     state: Exclude<PromAlertingRuleState | GrafanaAlertStateWithReason, PromAlertingRuleState.Inactive>;
     activeAt: string;
     value: string;
+    flappingSince?: string;
   }>;
   labels?: Labels;
   annotations?: Annotations;
@@ -277,9 +279,26 @@ export interface PostableGrafanaRuleDefinition {
     metric: string;
     from: string;
     target_datasource_uid?: string;
+    additional_target_datasource_uids?: string[];
   };
   intervalSeconds?: number;
   missing_series_evals_to_resolve?: number;
+  inhibited_by?: GrafanaRuleInhibition[];
+  evaluation_schedule?: GrafanaEvaluationSchedule;
+  flap_detection?: GrafanaFlapDetection;
+}
+export interface GrafanaRuleInhibition {
+  rule_uid: string;
+  equal?: string[];
+}
+export interface GrafanaEvaluationSchedule {
+  cron?: string;
+  timezone?: string;
+  active_time_intervals?: TimeInterval[];
+}
+export interface GrafanaFlapDetection {
+  transitions: number;
+  window: string;
 }
 export interface GrafanaRuleDefinition extends PostableGrafanaRuleDefinition {
   id?: string;
@@ -324,6 +343,8 @@ export type RulerRuleGroupDTO<R = RulerRuleDTO> = {
   name: string;
   interval?: string;
   source_tenants?: string[];
+  // Grafana-managed groups only, evaluates the rules of the group one after another
+  sequential_evaluation?: boolean;
   rules: R[];
 };
 